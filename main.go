@@ -1,16 +1,38 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"net/url"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/router"
 	"github.com/traPtitech/anke-to/tuning"
 )
 
+// schedulerInterval Schedulerが予定された処理を確認する間隔
+const schedulerInterval = time.Minute
+
+// outboxDispatchInterval OutboxDispatcherが送信するメッセージを確認する間隔
+const outboxDispatchInterval = 10 * time.Second
+
+// webhookDispatchInterval WebhookDispatcherが送信するイベントを確認する間隔
+const webhookDispatchInterval = 10 * time.Second
+
+// defaultReminderOffsets 回答期限の何時間前にリマインドするかのデフォルト
+const defaultReminderOffsets = "24h,1h"
+
 func main() {
 	env, ok := os.LookupEnv("ANKE-TO_ENV")
 	if !ok {
@@ -26,6 +48,17 @@ func main() {
 		case "bench":
 			tuning.Bench()
 			return
+		case "migrate":
+			err := model.EstablishConnection(!logOn)
+			if err != nil {
+				panic(err)
+			}
+
+			err = migrate(os.Args[2:])
+			if err != nil {
+				panic(err)
+			}
+			return
 		}
 	}
 
@@ -39,6 +72,23 @@ func main() {
 		panic(err)
 	}
 
+	// 全体の管理者がいないと管理者を追加できないため、環境変数から初期の管理者を登録する
+	systemAdmins, ok := os.LookupEnv("ANKE-TO_SYSTEM_ADMINS")
+	if ok {
+		err = bootstrapSystemAdmins(systemAdmins)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	groupsFile, ok := os.LookupEnv("TRAQ_GROUPS_FILE")
+	if ok {
+		err = syncGroups(groupsFile)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if env == "pprof" {
 		runtime.SetBlockProfileRate(1)
 		go func() {
@@ -51,5 +101,195 @@ func main() {
 		panic("no PORT")
 	}
 
-	SetRouting(port)
+	reminderConfig, err := loadReminderConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	messageConfig, err := loadMessageConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	// 回答開始日時を過ぎたアンケートの告知などを定期的に行う
+	go InjectScheduler(reminderConfig, messageConfig).Run(context.Background(), schedulerInterval)
+	// アンケートの変更と同じトランザクションで追加されたtraQへのメッセージを送信する
+	go InjectOutboxDispatcher().Run(context.Background(), outboxDispatchInterval)
+	// 回答などと同じトランザクションで追加された外部のWebhookへのイベントを送信する
+	go InjectWebhookDispatcher().Run(context.Background(), webhookDispatchInterval)
+
+	SetRouting(port, messageConfig)
+}
+
+// syncGroups traQのグループ一覧のJSONファイルを読み込み、DBと同期する
+func syncGroups(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open groups file: %w", err)
+	}
+	defer f.Close()
+
+	var groups []model.GroupInfo
+	err = json.NewDecoder(f).Decode(&groups)
+	if err != nil {
+		return fmt.Errorf("failed to decode groups file: %w", err)
+	}
+
+	group := model.NewGroup()
+	err = model.NewTransaction().Do(context.Background(), nil, func(ctx context.Context) error {
+		return group.SyncGroups(ctx, groups)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync groups: %w", err)
+	}
+
+	return nil
+}
+
+// loadReminderConfig 回答期限前のリマインドの設定を環境変数から読み込む
+// ANKE-TO_REMINDER_OFFSETSはカンマ区切りの時間(例: 24h,1h)で、空文字列の場合はリマインドしない
+// ANKE-TO_REMINDER_MODEはmention(チャンネルでメンション)かdm(DM)
+func loadReminderConfig() (router.ReminderConfig, error) {
+	strOffsets, ok := os.LookupEnv("ANKE-TO_REMINDER_OFFSETS")
+	if !ok {
+		strOffsets = defaultReminderOffsets
+	}
+
+	offsets := []time.Duration{}
+	for _, strOffset := range strings.Split(strOffsets, ",") {
+		strOffset = strings.TrimSpace(strOffset)
+		if len(strOffset) == 0 {
+			continue
+		}
+
+		offset, err := time.ParseDuration(strOffset)
+		if err != nil {
+			return router.ReminderConfig{}, fmt.Errorf("failed to parse reminder offset(%s): %w", strOffset, err)
+		}
+		if offset <= 0 {
+			return router.ReminderConfig{}, fmt.Errorf("reminder offset must be positive: %s", strOffset)
+		}
+
+		offsets = append(offsets, offset)
+	}
+
+	mode := router.ReminderMode(os.Getenv("ANKE-TO_REMINDER_MODE"))
+	switch mode {
+	case "":
+		mode = router.ReminderModeMention
+	case router.ReminderModeMention, router.ReminderModeDM:
+	default:
+		return router.ReminderConfig{}, fmt.Errorf("invalid reminder mode: %s", mode)
+	}
+
+	return router.ReminderConfig{
+		Offsets: offsets,
+		Mode:    mode,
+	}, nil
+}
+
+// loadMessageConfig traQに送信するメッセージの設定を環境変数から読み込む
+// ANKE-TO_BASE_URLはメッセージ中のリンクに使うanke-toのURLで、ステージングなどで本番にリンクしないように設定する
+func loadMessageConfig() (router.MessageConfig, error) {
+	baseURL, ok := os.LookupEnv("ANKE-TO_BASE_URL")
+	if !ok {
+		baseURL = router.DefaultBaseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return router.MessageConfig{}, fmt.Errorf("failed to parse base url(%s): %w", baseURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return router.MessageConfig{}, fmt.Errorf("base url must be an absolute http(s) url: %s", baseURL)
+	}
+
+	return router.MessageConfig{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// bootstrapSystemAdmins カンマ区切りのtraQIDを全体の管理者として登録する
+func bootstrapSystemAdmins(systemAdmins string) error {
+	userIDs := []string{}
+	for _, userID := range strings.Split(systemAdmins, ",") {
+		userID = strings.TrimSpace(userID)
+		if len(userID) != 0 {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	err := model.NewSystemAdmin().InsertSystemAdmins(context.Background(), userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap system admins: %w", err)
+	}
+
+	return nil
+}
+
+// migrate migrateサブコマンド
+// up: 未適用のマイグレーションをすべて適用する
+// down N: 適用済みのマイグレーションを新しいものからN個取り消す
+// status: マイグレーションごとの適用状況を表示する
+func migrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up | migrate down N | migrate status")
+	}
+
+	switch args[0] {
+	case "up":
+		err := model.Migrate()
+		if err != nil {
+			return fmt.Errorf("failed to migrate up: %w", err)
+		}
+	case "down":
+		if len(args) != 2 {
+			return errors.New("usage: migrate down N")
+		}
+
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of migrations: %s", args[1])
+		}
+
+		err = model.MigrateDown(n)
+		if err != nil {
+			return fmt.Errorf("failed to migrate down: %w", err)
+		}
+	case "status":
+		statuses, err := model.GetMigrationStatuses()
+		if err != nil {
+			return fmt.Errorf("failed to get migration statuses: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Unknown:
+				state = "unknown"
+			case status.ChecksumMismatch:
+				state = "checksum mismatch"
+			case status.Applied:
+				state = "applied"
+			}
+
+			appliedAt := ""
+			if status.AppliedAt.Valid {
+				appliedAt = status.AppliedAt.Time.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+
+		err = w.Flush()
+		if err != nil {
+			return fmt.Errorf("failed to write migration statuses: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command: %s", args[0])
+	}
+
+	return nil
 }
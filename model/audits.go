@@ -0,0 +1,11 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import "context"
+
+// IAudit AuditのRepository
+type IAudit interface {
+	InsertAuditEvent(ctx context.Context, userID string, questionnaireID int, targetType string, targetID int, action string, before interface{}, after interface{}) error
+	GetAuditEvents(ctx context.Context, questionnaireID int) ([]AuditEvents, error)
+}
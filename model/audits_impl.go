@@ -0,0 +1,176 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 監査ログの操作の種類
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// 監査ログの操作対象の種類
+const (
+	AuditTargetQuestionnaire = "questionnaire"
+	AuditTargetQuestion      = "question"
+	AuditTargetResponse      = "response"
+)
+
+// Audit AuditRepositoryの実装
+type Audit struct{}
+
+// NewAudit Auditのコンストラクター
+func NewAudit() *Audit {
+	return new(Audit)
+}
+
+// AuditEvents audit_eventsテーブルの構造体
+// アンケートの管理者による変更の記録
+type AuditEvents struct {
+	ID              int             `json:"id"              gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	QuestionnaireID int             `json:"questionnaireID" gorm:"type:int(11);not null;index"`
+	TargetType      string          `json:"target_type"     gorm:"type:char(20);size:20;not null"`
+	TargetID        int             `json:"target_id"       gorm:"type:int(11);not null"`
+	UserTraqid      string          `json:"user"            gorm:"type:varchar(32);size:32;not null"`
+	Action          string          `json:"action"          gorm:"type:char(20);size:20;not null"`
+	Diff            json.RawMessage `json:"diff"            gorm:"type:json;not null"`
+	CreatedAt       time.Time       `json:"created_at"      gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// AuditChange 変更された項目の変更前と変更後の値
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// InsertAuditEvent 監査ログの追加
+// before,afterは変更前と変更後の状態で、作成時のbeforeと削除時のafterはnil
+// JSONにしたときに値が変わった項目だけを記録する
+func (*Audit) InsertAuditEvent(ctx context.Context, userID string, questionnaireID int, targetType string, targetID int, action string, before interface{}, after interface{}) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	diff, err := createAuditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to create audit diff: %w", err)
+	}
+
+	err = db.Create(&AuditEvents{
+		QuestionnaireID: questionnaireID,
+		TargetType:      targetType,
+		TargetID:        targetID,
+		UserTraqid:      userID,
+		Action:          action,
+		Diff:            diff,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditEvents アンケートの監査ログを新しい順に取得
+func (*Audit) GetAuditEvents(ctx context.Context, questionnaireID int) ([]AuditEvents, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	auditEvents := []AuditEvents{}
+	err = db.
+		Where("questionnaire_id = ?", questionnaireID).
+		Order("id DESC").
+		Find(&auditEvents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+
+	return auditEvents, nil
+}
+
+// anonymizeResponseAuditEvents アンケートの回答の監査ログから回答者がわからないようにする
+// 回答の削除の記録には操作した人と、変更前の状態として回答者のtraQIDが含まれる
+func anonymizeResponseAuditEvents(db *gorm.DB, questionnaireID int) error {
+	err := db.
+		Model(&AuditEvents{}).
+		Where("questionnaire_id = ? AND target_type = ?", questionnaireID, AuditTargetResponse).
+		UpdateColumns(map[string]interface{}{
+			"user_traqid": "",
+			"diff":        gorm.Expr("JSON_REMOVE(diff, '$.traqID')"),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to anonymize response audit events: %w", err)
+	}
+
+	return nil
+}
+
+// createAuditDiff 変更前と変更後の状態から、値が変わった項目ごとのAuditChangeのJSONを作る
+func createAuditDiff(before interface{}, after interface{}) (json.RawMessage, error) {
+	beforeFields, err := toAuditFields(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert before: %w", err)
+	}
+
+	afterFields, err := toAuditFields(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert after: %w", err)
+	}
+
+	diff := map[string]AuditChange{}
+	for key, beforeValue := range beforeFields {
+		afterValue := afterFields[key]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			diff[key] = AuditChange{
+				Before: beforeValue,
+				After:  afterValue,
+			}
+		}
+	}
+	for key, afterValue := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			diff[key] = AuditChange{
+				Before: nil,
+				After:  afterValue,
+			}
+		}
+	}
+
+	rawDiff, err := json.Marshal(diff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal diff: %w", err)
+	}
+
+	return rawDiff, nil
+}
+
+// toAuditFields 状態をJSONのフィールド名ごとの値にする
+func toAuditFields(state interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if state == nil {
+		return fields, nil
+	}
+
+	rawState, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	err = json.Unmarshal(rawState, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestInsertAuditEvent(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaire := Questionnaires{
+		Title:       "第1回集会らん☆ぷろ募集アンケート",
+		Description: "第1回集会らん☆ぷろ参加者募集",
+		ResSharedTo: "public",
+	}
+	err := db.
+		Session(&gorm.Session{NewDB: true}).
+		Create(&questionnaire).Error
+	if err != nil {
+		t.Errorf("failed to create questionnaire: %v", err)
+		return
+	}
+
+	type state struct {
+		Title   string   `json:"title"`
+		Targets []string `json:"targets"`
+	}
+
+	type args struct {
+		action string
+		before interface{}
+		after  interface{}
+	}
+	type test struct {
+		description string
+		args
+		expectDiff map[string]AuditChange
+	}
+
+	testCases := []test{
+		{
+			description: "作成時は全ての項目の変更後の値を記録",
+			args: args{
+				action: AuditActionCreate,
+				after:  state{Title: "title", Targets: []string{userOne}},
+			},
+			expectDiff: map[string]AuditChange{
+				"title":   {Before: nil, After: "title"},
+				"targets": {Before: nil, After: []interface{}{userOne}},
+			},
+		},
+		{
+			description: "変更時は値が変わった項目のみ記録",
+			args: args{
+				action: AuditActionUpdate,
+				before: state{Title: "title", Targets: []string{userOne}},
+				after:  state{Title: "title", Targets: []string{userOne, userTwo}},
+			},
+			expectDiff: map[string]AuditChange{
+				"targets": {Before: []interface{}{userOne}, After: []interface{}{userOne, userTwo}},
+			},
+		},
+		{
+			description: "削除時は全ての項目の変更前の値を記録",
+			args: args{
+				action: AuditActionDelete,
+				before: state{Title: "title", Targets: []string{}},
+			},
+			expectDiff: map[string]AuditChange{
+				"title":   {Before: "title", After: nil},
+				"targets": {Before: []interface{}{}, After: nil},
+			},
+		},
+		{
+			description: "変更がなくてもエラーなし",
+			args: args{
+				action: AuditActionUpdate,
+				before: state{Title: "title"},
+				after:  state{Title: "title"},
+			},
+			expectDiff: map[string]AuditChange{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := auditImpl.InsertAuditEvent(ctx, userOne, questionnaire.ID, AuditTargetQuestionnaire, questionnaire.ID, testCase.args.action, testCase.args.before, testCase.args.after)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		var auditEvent AuditEvents
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("questionnaire_id = ?", questionnaire.ID).
+			Order("id DESC").
+			First(&auditEvent).Error
+		if err != nil {
+			t.Errorf("failed to get audit event: %v", err)
+			continue
+		}
+
+		assertion.Equal(userOne, auditEvent.UserTraqid, testCase.description, "user_traqid")
+		assertion.Equal(AuditTargetQuestionnaire, auditEvent.TargetType, testCase.description, "target_type")
+		assertion.Equal(questionnaire.ID, auditEvent.TargetID, testCase.description, "target_id")
+		assertion.Equal(testCase.args.action, auditEvent.Action, testCase.description, "action")
+
+		var actualDiff map[string]AuditChange
+		err = json.Unmarshal(auditEvent.Diff, &actualDiff)
+		if !assertion.NoError(err, testCase.description, "unmarshal diff") {
+			continue
+		}
+		assertion.Equal(testCase.expectDiff, actualDiff, testCase.description, "diff")
+	}
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaires := []Questionnaires{
+		{
+			Title:       "第1回集会らん☆ぷろ募集アンケート",
+			Description: "第1回集会らん☆ぷろ参加者募集",
+			ResSharedTo: "public",
+		},
+		{
+			Title:       "第1回集会らん☆ぷろ募集アンケート",
+			Description: "第1回集会らん☆ぷろ参加者募集",
+			ResSharedTo: "public",
+		},
+	}
+	err := db.
+		Session(&gorm.Session{NewDB: true}).
+		Create(&questionnaires).Error
+	if err != nil {
+		t.Errorf("failed to create questionnaires: %v", err)
+		return
+	}
+
+	auditEvents := []AuditEvents{
+		{
+			QuestionnaireID: questionnaires[0].ID,
+			TargetType:      AuditTargetQuestionnaire,
+			TargetID:        questionnaires[0].ID,
+			UserTraqid:      userOne,
+			Action:          AuditActionCreate,
+			Diff:            json.RawMessage(`{}`),
+		},
+		{
+			QuestionnaireID: questionnaires[0].ID,
+			TargetType:      AuditTargetQuestion,
+			TargetID:        1,
+			UserTraqid:      userTwo,
+			Action:          AuditActionDelete,
+			Diff:            json.RawMessage(`{}`),
+		},
+	}
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Create(&auditEvents).Error
+	if err != nil {
+		t.Errorf("failed to create audit events: %v", err)
+		return
+	}
+
+	type test struct {
+		description     string
+		questionnaireID int
+		expectIDs       []int
+	}
+
+	testCases := []test{
+		{
+			description:     "新しい順に取得できる",
+			questionnaireID: questionnaires[0].ID,
+			expectIDs:       []int{auditEvents[1].ID, auditEvents[0].ID},
+		},
+		{
+			description:     "監査ログがなくてもエラーなし",
+			questionnaireID: questionnaires[1].ID,
+			expectIDs:       []int{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		actualAuditEvents, err := auditImpl.GetAuditEvents(ctx, testCase.questionnaireID)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		actualIDs := make([]int, 0, len(actualAuditEvents))
+		for _, auditEvent := range actualAuditEvents {
+			actualIDs = append(actualIDs, auditEvent.ID)
+		}
+		assertion.Equal(testCase.expectIDs, actualIDs, testCase.description, "ids")
+	}
+}
@@ -10,20 +10,7 @@ import (
 	"gorm.io/plugin/prometheus"
 )
 
-var (
-	db        *gorm.DB
-	allTables = []interface{}{
-		Questionnaires{},
-		Questions{},
-		Respondents{},
-		Responses{},
-		Administrators{},
-		Options{},
-		ScaleLabels{},
-		Targets{},
-		Validations{},
-	}
-)
+var db *gorm.DB
 
 // EstablishConnection DBと接続
 func EstablishConnection(isProduction bool) error {
@@ -83,12 +70,38 @@ func EstablishConnection(isProduction bool) error {
 	return nil
 }
 
-// Migrate DBのMigrationを行う
+// Migrate 未適用のマイグレーションをすべて適用する
 func Migrate() error {
-	err := db.AutoMigrate(allTables...)
+	err := newMigrator(db, migrationTable, migrations).up()
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	// 後から追加された質問の種類などを登録する
+	err = seedTypes(db)
+	if err != nil {
+		return fmt.Errorf("failed to seed types: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateDown 適用済みのマイグレーションを新しいものからn個取り消す
+func MigrateDown(n int) error {
+	err := newMigrator(db, migrationTable, migrations).down(n)
 	if err != nil {
-		return fmt.Errorf("failed in table's migration: %w", err)
+		return fmt.Errorf("failed to roll back migrations: %w", err)
 	}
 
 	return nil
 }
+
+// GetMigrationStatuses マイグレーションごとの適用状況を取得する
+func GetMigrationStatuses() ([]MigrationStatus, error) {
+	statuses, err := newMigrator(db, migrationTable, migrations).status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration statuses: %w", err)
+	}
+
+	return statuses, nil
+}
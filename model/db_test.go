@@ -12,15 +12,26 @@ const (
 )
 
 var (
-	administratorImpl = new(Administrator)
-	questionnaireImpl = new(Questionnaire)
-	questionImpl      = new(Question)
-	respondentImpl    = new(Respondent)
-	responseImpl      = new(Response)
-	optionImpl        = new(Option)
-	scaleLabelImpl    = new(ScaleLabel)
-	validationImpl    = new(Validation)
-	targetImpl        = new(Target)
+	administratorImpl     = new(Administrator)
+	questionnaireImpl     = new(Questionnaire)
+	questionImpl          = new(Question)
+	respondentImpl        = new(Respondent)
+	responseImpl          = new(Response)
+	optionImpl            = new(Option)
+	scaleLabelImpl        = new(ScaleLabel)
+	validationImpl        = new(Validation)
+	questionConditionImpl = new(QuestionCondition)
+	targetImpl            = new(Target)
+	groupImpl             = new(Group)
+	systemAdminImpl       = new(SystemAdmin)
+	transactionImpl       = new(Transaction)
+	auditImpl             = new(Audit)
+	responseRevisionImpl  = new(ResponseRevision)
+	outboxImpl            = new(Outbox)
+	reminderImpl          = new(Reminder)
+	messageTemplateImpl   = new(MessageTemplate)
+	webhookImpl           = new(Webhook)
+	webhookDeliveryImpl   = new(WebhookDelivery)
 )
 
 //TestMain テストのmain
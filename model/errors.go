@@ -23,10 +23,46 @@ var (
 	ErrNumberBoundary = errors.New("the number is out of bounds")
 	// ErrTextMatching RegexPatternにマッチしていない
 	ErrTextMatching = errors.New("failed to match the pattern")
+	// ErrInvalidDateTime 回答やMinBound,MaxBoundが質問の種類に合った日時の形式ではない
+	ErrInvalidDateTime = errors.New("invalid date time")
+	// ErrDateTimeBoundary MinBound <= 日時 <= MaxBound でない
+	ErrDateTimeBoundary = errors.New("the date time is out of bounds")
+	// ErrInvalidSelections MinSelections,MaxSelectionsの指定が有効ではない
+	ErrInvalidSelections = errors.New("invalid selections")
+	// ErrSelectionCount MinSelections <= 選択数 <= MaxSelections でない
+	ErrSelectionCount = errors.New("the number of selections is out of bounds")
+	// ErrInvalidQuestionType 存在しないか、新しい質問で使えない質問の種類
+	ErrInvalidQuestionType = errors.New("invalid question type")
+	// ErrInvalidResShareType 存在しないか、新しく設定できない結果の公開範囲
+	ErrInvalidResShareType = errors.New("invalid res share type")
+	// ErrConflict 更新対象が指定された版から変更されている
+	ErrConflict = errors.New("conflict")
+	// ErrAnonymityLocked 回答があるアンケートの匿名を解除しようとした
+	ErrAnonymityLocked = errors.New("anonymity cannot be turned off")
+	// ErrTooManyResponsesPerUser ユーザーの回答数がアンケートの上限に達している
+	ErrTooManyResponsesPerUser = errors.New("too many responses per user")
+	// ErrTooManyResponses アンケートの回答数が上限に達している
+	ErrTooManyResponses = errors.New("too many responses")
+	// ErrInvalidOutboxStatus 存在しないoutboxのメッセージの状態
+	ErrInvalidOutboxStatus = errors.New("invalid outbox status")
+	// ErrInvalidMessageTemplateKind 存在しないtraQに送信するメッセージの種類
+	ErrInvalidMessageTemplateKind = errors.New("invalid message template kind")
+	// ErrInvalidWebhookEvent 存在しないWebhookで送信するイベントの種類
+	ErrInvalidWebhookEvent = errors.New("invalid webhook event")
 	// ErrInvalidAnsweredParam invalid sort param
 	ErrInvalidAnsweredParam = errors.New("invalid answered param")
 	// ErrInvalidTx transactionに誤った値が入っている
 	ErrInvalidTx = errors.New("invalid tx")
 	// ErrDeadlineExceeded deadline exceeded
 	ErrDeadlineExceeded = errors.New("deadline exceeded")
+	// ErrUnknownMigration DBに適用されているマイグレーションがコードに存在しない
+	ErrUnknownMigration = errors.New("unknown migration")
+	// ErrMigrationChecksumMismatch 適用済みのマイグレーションの内容が変更されている
+	ErrMigrationChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrMigrationOutOfOrder 適用済みのマイグレーションより古い未適用のマイグレーションがある
+	ErrMigrationOutOfOrder = errors.New("migration out of order")
+	// ErrIrreversibleMigration 取り消すとデータが失われるマイグレーションを取り消そうとした
+	ErrIrreversibleMigration = errors.New("irreversible migration")
+	// ErrMigrationLocked 他のプロセスがマイグレーション中で、ロックを取得できなかった
+	ErrMigrationLocked = errors.New("migration locked")
 )
@@ -0,0 +1,12 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import "context"
+
+// IGroup GroupのRepository
+type IGroup interface {
+	SyncGroups(ctx context.Context, groups []GroupInfo) error
+	GetGroups(ctx context.Context) ([]GroupInfo, error)
+	GetGroupMembersByNames(ctx context.Context, groupNames []string) (map[string][]string, error)
+}
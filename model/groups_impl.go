@@ -0,0 +1,193 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Group GroupRepositoryの実装
+type Group struct{}
+
+// NewGroup Groupのコンストラクター
+func NewGroup() *Group {
+	return new(Group)
+}
+
+// Groups traq_groupsテーブルの構造体
+// traQのユーザーグループをローカルに複製したもの
+type Groups struct {
+	ID          string    `json:"groupId"     gorm:"type:char(36);size:36;not null;primaryKey" validate:"required,max=36"`
+	Name        string    `json:"name"        gorm:"type:varchar(32);size:32;not null;uniqueIndex" validate:"required,max=32"`
+	Description string    `json:"description" gorm:"type:text;not null"`
+	AdminUser   string    `json:"adminUser"   gorm:"type:varchar(32);size:32;not null" validate:"max=32"`
+	CreatedAt   time.Time `json:"createdAt"   gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `json:"updatedAt"   gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName GROUPSはMySQLの予約語なのでその対応
+func (*Groups) TableName() string {
+	return "traq_groups"
+}
+
+// GroupMembers traq_group_membersテーブルの構造体
+type GroupMembers struct {
+	GroupID    string `gorm:"type:char(36);size:36;not null;primaryKey"`
+	UserTraqid string `gorm:"type:varchar(32);size:32;not null;primaryKey"`
+}
+
+// TableName traq_groupsに合わせる
+func (*GroupMembers) TableName() string {
+	return "traq_group_members"
+}
+
+// GroupInfo グループとそのメンバーの情報
+type GroupInfo struct {
+	Groups
+	Members []string `json:"members" validate:"dive,required,max=32"`
+}
+
+// SyncGroups グループの一覧を与えられたものに置き換える
+func (*Group) SyncGroups(ctx context.Context, groups []GroupInfo) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	err = db.
+		Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Delete(&GroupMembers{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete group members: %w", err)
+	}
+
+	err = db.
+		Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Delete(&Groups{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete groups: %w", err)
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	dbGroups := make([]Groups, 0, len(groups))
+	dbGroupMembers := []GroupMembers{}
+	for _, group := range groups {
+		dbGroups = append(dbGroups, group.Groups)
+
+		for _, member := range group.Members {
+			dbGroupMembers = append(dbGroupMembers, GroupMembers{
+				GroupID:    group.ID,
+				UserTraqid: member,
+			})
+		}
+	}
+
+	err = db.
+		Session(&gorm.Session{}).
+		Create(&dbGroups).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert groups: %w", err)
+	}
+
+	if len(dbGroupMembers) > 0 {
+		err = db.
+			Session(&gorm.Session{}).
+			Create(&dbGroupMembers).Error
+		if err != nil {
+			return fmt.Errorf("failed to insert group members: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetGroups グループとそのメンバーの一覧の取得
+func (*Group) GetGroups(ctx context.Context) ([]GroupInfo, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	groups := []Groups{}
+	err = db.
+		Order("name").
+		Find(&groups).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+
+	groupMembers := []GroupMembers{}
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Order("user_traqid").
+		Find(&groupMembers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+
+	memberMap := make(map[string][]string, len(groups))
+	for _, groupMember := range groupMembers {
+		memberMap[groupMember.GroupID] = append(memberMap[groupMember.GroupID], groupMember.UserTraqid)
+	}
+
+	groupInfos := make([]GroupInfo, 0, len(groups))
+	for _, group := range groups {
+		members, ok := memberMap[group.ID]
+		if !ok {
+			members = []string{}
+		}
+
+		groupInfos = append(groupInfos, GroupInfo{
+			Groups:  group,
+			Members: members,
+		})
+	}
+
+	return groupInfos, nil
+}
+
+// GetGroupMembersByNames グループ名からメンバーの取得
+// 存在しないグループ名は戻り値のmapに含まれない
+func (*Group) GetGroupMembersByNames(ctx context.Context, groupNames []string) (map[string][]string, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	memberMap := map[string][]string{}
+	if len(groupNames) == 0 {
+		return memberMap, nil
+	}
+
+	type groupMember struct {
+		Name       string
+		UserTraqid *string
+	}
+	groupMembers := []groupMember{}
+	err = db.
+		Table("traq_groups").
+		Joins("LEFT OUTER JOIN traq_group_members ON traq_groups.id = traq_group_members.group_id").
+		Where("traq_groups.name IN (?)", groupNames).
+		Order("traq_groups.name, traq_group_members.user_traqid").
+		Select("traq_groups.name, traq_group_members.user_traqid").
+		Find(&groupMembers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+
+	for _, member := range groupMembers {
+		if _, ok := memberMap[member.Name]; !ok {
+			memberMap[member.Name] = []string{}
+		}
+		if member.UserTraqid != nil {
+			memberMap[member.Name] = append(memberMap[member.Name], *member.UserTraqid)
+		}
+	}
+
+	return memberMap, nil
+}
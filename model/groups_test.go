@@ -0,0 +1,182 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// グループはテーブル全体を置き換えるため、並列には実行しない
+
+func TestSyncGroups(t *testing.T) {
+	ctx := context.Background()
+
+	type test struct {
+		description string
+		groups      []GroupInfo
+		isErr       bool
+	}
+
+	testCases := []test{
+		{
+			description: "グループを追加できる",
+			groups: []GroupInfo{
+				{
+					Groups:  Groups{ID: "00000000-0000-0000-0000-000000000001", Name: "SysAd", AdminUser: userOne},
+					Members: []string{userOne, userTwo},
+				},
+				{
+					Groups:  Groups{ID: "00000000-0000-0000-0000-000000000002", Name: "Game", Description: "ゲーム班"},
+					Members: []string{userThree},
+				},
+			},
+		},
+		{
+			description: "既存のグループが置き換えられる",
+			groups: []GroupInfo{
+				{
+					Groups:  Groups{ID: "00000000-0000-0000-0000-000000000003", Name: "Algorithm"},
+					Members: []string{userTwo},
+				},
+			},
+		},
+		{
+			description: "メンバーのいないグループもエラーなし",
+			groups: []GroupInfo{
+				{
+					Groups:  Groups{ID: "00000000-0000-0000-0000-000000000004", Name: "CTF"},
+					Members: []string{},
+				},
+			},
+		},
+		{
+			description: "グループが空でもエラーなし",
+			groups:      []GroupInfo{},
+		},
+		{
+			description: "グループ名が重複しているのでエラー",
+			groups: []GroupInfo{
+				{
+					Groups:  Groups{ID: "00000000-0000-0000-0000-000000000005", Name: "Sound"},
+					Members: []string{},
+				},
+				{
+					Groups:  Groups{ID: "00000000-0000-0000-0000-000000000006", Name: "Sound"},
+					Members: []string{},
+				},
+			},
+			isErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := syncGroupsInTransaction(ctx, testCase.groups)
+			if testCase.isErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			groups, err := groupImpl.GetGroups(ctx)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Len(t, groups, len(testCase.groups))
+
+			expectGroups := make(map[string]GroupInfo, len(testCase.groups))
+			for _, group := range testCase.groups {
+				expectGroups[group.Name] = group
+			}
+			for _, group := range groups {
+				expectGroup, ok := expectGroups[group.Name]
+				if !assert.Truef(t, ok, "unexpected group: %s", group.Name) {
+					continue
+				}
+
+				assert.Equal(t, expectGroup.ID, group.ID)
+				assert.Equal(t, expectGroup.Description, group.Description)
+				assert.Equal(t, expectGroup.AdminUser, group.AdminUser)
+				assert.ElementsMatch(t, expectGroup.Members, group.Members)
+			}
+		})
+	}
+}
+
+func TestGetGroupMembersByNames(t *testing.T) {
+	ctx := context.Background()
+
+	err := syncGroupsInTransaction(ctx, []GroupInfo{
+		{
+			Groups:  Groups{ID: "00000000-0000-0000-0000-000000000011", Name: "SysAd"},
+			Members: []string{userOne, userTwo},
+		},
+		{
+			Groups:  Groups{ID: "00000000-0000-0000-0000-000000000012", Name: "Game"},
+			Members: []string{},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to sync groups: %v", err)
+	}
+
+	type test struct {
+		description string
+		groupNames  []string
+		expect      map[string][]string
+	}
+
+	testCases := []test{
+		{
+			description: "グループのメンバーを取得できる",
+			groupNames:  []string{"SysAd"},
+			expect: map[string][]string{
+				"SysAd": {userOne, userTwo},
+			},
+		},
+		{
+			description: "メンバーのいないグループは空配列",
+			groupNames:  []string{"SysAd", "Game"},
+			expect: map[string][]string{
+				"SysAd": {userOne, userTwo},
+				"Game":  {},
+			},
+		},
+		{
+			description: "存在しないグループは含まれない",
+			groupNames:  []string{"SysAd", userThree},
+			expect: map[string][]string{
+				"SysAd": {userOne, userTwo},
+			},
+		},
+		{
+			description: "グループ名が空なら空のmap",
+			groupNames:  []string{},
+			expect:      map[string][]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			memberMap, err := groupImpl.GetGroupMembersByNames(ctx, testCase.groupNames)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Len(t, memberMap, len(testCase.expect))
+			for name, members := range testCase.expect {
+				assert.ElementsMatch(t, members, memberMap[name], name)
+			}
+		})
+	}
+}
+
+func syncGroupsInTransaction(ctx context.Context, groups []GroupInfo) error {
+	return transactionImpl.Do(ctx, nil, func(ctx context.Context) error {
+		return groupImpl.SyncGroups(ctx, groups)
+	})
+}
@@ -0,0 +1,15 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import (
+	"context"
+)
+
+// IMessageTemplate MessageTemplateのRepository
+type IMessageTemplate interface {
+	GetQuestionnaireMessageTemplates(ctx context.Context, questionnaireID int) ([]QuestionnaireMessageTemplates, error)
+	GetQuestionnaireMessageTemplate(ctx context.Context, questionnaireID int, kind string) (string, error)
+	UpsertQuestionnaireMessageTemplate(ctx context.Context, questionnaireID int, kind string, template string) error
+	DeleteQuestionnaireMessageTemplate(ctx context.Context, questionnaireID int, kind string) error
+}
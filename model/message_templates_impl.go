@@ -0,0 +1,148 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// traQに送信するメッセージの種類
+const (
+	// MessageTemplateKindAnnouncement アンケートの告知
+	MessageTemplateKindAnnouncement = "announcement"
+	// MessageTemplateKindReminder 回答期限前のリマインド
+	MessageTemplateKindReminder = "reminder"
+	// MessageTemplateKindClosing 回答期限を過ぎたときの通知
+	MessageTemplateKindClosing = "closing"
+)
+
+// MessageTemplateKinds traQに送信するメッセージの種類の一覧
+var MessageTemplateKinds = []string{
+	MessageTemplateKindAnnouncement,
+	MessageTemplateKindReminder,
+	MessageTemplateKindClosing,
+}
+
+// MessageTemplate MessageTemplateRepositoryの実装
+type MessageTemplate struct{}
+
+// NewMessageTemplate MessageTemplateのコンストラクター
+func NewMessageTemplate() *MessageTemplate {
+	return new(MessageTemplate)
+}
+
+// QuestionnaireMessageTemplates questionnaire_message_templatesテーブルの構造体
+// アンケートの管理者が設定したメッセージのテンプレートで、設定されていない種類はデフォルトのテンプレートを使う
+type QuestionnaireMessageTemplates struct {
+	QuestionnaireID int       `json:"-"          gorm:"type:int(11);not null;primaryKey"`
+	Kind            string    `json:"kind"       gorm:"type:char(20);size:20;not null;primaryKey"`
+	Template        string    `json:"template"   gorm:"type:text;not null"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// GetQuestionnaireMessageTemplates アンケートに設定されたメッセージのテンプレートの一覧の取得
+func (*MessageTemplate) GetQuestionnaireMessageTemplates(ctx context.Context, questionnaireID int) ([]QuestionnaireMessageTemplates, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	messageTemplates := []QuestionnaireMessageTemplates{}
+	err = db.
+		Where("questionnaire_id = ?", questionnaireID).
+		Order("kind").
+		Find(&messageTemplates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message templates: %w", err)
+	}
+
+	return messageTemplates, nil
+}
+
+// GetQuestionnaireMessageTemplate アンケートに設定されたメッセージのテンプレートの取得
+// 設定されていない場合はErrRecordNotFoundを返す
+func (*MessageTemplate) GetQuestionnaireMessageTemplate(ctx context.Context, questionnaireID int, kind string) (string, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var messageTemplate QuestionnaireMessageTemplates
+	err = db.
+		Select("template").
+		Where("questionnaire_id = ? AND kind = ?", questionnaireID, kind).
+		Take(&messageTemplate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrRecordNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get message template: %w", err)
+	}
+
+	return messageTemplate.Template, nil
+}
+
+// UpsertQuestionnaireMessageTemplate アンケートのメッセージのテンプレートの設定
+// 既に設定されている場合は上書きする
+func (*MessageTemplate) UpsertQuestionnaireMessageTemplate(ctx context.Context, questionnaireID int, kind string, template string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if !isMessageTemplateKind(kind) {
+		return ErrInvalidMessageTemplateKind
+	}
+
+	err = db.
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"template", "updated_at"}),
+		}).
+		Create(&QuestionnaireMessageTemplates{
+			QuestionnaireID: questionnaireID,
+			Kind:            kind,
+			Template:        template,
+			UpdatedAt:       time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert message template: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteQuestionnaireMessageTemplate アンケートのメッセージのテンプレートの削除
+// 削除した種類はデフォルトのテンプレートに戻る
+func (*MessageTemplate) DeleteQuestionnaireMessageTemplate(ctx context.Context, questionnaireID int, kind string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Where("questionnaire_id = ? AND kind = ?", questionnaireID, kind).
+		Delete(&QuestionnaireMessageTemplates{})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to delete message template: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordDeleted
+	}
+
+	return nil
+}
+
+func isMessageTemplateKind(kind string) bool {
+	for _, messageTemplateKind := range MessageTemplateKinds {
+		if kind == messageTemplateKind {
+			return true
+		}
+	}
+
+	return false
+}
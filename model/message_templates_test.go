@@ -0,0 +1,62 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestMessageTemplates(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+
+	_, err = messageTemplateImpl.GetQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement)
+	assertion.ErrorIs(err, ErrRecordNotFound, "not set")
+
+	err = messageTemplateImpl.UpsertQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement, "{{.Title}}")
+	require.NoError(t, err)
+
+	template, err := messageTemplateImpl.GetQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement)
+	require.NoError(t, err)
+	assertion.Equal("{{.Title}}", template, "inserted")
+
+	// 既に設定されている場合は上書きする
+	err = messageTemplateImpl.UpsertQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement, "{{.Description}}")
+	require.NoError(t, err)
+
+	template, err = messageTemplateImpl.GetQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement)
+	require.NoError(t, err)
+	assertion.Equal("{{.Description}}", template, "updated")
+
+	err = messageTemplateImpl.UpsertQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindReminder, "{{.ResponseURL}}")
+	require.NoError(t, err)
+
+	err = messageTemplateImpl.UpsertQuestionnaireMessageTemplate(ctx, questionnaireID, "unknown", "{{.Title}}")
+	assertion.ErrorIs(err, ErrInvalidMessageTemplateKind, "invalid kind")
+
+	messageTemplates, err := messageTemplateImpl.GetQuestionnaireMessageTemplates(ctx, questionnaireID)
+	require.NoError(t, err)
+	if assertion.Len(messageTemplates, 2, "templates") {
+		assertion.Equal(MessageTemplateKindAnnouncement, messageTemplates[0].Kind, "kind")
+		assertion.Equal("{{.Description}}", messageTemplates[0].Template, "template")
+		assertion.Equal(MessageTemplateKindReminder, messageTemplates[1].Kind, "kind")
+	}
+
+	err = messageTemplateImpl.DeleteQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement)
+	require.NoError(t, err)
+
+	_, err = messageTemplateImpl.GetQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement)
+	assertion.ErrorIs(err, ErrRecordNotFound, "deleted")
+
+	err = messageTemplateImpl.DeleteQuestionnaireMessageTemplate(ctx, questionnaireID, MessageTemplateKindAnnouncement)
+	assertion.ErrorIs(err, ErrNoRecordDeleted, "delete again")
+}
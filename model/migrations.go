@@ -0,0 +1,447 @@
+package model
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// migrations スキーマの変更の一覧
+// 適用済みのマイグレーションはchecksumで確認されるため、変更せずに新しいバージョンを追加する
+var migrations = []migration{
+	{
+		// AutoMigrateで作られていたスキーマ
+		// テーブルがあれば作らず、既存のテーブルに後から追加された列はupFuncで追加する
+		version: 1,
+		name:    "baseline",
+		up: []string{
+			"CREATE TABLE IF NOT EXISTS `question_types` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`name` varchar(30) NOT NULL," +
+				"`active` boolean NOT NULL DEFAULT true," +
+				"PRIMARY KEY (`id`)," +
+				"UNIQUE INDEX idx_question_types_name (`name`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `res_share_types` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`name` varchar(30) NOT NULL," +
+				"`active` boolean NOT NULL DEFAULT true," +
+				"PRIMARY KEY (`id`)," +
+				"UNIQUE INDEX idx_res_share_types_name (`name`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `questionnaires` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`title` char(50) NOT NULL," +
+				"`description` text NOT NULL," +
+				"`res_time_limit` TIMESTAMP NULL DEFAULT NULL," +
+				"`deleted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"`res_shared_to` int(11) NOT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`modified_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_questionnaires_res_shared_to_id (`res_shared_to`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `question` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`page_num` int(11) NOT NULL," +
+				"`question_num` int(11) NOT NULL," +
+				"`type` int(11) NOT NULL," +
+				"`body` text DEFAULT NULL," +
+				"`is_required` tinyint(4) NOT NULL DEFAULT false," +
+				"`deleted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_question_type_id (`type`)," +
+				"CONSTRAINT `fk_questionnaires_questions` FOREIGN KEY (`questionnaire_id`) REFERENCES `questionnaires`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `respondents` (" +
+				"`response_id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`user_traqid` varchar(32) DEFAULT NULL," +
+				"`modified_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`submitted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"`deleted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"PRIMARY KEY (`response_id`)," +
+				"CONSTRAINT `fk_questionnaires_respondents` FOREIGN KEY (`questionnaire_id`) REFERENCES `questionnaires`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `response` (" +
+				"`response_id` int(11) NOT NULL," +
+				"`question_id` int(11) NOT NULL," +
+				"`body` text DEFAULT NULL," +
+				"`modified_at` timestamp NOT NULL," +
+				"`deleted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"CONSTRAINT `fk_question_responses` FOREIGN KEY (`question_id`) REFERENCES `question`(`id`)," +
+				"CONSTRAINT `fk_respondents_responses` FOREIGN KEY (`response_id`) REFERENCES `respondents`(`response_id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `administrators` (" +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`user_traqid` varchar(32) NOT NULL," +
+				"PRIMARY KEY (`questionnaire_id`,`user_traqid`)," +
+				"CONSTRAINT `fk_questionnaires_administrators` FOREIGN KEY (`questionnaire_id`) REFERENCES `questionnaires`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `options` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`question_id` int(11) NOT NULL," +
+				"`option_num` int(11) NOT NULL," +
+				"`body` text DEFAULT NULL," +
+				"PRIMARY KEY (`id`)," +
+				"CONSTRAINT `fk_question_options` FOREIGN KEY (`question_id`) REFERENCES `question`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `scale_labels` (" +
+				"`question_id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`scale_label_right` text DEFAULT NULL," +
+				"`scale_label_left` text DEFAULT NULL," +
+				"`scale_min` int(11) DEFAULT NULL," +
+				"`scale_max` int(11) DEFAULT NULL," +
+				"PRIMARY KEY (`question_id`)," +
+				"CONSTRAINT `fk_question_scale_labels` FOREIGN KEY (`question_id`) REFERENCES `question`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `targets` (" +
+				"`questionnaire_id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`user_traqid` varchar(32) NOT NULL," +
+				"PRIMARY KEY (`questionnaire_id`,`user_traqid`)," +
+				"CONSTRAINT `fk_questionnaires_targets` FOREIGN KEY (`questionnaire_id`) REFERENCES `questionnaires`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `validations` (" +
+				"`question_id` int(11) NOT NULL," +
+				"`regex_pattern` text DEFAULT NULL," +
+				"`min_bound` text DEFAULT NULL," +
+				"`max_bound` text DEFAULT NULL," +
+				"`min_selections` int(11) DEFAULT NULL," +
+				"`max_selections` int(11) DEFAULT NULL," +
+				"PRIMARY KEY (`question_id`)," +
+				"CONSTRAINT `fk_question_validations` FOREIGN KEY (`question_id`) REFERENCES `question`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `traq_groups` (" +
+				"`id` char(36) NOT NULL," +
+				"`name` varchar(32) NOT NULL," +
+				"`description` text NOT NULL," +
+				"`admin_user` varchar(32) NOT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`id`)," +
+				"UNIQUE INDEX idx_traq_groups_name (`name`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `traq_group_members` (" +
+				"`group_id` char(36) NOT NULL," +
+				"`user_traqid` varchar(32) NOT NULL," +
+				"PRIMARY KEY (`group_id`,`user_traqid`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `system_admins` (" +
+				"`user_traqid` varchar(32) NOT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`user_traqid`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE IF NOT EXISTS `question_conditions` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`question_id` int(11) NOT NULL," +
+				"`condition_type` char(20) NOT NULL," +
+				"`option_body` text DEFAULT NULL," +
+				"`min_value` double DEFAULT NULL," +
+				"`max_value` double DEFAULT NULL," +
+				"`skip_to_page` int(11) NOT NULL," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_question_conditions_question_id (`question_id`)," +
+				"CONSTRAINT `fk_question_conditions` FOREIGN KEY (`question_id`) REFERENCES `question`(`id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+		},
+		// 既存のテーブルをすべて削除することになるため取り消さない
+		irreversible: true,
+		// 既存のテーブルに足りない列を追加し、question.type,questionnaires.res_shared_toが名前のままの既存のDBを種類の表のIDに置き換える
+		upFunc: func(db *gorm.DB) error {
+			err := addMissingColumns(db, baselineColumns)
+			if err != nil {
+				return fmt.Errorf("failed to add baseline columns: %w", err)
+			}
+
+			err = seedTypes(db)
+			if err != nil {
+				return fmt.Errorf("failed to seed types: %w", err)
+			}
+
+			err = migrateTypeColumns(db)
+			if err != nil {
+				return fmt.Errorf("failed to migrate type columns: %w", err)
+			}
+
+			return nil
+		},
+	},
+	{
+		version: 2,
+		name:    "add questionnaires.is_template",
+		up: []string{
+			"ALTER TABLE `questionnaires` ADD COLUMN `is_template` boolean NOT NULL DEFAULT false",
+		},
+		down: []string{
+			"ALTER TABLE `questionnaires` DROP COLUMN `is_template`",
+		},
+	},
+	{
+		version: 3,
+		name:    "create audit_events",
+		up: []string{
+			"CREATE TABLE `audit_events` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`target_type` char(20) NOT NULL," +
+				"`target_id` int(11) NOT NULL," +
+				"`user_traqid` varchar(32) NOT NULL," +
+				"`action` char(20) NOT NULL," +
+				"`diff` json NOT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_audit_events_questionnaire_id (`questionnaire_id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS `audit_events`",
+		},
+	},
+	{
+		// 既存の回答は1つ目の版にする
+		// 変更前の回答として論理削除されている行はどの版かわからないため、0にして版の回答から除く
+		version: 4,
+		name:    "add response revisions",
+		up: []string{
+			"ALTER TABLE `response` ADD COLUMN `revision` int(11) NOT NULL DEFAULT 1",
+			"UPDATE `response` SET `revision` = 0 WHERE `deleted_at` IS NOT NULL",
+			"CREATE TABLE `response_revisions` (" +
+				"`response_id` int(11) NOT NULL," +
+				"`revision` int(11) NOT NULL," +
+				"`submitted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`response_id`,`revision`)," +
+				"CONSTRAINT `fk_respondents_response_revisions` FOREIGN KEY (`response_id`) REFERENCES `respondents`(`response_id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"INSERT INTO `response_revisions` (`response_id`, `revision`, `submitted_at`, `created_at`) " +
+				"SELECT `response_id`, 1, `submitted_at`, `modified_at` FROM `respondents`",
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS `response_revisions`",
+			"ALTER TABLE `response` DROP COLUMN `revision`",
+		},
+	},
+	{
+		version: 5,
+		name:    "add anonymous questionnaires",
+		up: []string{
+			"ALTER TABLE `questionnaires` ADD COLUMN `is_anonymous` boolean NOT NULL DEFAULT false",
+			"ALTER TABLE `questionnaires` ADD COLUMN `anonymous_salt` char(64) DEFAULT NULL",
+			"ALTER TABLE `respondents` ADD COLUMN `user_hash` char(64) DEFAULT NULL",
+		},
+		down: []string{
+			"ALTER TABLE `respondents` DROP COLUMN `user_hash`",
+			"ALTER TABLE `questionnaires` DROP COLUMN `anonymous_salt`",
+			"ALTER TABLE `questionnaires` DROP COLUMN `is_anonymous`",
+		},
+	},
+	{
+		version: 6,
+		name:    "add response limits",
+		up: []string{
+			"ALTER TABLE `questionnaires` ADD COLUMN `max_responses_per_user` int(11) DEFAULT NULL",
+			"ALTER TABLE `questionnaires` ADD COLUMN `max_total_responses` int(11) DEFAULT NULL",
+		},
+		down: []string{
+			"ALTER TABLE `questionnaires` DROP COLUMN `max_total_responses`",
+			"ALTER TABLE `questionnaires` DROP COLUMN `max_responses_per_user`",
+		},
+	},
+	{
+		version: 7,
+		name:    "add questionnaire schedule",
+		up: []string{
+			"ALTER TABLE `questionnaires` ADD COLUMN `res_start_at` TIMESTAMP NULL DEFAULT NULL",
+			"ALTER TABLE `questionnaires` ADD COLUMN `is_closed` boolean NOT NULL DEFAULT false",
+			"ALTER TABLE `questionnaires` ADD COLUMN `announced_at` TIMESTAMP NULL DEFAULT NULL",
+			// 既存のアンケートは作成時に告知済み
+			"UPDATE `questionnaires` SET `announced_at` = `created_at` WHERE `is_template` = false",
+		},
+		down: []string{
+			"ALTER TABLE `questionnaires` DROP COLUMN `announced_at`",
+			"ALTER TABLE `questionnaires` DROP COLUMN `is_closed`",
+			"ALTER TABLE `questionnaires` DROP COLUMN `res_start_at`",
+		},
+	},
+	{
+		version: 8,
+		name:    "create outbox_messages",
+		up: []string{
+			"CREATE TABLE `outbox_messages` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`message` text NOT NULL," +
+				"`status` char(20) NOT NULL," +
+				"`attempts` int(11) NOT NULL DEFAULT 0," +
+				"`next_attempt_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`last_error` text DEFAULT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`delivered_at` TIMESTAMP NULL DEFAULT NULL," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_outbox_messages_status_next_attempt_at (`status`,`next_attempt_at`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS `outbox_messages`",
+		},
+	},
+	{
+		version: 9,
+		name:    "add deadline reminders",
+		up: []string{
+			"ALTER TABLE `questionnaires` ADD COLUMN `disable_reminders` boolean NOT NULL DEFAULT false",
+			"CREATE TABLE `questionnaire_reminders` (" +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`res_time_limit` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`offset_seconds` int(11) NOT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`questionnaire_id`,`res_time_limit`,`offset_seconds`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"ALTER TABLE `outbox_messages` ADD COLUMN `user_traqid` varchar(32) DEFAULT NULL",
+		},
+		down: []string{
+			"ALTER TABLE `outbox_messages` DROP COLUMN `user_traqid`",
+			"DROP TABLE IF EXISTS `questionnaire_reminders`",
+			"ALTER TABLE `questionnaires` DROP COLUMN `disable_reminders`",
+		},
+	},
+	{
+		version: 10,
+		name:    "add questionnaire message templates",
+		up: []string{
+			"CREATE TABLE `questionnaire_message_templates` (" +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`kind` char(20) NOT NULL," +
+				"`template` text NOT NULL," +
+				"`updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"PRIMARY KEY (`questionnaire_id`,`kind`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS `questionnaire_message_templates`",
+		},
+	},
+	{
+		version: 11,
+		name:    "add questionnaire closing summaries",
+		up: []string{
+			"ALTER TABLE `questionnaires` ADD COLUMN `summarized_at` TIMESTAMP NULL DEFAULT NULL",
+			// 既に締め切られたアンケートの結果はまとめて送らない
+			"UPDATE `questionnaires` SET `summarized_at` = CURRENT_TIMESTAMP WHERE `is_closed` = true OR `res_time_limit` <= CURRENT_TIMESTAMP",
+		},
+		down: []string{
+			"ALTER TABLE `questionnaires` DROP COLUMN `summarized_at`",
+		},
+	},
+	{
+		version: 12,
+		name:    "create questionnaire webhooks",
+		up: []string{
+			"CREATE TABLE `questionnaire_webhooks` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`questionnaire_id` int(11) NOT NULL," +
+				"`url` varchar(2048) NOT NULL," +
+				"`secret` char(64) NOT NULL," +
+				"`events` varchar(255) NOT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`deleted_at` TIMESTAMP NULL DEFAULT NULL," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_questionnaire_webhooks_questionnaire_id (`questionnaire_id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+			"CREATE TABLE `webhook_deliveries` (" +
+				"`id` int(11) AUTO_INCREMENT NOT NULL," +
+				"`webhook_id` int(11) NOT NULL," +
+				"`event` char(30) NOT NULL," +
+				"`payload` mediumtext NOT NULL," +
+				"`status` char(20) NOT NULL," +
+				"`attempts` int(11) NOT NULL DEFAULT 0," +
+				"`next_attempt_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`last_error` text DEFAULT NULL," +
+				"`last_status_code` int(11) DEFAULT NULL," +
+				"`created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP," +
+				"`delivered_at` TIMESTAMP NULL DEFAULT NULL," +
+				"PRIMARY KEY (`id`)," +
+				"INDEX idx_webhook_deliveries_status_next_attempt_at (`status`,`next_attempt_at`)," +
+				"INDEX idx_webhook_deliveries_webhook_id (`webhook_id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci",
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS `webhook_deliveries`",
+			"DROP TABLE IF EXISTS `questionnaire_webhooks`",
+		},
+	},
+	{
+		// ETagに使うので、1秒以内の更新も区別できるようにする
+		version: 13,
+		name:    "use microsecond modified_at",
+		up: []string{
+			"ALTER TABLE `questionnaires` MODIFY `modified_at` TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+			"ALTER TABLE `respondents` MODIFY `modified_at` TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+		},
+		down: []string{
+			"ALTER TABLE `respondents` MODIFY `modified_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP",
+			"ALTER TABLE `questionnaires` MODIFY `modified_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP",
+		},
+	},
+	{
+		// 複数のプロセスが同じメッセージを送信しないように、送信前に送信中にして確保する
+		version: 14,
+		name:    "add outbox claims",
+		up: []string{
+			"ALTER TABLE `outbox_messages` ADD COLUMN `claimed_by` char(32) DEFAULT NULL",
+			"ALTER TABLE `outbox_messages` ADD COLUMN `lease_until` TIMESTAMP NULL DEFAULT NULL",
+		},
+		down: []string{
+			// 送信中のまま残らないように未送信に戻す
+			"UPDATE `outbox_messages` SET `status` = 'pending' WHERE `status` = 'sending'",
+			"ALTER TABLE `outbox_messages` DROP COLUMN `lease_until`",
+			"ALTER TABLE `outbox_messages` DROP COLUMN `claimed_by`",
+		},
+	},
+	{
+		// outboxのメッセージと同じように、送信前に送信中にして確保する
+		version: 15,
+		name:    "add webhook delivery claims",
+		up: []string{
+			"ALTER TABLE `webhook_deliveries` ADD COLUMN `claimed_by` char(32) DEFAULT NULL",
+			"ALTER TABLE `webhook_deliveries` ADD COLUMN `lease_until` TIMESTAMP NULL DEFAULT NULL",
+		},
+		down: []string{
+			// 送信中のまま残らないように未送信に戻す
+			"UPDATE `webhook_deliveries` SET `status` = 'pending' WHERE `status` = 'sending'",
+			"ALTER TABLE `webhook_deliveries` DROP COLUMN `lease_until`",
+			"ALTER TABLE `webhook_deliveries` DROP COLUMN `claimed_by`",
+		},
+	},
+}
+
+// baselineColumns 最初のスキーマの後に既存のテーブルへ追加された列
+// 最初のスキーマのままのDBではCREATE TABLE IF NOT EXISTSでは追加されないため、baselineで追加する
+var baselineColumns = []migrationColumn{
+	{table: "validations", column: "min_selections", definition: "int(11) DEFAULT NULL"},
+	{table: "validations", column: "max_selections", definition: "int(11) DEFAULT NULL"},
+}
+
+// migrationColumn マイグレーションで存在しなければ追加する列
+type migrationColumn struct {
+	table      string
+	column     string
+	definition string
+}
+
+// addMissingColumns columnsのうち存在しない列を追加する
+func addMissingColumns(db *gorm.DB, columns []migrationColumn) error {
+	for _, column := range columns {
+		if db.Migrator().HasColumn(column.table, column.column) {
+			continue
+		}
+
+		err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s", column.table, column.column, column.definition)).Error
+		if err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", column.table, column.column, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,341 @@
+package model
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+const migrationTable = "schema_migrations"
+
+const (
+	// migrationLockName 複数のプロセスが同時にマイグレーションしないためのロックの名前
+	migrationLockName = "anke-to-migrate"
+	// migrationLockTimeout ロックの取得を待つ秒数
+	migrationLockTimeout = 300
+)
+
+// SchemaMigrations schema_migrationsテーブルの構造体
+type SchemaMigrations struct {
+	Version   int       `gorm:"type:int(11);not null;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(100);size:100;not null"`
+	Checksum  string    `gorm:"type:char(64);size:64;not null"`
+	AppliedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// MigrationStatus マイグレーションの適用状況
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt null.Time
+	// ChecksumMismatch 適用後にマイグレーションの内容が変更されている
+	ChecksumMismatch bool
+	// Unknown DBには適用されているが、コードに存在しない
+	Unknown bool
+}
+
+// migration バージョン付きのスキーマの変更
+type migration struct {
+	// version 適用する順番 リリース後は変更や再利用をしてはいけない
+	version int
+	name    string
+	// up,down 適用、取り消しで順に実行するSQL checksumはこれらから計算する
+	up   []string
+	down []string
+	// upFunc upのSQLの後に実行する、SQLで書けないデータの移行
+	// checksumに含まれないため、リリース後は変更しない
+	upFunc func(db *gorm.DB) error
+	// irreversible 取り消すとデータが失われるため、downで取り消さない
+	irreversible bool
+}
+
+// checksum マイグレーションの内容のSHA-256
+func (m *migration) checksum() string {
+	h := sha256.New()
+	h.Write([]byte(strconv.Itoa(m.version)))
+	h.Write([]byte{0})
+	h.Write([]byte(m.name))
+	for _, statement := range m.up {
+		h.Write([]byte{0})
+		h.Write([]byte(statement))
+	}
+	h.Write([]byte{0, 0})
+	for _, statement := range m.down {
+		h.Write([]byte{0})
+		h.Write([]byte(statement))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// migrator マイグレーションを適用、取り消しする
+// Note: MySQLではALTER TABLEなどがトランザクション内でもコミットされるため、マイグレーションごとのトランザクションは使わない
+// 途中で失敗した場合は、schema_migrationsに記録されずに残るため手動で直す必要がある
+type migrator struct {
+	db         *gorm.DB
+	table      string
+	migrations []migration
+}
+
+func newMigrator(db *gorm.DB, table string, migrations []migration) *migrator {
+	sortedMigrations := make([]migration, len(migrations))
+	copy(sortedMigrations, migrations)
+	sort.Slice(sortedMigrations, func(i, j int) bool {
+		return sortedMigrations[i].version < sortedMigrations[j].version
+	})
+
+	return &migrator{
+		db:         db,
+		table:      table,
+		migrations: sortedMigrations,
+	}
+}
+
+// withLock ロックを取得してからfを実行する
+// GET_LOCKのロックはコネクションに紐づくため、1つのコネクションでロックを取得し、fにもそのコネクションだけを使うmigratorを渡す
+func (m *migrator) withLock(f func(locked *migrator) error) error {
+	return m.db.Connection(func(conn *gorm.DB) (err error) {
+		var acquired sql.NullInt64
+		err = conn.
+			Raw("SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).
+			Row().
+			Scan(&acquired)
+		if err != nil {
+			return fmt.Errorf("failed to get migration lock: %w", err)
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return fmt.Errorf("failed to get migration lock in %d seconds: %w", migrationLockTimeout, ErrMigrationLocked)
+		}
+
+		defer func() {
+			var released sql.NullInt64
+			releaseErr := conn.
+				Raw("SELECT RELEASE_LOCK(?)", migrationLockName).
+				Row().
+				Scan(&released)
+			if releaseErr != nil && err == nil {
+				err = fmt.Errorf("failed to release migration lock: %w", releaseErr)
+			}
+		}()
+
+		return f(&migrator{
+			db:         conn,
+			table:      m.table,
+			migrations: m.migrations,
+		})
+	})
+}
+
+// up 未適用のマイグレーションをすべて適用する
+func (m *migrator) up() error {
+	return m.withLock((*migrator).upLocked)
+}
+
+// upLocked ロックを取得した状態でupを実行する
+// 他のプロセスが適用し終えている場合があるので、適用済みのマイグレーションはロックを取得してから読む
+func (m *migrator) upLocked() error {
+	applied, err := m.getVerifiedMigrations()
+	if err != nil {
+		return err
+	}
+
+	latestVersion := 0
+	for version := range applied {
+		if version > latestVersion {
+			latestVersion = version
+		}
+	}
+
+	for i := range m.migrations {
+		mig := &m.migrations[i]
+		if _, ok := applied[mig.version]; ok {
+			continue
+		}
+		if mig.version < latestVersion {
+			return fmt.Errorf("migration(%d) is older than applied migration(%d): %w", mig.version, latestVersion, ErrMigrationOutOfOrder)
+		}
+
+		for _, statement := range mig.up {
+			err := m.db.Exec(statement).Error
+			if err != nil {
+				return fmt.Errorf("failed to apply migration(%d %s): %w", mig.version, mig.name, err)
+			}
+		}
+
+		if mig.upFunc != nil {
+			err := mig.upFunc(m.db)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration(%d %s): %w", mig.version, mig.name, err)
+			}
+		}
+
+		err := m.db.
+			Table(m.table).
+			Create(&SchemaMigrations{
+				Version:   mig.version,
+				Name:      mig.name,
+				Checksum:  mig.checksum(),
+				AppliedAt: time.Now(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to record migration(%d %s): %w", mig.version, mig.name, err)
+		}
+	}
+
+	return nil
+}
+
+// down 適用済みのマイグレーションを新しいものからn個取り消す
+func (m *migrator) down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("invalid number of migrations to roll back: %d", n)
+	}
+
+	return m.withLock(func(locked *migrator) error {
+		return locked.downLocked(n)
+	})
+}
+
+// downLocked ロックを取得した状態でdownを実行する
+func (m *migrator) downLocked(n int) error {
+	applied, err := m.getVerifiedMigrations()
+	if err != nil {
+		return err
+	}
+	if n > len(applied) {
+		return fmt.Errorf("cannot roll back %d migrations: only %d migrations are applied", n, len(applied))
+	}
+
+	// 取り消せないマイグレーションが含まれる場合は、途中まで取り消さないように先に確認する
+	rollbacks := make([]*migration, 0, n)
+	for i := len(m.migrations) - 1; i >= 0 && len(rollbacks) < n; i-- {
+		mig := &m.migrations[i]
+		if _, ok := applied[mig.version]; !ok {
+			continue
+		}
+		if mig.irreversible {
+			return fmt.Errorf("migration(%d %s) cannot be rolled back: %w", mig.version, mig.name, ErrIrreversibleMigration)
+		}
+
+		rollbacks = append(rollbacks, mig)
+	}
+
+	for _, mig := range rollbacks {
+		for _, statement := range mig.down {
+			err := m.db.Exec(statement).Error
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration(%d %s): %w", mig.version, mig.name, err)
+			}
+		}
+
+		err := m.db.
+			Table(m.table).
+			Where("version = ?", mig.version).
+			Delete(&SchemaMigrations{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete migration record(%d %s): %w", mig.version, mig.name, err)
+		}
+	}
+
+	return nil
+}
+
+// status マイグレーションごとの適用状況をバージョン順に返す
+func (m *migrator) status() ([]MigrationStatus, error) {
+	applied, err := m.getAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for i := range m.migrations {
+		mig := &m.migrations[i]
+		status := MigrationStatus{
+			Version: mig.version,
+			Name:    mig.name,
+		}
+
+		record, ok := applied[mig.version]
+		if ok {
+			status.Applied = true
+			status.AppliedAt = null.TimeFrom(record.AppliedAt)
+			status.ChecksumMismatch = record.Checksum != mig.checksum()
+			delete(applied, mig.version)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: null.TimeFrom(record.AppliedAt),
+			Unknown:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// getAppliedMigrations schema_migrationsに記録された適用済みのマイグレーションを取得する
+func (m *migrator) getAppliedMigrations() (map[int]SchemaMigrations, error) {
+	err := m.db.Table(m.table).AutoMigrate(&SchemaMigrations{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", m.table, err)
+	}
+
+	records := []SchemaMigrations{}
+	err = m.db.
+		Table(m.table).
+		Order("version").
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	applied := make(map[int]SchemaMigrations, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// getVerifiedMigrations 適用済みのマイグレーションを取得し、コードと一致しているか確認する
+func (m *migrator) getVerifiedMigrations() (map[int]SchemaMigrations, error) {
+	applied, err := m.getAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	migrationMap := make(map[int]*migration, len(m.migrations))
+	for i := range m.migrations {
+		migrationMap[m.migrations[i].version] = &m.migrations[i]
+	}
+
+	for version, record := range applied {
+		mig, ok := migrationMap[version]
+		if !ok {
+			return nil, fmt.Errorf("migration(%d %s) is applied but not found: %w", version, record.Name, ErrUnknownMigration)
+		}
+		if record.Checksum != mig.checksum() {
+			return nil, fmt.Errorf("migration(%d %s) has been changed after applied: %w", version, mig.name, ErrMigrationChecksumMismatch)
+		}
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,288 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const migratorTestTable = "schema_migrations_test"
+
+var migratorTestMigrations = []migration{
+	{
+		version: 1,
+		name:    "create migrator_test_a",
+		up: []string{
+			"CREATE TABLE `migrator_test_a` (`id` int(11) NOT NULL, PRIMARY KEY (`id`))",
+		},
+		down: []string{
+			"DROP TABLE `migrator_test_a`",
+		},
+	},
+	{
+		version: 2,
+		name:    "add migrator_test_a.name",
+		up: []string{
+			"ALTER TABLE `migrator_test_a` ADD COLUMN `name` varchar(30) DEFAULT NULL",
+		},
+		down: []string{
+			"ALTER TABLE `migrator_test_a` DROP COLUMN `name`",
+		},
+	},
+	{
+		version: 3,
+		name:    "create migrator_test_b",
+		up: []string{
+			"CREATE TABLE `migrator_test_b` (`id` int(11) NOT NULL, PRIMARY KEY (`id`))",
+		},
+		down: []string{
+			"DROP TABLE `migrator_test_b`",
+		},
+	},
+}
+
+func TestMigrationChecksum(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	mig := migratorTestMigrations[0]
+	assertion.Equal(mig.checksum(), mig.checksum(), "same migration")
+	assertion.Len(mig.checksum(), 64, "length")
+
+	changedUp := mig
+	changedUp.up = []string{"CREATE TABLE `migrator_test_a` (`id` bigint NOT NULL, PRIMARY KEY (`id`))"}
+	assertion.NotEqual(mig.checksum(), changedUp.checksum(), "up changed")
+
+	changedDown := mig
+	changedDown.down = []string{"DROP TABLE IF EXISTS `migrator_test_a`"}
+	assertion.NotEqual(mig.checksum(), changedDown.checksum(), "down changed")
+
+	// upとdownの境界が変わった場合も別のchecksumになる
+	moved := mig
+	moved.up = append(append([]string{}, mig.up...), mig.down...)
+	moved.down = []string{}
+	assertion.NotEqual(mig.checksum(), moved.checksum(), "statement moved")
+}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS `migrator_test_b`")
+		db.Exec("DROP TABLE IF EXISTS `migrator_test_a`")
+		db.Exec("DROP TABLE IF EXISTS `" + migratorTestTable + "`")
+	})
+
+	m := newMigrator(db, migratorTestTable, migratorTestMigrations)
+
+	statuses, err := m.status()
+	require.NoError(t, err)
+	assertion.Len(statuses, 3, "status before up")
+	for _, status := range statuses {
+		assertion.False(status.Applied, "status before up")
+	}
+
+	err = m.up()
+	require.NoError(t, err)
+	assertion.True(db.Migrator().HasTable("migrator_test_a"), "up")
+	assertion.True(db.Migrator().HasTable("migrator_test_b"), "up")
+
+	statuses, err = m.status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assertion.True(status.Applied, "status after up")
+		assertion.True(status.AppliedAt.Valid, "status after up")
+		assertion.False(status.ChecksumMismatch, "status after up")
+	}
+
+	// 適用済みのときは何もしない
+	err = m.up()
+	assertion.NoError(err, "up twice")
+
+	err = m.down(2)
+	require.NoError(t, err)
+	assertion.False(db.Migrator().HasTable("migrator_test_b"), "down")
+	assertion.Error(db.Exec("SELECT `name` FROM `migrator_test_a`").Error, "down")
+	assertion.True(db.Migrator().HasTable("migrator_test_a"), "down")
+
+	statuses, err = m.status()
+	require.NoError(t, err)
+	assertion.True(statuses[0].Applied, "status after down")
+	assertion.False(statuses[1].Applied, "status after down")
+	assertion.False(statuses[2].Applied, "status after down")
+
+	err = m.down(2)
+	assertion.Error(err, "down more than applied")
+	assertion.True(db.Migrator().HasTable("migrator_test_a"), "down more than applied")
+
+	err = m.down(0)
+	assertion.Error(err, "down 0")
+
+	// 適用済みのマイグレーションが変更されていたら止める
+	changedMigrations := make([]migration, len(migratorTestMigrations))
+	copy(changedMigrations, migratorTestMigrations)
+	changedMigrations[0].up = []string{"CREATE TABLE `migrator_test_a` (`id` bigint NOT NULL, PRIMARY KEY (`id`))"}
+	changedMigrator := newMigrator(db, migratorTestTable, changedMigrations)
+
+	err = changedMigrator.up()
+	if !errors.Is(err, ErrMigrationChecksumMismatch) {
+		t.Errorf("invalid error: expected: %+v, actual: %+v", ErrMigrationChecksumMismatch, err)
+	}
+	assertion.False(db.Migrator().HasTable("migrator_test_b"), "checksum mismatch")
+
+	statuses, err = changedMigrator.status()
+	require.NoError(t, err)
+	assertion.True(statuses[0].ChecksumMismatch, "status checksum mismatch")
+
+	// 適用済みのマイグレーションがコードにない場合も止める
+	unknownMigrator := newMigrator(db, migratorTestTable, migratorTestMigrations[1:])
+
+	err = unknownMigrator.up()
+	if !errors.Is(err, ErrUnknownMigration) {
+		t.Errorf("invalid error: expected: %+v, actual: %+v", ErrUnknownMigration, err)
+	}
+
+	statuses, err = unknownMigrator.status()
+	require.NoError(t, err)
+	assertion.Len(statuses, 3, "status unknown")
+	assertion.True(statuses[0].Unknown, "status unknown")
+
+	err = m.down(1)
+	require.NoError(t, err)
+	assertion.False(db.Migrator().HasTable("migrator_test_a"), "down all")
+}
+
+func TestMigratorIrreversible(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	const table = "schema_migrations_irreversible_test"
+	irreversibleMigrations := []migration{
+		{
+			version: 1,
+			name:    "create migrator_test_irreversible",
+			up: []string{
+				"CREATE TABLE `migrator_test_irreversible` (`id` int(11) NOT NULL, PRIMARY KEY (`id`))",
+			},
+			irreversible: true,
+		},
+		{
+			version: 2,
+			name:    "add migrator_test_irreversible.name",
+			up: []string{
+				"ALTER TABLE `migrator_test_irreversible` ADD COLUMN `name` varchar(30) DEFAULT NULL",
+			},
+			down: []string{
+				"ALTER TABLE `migrator_test_irreversible` DROP COLUMN `name`",
+			},
+		},
+	}
+	m := newMigrator(db, table, irreversibleMigrations)
+
+	err := m.up()
+	require.NoError(t, err)
+
+	// 取り消せないマイグレーションを含む場合は、新しいものも取り消さない
+	err = m.down(2)
+	if !errors.Is(err, ErrIrreversibleMigration) {
+		t.Errorf("invalid error: expected: %+v, actual: %+v", ErrIrreversibleMigration, err)
+	}
+	assertion.True(db.Migrator().HasColumn("migrator_test_irreversible", "name"), "not rolled back")
+
+	err = m.down(1)
+	require.NoError(t, err)
+	assertion.False(db.Migrator().HasColumn("migrator_test_irreversible", "name"), "rolled back")
+
+	err = m.down(1)
+	assertion.ErrorIs(err, ErrIrreversibleMigration, "irreversible")
+	assertion.True(db.Migrator().HasTable("migrator_test_irreversible"), "not dropped")
+}
+
+func TestMigratorConcurrentUp(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	const table = "schema_migrations_concurrent_test"
+	concurrentMigrations := []migration{
+		{
+			version: 1,
+			name:    "create migrator_test_concurrent",
+			up: []string{
+				"CREATE TABLE `migrator_test_concurrent` (`id` int(11) NOT NULL, PRIMARY KEY (`id`))",
+			},
+			down: []string{
+				"DROP TABLE `migrator_test_concurrent`",
+			},
+		},
+	}
+
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS `migrator_test_concurrent`")
+		db.Exec("DROP TABLE IF EXISTS `" + table + "`")
+	})
+
+	// ロックがないと、両方がCREATE TABLEを実行してどちらかが失敗する
+	const n = 2
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- newMigrator(db, table, concurrentMigrations).up()
+		}()
+	}
+	for i := 0; i < n; i++ {
+		assertion.NoError(<-errs, "concurrent up")
+	}
+
+	var count int64
+	err := db.Table(table).Count(&count).Error
+	require.NoError(t, err)
+	assertion.Equal(int64(1), count, "applied once")
+}
+
+func TestAddMissingColumns(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	err := db.Exec("CREATE TABLE `migrator_test_columns` (`id` int(11) NOT NULL, `name` varchar(30) DEFAULT NULL, PRIMARY KEY (`id`))").Error
+	require.NoError(t, err)
+
+	columns := []migrationColumn{
+		{table: "migrator_test_columns", column: "name", definition: "varchar(30) DEFAULT NULL"},
+		{table: "migrator_test_columns", column: "min_value", definition: "int(11) DEFAULT NULL"},
+	}
+
+	err = addMissingColumns(db, columns)
+	require.NoError(t, err)
+	assertion.True(db.Migrator().HasColumn("migrator_test_columns", "min_value"), "added")
+
+	// 既に列がある場合は何もしない
+	err = addMissingColumns(db, columns)
+	assertion.NoError(err, "twice")
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	// TestMainでMigrateしているため、すべて適用済みになっている
+	statuses, err := GetMigrationStatuses()
+	require.NoError(t, err)
+	assertion.Len(statuses, len(migrations))
+	for _, status := range statuses {
+		assertion.True(status.Applied, status.Name)
+		assertion.False(status.ChecksumMismatch, status.Name)
+		assertion.False(status.Unknown, status.Name)
+	}
+
+	err = Migrate()
+	assertion.NoError(err, "migrate twice")
+}
@@ -0,0 +1,20 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// IOutbox OutboxのRepository
+type IOutbox interface {
+	InsertOutboxMessage(ctx context.Context, message string) (int, error)
+	InsertOutboxDirectMessage(ctx context.Context, userID string, message string) (int, error)
+	ClaimOutboxMessagesToDispatch(ctx context.Context, limit int, leaseUntil time.Time) ([]OutboxMessages, error)
+	GetOutboxMessages(ctx context.Context, status string, limit int) ([]OutboxMessages, error)
+	UpdateOutboxMessageDelivered(ctx context.Context, messageID int, claimedBy string) error
+	UpdateOutboxMessageRetry(ctx context.Context, messageID int, claimedBy string, lastError string, nextAttemptAt time.Time) error
+	UpdateOutboxMessageDead(ctx context.Context, messageID int, claimedBy string, lastError string) error
+	ReplayOutboxMessage(ctx context.Context, messageID int) error
+}
@@ -0,0 +1,297 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+// outboxのメッセージの状態
+const (
+	// OutboxStatusPending 未送信で、next_attempt_atを過ぎたら送信する
+	OutboxStatusPending = "pending"
+	// OutboxStatusSending claimed_byが送信中で、lease_untilを過ぎても送信済みにならなければ再送する
+	OutboxStatusSending = "sending"
+	// OutboxStatusDelivered 送信済み
+	OutboxStatusDelivered = "delivered"
+	// OutboxStatusDead 再送の上限に達したため送信を諦めた
+	OutboxStatusDead = "dead"
+)
+
+// Outbox OutboxRepositoryの実装
+type Outbox struct{}
+
+// NewOutbox Outboxのコンストラクター
+func NewOutbox() *Outbox {
+	return new(Outbox)
+}
+
+// OutboxMessages outbox_messagesテーブルの構造体
+// traQに送信するメッセージで、アンケートの変更と同じトランザクションで追加し、後から送信する
+// UserTraqidがnullの場合はWebhookでチャンネルに、そうでない場合はそのユーザーにDMで送信する
+type OutboxMessages struct {
+	ID            int         `json:"id"              gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	UserTraqid    null.String `json:"user_traqid"     gorm:"type:varchar(32);size:32;default:NULL"`
+	Message       string      `json:"message"         gorm:"type:text;not null"`
+	Status        string      `json:"status"          gorm:"type:char(20);size:20;not null"`
+	Attempts      int         `json:"attempts"        gorm:"type:int(11);not null;default:0"`
+	NextAttemptAt time.Time   `json:"next_attempt_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	LastError     null.String `json:"last_error"      gorm:"type:text;default:NULL"`
+	ClaimedBy     null.String `json:"claimed_by"      gorm:"type:char(32);size:32;default:NULL"`
+	LeaseUntil    null.Time   `json:"lease_until"     gorm:"type:TIMESTAMP NULL;default:NULL"`
+	CreatedAt     time.Time   `json:"created_at"      gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeliveredAt   null.Time   `json:"delivered_at"    gorm:"type:TIMESTAMP NULL;default:NULL"`
+}
+
+// InsertOutboxMessage 送信するメッセージの追加
+// 呼び出し元のトランザクションがコミットされたときだけ送信される
+func (*Outbox) InsertOutboxMessage(ctx context.Context, message string) (int, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	now := time.Now()
+	outboxMessage := OutboxMessages{
+		Message:       message,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	err = db.Create(&outboxMessage).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert outbox message: %w", err)
+	}
+
+	return outboxMessage.ID, nil
+}
+
+// InsertOutboxDirectMessage ユーザーにDMで送信するメッセージの追加
+// 呼び出し元のトランザクションがコミットされたときだけ送信される
+func (*Outbox) InsertOutboxDirectMessage(ctx context.Context, userID string, message string) (int, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	now := time.Now()
+	outboxMessage := OutboxMessages{
+		UserTraqid:    null.StringFrom(userID),
+		Message:       message,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	err = db.Create(&outboxMessage).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert outbox direct message: %w", err)
+	}
+
+	return outboxMessage.ID, nil
+}
+
+// ClaimOutboxMessagesToDispatch 送信するメッセージを送信中にして取得
+// 未送信でnext_attempt_atを過ぎたメッセージと、lease_untilを過ぎても送信済みにならなかったメッセージを、
+// next_attempt_atが古い順に最大limit件、leaseUntilまで送信中にして返す
+// 送信中にする条件付きのUPDATEで確保するので、複数のプロセスから呼んでも同じメッセージは1つのプロセスにしか返らない
+func (*Outbox) ClaimOutboxMessagesToDispatch(ctx context.Context, limit int, leaseUntil time.Time) ([]OutboxMessages, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	claimedBy, err := generateClaimToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate claim token: %w", err)
+	}
+
+	now := time.Now()
+	err = db.Exec(
+		"UPDATE outbox_messages SET status = ?, claimed_by = ?, lease_until = ? "+
+			"WHERE (status = ? AND next_attempt_at <= ?) OR (status = ? AND lease_until <= ?) "+
+			"ORDER BY next_attempt_at, id LIMIT ?",
+		OutboxStatusSending, claimedBy, leaseUntil,
+		OutboxStatusPending, now, OutboxStatusSending, now,
+		limit,
+	).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	outboxMessages := []OutboxMessages{}
+	err = db.
+		Where("status = ? AND claimed_by = ?", OutboxStatusSending, claimedBy).
+		Order("next_attempt_at, id").
+		Find(&outboxMessages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed outbox messages: %w", err)
+	}
+
+	return outboxMessages, nil
+}
+
+// GetOutboxMessages メッセージの一覧の取得
+// statusが空文字列の場合は全ての状態のメッセージを、新しい順に最大limit件返す
+func (*Outbox) GetOutboxMessages(ctx context.Context, status string, limit int) ([]OutboxMessages, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	switch status {
+	case "":
+	case OutboxStatusPending, OutboxStatusSending, OutboxStatusDelivered, OutboxStatusDead:
+		db = db.Where("status = ?", status)
+	default:
+		return nil, ErrInvalidOutboxStatus
+	}
+
+	outboxMessages := []OutboxMessages{}
+	err = db.
+		Order("id DESC").
+		Limit(limit).
+		Find(&outboxMessages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox messages: %w", err)
+	}
+
+	return outboxMessages, nil
+}
+
+// UpdateOutboxMessageDelivered claimedByが送信中のメッセージを送信済みにする
+func (*Outbox) UpdateOutboxMessageDelivered(ctx context.Context, messageID int, claimedBy string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&OutboxMessages{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", messageID, OutboxStatusSending, claimedBy).
+		UpdateColumns(map[string]interface{}{
+			"status":       OutboxStatusDelivered,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"delivered_at": time.Now(),
+			"lease_until":  gorm.Expr("NULL"),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
+// UpdateOutboxMessageRetry claimedByが送信に失敗したメッセージをnextAttemptAtに再送するようにする
+func (*Outbox) UpdateOutboxMessageRetry(ctx context.Context, messageID int, claimedBy string, lastError string, nextAttemptAt time.Time) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&OutboxMessages{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", messageID, OutboxStatusSending, claimedBy).
+		UpdateColumns(map[string]interface{}{
+			"status":          OutboxStatusPending,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+			"lease_until":     gorm.Expr("NULL"),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
+// UpdateOutboxMessageDead claimedByが送信に失敗したメッセージを再送しないようにする
+func (*Outbox) UpdateOutboxMessageDead(ctx context.Context, messageID int, claimedBy string, lastError string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&OutboxMessages{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", messageID, OutboxStatusSending, claimedBy).
+		UpdateColumns(map[string]interface{}{
+			"status":      OutboxStatusDead,
+			"attempts":    gorm.Expr("attempts + 1"),
+			"last_error":  lastError,
+			"lease_until": gorm.Expr("NULL"),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
+// ReplayOutboxMessage 送信を諦めたメッセージを再び送信するようにする
+// 再送の回数は0に戻す
+func (*Outbox) ReplayOutboxMessage(ctx context.Context, messageID int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&OutboxMessages{}).
+		Where("id = ? AND status = ?", messageID, OutboxStatusDead).
+		UpdateColumns(map[string]interface{}{
+			"status":          OutboxStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to replay outbox message: %w", err)
+	}
+	if result.RowsAffected != 0 {
+		return nil
+	}
+
+	err = db.
+		Select("id").
+		Where("id = ?", messageID).
+		Take(&OutboxMessages{}).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRecordNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get outbox message: %w", err)
+	}
+
+	return ErrNoRecordUpdated
+}
+
+// generateClaimToken 送信中にしたプロセスを区別するための値を作る
+// 確保するごとに作るので、同じプロセスでも確保したときごとに異なる
+func generateClaimToken() (string, error) {
+	token := make([]byte, 16)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return hex.EncodeToString(token), nil
+}
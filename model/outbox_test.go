@@ -0,0 +1,317 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestInsertOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	messageID, err := outboxImpl.InsertOutboxMessage(ctx, "outbox message")
+	require.NoError(t, err)
+
+	var outboxMessage OutboxMessages
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ?", messageID).
+		Take(&outboxMessage).Error
+	require.NoError(t, err)
+
+	assertion.Equal("outbox message", outboxMessage.Message, "message")
+	assertion.Equal(OutboxStatusPending, outboxMessage.Status, "status")
+	assertion.Equal(0, outboxMessage.Attempts, "attempts")
+	assertion.WithinDuration(time.Now(), outboxMessage.NextAttemptAt, 2*time.Second, "next_attempt_at")
+	assertion.False(outboxMessage.DeliveredAt.Valid, "delivered_at")
+}
+
+func TestInsertOutboxDirectMessage(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	messageID, err := outboxImpl.InsertOutboxDirectMessage(ctx, "mazrean", "direct message")
+	require.NoError(t, err)
+
+	var outboxMessage OutboxMessages
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ?", messageID).
+		Take(&outboxMessage).Error
+	require.NoError(t, err)
+
+	assertion.Equal("mazrean", outboxMessage.UserTraqid.ValueOrZero(), "user_traqid")
+	assertion.Equal("direct message", outboxMessage.Message, "message")
+	assertion.Equal(OutboxStatusPending, outboxMessage.Status, "status")
+}
+
+// TestClaimOutboxMessagesToDispatch 送信時刻を過ぎた全てのメッセージを送信中にするので、
+// 他のテストで追加したメッセージの状態を変えないように並列には実行しない
+func TestClaimOutboxMessagesToDispatch(t *testing.T) {
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	dueMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "due message")
+	require.NoError(t, err)
+
+	retryMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "retry message")
+	require.NoError(t, err)
+	err = outboxImpl.UpdateOutboxMessageRetry(ctx, retryMessageID, claimOutboxMessage(t, retryMessageID, time.Now().Add(time.Minute)), "error", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	deliveredMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "delivered message")
+	require.NoError(t, err)
+	err = outboxImpl.UpdateOutboxMessageDelivered(ctx, deliveredMessageID, claimOutboxMessage(t, deliveredMessageID, time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+
+	deadMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "dead message")
+	require.NoError(t, err)
+	err = outboxImpl.UpdateOutboxMessageDead(ctx, deadMessageID, claimOutboxMessage(t, deadMessageID, time.Now().Add(time.Minute)), "error")
+	require.NoError(t, err)
+
+	// 他のプロセスが送信中のメッセージ
+	sendingMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "sending message")
+	require.NoError(t, err)
+	claimOutboxMessage(t, sendingMessageID, time.Now().Add(time.Minute))
+
+	// 送信中のままプロセスが終了したメッセージ
+	expiredMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "expired message")
+	require.NoError(t, err)
+	expiredClaimedBy := claimOutboxMessage(t, expiredMessageID, time.Now().Add(-time.Minute))
+
+	leaseUntil := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	outboxMessages, err := outboxImpl.ClaimOutboxMessagesToDispatch(ctx, 1000, leaseUntil)
+	require.NoError(t, err)
+
+	messageIDs := make([]int, 0, len(outboxMessages))
+	for _, outboxMessage := range outboxMessages {
+		assertion.Equal(OutboxStatusSending, outboxMessage.Status, "status")
+		assertion.Equal(outboxMessages[0].ClaimedBy, outboxMessage.ClaimedBy, "claimed_by")
+		assertion.WithinDuration(leaseUntil, outboxMessage.LeaseUntil.ValueOrZero(), time.Second, "lease_until")
+		messageIDs = append(messageIDs, outboxMessage.ID)
+	}
+
+	assertion.Contains(messageIDs, dueMessageID, "due message")
+	assertion.NotContains(messageIDs, retryMessageID, "retry message")
+	assertion.NotContains(messageIDs, deliveredMessageID, "delivered message")
+	assertion.NotContains(messageIDs, deadMessageID, "dead message")
+	assertion.NotContains(messageIDs, sendingMessageID, "sending message")
+	assertion.Contains(messageIDs, expiredMessageID, "expired message")
+
+	// 送信中にしたメッセージは他から取得できない
+	outboxMessages, err = outboxImpl.ClaimOutboxMessagesToDispatch(ctx, 1000, leaseUntil)
+	require.NoError(t, err)
+	for _, outboxMessage := range outboxMessages {
+		assertion.NotEqual(dueMessageID, outboxMessage.ID, "claimed due message")
+		assertion.NotEqual(expiredMessageID, outboxMessage.ID, "claimed expired message")
+	}
+
+	// 送信中でなくなったプロセスは送信済みにできない
+	err = outboxImpl.UpdateOutboxMessageDelivered(ctx, expiredMessageID, expiredClaimedBy)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "deliver expired message")
+}
+
+func TestGetOutboxMessages(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	deadMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "dead message")
+	require.NoError(t, err)
+	err = outboxImpl.UpdateOutboxMessageDead(ctx, deadMessageID, claimOutboxMessage(t, deadMessageID, time.Now().Add(time.Minute)), "error")
+	require.NoError(t, err)
+
+	type test struct {
+		description string
+		status      string
+		isErr       bool
+		err         error
+	}
+
+	testCases := []test{
+		{
+			description: "状態を指定しないので全ての状態を返す",
+			status:      "",
+		},
+		{
+			description: "状態を指定したのでその状態だけ返す",
+			status:      OutboxStatusDead,
+		},
+		{
+			description: "存在しない状態なのでエラー",
+			status:      "unknown",
+			isErr:       true,
+			err:         ErrInvalidOutboxStatus,
+		},
+	}
+
+	for _, testCase := range testCases {
+		outboxMessages, err := outboxImpl.GetOutboxMessages(ctx, testCase.status, 1000)
+		if testCase.isErr {
+			assertion.ErrorIs(err, testCase.err, testCase.description, "error")
+			continue
+		}
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		messageIDs := make([]int, 0, len(outboxMessages))
+		for _, outboxMessage := range outboxMessages {
+			if testCase.status != "" {
+				assertion.Equal(testCase.status, outboxMessage.Status, testCase.description, "status")
+			}
+			messageIDs = append(messageIDs, outboxMessage.ID)
+		}
+		assertion.Contains(messageIDs, deadMessageID, testCase.description, "dead message")
+	}
+}
+
+func TestUpdateOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	messageID, err := outboxImpl.InsertOutboxMessage(ctx, "outbox message")
+	require.NoError(t, err)
+
+	// 送信中でないメッセージは更新しない
+	nextAttemptAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	err = outboxImpl.UpdateOutboxMessageRetry(ctx, messageID, "", "retry error", nextAttemptAt)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "retry pending message")
+
+	claimedBy := claimOutboxMessage(t, messageID, time.Now().Add(time.Minute))
+
+	// 他のプロセスが送信中のメッセージは更新しない
+	err = outboxImpl.UpdateOutboxMessageRetry(ctx, messageID, "other", "retry error", nextAttemptAt)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "retry message claimed by other")
+
+	err = outboxImpl.UpdateOutboxMessageRetry(ctx, messageID, claimedBy, "retry error", nextAttemptAt)
+	require.NoError(t, err)
+
+	var outboxMessage OutboxMessages
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ?", messageID).
+		Take(&outboxMessage).Error
+	require.NoError(t, err)
+	assertion.Equal(OutboxStatusPending, outboxMessage.Status, "retry status")
+	assertion.Equal(1, outboxMessage.Attempts, "retry attempts")
+	assertion.Equal("retry error", outboxMessage.LastError.ValueOrZero(), "retry last_error")
+	assertion.WithinDuration(nextAttemptAt, outboxMessage.NextAttemptAt, time.Second, "retry next_attempt_at")
+	assertion.False(outboxMessage.LeaseUntil.Valid, "retry lease_until")
+
+	claimedBy = claimOutboxMessage(t, messageID, time.Now().Add(time.Minute))
+	err = outboxImpl.UpdateOutboxMessageDelivered(ctx, messageID, claimedBy)
+	require.NoError(t, err)
+
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ?", messageID).
+		Take(&outboxMessage).Error
+	require.NoError(t, err)
+	assertion.Equal(OutboxStatusDelivered, outboxMessage.Status, "delivered status")
+	assertion.Equal(2, outboxMessage.Attempts, "delivered attempts")
+	assertion.True(outboxMessage.DeliveredAt.Valid, "delivered delivered_at")
+
+	// 送信済みのメッセージは更新しない
+	err = outboxImpl.UpdateOutboxMessageRetry(ctx, messageID, claimedBy, "retry error", nextAttemptAt)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "retry delivered message")
+	err = outboxImpl.UpdateOutboxMessageDead(ctx, messageID, claimedBy, "dead error")
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "dead delivered message")
+	err = outboxImpl.UpdateOutboxMessageDelivered(ctx, messageID, claimedBy)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "deliver delivered message")
+}
+
+func TestReplayOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	deadMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "dead message")
+	require.NoError(t, err)
+	err = outboxImpl.UpdateOutboxMessageDead(ctx, deadMessageID, claimOutboxMessage(t, deadMessageID, time.Now().Add(time.Minute)), "error")
+	require.NoError(t, err)
+
+	pendingMessageID, err := outboxImpl.InsertOutboxMessage(ctx, "pending message")
+	require.NoError(t, err)
+
+	type test struct {
+		description string
+		messageID   int
+		isErr       bool
+		err         error
+	}
+
+	testCases := []test{
+		{
+			description: "送信を諦めたメッセージなのでエラーなし",
+			messageID:   deadMessageID,
+		},
+		{
+			description: "送信を諦めていないメッセージなのでErrNoRecordUpdated",
+			messageID:   pendingMessageID,
+			isErr:       true,
+			err:         ErrNoRecordUpdated,
+		},
+		{
+			description: "存在しないメッセージなのでErrRecordNotFound",
+			messageID:   -1,
+			isErr:       true,
+			err:         ErrRecordNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := outboxImpl.ReplayOutboxMessage(ctx, testCase.messageID)
+		if testCase.isErr {
+			assertion.ErrorIs(err, testCase.err, testCase.description, "error")
+			continue
+		}
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		var outboxMessage OutboxMessages
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", testCase.messageID).
+			Take(&outboxMessage).Error
+		require.NoError(t, err)
+		assertion.Equal(OutboxStatusPending, outboxMessage.Status, testCase.description, "status")
+		assertion.Equal(0, outboxMessage.Attempts, testCase.description, "attempts")
+	}
+}
+
+// claimOutboxMessage メッセージをleaseUntilまで送信中にして、送信中にしたときの値を返す
+// ClaimOutboxMessagesToDispatchは他のテストのメッセージも送信中にするので、指定したメッセージだけ送信中にする
+func claimOutboxMessage(t *testing.T, messageID int, leaseUntil time.Time) string {
+	t.Helper()
+
+	claimedBy, err := generateClaimToken()
+	require.NoError(t, err)
+
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&OutboxMessages{}).
+		Where("id = ?", messageID).
+		UpdateColumns(map[string]interface{}{
+			"status":      OutboxStatusSending,
+			"claimed_by":  claimedBy,
+			"lease_until": leaseUntil,
+		}).Error
+	require.NoError(t, err)
+
+	return claimedBy
+}
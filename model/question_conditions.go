@@ -0,0 +1,13 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import "context"
+
+// IQuestionCondition QuestionConditionのRepository
+type IQuestionCondition interface {
+	InsertQuestionConditions(ctx context.Context, questionID int, conditions []QuestionConditions) error
+	UpdateQuestionConditions(ctx context.Context, questionID int, conditions []QuestionConditions) error
+	DeleteQuestionConditions(ctx context.Context, questionID int) error
+	GetQuestionConditions(ctx context.Context, questionIDs []int) ([]QuestionConditions, error)
+}
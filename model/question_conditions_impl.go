@@ -0,0 +1,105 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// QuestionCondition QuestionConditionRepositoryの実装
+type QuestionCondition struct{}
+
+// NewQuestionCondition QuestionConditionのコンストラクター
+func NewQuestionCondition() *QuestionCondition {
+	return new(QuestionCondition)
+}
+
+// QuestionConditions question_conditionsテーブルの構造体
+// 質問(QuestionID)への回答が条件を満たしたとき、その質問のページからSkipToPageの手前のページまでを飛ばす
+type QuestionConditions struct {
+	ID            int         `json:"-"              gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	QuestionID    int         `json:"-"              gorm:"type:int(11);not null;index"`
+	ConditionType string      `json:"condition_type" gorm:"type:char(20);size:20;not null" validate:"required,oneof=option range answered unanswered"`
+	OptionBody    null.String `json:"option"         gorm:"type:text;default:NULL"`
+	MinValue      null.Float  `json:"min_value"      gorm:"type:double;default:NULL"`
+	MaxValue      null.Float  `json:"max_value"      gorm:"type:double;default:NULL"`
+	SkipToPage    int         `json:"skip_to_page"   gorm:"type:int(11);not null" validate:"min=1"`
+}
+
+// InsertQuestionConditions 質問の分岐条件の追加
+func (*QuestionCondition) InsertQuestionConditions(ctx context.Context, questionID int, conditions []QuestionConditions) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	dbConditions := make([]QuestionConditions, 0, len(conditions))
+	for _, condition := range conditions {
+		condition.ID = 0
+		condition.QuestionID = questionID
+		dbConditions = append(dbConditions, condition)
+	}
+
+	err = db.Create(&dbConditions).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert question conditions: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateQuestionConditions 質問の分岐条件を与えられたものに置き換える
+func (qc *QuestionCondition) UpdateQuestionConditions(ctx context.Context, questionID int, conditions []QuestionConditions) error {
+	err := qc.DeleteQuestionConditions(ctx, questionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete question conditions: %w", err)
+	}
+
+	err = qc.InsertQuestionConditions(ctx, questionID, conditions)
+	if err != nil {
+		return fmt.Errorf("failed to insert question conditions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteQuestionConditions 質問の分岐条件の削除
+func (*QuestionCondition) DeleteQuestionConditions(ctx context.Context, questionID int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	err = db.
+		Where("question_id = ?", questionID).
+		Delete(&QuestionConditions{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete question conditions: %w", err)
+	}
+
+	return nil
+}
+
+// GetQuestionConditions 質問の分岐条件の取得
+func (*QuestionCondition) GetQuestionConditions(ctx context.Context, questionIDs []int) ([]QuestionConditions, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	conditions := []QuestionConditions{}
+	err = db.
+		Where("question_id IN (?)", questionIDs).
+		Order("question_id, id").
+		Find(&conditions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question conditions: %w", err)
+	}
+
+	return conditions, nil
+}
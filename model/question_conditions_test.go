@@ -0,0 +1,175 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func setupQuestionConditionTest(t *testing.T) int {
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
+	require.NoError(t, err)
+
+	questionID, err := questionImpl.InsertQuestion(ctx, questionnaireID, 1, 1, "MultipleChoice", "発表しますか？", true)
+	require.NoError(t, err)
+
+	return questionID
+}
+
+func TestInsertQuestionConditions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	type test struct {
+		description string
+		conditions  []QuestionConditions
+	}
+
+	testCases := []test{
+		{
+			description: "選択肢の分岐条件を追加できる",
+			conditions: []QuestionConditions{
+				{ConditionType: "option", OptionBody: null.StringFrom("はい"), SkipToPage: 3},
+			},
+		},
+		{
+			description: "複数の分岐条件を追加できる",
+			conditions: []QuestionConditions{
+				{ConditionType: "range", MinValue: null.FloatFrom(0), MaxValue: null.FloatFrom(1.5), SkipToPage: 2},
+				{ConditionType: "unanswered", SkipToPage: 4},
+			},
+		},
+		{
+			description: "空でもエラーなし",
+			conditions:  []QuestionConditions{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			questionID := setupQuestionConditionTest(t)
+
+			err := questionConditionImpl.InsertQuestionConditions(ctx, questionID, testCase.conditions)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			conditions, err := questionConditionImpl.GetQuestionConditions(ctx, []int{questionID})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if !assert.Len(t, conditions, len(testCase.conditions)) {
+				return
+			}
+			for i, condition := range conditions {
+				assert.Equal(t, questionID, condition.QuestionID)
+				assert.Equal(t, testCase.conditions[i].ConditionType, condition.ConditionType)
+				assert.Equal(t, testCase.conditions[i].OptionBody, condition.OptionBody)
+				assert.Equal(t, testCase.conditions[i].MinValue, condition.MinValue)
+				assert.Equal(t, testCase.conditions[i].MaxValue, condition.MaxValue)
+				assert.Equal(t, testCase.conditions[i].SkipToPage, condition.SkipToPage)
+			}
+		})
+	}
+}
+
+func TestUpdateQuestionConditions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	questionID := setupQuestionConditionTest(t)
+
+	err := questionConditionImpl.InsertQuestionConditions(ctx, questionID, []QuestionConditions{
+		{ConditionType: "answered", SkipToPage: 2},
+		{ConditionType: "unanswered", SkipToPage: 3},
+	})
+	require.NoError(t, err)
+
+	err = questionConditionImpl.UpdateQuestionConditions(ctx, questionID, []QuestionConditions{
+		{ConditionType: "option", OptionBody: null.StringFrom("いいえ"), SkipToPage: 5},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	conditions, err := questionConditionImpl.GetQuestionConditions(ctx, []int{questionID})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.Len(t, conditions, 1) {
+		assert.Equal(t, "option", conditions[0].ConditionType)
+		assert.Equal(t, null.StringFrom("いいえ"), conditions[0].OptionBody)
+		assert.Equal(t, 5, conditions[0].SkipToPage)
+	}
+}
+
+func TestDeleteQuestionConditions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	questionID := setupQuestionConditionTest(t)
+
+	err := questionConditionImpl.InsertQuestionConditions(ctx, questionID, []QuestionConditions{
+		{ConditionType: "answered", SkipToPage: 2},
+	})
+	require.NoError(t, err)
+
+	err = questionConditionImpl.DeleteQuestionConditions(ctx, questionID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	conditions, err := questionConditionImpl.GetQuestionConditions(ctx, []int{questionID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, conditions, 0)
+
+	// 分岐条件がなくてもエラーなし
+	err = questionConditionImpl.DeleteQuestionConditions(ctx, questionID)
+	assert.NoError(t, err)
+}
+
+func TestGetQuestionConditions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	questionID1 := setupQuestionConditionTest(t)
+	questionID2 := setupQuestionConditionTest(t)
+
+	err := questionConditionImpl.InsertQuestionConditions(ctx, questionID1, []QuestionConditions{
+		{ConditionType: "answered", SkipToPage: 2},
+	})
+	require.NoError(t, err)
+	err = questionConditionImpl.InsertQuestionConditions(ctx, questionID2, []QuestionConditions{
+		{ConditionType: "unanswered", SkipToPage: 3},
+	})
+	require.NoError(t, err)
+
+	conditions, err := questionConditionImpl.GetQuestionConditions(ctx, []int{questionID1, questionID2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, conditions, 2)
+
+	conditions, err = questionConditionImpl.GetQuestionConditions(ctx, []int{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, conditions, 0)
+}
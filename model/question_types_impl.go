@@ -0,0 +1,306 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuestionTypes question_typesテーブルの構造体
+type QuestionTypes struct {
+	ID     int    `gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	Name   string `gorm:"type:varchar(30);size:30;not null;uniqueIndex"`
+	Active bool   `gorm:"type:boolean;not null;default:true"`
+}
+
+// ResShareTypes res_share_typesテーブルの構造体
+type ResShareTypes struct {
+	ID     int    `gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	Name   string `gorm:"type:varchar(30);size:30;not null;uniqueIndex"`
+	Active bool   `gorm:"type:boolean;not null;default:true"`
+}
+
+// QuestionTypeInfo 質問の種類ごとの性質
+// 質問の種類による処理の分岐はこの値で行い、種類の名前の一覧を他の場所に書かない
+type QuestionTypeInfo struct {
+	// ID question_typesテーブルでのID
+	ID   int
+	Name string
+	// HasRegexPattern 正規表現で回答を制限できるか
+	HasRegexPattern bool
+	// HasOptions 選択肢から回答するか
+	HasOptions bool
+	// MultipleSelection 選択肢を複数選べるか
+	MultipleSelection bool
+	// HasScaleLabel 目盛りのラベルを持つか
+	HasScaleLabel bool
+	// HasBounds min_bound,max_boundで回答の範囲を制限できるか
+	HasBounds bool
+	// IsNumeric 回答が数値か
+	IsNumeric bool
+	// IsDateTime 回答がISO 8601の日時か
+	IsDateTime bool
+	// ActiveByDefault 最初に登録するときに新しい質問で使えるようにするか
+	ActiveByDefault bool
+}
+
+// HasValidation validationsテーブルに制限を持つか
+func (info QuestionTypeInfo) HasValidation() bool {
+	return info.HasRegexPattern || info.HasBounds || info.MultipleSelection
+}
+
+// questionTypeInfos 質問の種類の一覧
+// IDはDBに保存されるため、変更や再利用をしてはいけない
+var questionTypeInfos = []QuestionTypeInfo{
+	{ID: 1, Name: "Text", HasRegexPattern: true, ActiveByDefault: true},
+	{ID: 2, Name: "TextArea", ActiveByDefault: true},
+	{ID: 3, Name: "Number", HasBounds: true, IsNumeric: true, ActiveByDefault: true},
+	{ID: 4, Name: "MultipleChoice", HasOptions: true, ActiveByDefault: true},
+	{ID: 5, Name: "Checkbox", HasOptions: true, MultipleSelection: true, ActiveByDefault: true},
+	// Dropdownは回答を受け付けていないため、新しい質問では使えない状態で登録する
+	{ID: 6, Name: "Dropdown", HasOptions: true, ActiveByDefault: false},
+	{ID: 7, Name: "LinearScale", HasScaleLabel: true, IsNumeric: true, ActiveByDefault: true},
+	{ID: 8, Name: "Date", HasBounds: true, IsDateTime: true, ActiveByDefault: true},
+	{ID: 9, Name: "Time", HasBounds: true, IsDateTime: true, ActiveByDefault: true},
+	{ID: 10, Name: "DateTime", HasBounds: true, IsDateTime: true, ActiveByDefault: true},
+}
+
+// resShareTypeNames 結果の公開範囲の一覧(IDの順)
+// IDはDBに保存されるため、変更や再利用をしてはいけない
+var resShareTypeNames = []string{
+	"administrators",
+	"respondents",
+	"public",
+}
+
+// GetQuestionTypeInfo 名前から質問の種類の性質を取得する
+func GetQuestionTypeInfo(name string) (QuestionTypeInfo, bool) {
+	for _, info := range questionTypeInfos {
+		if info.Name == name {
+			return info, true
+		}
+	}
+
+	return QuestionTypeInfo{}, false
+}
+
+func getQuestionTypeInfoByID(id int) (QuestionTypeInfo, bool) {
+	for _, info := range questionTypeInfos {
+		if info.ID == id {
+			return info, true
+		}
+	}
+
+	return QuestionTypeInfo{}, false
+}
+
+// questionTypeIDs 条件を満たす質問の種類のIDの一覧
+func questionTypeIDs(filter func(info QuestionTypeInfo) bool) []int {
+	ids := []int{}
+	for _, info := range questionTypeInfos {
+		if filter(info) {
+			ids = append(ids, info.ID)
+		}
+	}
+
+	return ids
+}
+
+// IsResShareType 結果の公開範囲として登録されている名前か
+// 新しく設定できるかはcheckResShareTypeActiveでDBのactiveも確認する
+func IsResShareType(name string) bool {
+	_, ok := getResShareTypeID(name)
+	return ok
+}
+
+func getResShareTypeID(name string) (int, bool) {
+	for i, resShareTypeName := range resShareTypeNames {
+		if resShareTypeName == name {
+			return i + 1, true
+		}
+	}
+
+	return 0, false
+}
+
+func getResShareTypeName(id int) (string, bool) {
+	if id < 1 || id > len(resShareTypeNames) {
+		return "", false
+	}
+
+	return resShareTypeNames[id-1], true
+}
+
+// checkQuestionTypeActive 新しい質問で使える質問の種類か確認し、IDを返す
+func checkQuestionTypeActive(ctx context.Context, questionType string) (int, error) {
+	info, ok := GetQuestionTypeInfo(questionType)
+	if !ok {
+		return 0, fmt.Errorf("unknown question type(%s): %w", questionType, ErrInvalidQuestionType)
+	}
+
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var questionTypeRecord QuestionTypes
+	err = db.
+		Where("id = ?", info.ID).
+		Select("active").
+		Take(&questionTypeRecord).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("question type(%s) is not registered: %w", questionType, ErrInvalidQuestionType)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get question type: %w", err)
+	}
+	if !questionTypeRecord.Active {
+		return 0, fmt.Errorf("question type(%s) is not active: %w", questionType, ErrInvalidQuestionType)
+	}
+
+	return info.ID, nil
+}
+
+// checkResShareTypeActive 新しく設定できる結果の公開範囲か確認し、IDを返す
+func checkResShareTypeActive(ctx context.Context, resSharedTo string) (int, error) {
+	id, ok := getResShareTypeID(resSharedTo)
+	if !ok {
+		return 0, fmt.Errorf("unknown res share type(%s): %w", resSharedTo, ErrInvalidResShareType)
+	}
+
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var resShareTypeRecord ResShareTypes
+	err = db.
+		Where("id = ?", id).
+		Select("active").
+		Take(&resShareTypeRecord).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("res share type(%s) is not registered: %w", resSharedTo, ErrInvalidResShareType)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get res share type: %w", err)
+	}
+	if !resShareTypeRecord.Active {
+		return 0, fmt.Errorf("res share type(%s) is not active: %w", resSharedTo, ErrInvalidResShareType)
+	}
+
+	return id, nil
+}
+
+// seedTypes question_types,res_share_typesに種類を登録する
+// 既に登録されている種類のactiveは運用で変更されている可能性があるため変更しない
+func seedTypes(db *gorm.DB) error {
+	questionTypes := make([]QuestionTypes, 0, len(questionTypeInfos))
+	for _, info := range questionTypeInfos {
+		questionTypes = append(questionTypes, QuestionTypes{
+			ID:     info.ID,
+			Name:   info.Name,
+			Active: info.ActiveByDefault,
+		})
+	}
+	err := db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Select("ID", "Name", "Active").
+		Create(&questionTypes).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert question types: %w", err)
+	}
+
+	resShareTypes := make([]ResShareTypes, 0, len(resShareTypeNames))
+	for i, name := range resShareTypeNames {
+		resShareTypes = append(resShareTypes, ResShareTypes{
+			ID:     i + 1,
+			Name:   name,
+			Active: true,
+		})
+	}
+	err = db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&resShareTypes).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert res share types: %w", err)
+	}
+
+	return nil
+}
+
+// migrateTypeColumns question.type,questionnaires.res_shared_toを名前からquestion_types,res_share_typesのIDに置き換える
+// 既にIDになっている場合は何もしない
+func migrateTypeColumns(db *gorm.DB) error {
+	err := migrateNameColumnToID(db, &Questions{}, "question", "type", "question_types", "idx_question_type_id")
+	if err != nil {
+		return fmt.Errorf("failed to migrate question.type: %w", err)
+	}
+
+	err = migrateNameColumnToID(db, &Questionnaires{}, "questionnaires", "res_shared_to", "res_share_types", "idx_questionnaires_res_shared_to_id")
+	if err != nil {
+		return fmt.Errorf("failed to migrate questionnaires.res_shared_to: %w", err)
+	}
+
+	return nil
+}
+
+func migrateNameColumnToID(db *gorm.DB, model interface{}, table string, column string, typeTable string, indexName string) error {
+	if !db.Migrator().HasTable(model) {
+		return nil
+	}
+
+	columnTypes, err := db.Migrator().ColumnTypes(model)
+	if err != nil {
+		return fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	isNameColumn := false
+	for _, columnType := range columnTypes {
+		if columnType.Name() == column {
+			switch strings.ToLower(columnType.DatabaseTypeName()) {
+			case "char", "varchar":
+				isNameColumn = true
+			}
+		}
+	}
+	if !isNameColumn {
+		return nil
+	}
+
+	tmpColumn := column + "_id"
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` int(11) DEFAULT NULL", table, tmpColumn),
+		fmt.Sprintf("UPDATE `%s` INNER JOIN `%s` ON `%s`.`name` = `%s`.`%s` SET `%s`.`%s` = `%s`.`id`", table, typeTable, typeTable, table, column, table, tmpColumn, typeTable),
+		fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`", table, column),
+		fmt.Sprintf("ALTER TABLE `%s` CHANGE `%s` `%s` int(11) NOT NULL", table, tmpColumn, column),
+		fmt.Sprintf("CREATE INDEX `%s` ON `%s` (`%s`)", indexName, table, column),
+	}
+
+	// 種類の表にない名前があると、IDに置き換えられずに失われるため止める
+	var unknownCount int64
+	err = db.
+		Table(table).
+		Joins(fmt.Sprintf("LEFT OUTER JOIN `%s` ON `%s`.`name` = `%s`.`%s`", typeTable, typeTable, table, column)).
+		Where(fmt.Sprintf("`%s`.`id` IS NULL", typeTable)).
+		Count(&unknownCount).Error
+	if err != nil {
+		return fmt.Errorf("failed to count unknown types: %w", err)
+	}
+	if unknownCount != 0 {
+		return fmt.Errorf("%d records in %s have unknown %s", unknownCount, table, column)
+	}
+
+	// Note: MySQLではALTER TABLEがトランザクション内でもコミットされるため、トランザクションは使わない
+	for _, statement := range statements {
+		err := db.Exec(statement).Error
+		if err != nil {
+			return fmt.Errorf("failed to execute %s: %w", statement, err)
+		}
+	}
+
+	return nil
+}
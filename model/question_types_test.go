@@ -0,0 +1,118 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetQuestionTypeInfo(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	ids := map[int]struct{}{}
+	for _, info := range questionTypeInfos {
+		_, ok := ids[info.ID]
+		assertion.False(ok, "duplicate id: %d", info.ID)
+		ids[info.ID] = struct{}{}
+
+		gotInfo, ok := GetQuestionTypeInfo(info.Name)
+		assertion.True(ok, info.Name)
+		assertion.Equal(info, gotInfo, info.Name)
+
+		gotInfo, ok = getQuestionTypeInfoByID(info.ID)
+		assertion.True(ok, info.Name)
+		assertion.Equal(info, gotInfo, info.Name)
+	}
+
+	_, ok := GetQuestionTypeInfo("Unknown")
+	assertion.False(ok, "unknown")
+
+	_, ok = getQuestionTypeInfoByID(0)
+	assertion.False(ok, "id 0")
+}
+
+func TestIsResShareType(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	for _, name := range resShareTypeNames {
+		assertion.True(IsResShareType(name), name)
+	}
+
+	assertion.False(IsResShareType("private"), "private")
+	assertion.False(IsResShareType(""), "empty")
+}
+
+func TestCheckQuestionTypeActive(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	type test struct {
+		description  string
+		questionType string
+		isErr        bool
+		err          error
+	}
+
+	testCases := []test{
+		{
+			description:  "active",
+			questionType: "Text",
+		},
+		{
+			description:  "inactive",
+			questionType: "Dropdown",
+			isErr:        true,
+			err:          ErrInvalidQuestionType,
+		},
+		{
+			description:  "unknown",
+			questionType: "Unknown",
+			isErr:        true,
+			err:          ErrInvalidQuestionType,
+		},
+	}
+
+	for _, testCase := range testCases {
+		id, err := checkQuestionTypeActive(ctx, testCase.questionType)
+
+		if !testCase.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.err != nil {
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("invalid error(%s): expected: %+v, actual: %+v", testCase.description, testCase.err, err)
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		info, _ := GetQuestionTypeInfo(testCase.questionType)
+		assertion.Equal(info.ID, id, testCase.description, "id")
+	}
+}
+
+func TestCheckResShareTypeActive(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	for i, name := range resShareTypeNames {
+		id, err := checkResShareTypeActive(ctx, name)
+		assertion.NoError(err, name)
+		assertion.Equal(i+1, id, name)
+	}
+
+	_, err := checkResShareTypeActive(ctx, "unknown")
+	if !errors.Is(err, ErrInvalidResShareType) {
+		t.Errorf("invalid error: expected: %+v, actual: %+v", ErrInvalidResShareType, err)
+	}
+}
@@ -4,19 +4,33 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"gopkg.in/guregu/null.v4"
 )
 
 // IQuestionnaire QuestionnaireのRepository
 type IQuestionnaire interface {
-	InsertQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string) (int, error)
-	UpdateQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string, questionnaireID int) error
+	InsertQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string, isTemplate bool) (int, error)
+	UpdateQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string, isTemplate bool, questionnaireID int) error
+	UpdateQuestionnaireModifiedAt(ctx context.Context, questionnaireID int, expectedModifiedAt null.Time) (time.Time, error)
+	UpdateQuestionnaireAnonymous(ctx context.Context, questionnaireID int, isAnonymous bool) error
+	UpdateQuestionnaireResponseLimits(ctx context.Context, questionnaireID int, maxResponsesPerUser null.Int, maxTotalResponses null.Int) error
+	UpdateQuestionnaireSchedule(ctx context.Context, questionnaireID int, resStartAt null.Time, isClosed bool) error
+	UpdateQuestionnaireReminders(ctx context.Context, questionnaireID int, disableReminders bool) error
+	UpdateQuestionnaireAnnounced(ctx context.Context, questionnaireID int) error
+	UpdateQuestionnaireSummarized(ctx context.Context, questionnaireID int) error
 	DeleteQuestionnaire(ctx context.Context, questionnaireID int) error
-	GetQuestionnaires(ctx context.Context, userID string, sort string, search string, pageNum int, nontargeted bool) ([]QuestionnaireInfo, int, error)
+	GetQuestionnaires(ctx context.Context, userID string, sort string, search string, pageNum int, nontargeted bool, isTemplate bool) ([]QuestionnaireInfo, int, error)
 	GetAdminQuestionnaires(ctx context.Context, userID string) ([]Questionnaires, error)
 	GetQuestionnaireInfo(ctx context.Context, questionnaireID int) (*Questionnaires, []string, []string, []string, error)
 	GetTargettedQuestionnaires(ctx context.Context, userID string, answered string, sort string) ([]TargettedQuestionnaire, error)
+	CheckQuestionnaireTemplate(ctx context.Context, questionnaireID int) (bool, error)
+	CheckQuestionnaireAnonymous(ctx context.Context, questionnaireID int) (bool, error)
+	CheckQuestionnaireOpen(ctx context.Context, questionnaireID int) (bool, error)
+	GetQuestionnairesToAnnounce(ctx context.Context) ([]Questionnaires, error)
+	GetQuestionnairesToRemind(ctx context.Context, offset time.Duration) ([]Questionnaires, error)
+	GetQuestionnairesToSummarize(ctx context.Context) ([]Questionnaires, error)
 	GetQuestionnaireLimit(ctx context.Context, questionnaireID int) (null.Time, error)
 	GetQuestionnaireLimitByResponseID(ctx context.Context, responseID int) (null.Time, error)
 	GetResponseReadPrivilegeInfoByResponseID(ctx context.Context, userID string, responseID int) (*ResponseReadPrivilegeInfo, error)
@@ -9,6 +9,7 @@ import (
 
 	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Questionnaire QuestionnaireRepositoryの実装
@@ -21,18 +22,29 @@ func NewQuestionnaire() *Questionnaire {
 
 //Questionnaires questionnairesテーブルの構造体
 type Questionnaires struct {
-	ID             int              `json:"questionnaireID" gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
-	Title          string           `json:"title"           gorm:"type:char(50);size:50;not null"`
-	Description    string           `json:"description"     gorm:"type:text;not null"`
-	ResTimeLimit   null.Time        `json:"res_time_limit,omitempty"  gorm:"type:TIMESTAMP NULL;default:NULL;"`
-	DeletedAt      gorm.DeletedAt   `json:"-"      gorm:"type:TIMESTAMP NULL;default:NULL;"`
-	ResSharedTo    string           `json:"res_shared_to"   gorm:"type:char(30);size:30;not null;default:administrators"`
-	CreatedAt      time.Time        `json:"created_at"      gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	ModifiedAt     time.Time        `json:"modified_at"     gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	Administrators []Administrators `json:"-"  gorm:"foreignKey:QuestionnaireID"`
-	Targets        []Targets        `json:"-"  gorm:"foreignKey:QuestionnaireID"`
-	Questions      []Questions      `json:"-"  gorm:"foreignKey:QuestionnaireID"`
-	Respondents    []Respondents    `json:"-"  gorm:"foreignKey:QuestionnaireID"`
+	ID                  int              `json:"questionnaireID" gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	Title               string           `json:"title"           gorm:"type:char(50);size:50;not null"`
+	Description         string           `json:"description"     gorm:"type:text;not null"`
+	ResTimeLimit        null.Time        `json:"res_time_limit,omitempty"  gorm:"type:TIMESTAMP NULL;default:NULL;"`
+	ResStartAt          null.Time        `json:"res_start_at,omitempty"    gorm:"type:TIMESTAMP NULL;default:NULL;"`
+	IsClosed            bool             `json:"is_closed"       gorm:"type:boolean;not null;default:false"`
+	AnnouncedAt         null.Time        `json:"-"               gorm:"type:TIMESTAMP NULL;default:NULL;"`
+	SummarizedAt        null.Time        `json:"-"               gorm:"type:TIMESTAMP NULL;default:NULL;"`
+	DeletedAt           gorm.DeletedAt   `json:"-"      gorm:"type:TIMESTAMP NULL;default:NULL;"`
+	ResSharedToID       int              `json:"-"               gorm:"column:res_shared_to;type:int(11);not null;index"`
+	ResSharedTo         string           `json:"res_shared_to"   gorm:"-"`
+	IsTemplate          bool             `json:"is_template"     gorm:"type:boolean;not null;default:false"`
+	IsAnonymous         bool             `json:"is_anonymous"    gorm:"type:boolean;not null;default:false"`
+	AnonymousSalt       null.String      `json:"-"               gorm:"type:char(64);default:NULL"`
+	MaxResponsesPerUser null.Int         `json:"max_responses_per_user" gorm:"type:int(11);default:NULL"`
+	MaxTotalResponses   null.Int         `json:"max_total_responses"    gorm:"type:int(11);default:NULL"`
+	DisableReminders    bool             `json:"disable_reminders"      gorm:"type:boolean;not null;default:false"`
+	CreatedAt           time.Time        `json:"created_at"      gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ModifiedAt          time.Time        `json:"modified_at"     gorm:"type:timestamp(6);not null;default:CURRENT_TIMESTAMP(6)"`
+	Administrators      []Administrators `json:"-"  gorm:"foreignKey:QuestionnaireID"`
+	Targets             []Targets        `json:"-"  gorm:"foreignKey:QuestionnaireID"`
+	Questions           []Questions      `json:"-"  gorm:"foreignKey:QuestionnaireID"`
+	Respondents         []Respondents    `json:"-"  gorm:"foreignKey:QuestionnaireID"`
 }
 
 // BeforeCreate Update時に自動でmodified_atを現在時刻に
@@ -41,6 +53,16 @@ func (questionnaire *Questionnaires) BeforeCreate(tx *gorm.DB) error {
 	questionnaire.ModifiedAt = now
 	questionnaire.CreatedAt = now
 
+	// 結果の公開範囲は名前ではなくres_share_typesのIDで保存する
+	if questionnaire.ResSharedTo == "" {
+		questionnaire.ResSharedTo = "administrators"
+	}
+	resSharedToID, ok := getResShareTypeID(questionnaire.ResSharedTo)
+	if !ok {
+		return fmt.Errorf("unknown res share type(%s): %w", questionnaire.ResSharedTo, ErrInvalidResShareType)
+	}
+	questionnaire.ResSharedToID = resSharedToID
+
 	return nil
 }
 
@@ -51,6 +73,22 @@ func (questionnaire *Questionnaires) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
+// AfterFind 取得時にres_share_typesのIDから結果の公開範囲の名前を設定する
+func (questionnaire *Questionnaires) AfterFind(tx *gorm.DB) error {
+	// res_shared_toを取得していない場合
+	if questionnaire.ResSharedToID == 0 {
+		return nil
+	}
+
+	resSharedTo, ok := getResShareTypeName(questionnaire.ResSharedToID)
+	if !ok {
+		return fmt.Errorf("unknown res share type id(%d): %w", questionnaire.ResSharedToID, ErrInvalidResShareType)
+	}
+	questionnaire.ResSharedTo = resSharedTo
+
+	return nil
+}
+
 //QuestionnaireInfo Questionnaireにtargetかの情報追加
 type QuestionnaireInfo struct {
 	Questionnaires
@@ -79,18 +117,25 @@ type ResponseReadPrivilegeInfo struct {
 }
 
 //InsertQuestionnaire アンケートの追加
-func (*Questionnaire) InsertQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string) (int, error) {
+func (*Questionnaire) InsertQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string, isTemplate bool) (int, error) {
 	db, err := getTx(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get tx: %w", err)
 	}
 
+	// 使われなくなった公開範囲は新しく設定できない
+	_, err = checkResShareTypeActive(ctx, resSharedTo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check res share type: %w", err)
+	}
+
 	var questionnaire Questionnaires
 	if !resTimeLimit.Valid {
 		questionnaire = Questionnaires{
 			Title:       title,
 			Description: description,
 			ResSharedTo: resSharedTo,
+			IsTemplate:  isTemplate,
 		}
 	} else {
 		questionnaire = Questionnaires{
@@ -98,6 +143,7 @@ func (*Questionnaire) InsertQuestionnaire(ctx context.Context, title string, des
 			Description:  description,
 			ResTimeLimit: resTimeLimit,
 			ResSharedTo:  resSharedTo,
+			IsTemplate:   isTemplate,
 		}
 	}
 
@@ -110,27 +156,45 @@ func (*Questionnaire) InsertQuestionnaire(ctx context.Context, title string, des
 }
 
 //UpdateQuestionnaire アンケートの更新
-func (*Questionnaire) UpdateQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string, questionnaireID int) error {
+func (*Questionnaire) UpdateQuestionnaire(ctx context.Context, title string, description string, resTimeLimit null.Time, resSharedTo string, isTemplate bool, questionnaireID int) error {
 	db, err := getTx(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get tx: %w", err)
 	}
 
-	var questionnaire interface{}
-	if resTimeLimit.Valid {
-		questionnaire = Questionnaires{
-			Title:        title,
-			Description:  description,
-			ResTimeLimit: resTimeLimit,
-			ResSharedTo:  resSharedTo,
+	resSharedToID, ok := getResShareTypeID(resSharedTo)
+	if !ok {
+		return fmt.Errorf("unknown res share type(%s): %w", resSharedTo, ErrInvalidResShareType)
+	}
+
+	// 使われなくなった公開範囲の既存のアンケートは、公開範囲を変えなければ編集できる
+	var currentQuestionnaire Questionnaires
+	err = db.
+		Where("id = ?", questionnaireID).
+		Select("res_shared_to").
+		Take(&currentQuestionnaire).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get the res share type: %w", err)
+	}
+	if currentQuestionnaire.ResSharedToID != resSharedToID {
+		_, err = checkResShareTypeActive(ctx, resSharedTo)
+		if err != nil {
+			return fmt.Errorf("failed to check res share type: %w", err)
 		}
+	}
+
+	// is_templateのfalseも更新するため、構造体ではなくmapで更新する
+	questionnaire := map[string]interface{}{
+		"title":         title,
+		"description":   description,
+		"res_shared_to": resSharedToID,
+		"is_template":   isTemplate,
+		"modified_at":   time.Now(),
+	}
+	if resTimeLimit.Valid {
+		questionnaire["res_time_limit"] = resTimeLimit
 	} else {
-		questionnaire = map[string]interface{}{
-			"title":          title,
-			"description":    description,
-			"res_time_limit": gorm.Expr("NULL"),
-			"res_shared_to":  resSharedTo,
-		}
+		questionnaire["res_time_limit"] = gorm.Expr("NULL")
 	}
 
 	result := db.
@@ -148,6 +212,260 @@ func (*Questionnaire) UpdateQuestionnaire(ctx context.Context, title string, des
 	return nil
 }
 
+// UpdateQuestionnaireModifiedAt アンケートの更新日時を現在時刻にする
+// expectedModifiedAtが指定されている場合は、更新日時がexpectedModifiedAtのときのみ更新する
+// 同じアンケートへの更新はトランザクションが終わるまで待たされる
+func (*Questionnaire) UpdateQuestionnaireModifiedAt(ctx context.Context, questionnaireID int, expectedModifiedAt null.Time) (time.Time, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", questionnaireID).
+		Select("modified_at").
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, ErrRecordNotFound
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
+	if expectedModifiedAt.Valid && !questionnaire.ModifiedAt.Equal(expectedModifiedAt.Time) {
+		return time.Time{}, ErrConflict
+	}
+
+	modifiedAt := nextModifiedAt(questionnaire.ModifiedAt)
+	err = db.
+		Model(&Questionnaires{}).
+		Where("id = ?", questionnaireID).
+		UpdateColumn("modified_at", modifiedAt).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to update modified_at: %w", err)
+	}
+
+	return modifiedAt, nil
+}
+
+// nextModifiedAt 更新日時をcurrentから進める
+// timestamp(6)型に合わせてマイクロ秒未満は切り捨て、同じマイクロ秒内の更新でも前と同じ値にならないようにする
+func nextModifiedAt(current time.Time) time.Time {
+	modifiedAt := time.Now().Truncate(time.Microsecond)
+	if !modifiedAt.After(current) {
+		modifiedAt = current.Add(time.Microsecond)
+	}
+
+	return modifiedAt
+}
+
+// UpdateQuestionnaireAnonymous アンケートの匿名の設定の変更
+// 匿名にするときは既存の回答者もtraQIDの代わりにハッシュで記録し、監査ログとWebhookの送信の記録からも回答者を消す
+// 回答があるアンケートの匿名は解除できない
+func (*Questionnaire) UpdateQuestionnaireAnonymous(ctx context.Context, questionnaireID int, isAnonymous bool) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", questionnaireID).
+		Select("is_anonymous", "anonymous_salt").
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRecordNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
+	if questionnaire.IsAnonymous == isAnonymous {
+		return nil
+	}
+
+	if !isAnonymous {
+		var count int64
+		err = db.
+			Model(&Respondents{}).
+			Where("questionnaire_id = ?", questionnaireID).
+			Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to count respondents: %w", err)
+		}
+		if count != 0 {
+			return ErrAnonymityLocked
+		}
+
+		err = db.
+			Model(&Questionnaires{}).
+			Where("id = ?", questionnaireID).
+			UpdateColumn("is_anonymous", false).Error
+		if err != nil {
+			return fmt.Errorf("failed to update is_anonymous: %w", err)
+		}
+
+		return nil
+	}
+
+	salt := questionnaire.AnonymousSalt.String
+	if !questionnaire.AnonymousSalt.Valid {
+		salt, err = generateAnonymousSalt()
+		if err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	err = db.
+		Model(&Questionnaires{}).
+		Where("id = ?", questionnaireID).
+		UpdateColumns(map[string]interface{}{
+			"is_anonymous":   true,
+			"anonymous_salt": salt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update is_anonymous: %w", err)
+	}
+
+	// 削除された回答も含めて回答者のtraQIDを残さない
+	// Note: MySQLでは左から順に代入されるため、user_hashはtraQIDを消す前の値から求められる
+	err = db.
+		Unscoped().
+		Model(&Respondents{}).
+		Where("questionnaire_id = ? AND user_traqid IS NOT NULL", questionnaireID).
+		UpdateColumns(map[string]interface{}{
+			"user_hash":   gorm.Expr("SHA2(CONCAT(?, user_traqid), 256)", salt),
+			"user_traqid": gorm.Expr("NULL"),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to anonymize respondents: %w", err)
+	}
+
+	// 回答者がわかる監査ログと送信の記録も同じトランザクションで消す
+	err = anonymizeResponseAuditEvents(db, questionnaireID)
+	if err != nil {
+		return err
+	}
+
+	err = anonymizeWebhookDeliveries(db, questionnaireID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateQuestionnaireResponseLimits アンケートの回答数の上限の変更
+// 上限がない場合はnullにする
+func (*Questionnaire) UpdateQuestionnaireResponseLimits(ctx context.Context, questionnaireID int, maxResponsesPerUser null.Int, maxTotalResponses null.Int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	err = db.
+		Model(&Questionnaires{}).
+		Where("id = ?", questionnaireID).
+		UpdateColumns(map[string]interface{}{
+			"max_responses_per_user": maxResponsesPerUser,
+			"max_total_responses":    maxTotalResponses,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update response limits: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateQuestionnaireSchedule アンケートの回答開始日時と締め切りの変更
+// resStartAtがnullの場合は作成時から回答できる
+func (*Questionnaire) UpdateQuestionnaireSchedule(ctx context.Context, questionnaireID int, resStartAt null.Time, isClosed bool) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	err = db.
+		Model(&Questionnaires{}).
+		Where("id = ?", questionnaireID).
+		UpdateColumns(map[string]interface{}{
+			"res_start_at": resStartAt,
+			"is_closed":    isClosed,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateQuestionnaireReminders アンケートの回答期限前のリマインドを送るかの変更
+func (*Questionnaire) UpdateQuestionnaireReminders(ctx context.Context, questionnaireID int, disableReminders bool) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	err = db.
+		Model(&Questionnaires{}).
+		Where("id = ?", questionnaireID).
+		UpdateColumn("disable_reminders", disableReminders).Error
+	if err != nil {
+		return fmt.Errorf("failed to update disable_reminders: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateQuestionnaireAnnounced アンケートを告知済みにする
+// 既に告知済みの場合はErrNoRecordUpdatedを返す
+func (*Questionnaire) UpdateQuestionnaireAnnounced(ctx context.Context, questionnaireID int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	result := db.
+		Model(&Questionnaires{}).
+		Where("id = ? AND announced_at IS NULL", questionnaireID).
+		UpdateColumn("announced_at", time.Now())
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update announced_at: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
+// UpdateQuestionnaireSummarized アンケートを締め切り後の結果の通知済みにする
+// 既に通知済みの場合はErrNoRecordUpdatedを返す
+func (*Questionnaire) UpdateQuestionnaireSummarized(ctx context.Context, questionnaireID int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	result := db.
+		Model(&Questionnaires{}).
+		Where("id = ? AND summarized_at IS NULL", questionnaireID).
+		UpdateColumn("summarized_at", time.Now())
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update summarized_at: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
 //DeleteQuestionnaire アンケートの削除
 func (*Questionnaire) DeleteQuestionnaire(ctx context.Context, questionnaireID int) error {
 	db, err := getTx(ctx)
@@ -168,8 +486,9 @@ func (*Questionnaire) DeleteQuestionnaire(ctx context.Context, questionnaireID i
 }
 
 /*GetQuestionnaires アンケートの一覧
+isTemplateがtrueのときはテンプレートの一覧
 2つ目の戻り値はページ数の最大値*/
-func (*Questionnaire) GetQuestionnaires(ctx context.Context, userID string, sort string, search string, pageNum int, nontargeted bool) ([]QuestionnaireInfo, int, error) {
+func (*Questionnaire) GetQuestionnaires(ctx context.Context, userID string, sort string, search string, pageNum int, nontargeted bool, isTemplate bool) ([]QuestionnaireInfo, int, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
@@ -180,9 +499,12 @@ func (*Questionnaire) GetQuestionnaires(ctx context.Context, userID string, sort
 
 	questionnaires := make([]QuestionnaireInfo, 0, 20)
 
+	// 回答開始前のアンケートは管理者にのみ表示する
 	query := db.
 		Table("questionnaires").
-		Joins("LEFT OUTER JOIN targets ON questionnaires.id = targets.questionnaire_id")
+		Joins("LEFT OUTER JOIN targets ON questionnaires.id = targets.questionnaire_id").
+		Where("questionnaires.is_template = ?", isTemplate).
+		Where("questionnaires.res_start_at IS NULL OR questionnaires.res_start_at <= ? OR questionnaires.id IN (SELECT questionnaire_id FROM administrators WHERE user_traqid = ?)", time.Now(), userID)
 
 	query, err = setQuestionnairesOrder(query, sort)
 	if err != nil {
@@ -300,10 +622,11 @@ func (*Questionnaire) GetQuestionnaireInfo(ctx context.Context, questionnaireID
 		return nil, nil, nil, nil, fmt.Errorf("failed to get administrators: %w", err)
 	}
 
+	// 匿名のアンケートの回答者はtraQIDを記録していないので含まれない
 	err = db.
 		Session(&gorm.Session{NewDB: true}).
 		Table("respondents").
-		Where("questionnaire_id = ? AND deleted_at IS NULL AND submitted_at IS NOT NULL", questionnaire.ID).
+		Where("questionnaire_id = ? AND deleted_at IS NULL AND submitted_at IS NOT NULL AND user_traqid IS NOT NULL", questionnaire.ID).
 		Pluck("user_traqid", &respondents).Error
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("failed to get respondents: %w", err)
@@ -322,9 +645,11 @@ func (*Questionnaire) GetTargettedQuestionnaires(ctx context.Context, userID str
 	query := db.
 		Table("questionnaires").
 		Where("questionnaires.res_time_limit > ? OR questionnaires.res_time_limit IS NULL", time.Now()).
+		Where("questionnaires.is_template = ?", false).
 		Joins("INNER JOIN targets ON questionnaires.id = targets.questionnaire_id").
 		Where("targets.user_traqid = ? OR targets.user_traqid = 'traP'", userID).
-		Joins("LEFT OUTER JOIN respondents ON questionnaires.id = respondents.questionnaire_id AND respondents.user_traqid = ? AND respondents.deleted_at IS NULL", userID).
+		Where("questionnaires.res_start_at IS NULL OR questionnaires.res_start_at <= ?", time.Now()).
+		Joins("LEFT OUTER JOIN respondents ON questionnaires.id = respondents.questionnaire_id AND "+respondentUserCondition("respondents")+" AND respondents.deleted_at IS NULL", userID, userID).
 		Group("questionnaires.id,respondents.user_traqid").
 		Select("questionnaires.*, MAX(respondents.submitted_at) AS responded_at, COUNT(respondents.response_id) != 0 AS has_response")
 
@@ -341,7 +666,8 @@ func (*Questionnaire) GetTargettedQuestionnaires(ctx context.Context, userID str
 	case "answered":
 		query = query.Where("respondents.questionnaire_id IS NOT NULL")
 	case "unanswered":
-		query = query.Where("respondents.questionnaire_id IS NULL")
+		// 締め切られたアンケートには回答できないので含めない
+		query = query.Where("respondents.questionnaire_id IS NULL AND questionnaires.is_closed = false")
 	case "":
 	default:
 		return nil, fmt.Errorf("invalid answered parameter value(%s): %w", answered, ErrInvalidAnsweredParam)
@@ -356,6 +682,151 @@ func (*Questionnaire) GetTargettedQuestionnaires(ctx context.Context, userID str
 	return questionnaires, nil
 }
 
+// CheckQuestionnaireTemplate アンケートがテンプレートかの確認
+func (*Questionnaire) CheckQuestionnaireTemplate(ctx context.Context, questionnaireID int) (bool, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Where("id = ?", questionnaireID).
+		Select("is_template").
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, ErrRecordNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
+	return questionnaire.IsTemplate, nil
+}
+
+// CheckQuestionnaireAnonymous アンケートが匿名かの確認
+func (*Questionnaire) CheckQuestionnaireAnonymous(ctx context.Context, questionnaireID int) (bool, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Where("id = ?", questionnaireID).
+		Select("is_anonymous").
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, ErrRecordNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
+	return questionnaire.IsAnonymous, nil
+}
+
+// CheckQuestionnaireOpen アンケートが回答を受け付けているかの確認
+// 回答開始前と締め切られた後は受け付けない (回答期限は別途GetQuestionnaireLimitで確認する)
+func (*Questionnaire) CheckQuestionnaireOpen(ctx context.Context, questionnaireID int) (bool, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Where("id = ?", questionnaireID).
+		Select("res_start_at", "is_closed").
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, ErrRecordNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
+	if questionnaire.IsClosed {
+		return false, nil
+	}
+	if questionnaire.ResStartAt.Valid && questionnaire.ResStartAt.Time.After(time.Now()) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// GetQuestionnairesToAnnounce 回答開始日時を過ぎたが、まだ告知していないアンケートの取得
+// テンプレートと締め切られたアンケートは告知しない
+func (*Questionnaire) GetQuestionnairesToAnnounce(ctx context.Context) ([]Questionnaires, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	questionnaires := []Questionnaires{}
+	err = db.
+		Where("announced_at IS NULL AND is_template = false AND is_closed = false").
+		Where("res_start_at IS NULL OR res_start_at <= ?", time.Now()).
+		Order("id").
+		Find(&questionnaires).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires to announce: %w", err)
+	}
+
+	return questionnaires, nil
+}
+
+// GetQuestionnairesToSummarize 回答期限を過ぎたか締め切られたが、結果を通知していないアンケートの取得
+// テンプレート・告知していないアンケートは通知しない
+func (*Questionnaire) GetQuestionnairesToSummarize(ctx context.Context) ([]Questionnaires, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	questionnaires := []Questionnaires{}
+	err = db.
+		Where("summarized_at IS NULL AND announced_at IS NOT NULL AND is_template = false").
+		Where("is_closed = true OR (res_time_limit IS NOT NULL AND res_time_limit <= ?)", time.Now()).
+		Order("id").
+		Find(&questionnaires).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires to summarize: %w", err)
+	}
+
+	return questionnaires, nil
+}
+
+// GetQuestionnairesToRemind 回答期限までoffset以内になったが、その回答期限でoffsetのリマインドをしていないアンケートの取得
+// テンプレート・締め切られたアンケート・告知していないアンケート・リマインドを止めたアンケートはリマインドしない
+func (*Questionnaire) GetQuestionnairesToRemind(ctx context.Context, offset time.Duration) ([]Questionnaires, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	now := time.Now()
+	questionnaires := []Questionnaires{}
+	err = db.
+		Where("is_template = false AND is_closed = false AND disable_reminders = false AND announced_at IS NOT NULL").
+		Where("res_time_limit > ? AND res_time_limit <= ?", now, now.Add(offset)).
+		Where("NOT EXISTS (?)", db.
+			Session(&gorm.Session{NewDB: true}).
+			Model(&QuestionnaireReminders{}).
+			Select("1").
+			Where("questionnaire_reminders.questionnaire_id = questionnaires.id").
+			Where("questionnaire_reminders.res_time_limit = questionnaires.res_time_limit").
+			Where("questionnaire_reminders.offset_seconds = ?", int(offset/time.Second))).
+		Order("id").
+		Find(&questionnaires).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires to remind: %w", err)
+	}
+
+	return questionnaires, nil
+}
+
 //GetQuestionnaireLimit アンケートの回答期限の取得
 func (*Questionnaire) GetQuestionnaireLimit(ctx context.Context, questionnaireID int) (null.Time, error) {
 	db, err := getTx(ctx)
@@ -415,8 +886,9 @@ func (*Questionnaire) GetResponseReadPrivilegeInfoByResponseID(ctx context.Conte
 		Where("respondents.response_id = ? AND respondents.submitted_at IS NOT NULL", responseID).
 		Joins("INNER JOIN questionnaires ON questionnaires.id = respondents.questionnaire_id").
 		Joins("LEFT OUTER JOIN administrators ON questionnaires.id = administrators.questionnaire_id AND administrators.user_traqid = ?", userID).
-		Joins("LEFT OUTER JOIN respondents AS respondents2 ON questionnaires.id = respondents2.questionnaire_id AND respondents2.user_traqid = ? AND respondents2.submitted_at IS NOT NULL", userID).
-		Select("questionnaires.res_shared_to, administrators.questionnaire_id IS NOT NULL AS is_administrator, respondents2.response_id IS NOT NULL AS is_respondent").
+		Joins("LEFT OUTER JOIN respondents AS respondents2 ON questionnaires.id = respondents2.questionnaire_id AND "+respondentUserCondition("respondents2")+" AND respondents2.submitted_at IS NOT NULL", userID, userID).
+		Joins("INNER JOIN res_share_types ON res_share_types.id = questionnaires.res_shared_to").
+		Select("res_share_types.name AS res_shared_to, administrators.questionnaire_id IS NOT NULL AS is_administrator, respondents2.response_id IS NOT NULL AS is_respondent").
 		Take(&responseReadPrivilegeInfo).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, ErrRecordNotFound
@@ -439,8 +911,9 @@ func (*Questionnaire) GetResponseReadPrivilegeInfoByQuestionnaireID(ctx context.
 		Table("questionnaires").
 		Where("questionnaires.id = ?", questionnaireID).
 		Joins("LEFT OUTER JOIN administrators ON questionnaires.id = administrators.questionnaire_id AND administrators.user_traqid = ?", userID).
-		Joins("LEFT OUTER JOIN respondents ON questionnaires.id = respondents.questionnaire_id AND respondents.user_traqid = ? AND respondents.submitted_at IS NOT NULL", userID).
-		Select("questionnaires.res_shared_to, administrators.questionnaire_id IS NOT NULL AS is_administrator, respondents.response_id IS NOT NULL AS is_respondent").
+		Joins("LEFT OUTER JOIN respondents ON questionnaires.id = respondents.questionnaire_id AND "+respondentUserCondition("respondents")+" AND respondents.submitted_at IS NOT NULL", userID, userID).
+		Joins("INNER JOIN res_share_types ON res_share_types.id = questionnaires.res_shared_to").
+		Select("res_share_types.name AS res_shared_to, administrators.questionnaire_id IS NOT NULL AS is_administrator, respondents.response_id IS NOT NULL AS is_respondent").
 		Take(&responseReadPrivilegeInfo).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, ErrRecordNotFound
@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"sort"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
 )
@@ -47,11 +49,22 @@ func TestQuestionnaires(t *testing.T) {
 
 	t.Run("InsertQuestionnaire", insertQuestionnaireTest)
 	t.Run("UpdateQuestionnaire", updateQuestionnaireTest)
+	t.Run("UpdateQuestionnaireModifiedAt", updateQuestionnaireModifiedAtTest)
+	t.Run("UpdateQuestionnaireAnonymous", updateQuestionnaireAnonymousTest)
+	t.Run("UpdateQuestionnaireResponseLimits", updateQuestionnaireResponseLimitsTest)
+	t.Run("UpdateQuestionnaireAnnounced", updateQuestionnaireAnnouncedTest)
+	t.Run("UpdateQuestionnaireSummarized", updateQuestionnaireSummarizedTest)
 	t.Run("DeleteQuestionnaire", deleteQuestionnaireTest)
 	t.Run("GetQuestionnaires", getQuestionnairesTest)
 	t.Run("GetAdminQuestionnaires", getAdminQuestionnairesTest)
 	t.Run("GetQuestionnaireInfo", getQuestionnaireInfoTest)
 	t.Run("GetTargettedQuestionnaires", getTargettedQuestionnairesTest)
+	t.Run("CheckQuestionnaireTemplate", checkQuestionnaireTemplateTest)
+	t.Run("CheckQuestionnaireAnonymous", checkQuestionnaireAnonymousTest)
+	t.Run("CheckQuestionnaireOpen", checkQuestionnaireOpenTest)
+	t.Run("GetQuestionnairesToAnnounce", getQuestionnairesToAnnounceTest)
+	t.Run("GetQuestionnairesToRemind", getQuestionnairesToRemindTest)
+	t.Run("GetQuestionnairesToSummarize", getQuestionnairesToSummarizeTest)
 	t.Run("GetQuestionnaireLimit", getQuestionnaireLimitTest)
 	t.Run("GetQuestionnaireLimitByResponseID", getQuestionnaireLimitByResponseIDTest)
 	t.Run("GetResponseReadPrivilegeInfoByResponseID", getResponseReadPrivilegeInfoByResponseIDTest)
@@ -271,6 +284,19 @@ func setupQuestionnairesTest(t *testing.T) {
 				isSubmitted: true,
 			},
 		},
+	}, &QuestionnairesTestData{
+		questionnaire: &Questionnaires{
+			Title:        "第1回集会らん☆ぷろ募集アンケートテンプレート",
+			Description:  "第1回集会らん☆ぷろ参加者募集",
+			ResTimeLimit: null.NewTime(time.Time{}, false),
+			ResSharedTo:  "public",
+			IsTemplate:   true,
+			CreatedAt:    questionnairesNow,
+			ModifiedAt:   questionnairesNow,
+		},
+		targets:        []string{},
+		administrators: []string{},
+		respondents:    []*QuestionnairesTestRespondent{},
 	})
 
 	for i, data := range datas {
@@ -355,6 +381,7 @@ func insertQuestionnaireTest(t *testing.T) {
 		description  string
 		resTimeLimit null.Time
 		resSharedTo  string
+		isTemplate   bool
 	}
 	type expect struct {
 		isErr bool
@@ -404,6 +431,16 @@ func insertQuestionnaireTest(t *testing.T) {
 				resSharedTo:  "administrators",
 			},
 		},
+		{
+			description: "template",
+			args: args{
+				title:        "第1回集会らん☆ぷろ募集アンケート",
+				description:  "第1回集会らん☆ぷろ参加者募集",
+				resTimeLimit: null.NewTime(time.Time{}, false),
+				resSharedTo:  "public",
+				isTemplate:   true,
+			},
+		},
 		{
 			description: "long title",
 			args: args{
@@ -451,7 +488,7 @@ func insertQuestionnaireTest(t *testing.T) {
 	for _, testCase := range testCases {
 		ctx := context.Background()
 
-		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, testCase.args.title, testCase.args.description, testCase.args.resTimeLimit, testCase.args.resSharedTo)
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, testCase.args.title, testCase.args.description, testCase.args.resTimeLimit, testCase.args.resSharedTo, testCase.args.isTemplate)
 
 		if !testCase.expect.isErr {
 			assertion.NoError(err, testCase.description, "no error")
@@ -475,6 +512,7 @@ func insertQuestionnaireTest(t *testing.T) {
 		assertion.Equal(testCase.args.description, questionnaire.Description, testCase.description, "description")
 		assertion.WithinDuration(testCase.args.resTimeLimit.ValueOrZero(), questionnaire.ResTimeLimit.ValueOrZero(), 2*time.Second, testCase.description, "res_time_limit")
 		assertion.Equal(testCase.args.resSharedTo, questionnaire.ResSharedTo, testCase.description, "res_shared_to")
+		assertion.Equal(testCase.args.isTemplate, questionnaire.IsTemplate, testCase.description, "is_template")
 
 		assertion.WithinDuration(time.Now(), questionnaire.CreatedAt, 2*time.Second, testCase.description, "created_at")
 		assertion.WithinDuration(time.Now(), questionnaire.ModifiedAt, 2*time.Second, testCase.description, "modified_at")
@@ -492,6 +530,7 @@ func updateQuestionnaireTest(t *testing.T) {
 		description  string
 		resTimeLimit null.Time
 		resSharedTo  string
+		isTemplate   bool
 	}
 	type expect struct {
 		isErr bool
@@ -641,6 +680,38 @@ func updateQuestionnaireTest(t *testing.T) {
 				resSharedTo:  "public",
 			},
 		},
+		{
+			description: "update is_template(false->true)",
+			before: args{
+				title:        "第1回集会らん☆ぷろ募集アンケート",
+				description:  "第1回集会らん☆ぷろ参加者募集",
+				resTimeLimit: null.NewTime(time.Time{}, false),
+				resSharedTo:  "public",
+			},
+			after: args{
+				title:        "第1回集会らん☆ぷろ募集アンケート",
+				description:  "第1回集会らん☆ぷろ参加者募集",
+				resTimeLimit: null.NewTime(time.Time{}, false),
+				resSharedTo:  "public",
+				isTemplate:   true,
+			},
+		},
+		{
+			description: "update is_template(true->false)",
+			before: args{
+				title:        "第1回集会らん☆ぷろ募集アンケート",
+				description:  "第1回集会らん☆ぷろ参加者募集",
+				resTimeLimit: null.NewTime(time.Time{}, false),
+				resSharedTo:  "public",
+				isTemplate:   true,
+			},
+			after: args{
+				title:        "第1回集会らん☆ぷろ募集アンケート",
+				description:  "第1回集会らん☆ぷろ参加者募集",
+				resTimeLimit: null.NewTime(time.Time{}, false),
+				resSharedTo:  "public",
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -652,6 +723,7 @@ func updateQuestionnaireTest(t *testing.T) {
 			Description:  before.description,
 			ResTimeLimit: before.resTimeLimit,
 			ResSharedTo:  before.resSharedTo,
+			IsTemplate:   before.isTemplate,
 		}
 		err := db.
 			Session(&gorm.Session{NewDB: true}).
@@ -663,7 +735,7 @@ func updateQuestionnaireTest(t *testing.T) {
 		createdAt := questionnaire.CreatedAt
 		questionnaireID := questionnaire.ID
 		after := &testCase.after
-		err = questionnaireImpl.UpdateQuestionnaire(ctx, after.title, after.description, after.resTimeLimit, after.resSharedTo, questionnaireID)
+		err = questionnaireImpl.UpdateQuestionnaire(ctx, after.title, after.description, after.resTimeLimit, after.resSharedTo, after.isTemplate, questionnaireID)
 
 		if !testCase.expect.isErr {
 			assertion.NoError(err, testCase.description, "no error")
@@ -687,6 +759,7 @@ func updateQuestionnaireTest(t *testing.T) {
 		assertion.Equal(after.description, questionnaire.Description, testCase.description, "description")
 		assertion.WithinDuration(after.resTimeLimit.ValueOrZero(), questionnaire.ResTimeLimit.ValueOrZero(), 2*time.Second, testCase.description, "res_time_limit")
 		assertion.Equal(after.resSharedTo, questionnaire.ResSharedTo, testCase.description, "res_shared_to")
+		assertion.Equal(after.isTemplate, questionnaire.IsTemplate, testCase.description, "is_template")
 
 		assertion.WithinDuration(createdAt, questionnaire.CreatedAt, 2*time.Second, testCase.description, "created_at")
 		assertion.WithinDuration(time.Now(), questionnaire.ModifiedAt, 2*time.Second, testCase.description, "modified_at")
@@ -717,27 +790,475 @@ func updateQuestionnaireTest(t *testing.T) {
 			resSharedTo:  "public",
 		},
 		{
-			title:        "第1回集会らん☆ぷろ募集アンケート",
-			description:  "第1回集会らん☆ぷろ参加者募集",
-			resTimeLimit: null.NewTime(time.Now(), true),
-			resSharedTo:  "public",
+			title:        "第1回集会らん☆ぷろ募集アンケート",
+			description:  "第1回集会らん☆ぷろ参加者募集",
+			resTimeLimit: null.NewTime(time.Now(), true),
+			resSharedTo:  "public",
+		},
+	}
+
+	for _, arg := range invalidTestCases {
+		ctx := context.Background()
+
+		err := questionnaireImpl.UpdateQuestionnaire(ctx, arg.title, arg.description, arg.resTimeLimit, arg.resSharedTo, false, invalidQuestionnaireID)
+		if !errors.Is(err, ErrNoRecordUpdated) {
+			if err == nil {
+				t.Errorf("Succeeded with invalid questionnaireID")
+			} else {
+				t.Errorf("failed to update questionnaire(invalid questionnireID): %w", err)
+			}
+		}
+	}
+}
+
+func updateQuestionnaireModifiedAtTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	invalidQuestionnaireID := 1000
+	for {
+		err := db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", invalidQuestionnaireID).
+			First(&Questionnaires{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			t.Errorf("failed to get questionnaire(make invalid questionnaireID): %v", err)
+			break
+		}
+
+		invalidQuestionnaireID *= 10
+	}
+
+	modifiedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	questionnaire := Questionnaires{
+		Title:        "第1回集会らん☆ぷろ募集アンケート",
+		Description:  "第1回集会らん☆ぷろ参加者募集",
+		ResTimeLimit: null.NewTime(time.Time{}, false),
+		ResSharedTo:  "public",
+	}
+	err := db.
+		Session(&gorm.Session{NewDB: true}).
+		Create(&questionnaire).Error
+	if err != nil {
+		t.Errorf("failed to create questionnaire: %v", err)
+	}
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&Questionnaires{}).
+		Where("id = ?", questionnaire.ID).
+		UpdateColumn("modified_at", modifiedAt).Error
+	if err != nil {
+		t.Errorf("failed to update modified_at: %v", err)
+	}
+
+	type args struct {
+		questionnaireID    int
+		expectedModifiedAt null.Time
+	}
+	type expect struct {
+		isErr bool
+		err   error
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "modified_at: valid",
+			args: args{
+				questionnaireID:    questionnaire.ID,
+				expectedModifiedAt: null.TimeFrom(modifiedAt),
+			},
+		},
+		{
+			description: "modified_at: already updated",
+			args: args{
+				questionnaireID:    questionnaire.ID,
+				expectedModifiedAt: null.TimeFrom(modifiedAt),
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrConflict,
+			},
+		},
+		{
+			description: "modified_at: updated within the same second",
+			args: args{
+				questionnaireID:    questionnaire.ID,
+				expectedModifiedAt: null.TimeFrom(modifiedAt.Add(time.Microsecond)),
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrConflict,
+			},
+		},
+		{
+			description: "modified_at: not specified",
+			args: args{
+				questionnaireID:    questionnaire.ID,
+				expectedModifiedAt: null.NewTime(time.Time{}, false),
+			},
+		},
+		{
+			description: "questionnaireID: invalid",
+			args: args{
+				questionnaireID:    invalidQuestionnaireID,
+				expectedModifiedAt: null.TimeFrom(modifiedAt),
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrRecordNotFound,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+
+		actualModifiedAt, err := questionnaireImpl.UpdateQuestionnaireModifiedAt(ctx, testCase.args.questionnaireID, testCase.args.expectedModifiedAt)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			if !errors.Is(err, testCase.expect.err) {
+				t.Errorf("invalid error(%s): expected: %+v, actual: %+v", testCase.description, testCase.expect.err, err)
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		assertion.WithinDuration(time.Now(), actualModifiedAt, 2*time.Second, testCase.description, "modified_at")
+
+		var actualQuestionnaire Questionnaires
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", testCase.args.questionnaireID).
+			First(&actualQuestionnaire).Error
+		if err != nil {
+			t.Errorf("failed to get questionnaire(%s): %v", testCase.description, err)
+		}
+
+		assertion.WithinDuration(actualModifiedAt, actualQuestionnaire.ModifiedAt, 0, testCase.description, "modified_at in db")
+	}
+}
+
+func updateQuestionnaireAnonymousTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	invalidQuestionnaireID := 1000
+	for {
+		err := db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", invalidQuestionnaireID).
+			First(&Questionnaires{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			t.Errorf("failed to get questionnaire(make invalid questionnaireID): %v", err)
+			break
+		}
+
+		invalidQuestionnaireID *= 10
+	}
+
+	type args struct {
+		validID         bool
+		isAnonymous     bool
+		beforeAnonymous bool
+		hasRespondent   bool
+		hasHistory      bool
+	}
+	type expect struct {
+		isErr bool
+		err   error
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "匿名にできる",
+			args: args{
+				validID:     true,
+				isAnonymous: true,
+			},
+		},
+		{
+			description: "回答があっても匿名にできる",
+			args: args{
+				validID:       true,
+				isAnonymous:   true,
+				hasRespondent: true,
+			},
+		},
+		{
+			description: "回答の削除の記録があっても匿名にでき、記録から回答者が消える",
+			args: args{
+				validID:       true,
+				isAnonymous:   true,
+				hasRespondent: true,
+				hasHistory:    true,
+			},
+		},
+		{
+			description: "回答がなければ匿名を解除できる",
+			args: args{
+				validID:         true,
+				beforeAnonymous: true,
+			},
+		},
+		{
+			description: "回答があれば匿名を解除できない",
+			args: args{
+				validID:         true,
+				beforeAnonymous: true,
+				hasRespondent:   true,
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrAnonymityLocked,
+			},
+		},
+		{
+			description: "変更がなくてもエラーなし",
+			args: args{
+				validID:         true,
+				isAnonymous:     true,
+				beforeAnonymous: true,
+				hasRespondent:   true,
+			},
+		},
+		{
+			description: "questionnaireID: invalid",
+			args: args{
+				isAnonymous: true,
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrRecordNotFound,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+		require.NoError(t, err)
+		if testCase.args.beforeAnonymous {
+			err = questionnaireImpl.UpdateQuestionnaireAnonymous(ctx, questionnaireID, true)
+			require.NoError(t, err)
+		}
+		if testCase.args.hasRespondent {
+			responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+			require.NoError(t, err)
+
+			if testCase.args.hasHistory {
+				// 匿名にする前に削除された回答の監査ログとWebhookの送信
+				before := RespondentDetail{
+					ResponseID:      responseID,
+					TraqID:          userTwo,
+					QuestionnaireID: questionnaireID,
+				}
+				err = auditImpl.InsertAuditEvent(ctx, userTwo, questionnaireID, AuditTargetResponse, responseID, AuditActionDelete, before, nil)
+				require.NoError(t, err)
+
+				_, err = webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/deleted", "secret", []string{WebhookEventResponseDeleted})
+				require.NoError(t, err)
+				err = webhookDeliveryImpl.InsertWebhookDeliveries(ctx, questionnaireID, WebhookEventResponseDeleted, fmt.Sprintf(`{"event":"response.deleted","data":{"responseID":%d,"traqID":"%s"}}`, responseID, userTwo))
+				require.NoError(t, err)
+			}
+		}
+		if !testCase.args.validID {
+			questionnaireID = invalidQuestionnaireID
+		}
+
+		err = questionnaireImpl.UpdateQuestionnaireAnonymous(ctx, questionnaireID, testCase.args.isAnonymous)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			if !errors.Is(err, testCase.expect.err) {
+				t.Errorf("invalid error(%s): expected: %+v, actual: %+v", testCase.description, testCase.expect.err, err)
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		var actualQuestionnaire Questionnaires
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", questionnaireID).
+			First(&actualQuestionnaire).Error
+		if err != nil {
+			t.Errorf("failed to get questionnaire(%s): %v", testCase.description, err)
+		}
+
+		assertion.Equal(testCase.args.isAnonymous, actualQuestionnaire.IsAnonymous, testCase.description, "is_anonymous")
+		if testCase.args.isAnonymous {
+			assertion.Len(actualQuestionnaire.AnonymousSalt.String, 64, testCase.description, "anonymous_salt")
+		}
+
+		if !testCase.args.hasRespondent {
+			continue
+		}
+
+		// 匿名にする前の回答者もtraQIDが残らず、ハッシュで回答者かを確認できる
+		var respondents []Respondents
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("questionnaire_id = ?", questionnaireID).
+			Find(&respondents).Error
+		if err != nil {
+			t.Errorf("failed to get respondents(%s): %v", testCase.description, err)
+		}
+		for _, respondent := range respondents {
+			assertion.Equal("", respondent.UserTraqid, testCase.description, "user_traqid")
+			assertion.True(respondent.UserHash.Valid, testCase.description, "user_hash")
+		}
+
+		isRespondent, err := respondentImpl.CheckRespondent(ctx, userTwo, questionnaireID)
+		assertion.NoError(err, testCase.description, "CheckRespondent")
+		assertion.True(isRespondent, testCase.description, "isRespondent")
+
+		if !testCase.args.hasHistory {
+			continue
+		}
+
+		// 匿名にする前の監査ログとWebhookの送信の記録にも回答者のtraQIDが残らない
+		auditEvents, err := auditImpl.GetAuditEvents(ctx, questionnaireID)
+		assertion.NoError(err, testCase.description, "GetAuditEvents")
+		if assertion.Len(auditEvents, 1, testCase.description, "audit events") {
+			assertion.Equal("", auditEvents[0].UserTraqid, testCase.description, "audit user_traqid")
+			assertion.NotContains(string(auditEvents[0].Diff), userTwo, testCase.description, "audit diff")
+			assertion.Contains(string(auditEvents[0].Diff), "responseID", testCase.description, "audit diff")
+		}
+
+		var deliveries []WebhookDeliveries
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("webhook_id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&QuestionnaireWebhooks{}).Where("questionnaire_id = ?", questionnaireID).Select("id")).
+			Find(&deliveries).Error
+		if err != nil {
+			t.Errorf("failed to get webhook deliveries(%s): %v", testCase.description, err)
+		}
+		if assertion.Len(deliveries, 1, testCase.description, "webhook deliveries") {
+			assertion.NotContains(deliveries[0].Payload, userTwo, testCase.description, "webhook payload")
+			assertion.Contains(deliveries[0].Payload, "responseID", testCase.description, "webhook payload")
+		}
+	}
+}
+
+func updateQuestionnaireResponseLimitsTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	type args struct {
+		maxResponsesPerUser null.Int
+		maxTotalResponses   null.Int
+	}
+	type test struct {
+		description string
+		args
+	}
+
+	testCases := []test{
+		{
+			description: "上限を設定できる",
+			args: args{
+				maxResponsesPerUser: null.IntFrom(1),
+				maxTotalResponses:   null.IntFrom(10),
+			},
+		},
+		{
+			description: "上限なしにできる",
+			args: args{
+				maxResponsesPerUser: null.NewInt(0, false),
+				maxTotalResponses:   null.NewInt(0, false),
+			},
 		},
 	}
 
-	for _, arg := range invalidTestCases {
-		ctx := context.Background()
+	for _, testCase := range testCases {
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+		require.NoError(t, err)
 
-		err := questionnaireImpl.UpdateQuestionnaire(ctx, arg.title, arg.description, arg.resTimeLimit, arg.resSharedTo, invalidQuestionnaireID)
-		if !errors.Is(err, ErrNoRecordUpdated) {
-			if err == nil {
-				t.Errorf("Succeeded with invalid questionnaireID")
-			} else {
-				t.Errorf("failed to update questionnaire(invalid questionnireID): %w", err)
-			}
+		err = questionnaireImpl.UpdateQuestionnaireResponseLimits(ctx, questionnaireID, testCase.args.maxResponsesPerUser, testCase.args.maxTotalResponses)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		var questionnaire Questionnaires
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", questionnaireID).
+			Take(&questionnaire).Error
+		if err != nil {
+			t.Errorf("failed to get questionnaire: %v", err)
+			continue
 		}
+
+		assertion.Equal(testCase.args.maxResponsesPerUser, questionnaire.MaxResponsesPerUser, testCase.description, "max_responses_per_user")
+		assertion.Equal(testCase.args.maxTotalResponses, questionnaire.MaxTotalResponses, testCase.description, "max_total_responses")
 	}
 }
 
+func updateQuestionnaireAnnouncedTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+
+	err = questionnaireImpl.UpdateQuestionnaireAnnounced(ctx, questionnaireID)
+	assertion.NoError(err, "first announcement")
+
+	// 2回は告知しない
+	err = questionnaireImpl.UpdateQuestionnaireAnnounced(ctx, questionnaireID)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "second announcement")
+}
+
+func updateQuestionnaireSummarizedTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+
+	err = questionnaireImpl.UpdateQuestionnaireSummarized(ctx, questionnaireID)
+	assertion.NoError(err, "first summary")
+
+	// 2回は通知しない
+	err = questionnaireImpl.UpdateQuestionnaireSummarized(ctx, questionnaireID)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "second summary")
+}
+
 func deleteQuestionnaireTest(t *testing.T) {
 	t.Helper()
 	t.Parallel()
@@ -890,6 +1411,7 @@ func getQuestionnairesTest(t *testing.T) {
 		search      string
 		pageNum     int
 		nontargeted bool
+		isTemplate  bool
 	}
 	type expect struct {
 		isErr      bool
@@ -1050,12 +1572,23 @@ func getQuestionnairesTest(t *testing.T) {
 				err:   ErrInvalidSortParam,
 			},
 		},
+		{
+			description: "userID:valid, sort:no, search:no, page:1, template",
+			args: args{
+				userID:      questionnairesTestUserID,
+				sort:        "",
+				search:      "",
+				pageNum:     1,
+				nontargeted: false,
+				isTemplate:  true,
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
 		ctx := context.Background()
 
-		questionnaires, pageMax, err := questionnaireImpl.GetQuestionnaires(ctx, testCase.args.userID, testCase.args.sort, testCase.args.search, testCase.args.pageNum, testCase.args.nontargeted)
+		questionnaires, pageMax, err := questionnaireImpl.GetQuestionnaires(ctx, testCase.args.userID, testCase.args.sort, testCase.args.search, testCase.args.pageNum, testCase.args.nontargeted, testCase.args.isTemplate)
 
 		if !testCase.expect.isErr {
 			assertion.NoError(err, testCase.description, "no error")
@@ -1072,7 +1605,7 @@ func getQuestionnairesTest(t *testing.T) {
 		err = db.
 			Session(&gorm.Session{NewDB: true}).
 			Model(&Questionnaires{}).
-			Where("deleted_at IS NULL").
+			Where("deleted_at IS NULL AND is_template = ?", testCase.args.isTemplate).
 			Count(&questionnaireNum).Error
 		if err != nil {
 			t.Errorf("failed to count questionnaire(%s): %w", testCase.description, err)
@@ -1093,6 +1626,7 @@ func getQuestionnairesTest(t *testing.T) {
 
 		for _, questionnaire := range questionnaires {
 			assertion.Regexp(testCase.args.search, questionnaire.Title, testCase.description, "regexp")
+			assertion.Equal(testCase.args.isTemplate, questionnaire.IsTemplate, testCase.description, "is_template")
 		}
 
 		if len(testCase.args.search) == 0 && !testCase.args.nontargeted {
@@ -1552,6 +2086,364 @@ func getTargettedQuestionnairesTest(t *testing.T) {
 	}
 }
 
+func checkQuestionnaireTemplateTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	invalidQuestionnaireID := 1000
+	for {
+		err := db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("id = ?", invalidQuestionnaireID).
+			First(&Questionnaires{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			t.Errorf("failed to get questionnaire(make invalid questionnaireID): %v", err)
+			break
+		}
+
+		invalidQuestionnaireID *= 10
+	}
+
+	templateQuestionnaireID := datas[len(datas)-1].questionnaire.ID
+
+	type args struct {
+		questionnaireID int
+	}
+	type expect struct {
+		isTemplate bool
+		isErr      bool
+		err        error
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+	testCases := []test{
+		{
+			description: "template",
+			args: args{
+				questionnaireID: templateQuestionnaireID,
+			},
+			expect: expect{
+				isTemplate: true,
+			},
+		},
+		{
+			description: "not template",
+			args: args{
+				questionnaireID: datas[0].questionnaire.ID,
+			},
+			expect: expect{
+				isTemplate: false,
+			},
+		},
+		{
+			description: "questionnaireID: invalid",
+			args: args{
+				questionnaireID: invalidQuestionnaireID,
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrRecordNotFound,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+
+		isTemplate, err := questionnaireImpl.CheckQuestionnaireTemplate(ctx, testCase.args.questionnaireID)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			if !errors.Is(err, testCase.expect.err) {
+				t.Errorf("invalid error(%s): expected: %+v, actual: %+v", testCase.description, testCase.expect.err, err)
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		assertion.Equal(testCase.expect.isTemplate, isTemplate, testCase.description, "is_template")
+	}
+}
+
+func checkQuestionnaireAnonymousTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+	anonymousQuestionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+	err = questionnaireImpl.UpdateQuestionnaireAnonymous(ctx, anonymousQuestionnaireID, true)
+	require.NoError(t, err)
+
+	type test struct {
+		description     string
+		questionnaireID int
+		isAnonymous     bool
+		err             error
+	}
+
+	testCases := []test{
+		{
+			description:     "anonymous",
+			questionnaireID: anonymousQuestionnaireID,
+			isAnonymous:     true,
+		},
+		{
+			description:     "not anonymous",
+			questionnaireID: questionnaireID,
+			isAnonymous:     false,
+		},
+		{
+			description:     "questionnaireID: invalid",
+			questionnaireID: -1,
+			err:             ErrRecordNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		isAnonymous, err := questionnaireImpl.CheckQuestionnaireAnonymous(ctx, testCase.questionnaireID)
+		if testCase.err != nil {
+			assertion.ErrorIs(err, testCase.err, testCase.description, "error")
+			continue
+		}
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		assertion.Equal(testCase.isAnonymous, isAnonymous, testCase.description, "is_anonymous")
+	}
+}
+
+func checkQuestionnaireOpenTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	type args struct {
+		resStartAt null.Time
+		isClosed   bool
+	}
+	type test struct {
+		description string
+		args
+		isOpen bool
+	}
+
+	testCases := []test{
+		{
+			description: "回答開始日時なし",
+			isOpen:      true,
+		},
+		{
+			description: "回答開始日時を過ぎている",
+			args: args{
+				resStartAt: null.TimeFrom(time.Now().Add(-time.Hour)),
+			},
+			isOpen: true,
+		},
+		{
+			description: "回答開始前",
+			args: args{
+				resStartAt: null.TimeFrom(time.Now().Add(time.Hour)),
+			},
+			isOpen: false,
+		},
+		{
+			description: "締め切られている",
+			args: args{
+				isClosed: true,
+			},
+			isOpen: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+		require.NoError(t, err)
+		err = questionnaireImpl.UpdateQuestionnaireSchedule(ctx, questionnaireID, testCase.args.resStartAt, testCase.args.isClosed)
+		require.NoError(t, err)
+
+		isOpen, err := questionnaireImpl.CheckQuestionnaireOpen(ctx, questionnaireID)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		assertion.Equal(testCase.isOpen, isOpen, testCase.description, "is_open")
+	}
+
+	_, err := questionnaireImpl.CheckQuestionnaireOpen(ctx, -1)
+	assertion.ErrorIs(err, ErrRecordNotFound, "questionnaireID: invalid")
+}
+
+func getQuestionnairesToAnnounceTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	insertQuestionnaire := func(resStartAt null.Time, isClosed bool, isTemplate bool) int {
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", isTemplate)
+		require.NoError(t, err)
+		err = questionnaireImpl.UpdateQuestionnaireSchedule(ctx, questionnaireID, resStartAt, isClosed)
+		require.NoError(t, err)
+
+		return questionnaireID
+	}
+
+	openedID := insertQuestionnaire(null.TimeFrom(time.Now().Add(-time.Hour)), false, false)
+	announcedID := insertQuestionnaire(null.TimeFrom(time.Now().Add(-time.Hour)), false, false)
+	err := questionnaireImpl.UpdateQuestionnaireAnnounced(ctx, announcedID)
+	require.NoError(t, err)
+	notOpenedID := insertQuestionnaire(null.TimeFrom(time.Now().Add(time.Hour)), false, false)
+	closedID := insertQuestionnaire(null.NewTime(time.Time{}, false), true, false)
+	templateID := insertQuestionnaire(null.NewTime(time.Time{}, false), false, true)
+
+	questionnaires, err := questionnaireImpl.GetQuestionnairesToAnnounce(ctx)
+	require.NoError(t, err)
+
+	questionnaireIDs := make(map[int]struct{}, len(questionnaires))
+	for _, questionnaire := range questionnaires {
+		questionnaireIDs[questionnaire.ID] = struct{}{}
+	}
+
+	assertion.Contains(questionnaireIDs, openedID, "opened")
+	assertion.NotContains(questionnaireIDs, announcedID, "announced")
+	assertion.NotContains(questionnaireIDs, notOpenedID, "not opened")
+	assertion.NotContains(questionnaireIDs, closedID, "closed")
+	assertion.NotContains(questionnaireIDs, templateID, "template")
+}
+
+func getQuestionnairesToRemindTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	insertQuestionnaire := func(resTimeLimit time.Time, isAnnounced bool, disableReminders bool) int {
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.TimeFrom(resTimeLimit), "public", false)
+		require.NoError(t, err)
+		err = questionnaireImpl.UpdateQuestionnaireReminders(ctx, questionnaireID, disableReminders)
+		require.NoError(t, err)
+		if isAnnounced {
+			err = questionnaireImpl.UpdateQuestionnaireAnnounced(ctx, questionnaireID)
+			require.NoError(t, err)
+		}
+
+		return questionnaireID
+	}
+
+	resTimeLimit := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	dueID := insertQuestionnaire(resTimeLimit, true, false)
+	remindedID := insertQuestionnaire(resTimeLimit, true, false)
+	err := reminderImpl.InsertReminder(ctx, remindedID, resTimeLimit, time.Hour)
+	require.NoError(t, err)
+	notDueID := insertQuestionnaire(time.Now().Add(2*time.Hour), true, false)
+	expiredID := insertQuestionnaire(time.Now().Add(-time.Minute), true, false)
+	notAnnouncedID := insertQuestionnaire(resTimeLimit, false, false)
+	disabledID := insertQuestionnaire(resTimeLimit, true, true)
+
+	questionnaires, err := questionnaireImpl.GetQuestionnairesToRemind(ctx, time.Hour)
+	require.NoError(t, err)
+
+	questionnaireIDs := make(map[int]struct{}, len(questionnaires))
+	for _, questionnaire := range questionnaires {
+		questionnaireIDs[questionnaire.ID] = struct{}{}
+	}
+
+	assertion.Contains(questionnaireIDs, dueID, "due")
+	assertion.NotContains(questionnaireIDs, remindedID, "reminded")
+	assertion.NotContains(questionnaireIDs, notDueID, "not due")
+	assertion.NotContains(questionnaireIDs, expiredID, "expired")
+	assertion.NotContains(questionnaireIDs, notAnnouncedID, "not announced")
+	assertion.NotContains(questionnaireIDs, disabledID, "disable reminders")
+
+	// 回答期限が変わると再びリマインドする
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&Questionnaires{}).
+		Where("id = ?", remindedID).
+		UpdateColumn("res_time_limit", resTimeLimit.Add(time.Minute)).Error
+	require.NoError(t, err)
+
+	questionnaires, err = questionnaireImpl.GetQuestionnairesToRemind(ctx, time.Hour)
+	require.NoError(t, err)
+
+	questionnaireIDs = make(map[int]struct{}, len(questionnaires))
+	for _, questionnaire := range questionnaires {
+		questionnaireIDs[questionnaire.ID] = struct{}{}
+	}
+	assertion.Contains(questionnaireIDs, remindedID, "res_time_limit changed")
+}
+
+func getQuestionnairesToSummarizeTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	insertQuestionnaire := func(resTimeLimit null.Time, isAnnounced bool, isClosed bool) int {
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", resTimeLimit, "public", false)
+		require.NoError(t, err)
+		err = questionnaireImpl.UpdateQuestionnaireSchedule(ctx, questionnaireID, null.NewTime(time.Time{}, false), isClosed)
+		require.NoError(t, err)
+		if isAnnounced {
+			err = questionnaireImpl.UpdateQuestionnaireAnnounced(ctx, questionnaireID)
+			require.NoError(t, err)
+		}
+
+		return questionnaireID
+	}
+
+	expiredID := insertQuestionnaire(null.TimeFrom(time.Now().Add(-time.Minute)), true, false)
+	closedID := insertQuestionnaire(null.NewTime(time.Time{}, false), true, true)
+	summarizedID := insertQuestionnaire(null.TimeFrom(time.Now().Add(-time.Minute)), true, false)
+	err := questionnaireImpl.UpdateQuestionnaireSummarized(ctx, summarizedID)
+	require.NoError(t, err)
+	notExpiredID := insertQuestionnaire(null.TimeFrom(time.Now().Add(time.Hour)), true, false)
+	noLimitID := insertQuestionnaire(null.NewTime(time.Time{}, false), true, false)
+	notAnnouncedID := insertQuestionnaire(null.TimeFrom(time.Now().Add(-time.Minute)), false, false)
+
+	questionnaires, err := questionnaireImpl.GetQuestionnairesToSummarize(ctx)
+	require.NoError(t, err)
+
+	questionnaireIDs := make(map[int]struct{}, len(questionnaires))
+	for _, questionnaire := range questionnaires {
+		questionnaireIDs[questionnaire.ID] = struct{}{}
+	}
+
+	assertion.Contains(questionnaireIDs, expiredID, "expired")
+	assertion.Contains(questionnaireIDs, closedID, "closed")
+	assertion.NotContains(questionnaireIDs, summarizedID, "summarized")
+	assertion.NotContains(questionnaireIDs, notExpiredID, "not expired")
+	assertion.NotContains(questionnaireIDs, noLimitID, "no limit")
+	assertion.NotContains(questionnaireIDs, notAnnouncedID, "not announced")
+
+	for _, questionnaire := range questionnaires {
+		if questionnaire.ID == expiredID {
+			assertion.Equal("public", questionnaire.ResSharedTo, "res_shared_to")
+		}
+	}
+}
+
 func getQuestionnaireLimitTest(t *testing.T) {
 	t.Helper()
 	t.Parallel()
@@ -10,6 +10,7 @@ type IQuestion interface {
 	UpdateQuestion(ctx context.Context, questionnaireID int, pageNum int, questionNum int, questionType string, body string, isRequired bool, questionID int) error
 	DeleteQuestion(ctx context.Context, questionID int) error
 	GetQuestions(ctx context.Context, questionnaireID int) ([]Questions, error)
+	GetQuestion(ctx context.Context, questionID int) (*Questions, error)
 	CheckQuestionAdmin(ctx context.Context, userID string, questionID int) (bool, error)
 	CheckQuestionNum(ctx context.Context, questionnaireID, questionNum int) (bool, error)
 }
@@ -19,25 +19,50 @@ func NewQuestion() *Question {
 
 //Questions questionテーブルの構造体
 type Questions struct {
-	ID              int            `json:"id"                  gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
-	QuestionnaireID int            `json:"questionnaireID"     gorm:"type:int(11);not null"`
-	PageNum         int            `json:"page_num"            gorm:"type:int(11);not null"`
-	QuestionNum     int            `json:"question_num"        gorm:"type:int(11);not null"`
-	Type            string         `json:"type"                gorm:"type:char(20);size:20;not null"`
-	Body            string         `json:"body"                gorm:"type:text;default:NULL"`
-	IsRequired      bool           `json:"is_required"         gorm:"type:tinyint(4);size:4;not null;default:0"`
-	DeletedAt       gorm.DeletedAt `json:"-"          gorm:"type:TIMESTAMP NULL;default:NULL"`
-	CreatedAt       time.Time      `json:"created_at"          gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	Options         []Options      `json:"-"  gorm:"foreignKey:QuestionID"`
-	Responses       []Responses    `json:"-"  gorm:"foreignKey:QuestionID"`
-	ScaleLabels     []ScaleLabels  `json:"-"  gorm:"foreignKey:QuestionID"`
-	Validations     []Validations  `json:"-"  gorm:"foreignKey:QuestionID"`
+	ID              int                  `json:"id"                  gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	QuestionnaireID int                  `json:"questionnaireID"     gorm:"type:int(11);not null"`
+	PageNum         int                  `json:"page_num"            gorm:"type:int(11);not null"`
+	QuestionNum     int                  `json:"question_num"        gorm:"type:int(11);not null"`
+	TypeID          int                  `json:"-"                   gorm:"column:type;type:int(11);not null;index"`
+	Type            string               `json:"type"                gorm:"-"`
+	Body            string               `json:"body"                gorm:"type:text;default:NULL"`
+	IsRequired      bool                 `json:"is_required"         gorm:"type:tinyint(4);size:4;not null;default:0"`
+	DeletedAt       gorm.DeletedAt       `json:"-"          gorm:"type:TIMESTAMP NULL;default:NULL"`
+	CreatedAt       time.Time            `json:"created_at"          gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Options         []Options            `json:"-"  gorm:"foreignKey:QuestionID"`
+	Responses       []Responses          `json:"-"  gorm:"foreignKey:QuestionID"`
+	ScaleLabels     []ScaleLabels        `json:"-"  gorm:"foreignKey:QuestionID"`
+	Validations     []Validations        `json:"-"  gorm:"foreignKey:QuestionID"`
+	Conditions      []QuestionConditions `json:"-"  gorm:"foreignKey:QuestionID"`
 }
 
 // BeforeCreate Update時に自動でmodified_atを現在時刻に
 func (questionnaire *Questions) BeforeCreate(tx *gorm.DB) error {
 	questionnaire.CreatedAt = time.Now()
 
+	// 質問の種類は名前ではなくquestion_typesのIDで保存する
+	info, ok := GetQuestionTypeInfo(questionnaire.Type)
+	if !ok {
+		return fmt.Errorf("unknown question type(%s): %w", questionnaire.Type, ErrInvalidQuestionType)
+	}
+	questionnaire.TypeID = info.ID
+
+	return nil
+}
+
+// AfterFind 取得時にquestion_typesのIDから質問の種類の名前を設定する
+func (questionnaire *Questions) AfterFind(tx *gorm.DB) error {
+	// typeを取得していない場合
+	if questionnaire.TypeID == 0 {
+		return nil
+	}
+
+	info, ok := getQuestionTypeInfoByID(questionnaire.TypeID)
+	if !ok {
+		return fmt.Errorf("unknown question type id(%d): %w", questionnaire.TypeID, ErrInvalidQuestionType)
+	}
+	questionnaire.Type = info.Name
+
 	return nil
 }
 
@@ -59,6 +84,12 @@ func (*Question) InsertQuestion(ctx context.Context, questionnaireID int, pageNu
 		return 0, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
+	// 使われなくなった種類の質問は新しく作れない
+	_, err = checkQuestionTypeActive(ctx, questionType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check question type: %w", err)
+	}
+
 	question := Questions{
 		QuestionnaireID: questionnaireID,
 		PageNum:         pageNum,
@@ -84,11 +115,32 @@ func (*Question) UpdateQuestion(ctx context.Context, questionnaireID int, pageNu
 		return fmt.Errorf("failed to get transaction: %w", err)
 	}
 
+	info, ok := GetQuestionTypeInfo(questionType)
+	if !ok {
+		return fmt.Errorf("unknown question type(%s): %w", questionType, ErrInvalidQuestionType)
+	}
+
+	// 使われなくなった種類の既存の質問は、種類を変えなければ編集できる
+	var currentQuestion Questions
+	err = db.
+		Where("id = ?", questionID).
+		Select("type").
+		Take(&currentQuestion).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get the question type: %w", err)
+	}
+	if currentQuestion.TypeID != info.ID {
+		_, err = checkQuestionTypeActive(ctx, questionType)
+		if err != nil {
+			return fmt.Errorf("failed to check question type: %w", err)
+		}
+	}
+
 	question := map[string]interface{}{
 		"questionnaire_id": questionnaireID,
 		"page_num":         pageNum,
 		"question_num":     questionNum,
-		"type":             questionType,
+		"type":             info.ID,
 		"body":             body,
 		"is_required":      isRequired,
 	}
@@ -146,6 +198,27 @@ func (*Question) GetQuestions(ctx context.Context, questionnaireID int) ([]Quest
 	return questions, nil
 }
 
+// GetQuestion 質問の取得
+func (*Question) GetQuestion(ctx context.Context, questionID int) (*Questions, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var question Questions
+	err = db.
+		Where("id = ?", questionID).
+		First(&question).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question: %w", err)
+	}
+
+	return &question, nil
+}
+
 // CheckQuestionAdmin Questionの管理者か
 func (*Question) CheckQuestionAdmin(ctx context.Context, userID string, questionID int) (bool, error) {
 	db, err := getTx(ctx)
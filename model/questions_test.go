@@ -38,6 +38,7 @@ func TestQuestions(t *testing.T) {
 	t.Run("UpdateQuestion", updateQuestionTest)
 	t.Run("DeleteQuestion", deleteQuestionTest)
 	t.Run("GetQuestions", getQuestionsTest)
+	t.Run("GetQuestion", getQuestionTest)
 	t.Run("CheckQuestionAdmin", checkQuestionAdminTest)
 	t.Run("CheckQuestionNum", checkQuestionNumTest)
 }
@@ -296,7 +297,7 @@ func insertQuestionTest(t *testing.T) {
 			},
 		},
 		{
-			description: "type:Dropdown, required: false",
+			description: "type:Dropdown(inactive), required: false",
 			args: args{
 				Questions: Questions{
 					QuestionnaireID: questionnaireDatas[0].ID,
@@ -307,6 +308,25 @@ func insertQuestionTest(t *testing.T) {
 					IsRequired:      false,
 				},
 			},
+			expect: expect{
+				isErr: true,
+			},
+		},
+		{
+			description: "unknown type",
+			args: args{
+				Questions: Questions{
+					QuestionnaireID: questionnaireDatas[0].ID,
+					PageNum:         1,
+					QuestionNum:     1,
+					Type:            "Unknown",
+					Body:            "自由記述欄",
+					IsRequired:      false,
+				},
+			},
+			expect: expect{
+				isErr: true,
+			},
 		},
 		{
 			description: "type:LinearScale, required: false",
@@ -918,6 +938,70 @@ func getQuestionsTest(t *testing.T) {
 	}
 }
 
+func getQuestionTest(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	question := Questions{
+		QuestionnaireID: questionnaireDatas[0].ID,
+		PageNum:         1,
+		QuestionNum:     100,
+		Type:            "Checkbox",
+		Body:            "getQuestionTest",
+		IsRequired:      true,
+	}
+	err := db.
+		Session(&gorm.Session{NewDB: true}).
+		Create(&question).Error
+	if err != nil {
+		t.Errorf("failed to create question: %v", err)
+		return
+	}
+
+	type test struct {
+		description string
+		questionID  int
+		isErr       bool
+		err         error
+	}
+
+	testCases := []test{
+		{
+			description: "question exists",
+			questionID:  question.ID,
+		},
+		{
+			description: "question does not exist",
+			questionID:  -1,
+			isErr:       true,
+			err:         ErrRecordNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actualQuestion, err := questionImpl.GetQuestion(ctx, testCase.questionID)
+
+		if !testCase.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.err != nil {
+			assertion.ErrorIs(err, testCase.err, testCase.description, "error")
+		}
+		if err != nil {
+			continue
+		}
+
+		assertion.Equal(question.ID, actualQuestion.ID, testCase.description, "id")
+		assertion.Equal(question.QuestionnaireID, actualQuestion.QuestionnaireID, testCase.description, "questionnaire_id")
+		assertion.Equal(question.QuestionNum, actualQuestion.QuestionNum, testCase.description, "question_num")
+		assertion.Equal(question.Type, actualQuestion.Type, testCase.description, "type")
+		assertion.Equal(question.Body, actualQuestion.Body, testCase.description, "body")
+		assertion.Equal(question.IsRequired, actualQuestion.IsRequired, testCase.description, "is_required")
+	}
+}
+
 func checkQuestionAdminTest(t *testing.T) {
 	t.Helper()
 	t.Parallel()
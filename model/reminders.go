@@ -0,0 +1,13 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// IReminder ReminderのRepository
+type IReminder interface {
+	InsertReminder(ctx context.Context, questionnaireID int, resTimeLimit time.Time, offset time.Duration) error
+}
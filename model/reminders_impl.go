@@ -0,0 +1,53 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// Reminder ReminderRepositoryの実装
+type Reminder struct{}
+
+// NewReminder Reminderのコンストラクター
+func NewReminder() *Reminder {
+	return new(Reminder)
+}
+
+// QuestionnaireReminders questionnaire_remindersテーブルの構造体
+// 回答期限のoffset前に送ったリマインドの記録で、回答期限が変わると再びリマインドする
+type QuestionnaireReminders struct {
+	QuestionnaireID int       `gorm:"type:int(11);not null;primaryKey"`
+	ResTimeLimit    time.Time `gorm:"type:timestamp;not null;primaryKey"`
+	OffsetSeconds   int       `gorm:"type:int(11);not null;primaryKey"`
+	CreatedAt       time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// InsertReminder リマインドを送ったことの記録
+// 既に記録されている場合はErrNoRecordUpdatedを返すので、同じトランザクションでリマインドを送れば二重に送らない
+func (*Reminder) InsertReminder(ctx context.Context, questionnaireID int, resTimeLimit time.Time, offset time.Duration) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&QuestionnaireReminders{
+			QuestionnaireID: questionnaireID,
+			ResTimeLimit:    resTimeLimit,
+			OffsetSeconds:   int(offset / time.Second),
+			CreatedAt:       time.Now(),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to insert reminder: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestInsertReminder(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	resTimeLimit := time.Now().Add(time.Hour).Truncate(time.Second)
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.TimeFrom(resTimeLimit), "public", false)
+	require.NoError(t, err)
+
+	err = reminderImpl.InsertReminder(ctx, questionnaireID, resTimeLimit, time.Hour)
+	assertion.NoError(err, "first reminder")
+
+	// 同じリマインドは二度記録しない
+	err = reminderImpl.InsertReminder(ctx, questionnaireID, resTimeLimit, time.Hour)
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "same reminder")
+
+	err = reminderImpl.InsertReminder(ctx, questionnaireID, resTimeLimit, 24*time.Hour)
+	assertion.NoError(err, "another offset")
+
+	err = reminderImpl.InsertReminder(ctx, questionnaireID, resTimeLimit.Add(time.Hour), time.Hour)
+	assertion.NoError(err, "another res_time_limit")
+}
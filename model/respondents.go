@@ -4,6 +4,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"gopkg.in/guregu/null.v4"
 )
@@ -12,11 +13,18 @@ import (
 type IRespondent interface {
 	InsertRespondent(ctx context.Context, userID string, questionnaireID int, submittedAt null.Time) (int, error)
 	UpdateSubmittedAt(ctx context.Context, responseID int) error
+	UpdateRespondentModifiedAt(ctx context.Context, responseID int, expectedModifiedAt null.Time) (time.Time, error)
 	DeleteRespondent(ctx context.Context, responseID int) error
 	GetRespondent(ctx context.Context, responseID int) (*Respondents, error)
 	GetRespondentInfos(ctx context.Context, userID string, questionnaireIDs ...int) ([]RespondentInfo, error)
-	GetRespondentDetail(ctx context.Context, responseID int) (RespondentDetail, error)
+	GetRespondentDetail(ctx context.Context, responseID int, revision null.Int) (RespondentDetail, error)
 	GetRespondentDetails(ctx context.Context, questionnaireID int, sort string) ([]RespondentDetail, error)
+	GetSortedResponseIDs(ctx context.Context, questionnaireID int, sort string) ([]int, error)
+	GetRespondentDetailsByResponseIDs(ctx context.Context, questionnaireID int, responseIDs []int) ([]RespondentDetail, error)
 	GetRespondentsUserIDs(ctx context.Context, questionnaireIDs []int) ([]Respondents, error)
+	GetNonRespondentUserIDs(ctx context.Context, questionnaireID int, userIDs []string) ([]string, error)
 	CheckRespondent(ctx context.Context, userID string, questionnaireID int) (bool, error)
+	CheckAnonymousRespondent(ctx context.Context, userID string, responseID int) (bool, error)
+	CheckResponseLimits(ctx context.Context, userID string, questionnaireID int) error
+	GetRespondentCount(ctx context.Context, questionnaireID int) (int, error)
 }
@@ -2,6 +2,9 @@ package model
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
@@ -12,6 +15,7 @@ import (
 
 	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Respondent RespondentRepositoryの実装
@@ -27,7 +31,8 @@ type Respondents struct {
 	ResponseID      int            `json:"responseID" gorm:"column:response_id;type:int(11) AUTO_INCREMENT;not null;primaryKey"`
 	QuestionnaireID int            `json:"questionnaireID" gorm:"type:int(11);not null"`
 	UserTraqid      string         `json:"user_traq_id,omitempty" gorm:"type:varchar(32);size:32;default:NULL"`
-	ModifiedAt      time.Time      `json:"modified_at,omitempty" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UserHash        null.String    `json:"-" gorm:"type:char(64);default:NULL"`
+	ModifiedAt      time.Time      `json:"modified_at,omitempty" gorm:"type:timestamp(6);not null;default:CURRENT_TIMESTAMP(6)"`
 	SubmittedAt     null.Time      `json:"submitted_at,omitempty" gorm:"type:TIMESTAMP NULL;default:NULL"`
 	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"type:TIMESTAMP NULL;default:NULL"`
 	Responses       []Responses    `json:"-"  gorm:"foreignKey:ResponseID;references:ResponseID"`
@@ -59,18 +64,29 @@ type RespondentDetail struct {
 	ResponseID      int            `json:"responseID,omitempty"`
 	TraqID          string         `json:"traqID,omitempty"`
 	QuestionnaireID int            `json:"questionnaireID,omitempty"`
+	Revision        int            `json:"revision,omitempty"`
 	SubmittedAt     null.Time      `json:"submitted_at,omitempty"`
 	ModifiedAt      time.Time      `json:"modified_at,omitempty"`
 	Responses       []ResponseBody `json:"body"`
 }
 
 //InsertRespondent 回答の追加
+// 匿名のアンケートでは回答者のtraQIDの代わりにハッシュを記録する
 func (*Respondent) InsertRespondent(ctx context.Context, userID string, questionnaireID int, submittedAt null.Time) (int, error) {
 	db, err := getTx(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get tx: %w", err)
 	}
 
+	var questionnaire Questionnaires
+	err = db.
+		Where("id = ?", questionnaireID).
+		Select("is_anonymous", "anonymous_salt").
+		Take(&questionnaire).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
 	var respondent Respondents
 	if submittedAt.Valid {
 		respondent = Respondents{
@@ -85,6 +101,11 @@ func (*Respondent) InsertRespondent(ctx context.Context, userID string, question
 		}
 	}
 
+	if questionnaire.IsAnonymous {
+		respondent.UserTraqid = ""
+		respondent.UserHash = null.StringFrom(hashAnonymousRespondent(questionnaire.AnonymousSalt.String, userID))
+	}
+
 	err = db.Create(&respondent).Error
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert a respondent record: %w", err)
@@ -111,6 +132,44 @@ func (*Respondent) UpdateSubmittedAt(ctx context.Context, responseID int) error
 	return nil
 }
 
+// UpdateRespondentModifiedAt 回答の更新日時を現在時刻にする
+// expectedModifiedAtが指定されている場合は、更新日時がexpectedModifiedAtのときのみ更新する
+// 同じ回答への更新はトランザクションが終わるまで待たされる
+func (*Respondent) UpdateRespondentModifiedAt(ctx context.Context, responseID int, expectedModifiedAt null.Time) (time.Time, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var respondent Respondents
+	err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("response_id = ?", responseID).
+		Select("modified_at").
+		Take(&respondent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, ErrRecordNotFound
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get the respondent: %w", err)
+	}
+
+	if expectedModifiedAt.Valid && !respondent.ModifiedAt.Equal(expectedModifiedAt.Time) {
+		return time.Time{}, ErrConflict
+	}
+
+	modifiedAt := nextModifiedAt(respondent.ModifiedAt)
+	err = db.
+		Model(&Respondents{}).
+		Where("response_id = ?", responseID).
+		UpdateColumn("modified_at", modifiedAt).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to update modified_at: %w", err)
+	}
+
+	return modifiedAt, nil
+}
+
 // DeleteRespondent 回答の削除
 func (*Respondent) DeleteRespondent(ctx context.Context, responseID int) error {
 	db, err := getTx(ctx)
@@ -167,7 +226,7 @@ func (*Respondent) GetRespondentInfos(ctx context.Context, userID string, questi
 		Table("respondents").
 		Joins("LEFT OUTER JOIN questionnaires ON respondents.questionnaire_id = questionnaires.id").
 		Order("respondents.submitted_at DESC").
-		Where("user_traqid = ? AND respondents.deleted_at IS NULL AND questionnaires.deleted_at IS NULL", userID)
+		Where(respondentUserCondition("respondents")+" AND respondents.deleted_at IS NULL AND questionnaires.deleted_at IS NULL", userID, userID)
 
 	if len(questionnaireIDs) != 0 {
 		questionnaireID := questionnaireIDs[0]
@@ -188,7 +247,8 @@ func (*Respondent) GetRespondentInfos(ctx context.Context, userID string, questi
 }
 
 // GetRespondentDetail 回答のIDから回答の詳細情報を取得
-func (*Respondent) GetRespondentDetail(ctx context.Context, responseID int) (RespondentDetail, error) {
+// revisionを指定した場合はその版の回答を、指定しない場合は最新の回答を返す
+func (*Respondent) GetRespondentDetail(ctx context.Context, responseID int, revision null.Int) (RespondentDetail, error) {
 	db, err := getTx(ctx)
 	if err != nil {
 		return RespondentDetail{}, fmt.Errorf("failed to get tx: %w", err)
@@ -208,45 +268,75 @@ func (*Respondent) GetRespondentDetail(ctx context.Context, responseID int) (Res
 		return RespondentDetail{}, fmt.Errorf("failed to get respondent: %w", err)
 	}
 
+	respondentDetail := RespondentDetail{
+		ResponseID:      responseID,
+		TraqID:          respondent.UserTraqid,
+		QuestionnaireID: respondent.QuestionnaireID,
+		ModifiedAt:      respondent.ModifiedAt,
+		SubmittedAt:     respondent.SubmittedAt,
+	}
+
+	responseRevision := ResponseRevisions{}
+	query := db.
+		Session(&gorm.Session{}).
+		Where("response_id = ?", responseID)
+	if revision.Valid {
+		query = query.Where("revision = ?", revision.Int64)
+	} else {
+		query = query.Order("revision DESC")
+	}
+	err = query.
+		Take(&responseRevision).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return RespondentDetail{}, fmt.Errorf("failed to get response revision: %w", err)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) && revision.Valid {
+		return RespondentDetail{}, ErrRecordNotFound
+	}
+	if err == nil {
+		respondentDetail.Revision = responseRevision.Revision
+	}
+
+	// 過去の版の回答は論理削除されている
+	if revision.Valid {
+		respondentDetail.ModifiedAt = responseRevision.CreatedAt
+		respondentDetail.SubmittedAt = responseRevision.SubmittedAt
+	}
+
 	questions := []Questions{}
 	err = db.
 		Where("questionnaire_id = ?", respondent.QuestionnaireID).
 		Preload("Responses", func(db *gorm.DB) *gorm.DB {
+			if revision.Valid {
+				db = db.
+					Unscoped().
+					Where("revision = ?", revision.Int64)
+			}
+
 			return db.
 				Select("QuestionID", "Body").
 				Where("response_id = ?", responseID)
 		}).
-		Select("ID", "Type").
+		Select("ID", "TypeID").
 		Find(&questions).Error
 	if err != nil {
 		return RespondentDetail{}, fmt.Errorf("failed to get questions: %w", err)
 	}
 
-	respondentDetail := RespondentDetail{
-		ResponseID:      responseID,
-		TraqID:          respondent.UserTraqid,
-		QuestionnaireID: respondent.QuestionnaireID,
-		ModifiedAt:      respondent.ModifiedAt,
-		SubmittedAt:     respondent.SubmittedAt,
-	}
-
 	for _, question := range questions {
 		responseBody := ResponseBody{
 			QuestionID:   question.ID,
 			QuestionType: question.Type,
 		}
 
-		switch question.Type {
-		case "MultipleChoice", "Checkbox", "Dropdown":
+		if info, _ := GetQuestionTypeInfo(question.Type); info.HasOptions {
 			for _, response := range question.Responses {
 				responseBody.OptionResponse = append(responseBody.OptionResponse, response.Body.String)
 			}
-		default:
-			if len(question.Responses) == 0 {
-				responseBody.Body = null.NewString("", false)
-			} else {
-				responseBody.Body = question.Responses[0].Body
-			}
+		} else if len(question.Responses) == 0 {
+			responseBody.Body = null.NewString("", false)
+		} else {
+			responseBody.Body = formatResponseBody(question.Type, question.Responses[0].Body)
 		}
 
 		respondentDetail.Responses = append(respondentDetail.Responses, responseBody)
@@ -285,14 +375,162 @@ func (*Respondent) GetRespondentDetails(ctx context.Context, questionnaireID int
 		return []RespondentDetail{}, nil
 	}
 
+	respondentDetails, questionNum, err := getRespondentDetails(db, questionnaireID, respondents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get respondent details: %w", err)
+	}
+
+	respondentDetails, err = sortRespondentDetail(sortNum, questionNum, respondentDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sort RespondentDetails: %w", err)
+	}
+
+	return respondentDetails, nil
+}
+
+// GetSortedResponseIDs アンケートの回答のresponseIDをGetRespondentDetailsと同じ順で取得
+func (*Respondent) GetSortedResponseIDs(ctx context.Context, questionnaireID int, sort string) ([]int, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	respondents := []Respondents{}
+
+	// Note: respondents.submitted_at IS NOT NULLで一時保存の回答を除外している
+	query := db.
+		Session(&gorm.Session{}).
+		Where("respondents.questionnaire_id = ? AND respondents.submitted_at IS NOT NULL", questionnaireID).
+		Select("ResponseID")
+
+	query, sortNum, err := setRespondentsOrder(query, sort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set order: %w", err)
+	}
+
+	err = query.
+		Find(&respondents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get respondents: %w", err)
+	}
+
+	responseIDs := make([]int, 0, len(respondents))
+	for _, respondent := range respondents {
+		responseIDs = append(responseIDs, respondent.ResponseID)
+	}
+
+	if sortNum == 0 || len(responseIDs) == 0 {
+		return responseIDs, nil
+	}
+
+	// 質問の回答で並べる場合は、並べ替えに使う質問の回答だけを取得する
+	questions := []Questions{}
+	err = db.
+		Where("questionnaire_id = ?", questionnaireID).
+		Order("question_num").
+		Select("ID", "TypeID").
+		Find(&questions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+
+	sortNumAbs := int(math.Abs(float64(sortNum)))
+	if sortNumAbs > len(questions) {
+		return nil, fmt.Errorf("sort param is too large: %d", sortNum)
+	}
+	question := questions[sortNumAbs-1]
+
+	responses := []Responses{}
+	err = db.
+		Where("question_id = ? AND response_id IN (?)", question.ID, responseIDs).
+		Select("ResponseID", "Body").
+		Find(&responses).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get responses: %w", err)
+	}
+
+	responseBodyMap := make(map[int][]string, len(responseIDs))
+	for _, response := range responses {
+		if response.Body.Valid {
+			responseBodyMap[response.ResponseID] = append(responseBodyMap[response.ResponseID], response.Body.String)
+		}
+	}
+
+	respondentDetails := make([]RespondentDetail, 0, len(responseIDs))
+	for _, responseID := range responseIDs {
+		responseBodies := make([]ResponseBody, len(questions))
+		responseBodies[sortNumAbs-1] = newResponseBody(question, responseBodyMap[responseID])
+
+		respondentDetails = append(respondentDetails, RespondentDetail{
+			ResponseID: responseID,
+			Responses:  responseBodies,
+		})
+	}
+
+	respondentDetails, err = sortRespondentDetail(sortNum, len(questions), respondentDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sort RespondentDetails: %w", err)
+	}
+
+	for i, respondentDetail := range respondentDetails {
+		responseIDs[i] = respondentDetail.ResponseID
+	}
+
+	return responseIDs, nil
+}
+
+// GetRespondentDetailsByResponseIDs 指定した回答の詳細情報を指定した順で取得
+func (*Respondent) GetRespondentDetailsByResponseIDs(ctx context.Context, questionnaireID int, responseIDs []int) ([]RespondentDetail, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	if len(responseIDs) == 0 {
+		return []RespondentDetail{}, nil
+	}
+
+	respondents := []Respondents{}
+
+	// Note: respondents.submitted_at IS NOT NULLで一時保存の回答を除外している
+	err = db.
+		Where("respondents.questionnaire_id = ? AND respondents.submitted_at IS NOT NULL AND respondents.response_id IN (?)", questionnaireID, responseIDs).
+		Select("ResponseID", "UserTraqid", "ModifiedAt", "SubmittedAt").
+		Find(&respondents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get respondents: %w", err)
+	}
+
+	if len(respondents) == 0 {
+		return []RespondentDetail{}, nil
+	}
+
+	orderMap := make(map[int]int, len(responseIDs))
+	for i, responseID := range responseIDs {
+		orderMap[responseID] = i
+	}
+	sort.Slice(respondents, func(i, j int) bool {
+		return orderMap[respondents[i].ResponseID] < orderMap[respondents[j].ResponseID]
+	})
+
+	respondentDetails, _, err := getRespondentDetails(db, questionnaireID, respondents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get respondent details: %w", err)
+	}
+
+	return respondentDetails, nil
+}
+
+// getRespondentDetails 回答者の一覧から回答の詳細情報を回答者と同じ順で作る
+// 並べ替えに使うため、質問の数も返す
+func getRespondentDetails(db *gorm.DB, questionnaireID int, respondents []Respondents) ([]RespondentDetail, int, error) {
 	responseIDs := make([]int, 0, len(respondents))
 	for _, respondent := range respondents {
 		responseIDs = append(responseIDs, respondent.ResponseID)
 	}
 
 	respondentDetails := make([]RespondentDetail, 0, len(respondents))
-	respondentDetailMap := make(map[int]*RespondentDetail, len(respondents))
-	for i, respondent := range respondents {
+	for _, respondent := range respondents {
 		respondentDetails = append(respondentDetails, RespondentDetail{
 			ResponseID:      respondent.ResponseID,
 			TraqID:          respondent.UserTraqid,
@@ -300,12 +538,10 @@ func (*Respondent) GetRespondentDetails(ctx context.Context, questionnaireID int
 			SubmittedAt:     respondent.SubmittedAt,
 			ModifiedAt:      respondent.ModifiedAt,
 		})
-
-		respondentDetailMap[respondent.ResponseID] = &respondentDetails[i]
 	}
 
 	questions := []Questions{}
-	err = db.
+	err := db.
 		Preload("Responses", func(db *gorm.DB) *gorm.DB {
 			return db.
 				Select("ResponseID", "QuestionID", "Body").
@@ -313,10 +549,10 @@ func (*Respondent) GetRespondentDetails(ctx context.Context, questionnaireID int
 		}).
 		Where("questionnaire_id = ?", questionnaireID).
 		Order("question_num").
-		Select("ID", "Type").
+		Select("ID", "TypeID").
 		Find(&questions).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get questions: %w", err)
+		return nil, 0, fmt.Errorf("failed to get questions: %w", err)
 	}
 
 	for _, question := range questions {
@@ -328,37 +564,34 @@ func (*Respondent) GetRespondentDetails(ctx context.Context, questionnaireID int
 		}
 
 		for i := range respondentDetails {
-			responseBodies := responseBodyMap[respondentDetails[i].ResponseID]
-			responseBody := ResponseBody{
-				QuestionID:   question.ID,
-				QuestionType: question.Type,
-			}
-
-			switch responseBody.QuestionType {
-			case "MultipleChoice", "Checkbox", "Dropdown":
-				if responseBodies == nil {
-					responseBody.OptionResponse = []string{}
-				} else {
-					responseBody.OptionResponse = responseBodies
-				}
-			default:
-				if len(responseBodies) == 0 {
-					responseBody.Body = null.NewString("", false)
-				} else {
-					responseBody.Body = null.NewString(responseBodies[0], true)
-				}
-			}
-
+			responseBody := newResponseBody(question, responseBodyMap[respondentDetails[i].ResponseID])
 			respondentDetails[i].Responses = append(respondentDetails[i].Responses, responseBody)
 		}
 	}
 
-	respondentDetails, err = sortRespondentDetail(sortNum, len(questions), respondentDetails)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sort RespondentDetails: %w", err)
+	return respondentDetails, len(questions), nil
+}
+
+// newResponseBody 質問への回答の本文の一覧から回答の詳細情報を作る
+func newResponseBody(question Questions, responseBodies []string) ResponseBody {
+	responseBody := ResponseBody{
+		QuestionID:   question.ID,
+		QuestionType: question.Type,
 	}
 
-	return respondentDetails, nil
+	if info, _ := GetQuestionTypeInfo(responseBody.QuestionType); info.HasOptions {
+		if responseBodies == nil {
+			responseBody.OptionResponse = []string{}
+		} else {
+			responseBody.OptionResponse = responseBodies
+		}
+	} else if len(responseBodies) == 0 {
+		responseBody.Body = null.NewString("", false)
+	} else {
+		responseBody.Body = formatResponseBody(responseBody.QuestionType, null.NewString(responseBodies[0], true))
+	}
+
+	return responseBody
 }
 
 // GetRespondentsUserIDs 回答者のユーザーID取得
@@ -370,8 +603,9 @@ func (*Respondent) GetRespondentsUserIDs(ctx context.Context, questionnaireIDs [
 
 	respondents := []Respondents{}
 
+	// 匿名のアンケートの回答者はtraQIDを記録していないので含まれない
 	err = db.
-		Where("questionnaire_id IN (?)", questionnaireIDs).
+		Where("questionnaire_id IN (?) AND user_traqid IS NOT NULL", questionnaireIDs).
 		Select("questionnaire_id, user_traqid").
 		Find(&respondents).Error
 	if err != nil {
@@ -381,6 +615,63 @@ func (*Respondent) GetRespondentsUserIDs(ctx context.Context, questionnaireIDs [
 	return respondents, nil
 }
 
+// GetNonRespondentUserIDs userIDsのうち、アンケートに回答を送信していないユーザーの取得
+// 一時保存の回答しかないユーザーも含む
+func (*Respondent) GetNonRespondentUserIDs(ctx context.Context, questionnaireID int, userIDs []string) ([]string, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Select("anonymous_salt").
+		Where("id = ?", questionnaireID).
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaire: %w", err)
+	}
+
+	respondents := []Respondents{}
+	err = db.
+		Where("questionnaire_id = ? AND submitted_at IS NOT NULL", questionnaireID).
+		Select("user_traqid, user_hash").
+		Find(&respondents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get respondents: %w", err)
+	}
+
+	// 匿名のアンケートの回答者はtraQIDの代わりにハッシュで確認する
+	responded := make(map[string]struct{}, len(respondents))
+	for _, respondent := range respondents {
+		if respondent.UserTraqid != "" {
+			responded[respondent.UserTraqid] = struct{}{}
+		}
+		if respondent.UserHash.Valid {
+			responded[respondent.UserHash.String] = struct{}{}
+		}
+	}
+
+	nonRespondentUserIDs := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := responded[userID]; ok {
+			continue
+		}
+		if questionnaire.AnonymousSalt.Valid {
+			if _, ok := responded[hashAnonymousRespondent(questionnaire.AnonymousSalt.String, userID)]; ok {
+				continue
+			}
+		}
+
+		nonRespondentUserIDs = append(nonRespondentUserIDs, userID)
+	}
+
+	return nonRespondentUserIDs, nil
+}
+
 // CheckRespondent 回答者かどうかの確認
 func (*Respondent) CheckRespondent(ctx context.Context, userID string, questionnaireID int) (bool, error) {
 	db, err := getTx(ctx)
@@ -389,7 +680,8 @@ func (*Respondent) CheckRespondent(ctx context.Context, userID string, questionn
 	}
 
 	err = db.
-		Where("user_traqid = ? AND questionnaire_id = ?", userID, questionnaireID).
+		Joins("INNER JOIN questionnaires ON questionnaires.id = respondents.questionnaire_id").
+		Where(respondentUserCondition("respondents")+" AND respondents.questionnaire_id = ?", userID, userID, questionnaireID).
 		First(&Respondents{}).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return false, nil
@@ -401,6 +693,143 @@ func (*Respondent) CheckRespondent(ctx context.Context, userID string, questionn
 	return true, nil
 }
 
+// CheckResponseLimits アンケートの回答数の上限に達していないかの確認
+// 一時保存の回答も含めて数える
+// 同じアンケートへの確認はトランザクションが終わるまで待たされるので、同じトランザクションで回答を追加すれば上限を超えない
+func (*Respondent) CheckResponseLimits(ctx context.Context, userID string, questionnaireID int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var questionnaire Questionnaires
+	err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", questionnaireID).
+		Select("max_responses_per_user", "max_total_responses").
+		Take(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRecordNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the questionnaire: %w", err)
+	}
+
+	if questionnaire.MaxResponsesPerUser.Valid {
+		var count int64
+		err = db.
+			Model(&Respondents{}).
+			Joins("INNER JOIN questionnaires ON questionnaires.id = respondents.questionnaire_id").
+			Where(respondentUserCondition("respondents")+" AND respondents.questionnaire_id = ?", userID, userID, questionnaireID).
+			Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to count responses of the user: %w", err)
+		}
+		if count >= questionnaire.MaxResponsesPerUser.Int64 {
+			return ErrTooManyResponsesPerUser
+		}
+	}
+
+	if questionnaire.MaxTotalResponses.Valid {
+		var count int64
+		err = db.
+			Model(&Respondents{}).
+			Where("questionnaire_id = ?", questionnaireID).
+			Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to count responses: %w", err)
+		}
+		if count >= questionnaire.MaxTotalResponses.Int64 {
+			return ErrTooManyResponses
+		}
+	}
+
+	return nil
+}
+
+// CheckAnonymousRespondent 匿名のアンケートの回答の回答者かどうかの確認
+func (*Respondent) CheckAnonymousRespondent(ctx context.Context, userID string, responseID int) (bool, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	err = db.
+		Joins("INNER JOIN questionnaires ON questionnaires.id = respondents.questionnaire_id").
+		Where("respondents.response_id = ? AND respondents.user_hash = SHA2(CONCAT(questionnaires.anonymous_salt, ?), 256)", responseID, userID).
+		Take(&Respondents{}).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get response: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetRespondentCount アンケートの送信済みの回答数の取得
+func (*Respondent) GetRespondentCount(ctx context.Context, questionnaireID int) (int, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	var count int64
+	err = db.
+		Model(&Respondents{}).
+		Where("questionnaire_id = ? AND submitted_at IS NOT NULL", questionnaireID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count respondents: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// respondentUserCondition 回答がユーザーのものかの条件
+// 匿名のアンケートの回答はtraQIDの代わりにアンケートごとのsaltを付けたハッシュで確認する
+// questionnairesテーブルをJOINし、プレースホルダーにはユーザーのtraQIDを2回渡す
+func respondentUserCondition(table string) string {
+	return fmt.Sprintf("(%[1]s.user_traqid = ? OR %[1]s.user_hash = SHA2(CONCAT(questionnaires.anonymous_salt, ?), 256))", table)
+}
+
+// hashAnonymousRespondent 匿名のアンケートの回答者のハッシュ
+// respondentUserConditionのSHA2(CONCAT(salt, traQID), 256)と同じ値になる
+func hashAnonymousRespondent(salt string, userID string) string {
+	hash := sha256.Sum256([]byte(salt + userID))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// generateAnonymousSalt 匿名のアンケートのsaltの生成
+func generateAnonymousSalt() (string, error) {
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return hex.EncodeToString(salt), nil
+}
+
+// formatResponseBody Date,Time,DateTimeの回答を正規化した形式で表示する
+func formatResponseBody(questionType string, body null.String) null.String {
+	if info, _ := GetQuestionTypeInfo(questionType); !info.IsDateTime {
+		return body
+	}
+	if !body.Valid || body.String == "" {
+		return body
+	}
+
+	value, err := NormalizeDateTimeResponse(questionType, body.String)
+	if err != nil {
+		return body
+	}
+
+	return null.StringFrom(value)
+}
+
 func setRespondentsOrder(query *gorm.DB, sort string) (*gorm.DB, int, error) {
 	var sortNum int
 	switch sort {
@@ -438,7 +867,8 @@ func sortRespondentDetail(sortNum int, questionNum int, respondentDetails []Resp
 	sort.Slice(respondentDetails, func(i, j int) bool {
 		bodyI := respondentDetails[i].Responses[sortNumAbs-1]
 		bodyJ := respondentDetails[j].Responses[sortNumAbs-1]
-		if bodyI.QuestionType == "Number" {
+		info, _ := GetQuestionTypeInfo(bodyI.QuestionType)
+		if info.IsNumeric {
 			numi, err := strconv.ParseFloat(bodyI.Body.String, 64)
 			if err != nil {
 				return true
@@ -452,7 +882,19 @@ func sortRespondentDetail(sortNum int, questionNum int, respondentDetails []Resp
 			}
 			return numi < numj
 		}
-		if bodyI.QuestionType == "MultipleChoice" {
+		if info.IsDateTime {
+			timeI, errI := ParseDateTimeResponse(bodyI.QuestionType, bodyI.Body.String)
+			timeJ, errJ := ParseDateTimeResponse(bodyJ.QuestionType, bodyJ.Body.String)
+			// 未回答や解釈できない回答は最後にする
+			if errI != nil || errJ != nil {
+				return errI == nil && errJ != nil
+			}
+			if sortNum < 0 {
+				return timeI.After(timeJ)
+			}
+			return timeI.Before(timeJ)
+		}
+		if info.HasOptions && !info.MultipleSelection {
 			choiceI := ""
 			if len(bodyI.OptionResponse) > 0 {
 				choiceI = bodyI.OptionResponse[0]
@@ -466,7 +908,7 @@ func sortRespondentDetail(sortNum int, questionNum int, respondentDetails []Resp
 			}
 			return choiceI < choiceJ
 		}
-		if bodyI.QuestionType == "Checkbox" {
+		if info.MultipleSelection {
 			selectionsI := strings.Join(bodyI.OptionResponse, ", ")
 			selectionsJ := strings.Join(bodyJ.OptionResponse, ", ")
 			if sortNum < 0 {
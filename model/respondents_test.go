@@ -19,7 +19,7 @@ func TestInsertRespondent(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "private")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -129,7 +129,7 @@ func TestUpdateSubmittedAt(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "private")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -197,13 +197,140 @@ func TestUpdateSubmittedAt(t *testing.T) {
 	}
 }
 
+func TestUpdateRespondentModifiedAt(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
+	require.NoError(t, err)
+
+	type args struct {
+		validresponseID     bool
+		updated             bool
+		updatedInSameSecond bool
+		isSpecified         bool
+	}
+	type expect struct {
+		isErr bool
+		err   error
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "modified_at: valid",
+			args: args{
+				validresponseID: true,
+				isSpecified:     true,
+			},
+		},
+		{
+			description: "modified_at: not specified",
+			args: args{
+				validresponseID: true,
+			},
+		},
+		{
+			description: "modified_at: already updated",
+			args: args{
+				validresponseID: true,
+				updated:         true,
+				isSpecified:     true,
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrConflict,
+			},
+		},
+		{
+			description: "modified_at: updated within the same second",
+			args: args{
+				validresponseID:     true,
+				updatedInSameSecond: true,
+				isSpecified:         true,
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrConflict,
+			},
+		},
+		{
+			description: "responseID: invalid",
+			args: args{
+				validresponseID: false,
+				isSpecified:     true,
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrRecordNotFound,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), false))
+		require.NoError(t, err)
+		if !testCase.args.validresponseID {
+			responseID = -1
+		}
+
+		modifiedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Model(&Respondents{}).
+			Where("response_id = ?", responseID).
+			UpdateColumn("modified_at", modifiedAt).Error
+		require.NoError(t, err)
+
+		expectedModifiedAt := null.NewTime(time.Time{}, false)
+		if testCase.args.isSpecified {
+			expectedModifiedAt = null.TimeFrom(modifiedAt)
+		}
+		if testCase.args.updated {
+			expectedModifiedAt = null.TimeFrom(modifiedAt.Add(-time.Minute))
+		}
+		if testCase.args.updatedInSameSecond {
+			expectedModifiedAt = null.TimeFrom(modifiedAt.Add(-time.Microsecond))
+		}
+
+		actualModifiedAt, err := respondentImpl.UpdateRespondentModifiedAt(ctx, responseID, expectedModifiedAt)
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+		if err != nil {
+			continue
+		}
+
+		respondent := Respondents{}
+		err = db.
+			Session(&gorm.Session{NewDB: true}).
+			Where("response_id = ?", responseID).
+			First(&respondent).Error
+		assertion.NoError(err, testCase.description, "get respondent")
+
+		assertion.WithinDuration(time.Now(), actualModifiedAt, 2*time.Second, testCase.description, "modified_at")
+		assertion.WithinDuration(actualModifiedAt, respondent.ModifiedAt, 0, testCase.description, "modified_at in db")
+	}
+}
+
 func TestDeleteRespondent(t *testing.T) {
 	t.Parallel()
 
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "private")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -289,7 +416,7 @@ func TestGetRespondent(t *testing.T) {
 		Title:        "第1回集会らん☆ぷろ募集アンケート",
 		Description:  "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！",
 		ResTimeLimit: null.NewTime(time.Now(), false),
-		ResSharedTo:  "private",
+		ResSharedTo:  "administrators",
 	}
 	err := db.
 		Session(&gorm.Session{NewDB: true}).
@@ -390,9 +517,9 @@ func TestGetRespondentInfos(t *testing.T) {
 		args
 		expect
 	}
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第2回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第2回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
-	questionnaireID2, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第2回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID2, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第2回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	questionnaire := Questionnaires{}
@@ -521,7 +648,7 @@ func TestGetRespondentDetail(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "private")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
 	require.NoError(t, err)
 
 	questionnaire := Questionnaires{}
@@ -578,11 +705,11 @@ func TestGetRespondentDetail(t *testing.T) {
 		if !testCase.args.validresponseID {
 			responseID = -1
 		} else {
-			err := responseImpl.InsertResponses(ctx, responseID, testCase.args.responseMetas)
+			err := responseImpl.InsertResponses(ctx, responseID, 1, testCase.args.responseMetas)
 			require.NoError(t, err)
 		}
 
-		respondentDetail, err := respondentImpl.GetRespondentDetail(ctx, responseID)
+		respondentDetail, err := respondentImpl.GetRespondentDetail(ctx, responseID, null.NewInt(0, false))
 		if !testCase.expect.isErr {
 			assertion.NoError(err, testCase.description, "no error")
 		} else if testCase.expect.err != nil {
@@ -610,6 +737,37 @@ func TestGetRespondentDetail(t *testing.T) {
 		assertion.Equal("MultipleChoice", responseBody.QuestionType, testCase.description, "QuestionType2")
 		assertion.Equal("選択肢1", optionResponse, testCase.description, "description2")
 	}
+
+	// 回答を変更した後も過去の版の回答を取得できる
+	responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	revision, err := responseRevisionImpl.InsertResponseRevision(ctx, responseID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	err = responseImpl.InsertResponses(ctx, responseID, revision, []*ResponseMeta{
+		{QuestionID: questionIDs[0], Data: "リマインダーBOTを作った話"},
+	})
+	require.NoError(t, err)
+	err = responseImpl.DeleteResponse(ctx, responseID)
+	require.NoError(t, err)
+	revision, err = responseRevisionImpl.InsertResponseRevision(ctx, responseID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	err = responseImpl.InsertResponses(ctx, responseID, revision, []*ResponseMeta{
+		{QuestionID: questionIDs[0], Data: "作らなかった話"},
+	})
+	require.NoError(t, err)
+
+	respondentDetail, err := respondentImpl.GetRespondentDetail(ctx, responseID, null.NewInt(0, false))
+	require.NoError(t, err)
+	assertion.Equal(2, respondentDetail.Revision, "latest revision")
+	assertion.Equal("作らなかった話", respondentDetail.Responses[0].Body.String, "latest body")
+
+	respondentDetail, err = respondentImpl.GetRespondentDetail(ctx, responseID, null.NewInt(1, true))
+	require.NoError(t, err)
+	assertion.Equal(1, respondentDetail.Revision, "old revision")
+	assertion.Equal("リマインダーBOTを作った話", respondentDetail.Responses[0].Body.String, "old body")
+
+	_, err = respondentImpl.GetRespondentDetail(ctx, responseID, null.NewInt(3, true))
+	assertion.ErrorIs(err, ErrRecordNotFound, "revision not exist")
 }
 
 func TestGetRespondentDetails(t *testing.T) {
@@ -618,7 +776,7 @@ func TestGetRespondentDetails(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "private")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
 	require.NoError(t, err)
 
 	questionnaire := Questionnaires{}
@@ -737,7 +895,7 @@ func TestGetRespondentDetails(t *testing.T) {
 		require.NoError(t, err)
 		responseIDs = append(responseIDs, responseID)
 
-		err = responseImpl.InsertResponses(ctx, responseIDs[i], responseMetasList[i])
+		err = responseImpl.InsertResponses(ctx, responseIDs[i], 1, responseMetasList[i])
 		require.NoError(t, err)
 
 	}
@@ -875,6 +1033,8 @@ func TestGetRespondentDetails(t *testing.T) {
 			assertion.Error(err, testCase.description, "any error")
 		}
 		if err != nil {
+			_, err = respondentImpl.GetSortedResponseIDs(ctx, testCase.args.questionnaireID, testCase.args.sort)
+			assertion.Error(err, testCase.description, "GetSortedResponseIDs error")
 			continue
 		}
 
@@ -883,7 +1043,28 @@ func TestGetRespondentDetails(t *testing.T) {
 			responseID := responseIDs[testCase.expect.sortIdx[i]]
 			assertion.Equal(responseID, respondentDetail.ResponseID, testCase.description, "sort ID")
 		}
+
+		// 並び順だけ先に取得して詳細を後から取得しても同じ結果になる
+		sortedResponseIDs, err := respondentImpl.GetSortedResponseIDs(ctx, testCase.args.questionnaireID, testCase.args.sort)
+		if !assertion.NoError(err, testCase.description, "GetSortedResponseIDs") {
+			continue
+		}
+		assertion.Equal(testCase.expect.length, len(sortedResponseIDs), testCase.description, "sortedResponseIDs length")
+		for i, responseID := range sortedResponseIDs {
+			assertion.Equal(responseIDs[testCase.expect.sortIdx[i]], responseID, testCase.description, "sorted response ID")
+		}
+
+		respondentDetailsByResponseIDs, err := respondentImpl.GetRespondentDetailsByResponseIDs(ctx, testCase.args.questionnaireID, sortedResponseIDs)
+		if !assertion.NoError(err, testCase.description, "GetRespondentDetailsByResponseIDs") {
+			continue
+		}
+		assertion.Equal(respondentDetails, respondentDetailsByResponseIDs, testCase.description, "respondentDetails by responseIDs")
 	}
+
+	// 一時保存の回答はresponseIDを指定しても取得しない
+	respondentDetails, err := respondentImpl.GetRespondentDetailsByResponseIDs(ctx, questionnaireID, []int{responseIDs[3]})
+	assertion.NoError(err, "draft response")
+	assertion.Empty(respondentDetails, "draft response")
 }
 
 func TestGetRespondentsUserIDs(t *testing.T) {
@@ -907,7 +1088,7 @@ func TestGetRespondentsUserIDs(t *testing.T) {
 	}
 	questionnaireIDs := make([]int, 0, 3)
 	for i := 0; i < 3; i++ {
-		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 		require.NoError(t, err)
 		questionnaireIDs = append(questionnaireIDs, questionnaireID)
 	}
@@ -995,7 +1176,7 @@ func TestTestCheckRespondent(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "private")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -1069,3 +1250,324 @@ func TestTestCheckRespondent(t *testing.T) {
 		assertion.Equal(testCase.expect.isRespondent, isRespondent, testCase.description, "isRespondent")
 	}
 }
+
+func TestCheckAnonymousRespondent(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
+	require.NoError(t, err)
+	err = questionnaireImpl.UpdateQuestionnaireAnonymous(ctx, questionnaireID, true)
+	require.NoError(t, err)
+
+	responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+
+	// 匿名のアンケートの回答者はtraQIDを記録しない
+	respondent, err := respondentImpl.GetRespondent(ctx, responseID)
+	require.NoError(t, err)
+	assertion.Equal("", respondent.UserTraqid, "user_traqid")
+	assertion.True(respondent.UserHash.Valid, "user_hash")
+
+	isRespondent, err := respondentImpl.CheckRespondent(ctx, userTwo, questionnaireID)
+	require.NoError(t, err)
+	assertion.True(isRespondent, "CheckRespondent")
+
+	type test struct {
+		description  string
+		userID       string
+		responseID   int
+		isRespondent bool
+	}
+
+	testCases := []test{
+		{
+			description:  "回答者",
+			userID:       userTwo,
+			responseID:   responseID,
+			isRespondent: true,
+		},
+		{
+			description:  "回答者でない",
+			userID:       userThree,
+			responseID:   responseID,
+			isRespondent: false,
+		},
+		{
+			description:  "responseIDが存在しない",
+			userID:       userTwo,
+			responseID:   -1,
+			isRespondent: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		isRespondent, err := respondentImpl.CheckAnonymousRespondent(ctx, testCase.userID, testCase.responseID)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		assertion.Equal(testCase.isRespondent, isRespondent, testCase.description, "isRespondent")
+	}
+}
+
+func TestGetNonRespondentUserIDs(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
+	require.NoError(t, err)
+	_, err = respondentImpl.InsertRespondent(ctx, userOne, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	// 一時保存の回答は回答済みにしない
+	_, err = respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Time{}, false))
+	require.NoError(t, err)
+
+	anonymousQuestionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "administrators", false)
+	require.NoError(t, err)
+	err = questionnaireImpl.UpdateQuestionnaireAnonymous(ctx, anonymousQuestionnaireID, true)
+	require.NoError(t, err)
+	_, err = respondentImpl.InsertRespondent(ctx, userOne, anonymousQuestionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+
+	type test struct {
+		description     string
+		questionnaireID int
+		userIDs         []string
+		expect          []string
+		isErr           bool
+		err             error
+	}
+
+	testCases := []test{
+		{
+			description:     "回答済みのユーザーを除く",
+			questionnaireID: questionnaireID,
+			userIDs:         []string{userOne, userTwo, userThree},
+			expect:          []string{userTwo, userThree},
+		},
+		{
+			description:     "匿名のアンケートでも回答済みのユーザーを除く",
+			questionnaireID: anonymousQuestionnaireID,
+			userIDs:         []string{userOne, userTwo},
+			expect:          []string{userTwo},
+		},
+		{
+			description:     "対象者がいないので空",
+			questionnaireID: questionnaireID,
+			userIDs:         []string{},
+			expect:          []string{},
+		},
+		{
+			description:     "アンケートが存在しないのでErrRecordNotFound",
+			questionnaireID: -1,
+			userIDs:         []string{userOne},
+			isErr:           true,
+			err:             ErrRecordNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		userIDs, err := respondentImpl.GetNonRespondentUserIDs(ctx, testCase.questionnaireID, testCase.userIDs)
+		if testCase.isErr {
+			assertion.ErrorIs(err, testCase.err, testCase.description, "error")
+			continue
+		}
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		assertion.Equal(testCase.expect, userIDs, testCase.description, "userIDs")
+	}
+}
+
+func TestCheckResponseLimits(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	type args struct {
+		maxResponsesPerUser null.Int
+		maxTotalResponses   null.Int
+		isAnonymous         bool
+		userID              string
+	}
+	type expect struct {
+		err error
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "上限なしなのでエラーなし",
+			args: args{
+				userID: userTwo,
+			},
+		},
+		{
+			description: "ユーザーの回答数が上限に達しているのでErrTooManyResponsesPerUser",
+			args: args{
+				maxResponsesPerUser: null.IntFrom(1),
+				userID:              userTwo,
+			},
+			expect: expect{
+				err: ErrTooManyResponsesPerUser,
+			},
+		},
+		{
+			description: "他のユーザーの回答は数えないのでエラーなし",
+			args: args{
+				maxResponsesPerUser: null.IntFrom(1),
+				userID:              userOne,
+			},
+		},
+		{
+			description: "匿名のアンケートでもユーザーの回答数を数える",
+			args: args{
+				maxResponsesPerUser: null.IntFrom(1),
+				isAnonymous:         true,
+				userID:              userTwo,
+			},
+			expect: expect{
+				err: ErrTooManyResponsesPerUser,
+			},
+		},
+		{
+			description: "全体の回答数が上限に達しているのでErrTooManyResponses",
+			args: args{
+				maxTotalResponses: null.IntFrom(2),
+				userID:            userOne,
+			},
+			expect: expect{
+				err: ErrTooManyResponses,
+			},
+		},
+		{
+			description: "全体の回答数が上限未満なのでエラーなし",
+			args: args{
+				maxTotalResponses: null.IntFrom(3),
+				userID:            userOne,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+		require.NoError(t, err)
+		err = questionnaireImpl.UpdateQuestionnaireResponseLimits(ctx, questionnaireID, testCase.args.maxResponsesPerUser, testCase.args.maxTotalResponses)
+		require.NoError(t, err)
+		if testCase.args.isAnonymous {
+			err = questionnaireImpl.UpdateQuestionnaireAnonymous(ctx, questionnaireID, true)
+			require.NoError(t, err)
+		}
+
+		// 一時保存の回答も数える
+		_, err = respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+		require.NoError(t, err)
+		_, err = respondentImpl.InsertRespondent(ctx, userThree, questionnaireID, null.NewTime(time.Time{}, false))
+		require.NoError(t, err)
+
+		err = respondentImpl.CheckResponseLimits(ctx, testCase.args.userID, questionnaireID)
+		if testCase.expect.err == nil {
+			assertion.NoError(err, testCase.description, "no error")
+		} else {
+			assertion.ErrorIs(err, testCase.expect.err, testCase.description, "error")
+		}
+	}
+}
+
+func TestGetRespondentCount(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	emptyQuestionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	_, err = respondentImpl.InsertRespondent(ctx, userOne, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	_, err = respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	// 一時保存の回答は数えない
+	_, err = respondentImpl.InsertRespondent(ctx, userThree, questionnaireID, null.NewTime(time.Time{}, false))
+	require.NoError(t, err)
+	// 削除済みの回答は数えない
+	deletedResponseID, err := respondentImpl.InsertRespondent(ctx, userThree, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	err = respondentImpl.DeleteRespondent(ctx, deletedResponseID)
+	require.NoError(t, err)
+
+	type args struct {
+		questionnaireID int
+	}
+	type expect struct {
+		isErr bool
+		err   error
+		count int
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "valid",
+			args: args{
+				questionnaireID: questionnaireID,
+			},
+			expect: expect{
+				count: 2,
+			},
+		},
+		{
+			description: "no respondents",
+			args: args{
+				questionnaireID: emptyQuestionnaireID,
+			},
+			expect: expect{
+				count: 0,
+			},
+		},
+		{
+			description: "questionnaireID does not exist",
+			args: args{
+				questionnaireID: -1,
+			},
+			expect: expect{
+				count: 0,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		count, err := respondentImpl.GetRespondentCount(ctx, testCase.args.questionnaireID)
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+		if err != nil {
+			continue
+		}
+
+		assertion.Equal(testCase.expect.count, count, testCase.description, "count")
+	}
+}
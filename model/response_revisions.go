@@ -0,0 +1,15 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import (
+	"context"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// IResponseRevision ResponseRevisionのRepository
+type IResponseRevision interface {
+	InsertResponseRevision(ctx context.Context, responseID int, submittedAt null.Time) (int, error)
+	GetResponseRevisions(ctx context.Context, responseID int) ([]ResponseRevisions, error)
+}
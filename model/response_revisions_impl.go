@@ -0,0 +1,80 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm/clause"
+)
+
+// ResponseRevision ResponseRevisionRepositoryの実装
+type ResponseRevision struct{}
+
+// NewResponseRevision ResponseRevisionのコンストラクター
+func NewResponseRevision() *ResponseRevision {
+	return new(ResponseRevision)
+}
+
+// ResponseRevisions response_revisionsテーブルの構造体
+// 回答の送信・変更ごとの版で、responseテーブルのrevisionが同じ行がその版の回答
+type ResponseRevisions struct {
+	ResponseID  int       `json:"-"            gorm:"type:int(11);not null;primaryKey"`
+	Revision    int       `json:"revision"     gorm:"type:int(11);not null;primaryKey"`
+	SubmittedAt null.Time `json:"submitted_at" gorm:"type:TIMESTAMP NULL;default:NULL"`
+	CreatedAt   time.Time `json:"created_at"   gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// InsertResponseRevision 回答の新しい版の追加
+// 一時保存の版のsubmittedAtはnull
+func (*ResponseRevision) InsertResponseRevision(ctx context.Context, responseID int, submittedAt null.Time) (int, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var latestRevision int
+	err = db.
+		Model(&ResponseRevisions{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("response_id = ?", responseID).
+		Select("COALESCE(MAX(revision), 0)").
+		Row().
+		Scan(&latestRevision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest revision: %w", err)
+	}
+
+	responseRevision := ResponseRevisions{
+		ResponseID:  responseID,
+		Revision:    latestRevision + 1,
+		SubmittedAt: submittedAt,
+		CreatedAt:   time.Now(),
+	}
+	err = db.Create(&responseRevision).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert response revision: %w", err)
+	}
+
+	return responseRevision.Revision, nil
+}
+
+// GetResponseRevisions 回答の版一覧を古い順に取得
+func (*ResponseRevision) GetResponseRevisions(ctx context.Context, responseID int) ([]ResponseRevisions, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	responseRevisions := []ResponseRevisions{}
+	err = db.
+		Where("response_id = ?", responseID).
+		Order("revision").
+		Find(&responseRevisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response revisions: %w", err)
+	}
+
+	return responseRevisions, nil
+}
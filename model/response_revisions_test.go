@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestInsertResponseRevision(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+
+	type test struct {
+		description    string
+		submittedAt    null.Time
+		expectRevision int
+	}
+
+	testCases := []test{
+		{
+			description:    "最初の版は1",
+			submittedAt:    null.NewTime(time.Now(), true),
+			expectRevision: 1,
+		},
+		{
+			description:    "一時保存でも版は増える",
+			submittedAt:    null.NewTime(time.Time{}, false),
+			expectRevision: 2,
+		},
+		{
+			description:    "版は連番",
+			submittedAt:    null.NewTime(time.Now(), true),
+			expectRevision: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		revision, err := responseRevisionImpl.InsertResponseRevision(ctx, responseID, testCase.submittedAt)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		assertion.Equal(testCase.expectRevision, revision, testCase.description, "revision")
+	}
+}
+
+func TestGetResponseRevisions(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+	emptyResponseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+	require.NoError(t, err)
+
+	submittedAts := []null.Time{
+		null.NewTime(time.Time{}, false),
+		null.NewTime(time.Now(), true),
+	}
+	for _, submittedAt := range submittedAts {
+		_, err := responseRevisionImpl.InsertResponseRevision(ctx, responseID, submittedAt)
+		require.NoError(t, err)
+	}
+
+	type test struct {
+		description     string
+		responseID      int
+		expectRevisions []int
+		expectSubmitted []bool
+	}
+
+	testCases := []test{
+		{
+			description:     "古い順に取得できる",
+			responseID:      responseID,
+			expectRevisions: []int{1, 2},
+			expectSubmitted: []bool{false, true},
+		},
+		{
+			description:     "版がなくてもエラーなし",
+			responseID:      emptyResponseID,
+			expectRevisions: []int{},
+			expectSubmitted: []bool{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		responseRevisions, err := responseRevisionImpl.GetResponseRevisions(ctx, testCase.responseID)
+		if !assertion.NoError(err, testCase.description, "no error") {
+			continue
+		}
+
+		actualRevisions := make([]int, 0, len(responseRevisions))
+		actualSubmitted := make([]bool, 0, len(responseRevisions))
+		for _, responseRevision := range responseRevisions {
+			actualRevisions = append(actualRevisions, responseRevision.Revision)
+			actualSubmitted = append(actualSubmitted, responseRevision.SubmittedAt.Valid)
+			assertion.WithinDuration(time.Now(), responseRevision.CreatedAt, 2*time.Second, testCase.description, "created_at")
+		}
+		assertion.Equal(testCase.expectRevisions, actualRevisions, testCase.description, "revisions")
+		assertion.Equal(testCase.expectSubmitted, actualSubmitted, testCase.description, "submitted")
+	}
+}
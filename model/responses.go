@@ -6,6 +6,7 @@ import "context"
 
 // IResponse ResponseのRepository
 type IResponse interface {
-	InsertResponses(ctx context.Context, responseID int, responseMetas []*ResponseMeta) error
+	InsertResponses(ctx context.Context, responseID int, revision int, responseMetas []*ResponseMeta) error
 	DeleteResponse(ctx context.Context, responseID int) error
+	GetQuestionStatistics(ctx context.Context, questionnaireID int) ([]QuestionStatistics, error)
 }
@@ -3,9 +3,13 @@ package model
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
 	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
-	"time"
 )
 
 // Response ResponseRepositoryの実装
@@ -20,6 +24,7 @@ func NewResponse() *Response {
 type Responses struct {
 	ResponseID int            `json:"-" gorm:"type:int(11);not null"`
 	QuestionID int            `json:"-" gorm:"type:int(11);not null"`
+	Revision   int            `json:"-" gorm:"type:int(11);not null;default:1"`
 	Body       null.String    `json:"response" gorm:"type:text;default:NULL"`
 	ModifiedAt time.Time      `json:"-" gorm:"type:timestamp;not null;dafault:CURRENT_TIMESTAMP"`
 	DeletedAt  gorm.DeletedAt `json:"-" gorm:"type:TIMESTAMP NULL;default:NULL"`
@@ -47,7 +52,7 @@ func (*Responses) TableName() string {
 // ResponseBody 質問に対する回答の構造体
 type ResponseBody struct {
 	QuestionID     int         `json:"questionID" gorm:"column:id" validate:"min=0"`
-	QuestionType   string      `json:"question_type" gorm:"column:type" validate:"required,oneof=Text TextArea Number MultipleChoice Checkbox LinearScale"`
+	QuestionType   string      `json:"question_type" gorm:"column:type" validate:"required"`
 	Body           null.String `json:"response" validate:"required"`
 	OptionResponse []string    `json:"option_response" validate:"required_if=QuestionType Checkbox,required_if=QuestionType MultipleChoice,dive,max=50"`
 }
@@ -58,8 +63,43 @@ type ResponseMeta struct {
 	Data       string
 }
 
+// QuestionStatistics 質問ごとの回答の集計結果
+type QuestionStatistics struct {
+	QuestionID    int             `json:"questionID"`
+	QuestionType  string          `json:"question_type"`
+	ResponseCount int             `json:"response_count"`
+	Options       []OptionCount   `json:"options,omitempty"`
+	Histogram     []ValueCount    `json:"histogram,omitempty"`
+	Mean          null.Float      `json:"mean"`
+	Median        null.Float      `json:"median"`
+	StdDev        null.Float      `json:"stddev"`
+	DateTimes     []DateTimeCount `json:"datetimes,omitempty"`
+	Earliest      null.String     `json:"earliest"`
+	Latest        null.String     `json:"latest"`
+}
+
+// OptionCount 選択肢ごとの回答数
+type OptionCount struct {
+	Body       string  `json:"body"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// DateTimeCount 日時ごとの回答数
+type DateTimeCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ValueCount 数値ごとの回答数
+type ValueCount struct {
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
+
 // InsertResponses 質問に対する回答の追加
-func (*Response) InsertResponses(ctx context.Context, responseID int, responseMetas []*ResponseMeta) error {
+// revisionはInsertResponseRevisionで作った回答の版
+func (*Response) InsertResponses(ctx context.Context, responseID int, revision int, responseMetas []*ResponseMeta) error {
 	db, err := getTx(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get transaction: %w", err)
@@ -70,6 +110,7 @@ func (*Response) InsertResponses(ctx context.Context, responseID int, responseMe
 		responses = append(responses, Responses{
 			ResponseID: responseID,
 			QuestionID: responseMeta.QuestionID,
+			Revision:   revision,
 			Body:       null.NewString(responseMeta.Data, true),
 		})
 	}
@@ -101,3 +142,223 @@ func (*Response) DeleteResponse(ctx context.Context, responseID int) error {
 
 	return nil
 }
+
+// GetQuestionStatistics アンケートの質問ごとの回答の集計
+func (*Response) GetQuestionStatistics(ctx context.Context, questionnaireID int) ([]QuestionStatistics, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	// Note: 一時保存・削除済みの回答は集計しない
+	const submittedRespondentCondition = "respondents.response_id = response.response_id AND respondents.submitted_at IS NOT NULL AND respondents.deleted_at IS NULL"
+
+	type questionCount struct {
+		QuestionID    int
+		TypeID        int
+		ResponseCount int
+	}
+	questionCounts := []questionCount{}
+	err = db.
+		Table("question").
+		Joins("LEFT OUTER JOIN response ON response.question_id = question.id AND response.deleted_at IS NULL AND response.body IS NOT NULL AND response.body != ''").
+		Joins("LEFT OUTER JOIN respondents ON "+submittedRespondentCondition).
+		Where("question.questionnaire_id = ? AND question.deleted_at IS NULL", questionnaireID).
+		Group("question.id").
+		Order("question.page_num, question.question_num").
+		Select("question.id AS question_id, question.type AS type_id, COUNT(DISTINCT respondents.response_id) AS response_count").
+		Find(&questionCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count responses: %w", err)
+	}
+
+	type optionCount struct {
+		QuestionID int
+		Body       null.String
+		Count      int
+	}
+	optionCounts := []optionCount{}
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Table("options").
+		Joins("INNER JOIN question ON question.id = options.question_id").
+		Joins("LEFT OUTER JOIN response ON response.question_id = options.question_id AND response.body = options.body AND response.deleted_at IS NULL").
+		Joins("LEFT OUTER JOIN respondents ON "+submittedRespondentCondition).
+		Where("question.questionnaire_id = ? AND question.deleted_at IS NULL", questionnaireID).
+		Where("question.type IN (?)", questionTypeIDs(func(info QuestionTypeInfo) bool {
+			return info.HasOptions
+		})).
+		Group("options.id").
+		Order("options.question_id, options.option_num").
+		Select("options.question_id, options.body, COUNT(respondents.response_id) AS count").
+		Find(&optionCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count options: %w", err)
+	}
+
+	type valueCount struct {
+		QuestionID int
+		TypeID     int
+		Body       string
+		Count      int
+	}
+	valueCounts := []valueCount{}
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Table("response").
+		Joins("INNER JOIN question ON question.id = response.question_id").
+		Joins("INNER JOIN respondents ON "+submittedRespondentCondition).
+		Where("question.questionnaire_id = ? AND question.deleted_at IS NULL", questionnaireID).
+		Where("question.type IN (?)", questionTypeIDs(func(info QuestionTypeInfo) bool {
+			return info.IsNumeric || info.IsDateTime
+		})).
+		Where("response.deleted_at IS NULL AND response.body IS NOT NULL AND response.body != ''").
+		Group("response.question_id, question.type, response.body").
+		Select("response.question_id, question.type AS type_id, response.body, COUNT(*) AS count").
+		Find(&valueCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count values: %w", err)
+	}
+
+	optionCountMap := make(map[int][]OptionCount, len(questionCounts))
+	for _, optionCount := range optionCounts {
+		optionCountMap[optionCount.QuestionID] = append(optionCountMap[optionCount.QuestionID], OptionCount{
+			Body:  optionCount.Body.ValueOrZero(),
+			Count: optionCount.Count,
+		})
+	}
+
+	// "1"と"1.0"のように文字列としては異なる同じ値をまとめる
+	histogramMap := make(map[int]map[float64]int, len(questionCounts))
+	// 日時はタイムゾーンなどの表記の違いをまとめるため、正規化した形式ごとに数える
+	dateTimeCountMap := make(map[int]map[string]int, len(questionCounts))
+	for _, valueCount := range valueCounts {
+		info, _ := getQuestionTypeInfoByID(valueCount.TypeID)
+		if info.IsDateTime {
+			value, err := NormalizeDateTimeResponse(info.Name, valueCount.Body)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := dateTimeCountMap[valueCount.QuestionID]; !ok {
+				dateTimeCountMap[valueCount.QuestionID] = map[string]int{}
+			}
+			dateTimeCountMap[valueCount.QuestionID][value] += valueCount.Count
+			continue
+		}
+
+		value, err := strconv.ParseFloat(valueCount.Body, 64)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := histogramMap[valueCount.QuestionID]; !ok {
+			histogramMap[valueCount.QuestionID] = map[float64]int{}
+		}
+		histogramMap[valueCount.QuestionID][value] += valueCount.Count
+	}
+
+	statistics := make([]QuestionStatistics, 0, len(questionCounts))
+	for _, questionCount := range questionCounts {
+		info, ok := getQuestionTypeInfoByID(questionCount.TypeID)
+		if !ok {
+			return nil, fmt.Errorf("unknown question type id(%d): %w", questionCount.TypeID, ErrInvalidQuestionType)
+		}
+
+		questionStatistics := QuestionStatistics{
+			QuestionID:    questionCount.QuestionID,
+			QuestionType:  info.Name,
+			ResponseCount: questionCount.ResponseCount,
+		}
+
+		switch {
+		case info.HasOptions:
+			options, ok := optionCountMap[questionCount.QuestionID]
+			if !ok {
+				options = []OptionCount{}
+			}
+			for i := range options {
+				if questionCount.ResponseCount != 0 {
+					options[i].Percentage = float64(options[i].Count) / float64(questionCount.ResponseCount) * 100
+				}
+			}
+			questionStatistics.Options = options
+		case info.IsNumeric:
+			histogram := make([]ValueCount, 0, len(histogramMap[questionCount.QuestionID]))
+			for value, count := range histogramMap[questionCount.QuestionID] {
+				histogram = append(histogram, ValueCount{
+					Value: value,
+					Count: count,
+				})
+			}
+			sort.Slice(histogram, func(i, j int) bool {
+				return histogram[i].Value < histogram[j].Value
+			})
+
+			questionStatistics.Histogram = histogram
+			questionStatistics.Mean, questionStatistics.Median, questionStatistics.StdDev = calcHistogramStatistics(histogram)
+		case info.IsDateTime:
+			dateTimes := make([]DateTimeCount, 0, len(dateTimeCountMap[questionCount.QuestionID]))
+			dateTimeMap := make(map[string]time.Time, len(dateTimeCountMap[questionCount.QuestionID]))
+			for value, count := range dateTimeCountMap[questionCount.QuestionID] {
+				dateTimes = append(dateTimes, DateTimeCount{
+					Value: value,
+					Count: count,
+				})
+				dateTimeMap[value], _ = ParseDateTimeResponse(info.Name, value)
+			}
+			sort.Slice(dateTimes, func(i, j int) bool {
+				return dateTimeMap[dateTimes[i].Value].Before(dateTimeMap[dateTimes[j].Value])
+			})
+
+			questionStatistics.DateTimes = dateTimes
+			if len(dateTimes) != 0 {
+				questionStatistics.Earliest = null.StringFrom(dateTimes[0].Value)
+				questionStatistics.Latest = null.StringFrom(dateTimes[len(dateTimes)-1].Value)
+			}
+		}
+
+		statistics = append(statistics, questionStatistics)
+	}
+
+	return statistics, nil
+}
+
+// calcHistogramStatistics 昇順のヒストグラムから平均・中央値・標準偏差を計算する
+func calcHistogramStatistics(histogram []ValueCount) (null.Float, null.Float, null.Float) {
+	total := 0
+	sum := 0.0
+	for _, valueCount := range histogram {
+		total += valueCount.Count
+		sum += valueCount.Value * float64(valueCount.Count)
+	}
+	if total == 0 {
+		return null.NewFloat(0, false), null.NewFloat(0, false), null.NewFloat(0, false)
+	}
+
+	mean := sum / float64(total)
+
+	variance := 0.0
+	for _, valueCount := range histogram {
+		variance += (valueCount.Value - mean) * (valueCount.Value - mean) * float64(valueCount.Count)
+	}
+	stdDev := math.Sqrt(variance / float64(total))
+
+	// 中央値は(total-1)/2番目とtotal/2番目の値の平均
+	lowerIndex, upperIndex := (total-1)/2, total/2
+	var lower, upper float64
+	index := 0
+	for _, valueCount := range histogram {
+		if index <= lowerIndex && lowerIndex < index+valueCount.Count {
+			lower = valueCount.Value
+		}
+		if index <= upperIndex && upperIndex < index+valueCount.Count {
+			upper = valueCount.Value
+			break
+		}
+		index += valueCount.Count
+	}
+	median := (lower + upper) / 2
+
+	return null.NewFloat(mean, true), null.NewFloat(median, true), null.NewFloat(stdDev, true)
+}
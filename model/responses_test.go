@@ -19,7 +19,7 @@ func TestInsertResponses(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -106,7 +106,7 @@ func TestInsertResponses(t *testing.T) {
 		if !testCase.args.validID {
 			responseID = -1
 		}
-		err = responseImpl.InsertResponses(ctx, responseID, testCase.args.responseMetas)
+		err = responseImpl.InsertResponses(ctx, responseID, 1, testCase.args.responseMetas)
 
 		if !testCase.expect.isErr {
 			assertion.NoError(err, testCase.description, "no error")
@@ -130,6 +130,7 @@ func TestInsertResponses(t *testing.T) {
 
 		assertion.Equal(responseID, response.ResponseID, testCase.description, "responseID")
 		assertion.Equal(questionID, response.QuestionID, testCase.description, "questionID")
+		assertion.Equal(1, response.Revision, testCase.description, "revision")
 		assertion.Equal(testCase.args.responseMetas[0].Data, response.Body.ValueOrZero(), testCase.description, "Body")
 		assertion.WithinDuration(time.Now(), response.ModifiedAt, 2*time.Second, testCase.description, "ModifiedAt")
 		assertion.Equal(time.Time{}, response.DeletedAt.Time, 2*time.Second, testCase.description, "DeletedAt")
@@ -142,7 +143,7 @@ func TestDeleteResponse(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -194,7 +195,7 @@ func TestDeleteResponse(t *testing.T) {
 	for _, testCase := range testCases {
 		responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
 		require.NoError(t, err)
-		err = responseImpl.InsertResponses(ctx, responseID, testCase.args.responseMetas)
+		err = responseImpl.InsertResponses(ctx, responseID, 1, testCase.args.responseMetas)
 		require.NoError(t, err)
 		if !testCase.args.validID {
 			responseID = -1
@@ -226,3 +227,135 @@ func TestDeleteResponse(t *testing.T) {
 		assertion.WithinDuration(time.Now(), response.DeletedAt.Time, 2*time.Second)
 	}
 }
+
+func TestGetQuestionStatistics(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	emptyQuestionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
+	require.NoError(t, err)
+
+	textQuestionID, err := questionImpl.InsertQuestion(ctx, questionnaireID, 1, 1, "Text", "発表タイトル", true)
+	require.NoError(t, err)
+	choiceQuestionID, err := questionImpl.InsertQuestion(ctx, questionnaireID, 1, 2, "Checkbox", "参加したい日", true)
+	require.NoError(t, err)
+	for i, option := range []string{"1日目", "2日目", "3日目"} {
+		err = optionImpl.InsertOption(ctx, choiceQuestionID, i+1, option)
+		require.NoError(t, err)
+	}
+	scaleQuestionID, err := questionImpl.InsertQuestion(ctx, questionnaireID, 1, 3, "LinearScale", "満足度", true)
+	require.NoError(t, err)
+
+	responses := [][]*ResponseMeta{
+		{
+			{QuestionID: textQuestionID, Data: "リマインダーBOTを作った話"},
+			{QuestionID: choiceQuestionID, Data: "1日目"},
+			{QuestionID: choiceQuestionID, Data: "2日目"},
+			{QuestionID: scaleQuestionID, Data: "1"},
+		},
+		{
+			{QuestionID: textQuestionID, Data: ""},
+			{QuestionID: choiceQuestionID, Data: "1日目"},
+			{QuestionID: scaleQuestionID, Data: "3"},
+		},
+	}
+	for _, responseMetas := range responses {
+		responseID, err := respondentImpl.InsertRespondent(ctx, userTwo, questionnaireID, null.NewTime(time.Now(), true))
+		require.NoError(t, err)
+		err = responseImpl.InsertResponses(ctx, responseID, 1, responseMetas)
+		require.NoError(t, err)
+	}
+
+	// 一時保存の回答は集計しない
+	responseID, err := respondentImpl.InsertRespondent(ctx, userThree, questionnaireID, null.NewTime(time.Time{}, false))
+	require.NoError(t, err)
+	err = responseImpl.InsertResponses(ctx, responseID, 1, []*ResponseMeta{
+		{QuestionID: choiceQuestionID, Data: "3日目"},
+	})
+	require.NoError(t, err)
+
+	type args struct {
+		questionnaireID int
+	}
+	type expect struct {
+		isErr      bool
+		err        error
+		statistics []QuestionStatistics
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "valid",
+			args: args{
+				questionnaireID: questionnaireID,
+			},
+			expect: expect{
+				statistics: []QuestionStatistics{
+					{
+						QuestionID:    textQuestionID,
+						QuestionType:  "Text",
+						ResponseCount: 1,
+					},
+					{
+						QuestionID:    choiceQuestionID,
+						QuestionType:  "Checkbox",
+						ResponseCount: 2,
+						Options: []OptionCount{
+							{Body: "1日目", Count: 2, Percentage: 100},
+							{Body: "2日目", Count: 1, Percentage: 50},
+							{Body: "3日目", Count: 0, Percentage: 0},
+						},
+					},
+					{
+						QuestionID:    scaleQuestionID,
+						QuestionType:  "LinearScale",
+						ResponseCount: 2,
+						Histogram: []ValueCount{
+							{Value: 1, Count: 1},
+							{Value: 3, Count: 1},
+						},
+						Mean:   null.NewFloat(2, true),
+						Median: null.NewFloat(2, true),
+						StdDev: null.NewFloat(1, true),
+					},
+				},
+			},
+		},
+		{
+			description: "no questions",
+			args: args{
+				questionnaireID: emptyQuestionnaireID,
+			},
+			expect: expect{
+				statistics: []QuestionStatistics{},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		statistics, err := responseImpl.GetQuestionStatistics(ctx, testCase.args.questionnaireID)
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+		if err != nil {
+			continue
+		}
+
+		assertion.Equal(testCase.expect.statistics, statistics, testCase.description, "statistics")
+	}
+}
@@ -0,0 +1,13 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import "context"
+
+// ISystemAdmin SystemAdminのRepository
+type ISystemAdmin interface {
+	InsertSystemAdmins(ctx context.Context, userIDs []string) error
+	DeleteSystemAdmin(ctx context.Context, userID string) error
+	GetSystemAdmins(ctx context.Context) ([]string, error)
+	CheckSystemAdmin(ctx context.Context, userID string) (bool, error)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// SystemAdmin SystemAdminRepositoryの実装
+type SystemAdmin struct{}
+
+// NewSystemAdmin SystemAdminのコンストラクター
+func NewSystemAdmin() *SystemAdmin {
+	return new(SystemAdmin)
+}
+
+// SystemAdmins system_adminsテーブルの構造体
+// 全てのアンケートの管理・回答の閲覧ができるanke-to全体の管理者
+type SystemAdmins struct {
+	UserTraqid string    `gorm:"type:varchar(32);size:32;not null;primaryKey"`
+	CreatedAt  time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// InsertSystemAdmins 全体の管理者を追加
+// 既に管理者であるユーザーは無視する
+func (*SystemAdmin) InsertSystemAdmins(ctx context.Context, userIDs []string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	systemAdmins := make([]SystemAdmins, 0, len(userIDs))
+	for _, userID := range userIDs {
+		systemAdmins = append(systemAdmins, SystemAdmins{
+			UserTraqid: userID,
+		})
+	}
+
+	err = db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&systemAdmins).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert system admins: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSystemAdmin 全体の管理者を削除
+func (*SystemAdmin) DeleteSystemAdmin(ctx context.Context, userID string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Where("user_traqid = ?", userID).
+		Delete(&SystemAdmins{})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to delete system admin: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordDeleted
+	}
+
+	return nil
+}
+
+// GetSystemAdmins 全体の管理者の一覧を取得
+func (*SystemAdmin) GetSystemAdmins(ctx context.Context) ([]string, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	userIDs := []string{}
+	err = db.
+		Model(&SystemAdmins{}).
+		Order("user_traqid").
+		Pluck("user_traqid", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system admins: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// CheckSystemAdmin 全体の管理者かどうかの確認
+func (*SystemAdmin) CheckSystemAdmin(ctx context.Context, userID string) (bool, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var count int64
+	err = db.
+		Model(&SystemAdmins{}).
+		Where("user_traqid = ?", userID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check if system admin: %w", err)
+	}
+
+	return count > 0, nil
+}
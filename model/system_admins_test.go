@@ -0,0 +1,195 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestInsertSystemAdmins(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	type test struct {
+		description  string
+		beforeAdmins []string
+		argAdmins    []string
+		afterAdmins  []string
+		isErr        bool
+	}
+
+	testCases := []test{
+		{
+			description: "管理者を追加できる",
+			argAdmins:   []string{"insertSystemAdmin1"},
+			afterAdmins: []string{"insertSystemAdmin1"},
+		},
+		{
+			description:  "既に管理者でもエラーなし",
+			beforeAdmins: []string{"insertSystemAdmin2"},
+			argAdmins:    []string{"insertSystemAdmin2", "insertSystemAdmin3"},
+			afterAdmins:  []string{"insertSystemAdmin2", "insertSystemAdmin3"},
+		},
+		{
+			description: "追加する管理者がいなくてもエラーなし",
+			argAdmins:   []string{},
+			afterAdmins: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			if len(testCase.beforeAdmins) != 0 {
+				systemAdmins := make([]SystemAdmins, 0, len(testCase.beforeAdmins))
+				for _, userID := range testCase.beforeAdmins {
+					systemAdmins = append(systemAdmins, SystemAdmins{UserTraqid: userID})
+				}
+				err := db.
+					Session(&gorm.Session{}).
+					Create(&systemAdmins).Error
+				if err != nil {
+					t.Errorf("failed to create system admins: %v", err)
+				}
+			}
+
+			err := systemAdminImpl.InsertSystemAdmins(ctx, testCase.argAdmins)
+			if testCase.isErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, userID := range testCase.afterAdmins {
+				var count int64
+				err = db.
+					Session(&gorm.Session{NewDB: true}).
+					Model(&SystemAdmins{}).
+					Where("user_traqid = ?", userID).
+					Count(&count).Error
+				if err != nil {
+					t.Errorf("failed to count system admins: %v", err)
+				}
+				assert.Equal(t, int64(1), count, userID)
+			}
+		})
+	}
+}
+
+func TestDeleteSystemAdmin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	err := systemAdminImpl.InsertSystemAdmins(ctx, []string{"deleteSystemAdmin1"})
+	if err != nil {
+		t.Fatalf("failed to insert system admins: %v", err)
+	}
+
+	type test struct {
+		description string
+		userID      string
+		isErr       bool
+		err         error
+	}
+
+	testCases := []test{
+		{
+			description: "管理者を削除できる",
+			userID:      "deleteSystemAdmin1",
+		},
+		{
+			description: "管理者でないのでErrNoRecordDeleted",
+			userID:      "deleteSystemAdmin2",
+			isErr:       true,
+			err:         ErrNoRecordDeleted,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := systemAdminImpl.DeleteSystemAdmin(ctx, testCase.userID)
+			if !testCase.isErr {
+				assert.NoError(t, err)
+			} else if testCase.err != nil {
+				assert.Equal(t, true, errors.Is(err, testCase.err))
+			} else {
+				assert.Error(t, err)
+			}
+			if err != nil {
+				return
+			}
+
+			isSystemAdmin, err := systemAdminImpl.CheckSystemAdmin(ctx, testCase.userID)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.False(t, isSystemAdmin)
+		})
+	}
+}
+
+func TestGetSystemAdmins(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	err := systemAdminImpl.InsertSystemAdmins(ctx, []string{"getSystemAdmin2", "getSystemAdmin1"})
+	if err != nil {
+		t.Fatalf("failed to insert system admins: %v", err)
+	}
+
+	systemAdmins, err := systemAdminImpl.GetSystemAdmins(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Subset(t, systemAdmins, []string{"getSystemAdmin1", "getSystemAdmin2"})
+	assert.IsNonDecreasing(t, systemAdmins)
+}
+
+func TestCheckSystemAdmin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	err := systemAdminImpl.InsertSystemAdmins(ctx, []string{"checkSystemAdmin1"})
+	if err != nil {
+		t.Fatalf("failed to insert system admins: %v", err)
+	}
+
+	type test struct {
+		description string
+		userID      string
+		expect      bool
+	}
+
+	testCases := []test{
+		{
+			description: "管理者なのでtrue",
+			userID:      "checkSystemAdmin1",
+			expect:      true,
+		},
+		{
+			description: "管理者でないのでfalse",
+			userID:      "checkSystemAdmin2",
+			expect:      false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			isSystemAdmin, err := systemAdminImpl.CheckSystemAdmin(ctx, testCase.userID)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, testCase.expect, isSystemAdmin)
+		})
+	}
+}
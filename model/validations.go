@@ -2,7 +2,11 @@
 
 package model
 
-import "context"
+import (
+	"context"
+
+	"gopkg.in/guregu/null.v4"
+)
 
 // IValidation ValidationのRepository
 type IValidation interface {
@@ -13,4 +17,8 @@ type IValidation interface {
 	CheckNumberValidation(validation Validations, Body string) error
 	CheckTextValidation(validation Validations, Response string) error
 	CheckNumberValid(MinBound, MaxBound string) error
+	CheckDateTimeValidation(validation Validations, questionType string, Body string) error
+	CheckDateTimeValid(questionType string, MinBound, MaxBound string) error
+	CheckSelectionValidation(validation Validations, selections []string) error
+	CheckSelectionValid(minSelections, maxSelections null.Int) error
 }
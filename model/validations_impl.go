@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
 )
 
 // Validation ValidationRepositoryの実装
@@ -17,10 +20,12 @@ func NewValidation() *Validation {
 
 //Validations validationsテーブルの構造体
 type Validations struct {
-	QuestionID   int    `json:"questionID"    gorm:"type:int(11);not null;primaryKey"`
-	RegexPattern string `json:"regex_pattern" gorm:"type:text;default:NULL"`
-	MinBound     string `json:"min_bound"     gorm:"type:text;default:NULL"`
-	MaxBound     string `json:"max_bound"     gorm:"type:text;default:NULL"`
+	QuestionID    int      `json:"questionID"     gorm:"type:int(11);not null;primaryKey"`
+	RegexPattern  string   `json:"regex_pattern"  gorm:"type:text;default:NULL"`
+	MinBound      string   `json:"min_bound"      gorm:"type:text;default:NULL"`
+	MaxBound      string   `json:"max_bound"      gorm:"type:text;default:NULL"`
+	MinSelections null.Int `json:"min_selections" gorm:"type:int(11);default:NULL"`
+	MaxSelections null.Int `json:"max_selections" gorm:"type:int(11);default:NULL"`
 }
 
 // InsertValidation IDを指定してvalidationsを挿入する
@@ -50,10 +55,12 @@ func (*Validation) UpdateValidation(ctx context.Context, questionID int, validat
 		Model(&Validations{}).
 		Where("question_id = ?", questionID).
 		Updates(map[string]interface{}{
-			"question_id":   questionID,
-			"regex_pattern": validation.RegexPattern,
-			"min_bound":     validation.MinBound,
-			"max_bound":     validation.MaxBound,
+			"question_id":    questionID,
+			"regex_pattern":  validation.RegexPattern,
+			"min_bound":      validation.MinBound,
+			"max_bound":      validation.MaxBound,
+			"min_selections": validation.MinSelections,
+			"max_selections": validation.MaxSelections,
 		})
 	err = result.Error
 	if err != nil {
@@ -176,3 +183,150 @@ func (*Validation) CheckNumberValid(MinBound, MaxBound string) error {
 
 	return nil
 }
+
+// dateTimeLayouts 質問の種類ごとの回答として受け付けるISO 8601の形式
+// 先頭の形式を正規化した形式とする
+var dateTimeLayouts = map[string][]string{
+	"Date":     {"2006-01-02"},
+	"Time":     {"15:04:05", "15:04"},
+	"DateTime": {time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04"},
+}
+
+// ParseDateTimeResponse Date,Time,DateTimeの質問への回答を解釈する
+// タイムゾーンのないDateTimeはサーバーのタイムゾーンとして扱う
+func ParseDateTimeResponse(questionType string, body string) (time.Time, error) {
+	layouts, ok := dateTimeLayouts[questionType]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s is not a date time question: %w", questionType, ErrInvalidDateTime)
+	}
+
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, body, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse %s (body: %s): %w", questionType, body, ErrInvalidDateTime)
+}
+
+// FormatDateTimeResponse Date,Time,DateTimeの質問への回答を正規化した形式にする
+func FormatDateTimeResponse(questionType string, t time.Time) string {
+	layouts, ok := dateTimeLayouts[questionType]
+	if !ok {
+		return ""
+	}
+	if questionType == "DateTime" {
+		t = t.Local()
+	}
+
+	return t.Format(layouts[0])
+}
+
+// NormalizeDateTimeResponse Date,Time,DateTimeの質問への回答を解釈し、正規化した形式にする
+func NormalizeDateTimeResponse(questionType string, body string) (string, error) {
+	t, err := ParseDateTimeResponse(questionType, body)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatDateTimeResponse(questionType, t), nil
+}
+
+// CheckDateTimeValidation BodyがISO 8601の日時で、MinBound,MaxBoundを満たしているか
+func (v *Validation) CheckDateTimeValidation(validation Validations, questionType string, Body string) error {
+	if err := v.CheckDateTimeValid(questionType, validation.MinBound, validation.MaxBound); err != nil {
+		return err
+	}
+
+	if Body == "" {
+		return nil
+	}
+	t, err := ParseDateTimeResponse(questionType, Body)
+	if err != nil {
+		return err
+	}
+
+	if validation.MinBound != "" {
+		minBound, _ := ParseDateTimeResponse(questionType, validation.MinBound)
+		if t.Before(minBound) {
+			return fmt.Errorf("failed to meet the boundary value. the date time must be after MinBound (date time: %s, MinBound: %s): %w", Body, validation.MinBound, ErrDateTimeBoundary)
+		}
+	}
+	if validation.MaxBound != "" {
+		maxBound, _ := ParseDateTimeResponse(questionType, validation.MaxBound)
+		if t.After(maxBound) {
+			return fmt.Errorf("failed to meet the boundary value. the date time must be before MaxBound (date time: %s, MaxBound: %s): %w", Body, validation.MaxBound, ErrDateTimeBoundary)
+		}
+	}
+
+	return nil
+}
+
+// CheckDateTimeValid MinBound,MaxBoundが指定されていれば，質問の種類に合った日時か確認する
+func (*Validation) CheckDateTimeValid(questionType string, MinBound, MaxBound string) error {
+	var minBound, maxBound time.Time
+	if MinBound != "" {
+		t, err := ParseDateTimeResponse(questionType, MinBound)
+		if err != nil {
+			return fmt.Errorf("failed to check the boundary value. MinBound is not a date time: %w", err)
+		}
+		minBound = t
+	}
+	if MaxBound != "" {
+		t, err := ParseDateTimeResponse(questionType, MaxBound)
+		if err != nil {
+			return fmt.Errorf("failed to check the boundary value. MaxBound is not a date time: %w", err)
+		}
+		maxBound = t
+	}
+
+	if MinBound != "" && MaxBound != "" && minBound.After(maxBound) {
+		return fmt.Errorf("failed to check the boundary value. MinBound must be before MaxBound (MinBound: %s, MaxBound: %s): %w", MinBound, MaxBound, ErrInvalidDateTime)
+	}
+
+	return nil
+}
+
+// CheckSelectionValidation 選択数がMinSelections,MaxSelectionsを満たしているか
+func (v *Validation) CheckSelectionValidation(validation Validations, selections []string) error {
+	if err := v.CheckSelectionValid(validation.MinSelections, validation.MaxSelections); err != nil {
+		return err
+	}
+
+	count := 0
+	for _, selection := range selections {
+		if selection != "" {
+			count++
+		}
+	}
+
+	// 未回答かどうかはis_requiredで確認する
+	if count == 0 {
+		return nil
+	}
+
+	if validation.MinSelections.Valid && int64(count) < validation.MinSelections.Int64 {
+		return fmt.Errorf("failed to meet the selection count. the count must be greater than or equal to MinSelections (count: %d, MinSelections: %d): %w", count, validation.MinSelections.Int64, ErrSelectionCount)
+	}
+	if validation.MaxSelections.Valid && int64(count) > validation.MaxSelections.Int64 {
+		return fmt.Errorf("failed to meet the selection count. the count must be less than or equal to MaxSelections (count: %d, MaxSelections: %d): %w", count, validation.MaxSelections.Int64, ErrSelectionCount)
+	}
+
+	return nil
+}
+
+// CheckSelectionValid MinSelections,MaxSelectionsが指定されていれば，有効な入力か確認する
+func (*Validation) CheckSelectionValid(minSelections, maxSelections null.Int) error {
+	if minSelections.Valid && minSelections.Int64 < 0 {
+		return fmt.Errorf("failed to check the selection count. MinSelections must not be negative (MinSelections: %d): %w", minSelections.Int64, ErrInvalidSelections)
+	}
+	if maxSelections.Valid && maxSelections.Int64 < 1 {
+		return fmt.Errorf("failed to check the selection count. MaxSelections must be positive (MaxSelections: %d): %w", maxSelections.Int64, ErrInvalidSelections)
+	}
+	if minSelections.Valid && maxSelections.Valid && minSelections.Int64 > maxSelections.Int64 {
+		return fmt.Errorf("failed to check the selection count. MinSelections must be less than MaxSelections (MinSelections: %d, MaxSelections: %d): %w", minSelections.Int64, maxSelections.Int64, ErrInvalidSelections)
+	}
+
+	return nil
+}
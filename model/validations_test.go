@@ -20,7 +20,7 @@ func TestInsertValidation(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -212,7 +212,7 @@ func TestUpdateValidation(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -360,7 +360,7 @@ func TestDeleteValidation(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -458,7 +458,7 @@ func TestGetValidations(t *testing.T) {
 	assertion := assert.New(t)
 	ctx := context.Background()
 
-	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public")
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回メンバー集会でのらん☆ぷろで発表したい人を募集します らん☆ぷろで発表したい人あつまれー！", null.NewTime(time.Now(), false), "public", false)
 	require.NoError(t, err)
 
 	err = administratorImpl.InsertAdministrators(ctx, questionnaireID, []string{userOne})
@@ -851,3 +851,465 @@ func TestCheckNumberValid(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckSelectionValidation(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type args struct {
+		validation Validations
+		selections []string
+	}
+
+	type expect struct {
+		isErr bool
+		err   error
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+	testCases := []test{
+		{
+			description: "within bounds",
+			args: args{
+				validation: Validations{
+					MinSelections: null.IntFrom(1),
+					MaxSelections: null.IntFrom(2),
+				},
+				selections: []string{"a", "b"},
+			},
+		},
+		{
+			description: "no bounds",
+			args: args{
+				validation: Validations{},
+				selections: []string{"a", "b", "c"},
+			},
+		},
+		{
+			description: "no selections",
+			args: args{
+				validation: Validations{
+					MinSelections: null.IntFrom(1),
+				},
+				selections: []string{},
+			},
+		},
+		{
+			description: "too few selections",
+			args: args{
+				validation: Validations{
+					MinSelections: null.IntFrom(2),
+				},
+				selections: []string{"a", ""},
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrSelectionCount,
+			},
+		},
+		{
+			description: "too many selections",
+			args: args{
+				validation: Validations{
+					MaxSelections: null.IntFrom(2),
+				},
+				selections: []string{"a", "b", "c"},
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrSelectionCount,
+			},
+		},
+		{
+			description: "invalid bounds",
+			args: args{
+				validation: Validations{
+					MinSelections: null.IntFrom(3),
+					MaxSelections: null.IntFrom(2),
+				},
+				selections: []string{"a"},
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidSelections,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		err := validationImpl.CheckSelectionValidation(testCase.args.validation, testCase.args.selections)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+	}
+}
+
+func TestCheckSelectionValid(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type args struct {
+		minSelections null.Int
+		maxSelections null.Int
+	}
+
+	type expect struct {
+		isErr bool
+		err   error
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+	testCases := []test{
+		{
+			description: "valid",
+			args: args{
+				minSelections: null.IntFrom(0),
+				maxSelections: null.IntFrom(2),
+			},
+		},
+		{
+			description: "no bounds",
+			args:        args{},
+		},
+		{
+			description: "negative min selections",
+			args: args{
+				minSelections: null.IntFrom(-1),
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidSelections,
+			},
+		},
+		{
+			description: "zero max selections",
+			args: args{
+				maxSelections: null.IntFrom(0),
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidSelections,
+			},
+		},
+		{
+			description: "min exceeds max",
+			args: args{
+				minSelections: null.IntFrom(3),
+				maxSelections: null.IntFrom(2),
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidSelections,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		err := validationImpl.CheckSelectionValid(testCase.args.minSelections, testCase.args.maxSelections)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+	}
+}
+
+func TestNormalizeDateTimeResponse(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type args struct {
+		questionType string
+		body         string
+	}
+
+	type expect struct {
+		isErr bool
+		err   error
+		value string
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+	testCases := []test{
+		{
+			description: "date",
+			args: args{
+				questionType: "Date",
+				body:         "2021-04-01",
+			},
+			expect: expect{
+				value: "2021-04-01",
+			},
+		},
+		{
+			description: "time without seconds",
+			args: args{
+				questionType: "Time",
+				body:         "09:30",
+			},
+			expect: expect{
+				value: "09:30:00",
+			},
+		},
+		{
+			description: "datetime without timezone",
+			args: args{
+				questionType: "DateTime",
+				body:         "2021-04-01T09:30",
+			},
+			expect: expect{
+				value: time.Date(2021, 4, 1, 9, 30, 0, 0, time.Local).Format(time.RFC3339),
+			},
+		},
+		{
+			description: "datetime with timezone",
+			args: args{
+				questionType: "DateTime",
+				body:         "2021-04-01T09:30:00Z",
+			},
+			expect: expect{
+				value: time.Date(2021, 4, 1, 9, 30, 0, 0, time.UTC).Local().Format(time.RFC3339),
+			},
+		},
+		{
+			description: "invalid date",
+			args: args{
+				questionType: "Date",
+				body:         "2021-02-30",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidDateTime,
+			},
+		},
+		{
+			description: "datetime as time",
+			args: args{
+				questionType: "Time",
+				body:         "2021-04-01T09:30",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidDateTime,
+			},
+		},
+		{
+			description: "not a date time question",
+			args: args{
+				questionType: "Text",
+				body:         "2021-04-01",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidDateTime,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		value, err := NormalizeDateTimeResponse(testCase.args.questionType, testCase.args.body)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+		if err != nil {
+			continue
+		}
+
+		assertion.Equal(testCase.expect.value, value, testCase.description, "value")
+	}
+}
+
+func TestCheckDateTimeValidation(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type args struct {
+		validation   Validations
+		questionType string
+		body         string
+	}
+
+	type expect struct {
+		isErr bool
+		err   error
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+	testCases := []test{
+		{
+			description: "within bounds",
+			args: args{
+				validation: Validations{
+					MinBound: "2021-04-01",
+					MaxBound: "2021-04-30",
+				},
+				questionType: "Date",
+				body:         "2021-04-15",
+			},
+		},
+		{
+			description: "empty body",
+			args: args{
+				validation: Validations{
+					MinBound: "2021-04-01",
+				},
+				questionType: "Date",
+				body:         "",
+			},
+		},
+		{
+			description: "before min bound",
+			args: args{
+				validation: Validations{
+					MinBound: "09:00",
+				},
+				questionType: "Time",
+				body:         "08:59:59",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrDateTimeBoundary,
+			},
+		},
+		{
+			description: "after max bound",
+			args: args{
+				validation: Validations{
+					MaxBound: "2021-04-01T18:00:00+09:00",
+				},
+				questionType: "DateTime",
+				body:         "2021-04-01T10:00:00Z",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrDateTimeBoundary,
+			},
+		},
+		{
+			description: "invalid body",
+			args: args{
+				validation:   Validations{},
+				questionType: "Date",
+				body:         "April 1st",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidDateTime,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		err := validationImpl.CheckDateTimeValidation(testCase.args.validation, testCase.args.questionType, testCase.args.body)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+	}
+}
+
+func TestCheckDateTimeValid(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type args struct {
+		questionType string
+		minBound     string
+		maxBound     string
+	}
+
+	type expect struct {
+		isErr bool
+		err   error
+	}
+
+	type test struct {
+		description string
+		args
+		expect
+	}
+	testCases := []test{
+		{
+			description: "valid",
+			args: args{
+				questionType: "Time",
+				minBound:     "09:00",
+				maxBound:     "18:00",
+			},
+		},
+		{
+			description: "no bounds",
+			args: args{
+				questionType: "DateTime",
+			},
+		},
+		{
+			description: "bound of another type",
+			args: args{
+				questionType: "Date",
+				minBound:     "09:00",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidDateTime,
+			},
+		},
+		{
+			description: "min is after max",
+			args: args{
+				questionType: "Date",
+				minBound:     "2021-05-01",
+				maxBound:     "2021-04-01",
+			},
+			expect: expect{
+				isErr: true,
+				err:   ErrInvalidDateTime,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		err := validationImpl.CheckDateTimeValid(testCase.args.questionType, testCase.args.minBound, testCase.args.maxBound)
+
+		if !testCase.expect.isErr {
+			assertion.NoError(err, testCase.description, "no error")
+		} else if testCase.expect.err != nil {
+			assertion.Equal(true, errors.Is(err, testCase.expect.err), testCase.description, "errorIs")
+		} else if testCase.expect.isErr {
+			assertion.Error(err, testCase.description, "any error")
+		}
+	}
+}
@@ -0,0 +1,20 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// IWebhookDelivery WebhookDeliveryのRepository
+type IWebhookDelivery interface {
+	InsertWebhookDeliveries(ctx context.Context, questionnaireID int, event string, payload string) error
+	ClaimWebhookDeliveriesToDispatch(ctx context.Context, limit int, leaseUntil time.Time) ([]WebhookDeliveries, error)
+	GetWebhookDeliveries(ctx context.Context, webhookID int, limit int) ([]WebhookDeliveries, error)
+	UpdateWebhookDeliveryDelivered(ctx context.Context, deliveryID int, claimedBy string, statusCode int) error
+	UpdateWebhookDeliveryRetry(ctx context.Context, deliveryID int, claimedBy string, lastError string, statusCode null.Int, nextAttemptAt time.Time) error
+	UpdateWebhookDeliveryDead(ctx context.Context, deliveryID int, claimedBy string, lastError string, statusCode null.Int) error
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+// WebhookDelivery WebhookDeliveryRepositoryの実装
+type WebhookDelivery struct{}
+
+// NewWebhookDelivery WebhookDeliveryのコンストラクター
+func NewWebhookDelivery() *WebhookDelivery {
+	return new(WebhookDelivery)
+}
+
+// WebhookDeliveries webhook_deliveriesテーブルの構造体
+// 外部のWebhookに送信するイベントで、状態はoutbox_messagesと同じくOutboxStatusPendingなどを使う
+type WebhookDeliveries struct {
+	ID             int                    `json:"id"               gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	WebhookID      int                    `json:"webhookID"        gorm:"type:int(11);not null;index"`
+	Event          string                 `json:"event"            gorm:"type:char(30);size:30;not null"`
+	Payload        string                 `json:"payload"          gorm:"type:mediumtext;not null"`
+	Status         string                 `json:"status"           gorm:"type:char(20);size:20;not null"`
+	Attempts       int                    `json:"attempts"         gorm:"type:int(11);not null;default:0"`
+	NextAttemptAt  time.Time              `json:"next_attempt_at"  gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	LastError      null.String            `json:"last_error"       gorm:"type:text;default:NULL"`
+	LastStatusCode null.Int               `json:"last_status_code" gorm:"type:int(11);default:NULL"`
+	ClaimedBy      null.String            `json:"claimed_by"       gorm:"type:char(32);size:32;default:NULL"`
+	LeaseUntil     null.Time              `json:"lease_until"      gorm:"type:TIMESTAMP NULL;default:NULL"`
+	CreatedAt      time.Time              `json:"created_at"       gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeliveredAt    null.Time              `json:"delivered_at"     gorm:"type:TIMESTAMP NULL;default:NULL"`
+	Webhook        *QuestionnaireWebhooks `json:"-"                gorm:"foreignKey:WebhookID"`
+}
+
+// InsertWebhookDeliveries アンケートのWebhookのうちeventを送信するものへの送信の追加
+// 呼び出し元のトランザクションがコミットされたときだけ送信される
+func (*WebhookDelivery) InsertWebhookDeliveries(ctx context.Context, questionnaireID int, event string, payload string) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if !isWebhookEvent(event) {
+		return ErrInvalidWebhookEvent
+	}
+
+	now := time.Now()
+	err = db.Exec(
+		"INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at) "+
+			"SELECT id, ?, ?, ?, ?, ? FROM questionnaire_webhooks "+
+			"WHERE questionnaire_id = ? AND deleted_at IS NULL AND FIND_IN_SET(?, events) > 0",
+		event, payload, OutboxStatusPending, now, now, questionnaireID, event,
+	).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// anonymizeWebhookDeliveries アンケートのWebhookで送信する(した)回答のイベントから回答者のtraQIDを消す
+// 削除されたWebhookへの送信の記録も対象にする
+func anonymizeWebhookDeliveries(db *gorm.DB, questionnaireID int) error {
+	err := db.
+		Model(&WebhookDeliveries{}).
+		Where("webhook_id IN (?)", db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&QuestionnaireWebhooks{}).Where("questionnaire_id = ?", questionnaireID).Select("id")).
+		Where("JSON_CONTAINS_PATH(payload, 'one', '$.data.traqID')").
+		UpdateColumn("payload", gorm.Expr("JSON_REMOVE(payload, '$.data.traqID')")).Error
+	if err != nil {
+		return fmt.Errorf("failed to anonymize webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveriesToDispatch 送信するイベントを送信中にして取得
+// 未送信でnext_attempt_atを過ぎたイベントと、lease_untilを過ぎても送信済みにならなかったイベントを、
+// next_attempt_atが古い順に最大limit件、leaseUntilまで送信中にして送信先のWebhookと一緒に返す
+// 削除されたWebhookへのイベントは返さない
+// 送信中にする条件付きのUPDATEで確保するので、複数のプロセスから呼んでも同じイベントは1つのプロセスにしか返らない
+func (*WebhookDelivery) ClaimWebhookDeliveriesToDispatch(ctx context.Context, limit int, leaseUntil time.Time) ([]WebhookDeliveries, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	claimedBy, err := generateClaimToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate claim token: %w", err)
+	}
+
+	now := time.Now()
+	err = db.Exec(
+		"UPDATE webhook_deliveries SET status = ?, claimed_by = ?, lease_until = ? "+
+			"WHERE ((status = ? AND next_attempt_at <= ?) OR (status = ? AND lease_until <= ?)) "+
+			"AND webhook_id IN (SELECT id FROM questionnaire_webhooks WHERE deleted_at IS NULL) "+
+			"ORDER BY next_attempt_at, id LIMIT ?",
+		OutboxStatusSending, claimedBy, leaseUntil,
+		OutboxStatusPending, now, OutboxStatusSending, now,
+		limit,
+	).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	deliveries := []WebhookDeliveries{}
+	err = db.
+		Preload("Webhook").
+		Where("status = ? AND claimed_by = ?", OutboxStatusSending, claimedBy).
+		Order("next_attempt_at, id").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetWebhookDeliveries Webhookへの送信の記録の取得
+// 新しい順に最大limit件返す
+func (*WebhookDelivery) GetWebhookDeliveries(ctx context.Context, webhookID int, limit int) ([]WebhookDeliveries, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	deliveries := []WebhookDeliveries{}
+	err = db.
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDeliveryDelivered claimedByが送信中のイベントを送信済みにする
+func (*WebhookDelivery) UpdateWebhookDeliveryDelivered(ctx context.Context, deliveryID int, claimedBy string, statusCode int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&WebhookDeliveries{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", deliveryID, OutboxStatusSending, claimedBy).
+		UpdateColumns(map[string]interface{}{
+			"status":           OutboxStatusDelivered,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_status_code": statusCode,
+			"delivered_at":     time.Now(),
+			"lease_until":      gorm.Expr("NULL"),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
+// UpdateWebhookDeliveryRetry claimedByが送信に失敗したイベントをnextAttemptAtに再送するようにする
+// statusCodeはレスポンスが返ってこなかった場合はnull
+func (*WebhookDelivery) UpdateWebhookDeliveryRetry(ctx context.Context, deliveryID int, claimedBy string, lastError string, statusCode null.Int, nextAttemptAt time.Time) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&WebhookDeliveries{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", deliveryID, OutboxStatusSending, claimedBy).
+		UpdateColumns(map[string]interface{}{
+			"status":           OutboxStatusPending,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_error":       lastError,
+			"last_status_code": statusCode,
+			"next_attempt_at":  nextAttemptAt,
+			"lease_until":      gorm.Expr("NULL"),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
+
+// UpdateWebhookDeliveryDead claimedByが送信に失敗したイベントを再送しないようにする
+// statusCodeはレスポンスが返ってこなかった場合はnull
+func (*WebhookDelivery) UpdateWebhookDeliveryDead(ctx context.Context, deliveryID int, claimedBy string, lastError string, statusCode null.Int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Model(&WebhookDeliveries{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", deliveryID, OutboxStatusSending, claimedBy).
+		UpdateColumns(map[string]interface{}{
+			"status":           OutboxStatusDead,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_error":       lastError,
+			"last_status_code": statusCode,
+			"lease_until":      gorm.Expr("NULL"),
+		})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordUpdated
+	}
+
+	return nil
+}
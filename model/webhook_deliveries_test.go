@@ -0,0 +1,182 @@
+package model
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+func TestInsertWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+
+	submittedWebhookID, err := webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/submitted", "secret", []string{WebhookEventResponseSubmitted})
+	require.NoError(t, err)
+	closedWebhookID, err := webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/closed", "secret", []string{WebhookEventQuestionnaireClosed})
+	require.NoError(t, err)
+	deletedWebhookID, err := webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/deleted", "secret", []string{WebhookEventResponseSubmitted})
+	require.NoError(t, err)
+	err = webhookImpl.DeleteQuestionnaireWebhook(ctx, questionnaireID, deletedWebhookID)
+	require.NoError(t, err)
+
+	err = webhookDeliveryImpl.InsertWebhookDeliveries(ctx, questionnaireID, WebhookEventResponseSubmitted, `{"event":"response.submitted"}`)
+	require.NoError(t, err)
+
+	err = webhookDeliveryImpl.InsertWebhookDeliveries(ctx, questionnaireID, "unknown", "{}")
+	assertion.ErrorIs(err, ErrInvalidWebhookEvent, "invalid event")
+
+	// イベントを送信するWebhookにだけ追加する
+	deliveries, err := webhookDeliveryImpl.GetWebhookDeliveries(ctx, submittedWebhookID, 100)
+	require.NoError(t, err)
+	if assertion.Len(deliveries, 1, "submitted") {
+		assertion.Equal(WebhookEventResponseSubmitted, deliveries[0].Event, "event")
+		assertion.Equal(`{"event":"response.submitted"}`, deliveries[0].Payload, "payload")
+		assertion.Equal(OutboxStatusPending, deliveries[0].Status, "status")
+		assertion.Equal(0, deliveries[0].Attempts, "attempts")
+	}
+
+	deliveries, err = webhookDeliveryImpl.GetWebhookDeliveries(ctx, closedWebhookID, 100)
+	require.NoError(t, err)
+	assertion.Len(deliveries, 0, "closed")
+
+	deliveries, err = webhookDeliveryImpl.GetWebhookDeliveries(ctx, deletedWebhookID, 100)
+	require.NoError(t, err)
+	assertion.Len(deliveries, 0, "deleted")
+}
+
+// TestClaimWebhookDeliveriesToDispatch 送信時刻を過ぎた全てのイベントを送信中にするので、
+// 他のテストで追加したイベントの状態を変えないように並列には実行しない
+func TestClaimWebhookDeliveriesToDispatch(t *testing.T) {
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+
+	webhookID, err := webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/webhook", "secret", []string{WebhookEventResponseSubmitted})
+	require.NoError(t, err)
+
+	insertDelivery := func() int {
+		err := webhookDeliveryImpl.InsertWebhookDeliveries(ctx, questionnaireID, WebhookEventResponseSubmitted, "{}")
+		require.NoError(t, err)
+
+		deliveries, err := webhookDeliveryImpl.GetWebhookDeliveries(ctx, webhookID, 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+
+		return deliveries[0].ID
+	}
+
+	dueDeliveryID := insertDelivery()
+
+	retryDeliveryID := insertDelivery()
+	err = webhookDeliveryImpl.UpdateWebhookDeliveryRetry(ctx, retryDeliveryID, claimWebhookDelivery(t, retryDeliveryID, time.Now().Add(time.Minute)), "error", null.IntFrom(http.StatusInternalServerError), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	deliveredDeliveryID := insertDelivery()
+	deliveredClaimedBy := claimWebhookDelivery(t, deliveredDeliveryID, time.Now().Add(time.Minute))
+	err = webhookDeliveryImpl.UpdateWebhookDeliveryDelivered(ctx, deliveredDeliveryID, deliveredClaimedBy, http.StatusNoContent)
+	require.NoError(t, err)
+
+	deadDeliveryID := insertDelivery()
+	err = webhookDeliveryImpl.UpdateWebhookDeliveryDead(ctx, deadDeliveryID, claimWebhookDelivery(t, deadDeliveryID, time.Now().Add(time.Minute)), "error", null.NewInt(0, false))
+	require.NoError(t, err)
+
+	// 他のプロセスが送信中のイベント
+	sendingDeliveryID := insertDelivery()
+	claimWebhookDelivery(t, sendingDeliveryID, time.Now().Add(time.Minute))
+
+	// 送信中のままプロセスが終了したイベント
+	expiredDeliveryID := insertDelivery()
+	claimWebhookDelivery(t, expiredDeliveryID, time.Now().Add(-time.Minute))
+
+	// 送信済みのイベントと、他のプロセスが送信中のイベントは更新しない
+	err = webhookDeliveryImpl.UpdateWebhookDeliveryRetry(ctx, deliveredDeliveryID, deliveredClaimedBy, "error", null.NewInt(0, false), time.Now())
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "delivered")
+	err = webhookDeliveryImpl.UpdateWebhookDeliveryRetry(ctx, sendingDeliveryID, "other", "error", null.NewInt(0, false), time.Now())
+	assertion.ErrorIs(err, ErrNoRecordUpdated, "claimed by other")
+
+	leaseUntil := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	deliveries, err := webhookDeliveryImpl.ClaimWebhookDeliveriesToDispatch(ctx, 1000, leaseUntil)
+	require.NoError(t, err)
+
+	deliveryIDs := make([]int, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		assertion.Equal(OutboxStatusSending, delivery.Status, "status")
+		assertion.Equal(deliveries[0].ClaimedBy, delivery.ClaimedBy, "claimed_by")
+		assertion.WithinDuration(leaseUntil, delivery.LeaseUntil.ValueOrZero(), time.Second, "lease_until")
+		deliveryIDs = append(deliveryIDs, delivery.ID)
+		if delivery.ID == dueDeliveryID && assertion.NotNil(delivery.Webhook, "webhook") {
+			assertion.Equal("https://example.com/webhook", delivery.Webhook.URL, "url")
+			assertion.Equal("secret", delivery.Webhook.Secret, "secret")
+		}
+	}
+
+	assertion.Contains(deliveryIDs, dueDeliveryID, "due delivery")
+	assertion.NotContains(deliveryIDs, retryDeliveryID, "retry delivery")
+	assertion.NotContains(deliveryIDs, deliveredDeliveryID, "delivered delivery")
+	assertion.NotContains(deliveryIDs, deadDeliveryID, "dead delivery")
+	assertion.NotContains(deliveryIDs, sendingDeliveryID, "sending delivery")
+	assertion.Contains(deliveryIDs, expiredDeliveryID, "expired delivery")
+
+	deliveries, err = webhookDeliveryImpl.GetWebhookDeliveries(ctx, webhookID, 100)
+	require.NoError(t, err)
+	for _, delivery := range deliveries {
+		switch delivery.ID {
+		case retryDeliveryID:
+			assertion.Equal(OutboxStatusPending, delivery.Status, "retry status")
+			assertion.Equal(1, delivery.Attempts, "retry attempts")
+			assertion.Equal(int64(http.StatusInternalServerError), delivery.LastStatusCode.ValueOrZero(), "retry status code")
+		case deliveredDeliveryID:
+			assertion.Equal(OutboxStatusDelivered, delivery.Status, "delivered status")
+			assertion.True(delivery.DeliveredAt.Valid, "delivered_at")
+		case deadDeliveryID:
+			assertion.Equal(OutboxStatusDead, delivery.Status, "dead status")
+			assertion.False(delivery.LastStatusCode.Valid, "dead status code")
+		}
+	}
+
+	// 削除されたWebhookへのイベントは送信しない
+	deletedDeliveryID := insertDelivery()
+	err = webhookImpl.DeleteQuestionnaireWebhook(ctx, questionnaireID, webhookID)
+	require.NoError(t, err)
+
+	deliveries, err = webhookDeliveryImpl.ClaimWebhookDeliveriesToDispatch(ctx, 1000, leaseUntil)
+	require.NoError(t, err)
+	for _, delivery := range deliveries {
+		assertion.NotEqual(deletedDeliveryID, delivery.ID, "deleted webhook")
+	}
+}
+
+// claimWebhookDelivery イベントをleaseUntilまで送信中にして、送信中にしたときの値を返す
+// ClaimWebhookDeliveriesToDispatchは他のテストのイベントも送信中にするので、指定したイベントだけ送信中にする
+func claimWebhookDelivery(t *testing.T, deliveryID int, leaseUntil time.Time) string {
+	t.Helper()
+
+	claimedBy, err := generateClaimToken()
+	require.NoError(t, err)
+
+	err = db.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&WebhookDeliveries{}).
+		Where("id = ?", deliveryID).
+		UpdateColumns(map[string]interface{}{
+			"status":      OutboxStatusSending,
+			"claimed_by":  claimedBy,
+			"lease_until": leaseUntil,
+		}).Error
+	require.NoError(t, err)
+
+	return claimedBy
+}
@@ -0,0 +1,15 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package model
+
+import (
+	"context"
+)
+
+// IWebhook WebhookのRepository
+type IWebhook interface {
+	InsertQuestionnaireWebhook(ctx context.Context, questionnaireID int, url string, secret string, events []string) (int, error)
+	GetQuestionnaireWebhooks(ctx context.Context, questionnaireID int) ([]QuestionnaireWebhooks, error)
+	GetQuestionnaireWebhook(ctx context.Context, questionnaireID int, webhookID int) (*QuestionnaireWebhooks, error)
+	DeleteQuestionnaireWebhook(ctx context.Context, questionnaireID int, webhookID int) error
+}
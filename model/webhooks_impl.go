@@ -0,0 +1,175 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhookで送信するイベントの種類
+const (
+	// WebhookEventResponseSubmitted 回答が提出された
+	WebhookEventResponseSubmitted = "response.submitted"
+	// WebhookEventResponseUpdated 提出済みの回答が変更された
+	WebhookEventResponseUpdated = "response.updated"
+	// WebhookEventResponseDeleted 提出済みの回答が削除された
+	WebhookEventResponseDeleted = "response.deleted"
+	// WebhookEventQuestionnaireClosed 回答期限を過ぎたか締め切られた
+	WebhookEventQuestionnaireClosed = "questionnaire.closed"
+	// WebhookEventQuestionnaireUpdated アンケートが変更された
+	WebhookEventQuestionnaireUpdated = "questionnaire.updated"
+)
+
+// WebhookEvents Webhookで送信するイベントの種類の一覧
+var WebhookEvents = []string{
+	WebhookEventResponseSubmitted,
+	WebhookEventResponseUpdated,
+	WebhookEventResponseDeleted,
+	WebhookEventQuestionnaireClosed,
+	WebhookEventQuestionnaireUpdated,
+}
+
+// Webhook WebhookRepositoryの実装
+type Webhook struct{}
+
+// NewWebhook Webhookのコンストラクター
+func NewWebhook() *Webhook {
+	return new(Webhook)
+}
+
+// QuestionnaireWebhooks questionnaire_webhooksテーブルの構造体
+// アンケートの管理者が登録した外部のWebhookで、Eventsのイベントが起きたときに送信する
+type QuestionnaireWebhooks struct {
+	ID              int            `json:"id"              gorm:"type:int(11) AUTO_INCREMENT;not null;primaryKey"`
+	QuestionnaireID int            `json:"questionnaireID" gorm:"type:int(11);not null;index"`
+	URL             string         `json:"url"             gorm:"column:url;type:varchar(2048);size:2048;not null"`
+	Secret          string         `json:"-"               gorm:"type:char(64);size:64;not null"`
+	EventNames      string         `json:"-"               gorm:"column:events;type:varchar(255);size:255;not null"`
+	Events          []string       `json:"events"          gorm:"-"`
+	CreatedAt       time.Time      `json:"created_at"      gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt       gorm.DeletedAt `json:"-"               gorm:"type:TIMESTAMP NULL;default:NULL"`
+}
+
+// BeforeCreate 作成時にイベントの種類の一覧をカンマ区切りにする
+func (webhook *QuestionnaireWebhooks) BeforeCreate(tx *gorm.DB) error {
+	for _, event := range webhook.Events {
+		if !isWebhookEvent(event) {
+			return fmt.Errorf("unknown webhook event(%s): %w", event, ErrInvalidWebhookEvent)
+		}
+	}
+	webhook.EventNames = strings.Join(webhook.Events, ",")
+
+	return nil
+}
+
+// AfterFind 取得時にカンマ区切りのイベントの種類を一覧にする
+func (webhook *QuestionnaireWebhooks) AfterFind(tx *gorm.DB) error {
+	webhook.Events = []string{}
+	if len(webhook.EventNames) != 0 {
+		webhook.Events = strings.Split(webhook.EventNames, ",")
+	}
+
+	return nil
+}
+
+// InsertQuestionnaireWebhook アンケートのWebhookの追加
+func (*Webhook) InsertQuestionnaireWebhook(ctx context.Context, questionnaireID int, url string, secret string, events []string) (int, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	webhook := QuestionnaireWebhooks{
+		QuestionnaireID: questionnaireID,
+		URL:             url,
+		Secret:          secret,
+		Events:          events,
+		CreatedAt:       time.Now(),
+	}
+	err = db.Create(&webhook).Error
+	if errors.Is(err, ErrInvalidWebhookEvent) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+
+	return webhook.ID, nil
+}
+
+// GetQuestionnaireWebhooks アンケートのWebhookの一覧の取得
+func (*Webhook) GetQuestionnaireWebhooks(ctx context.Context, questionnaireID int) ([]QuestionnaireWebhooks, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	webhooks := []QuestionnaireWebhooks{}
+	err = db.
+		Where("questionnaire_id = ?", questionnaireID).
+		Order("id").
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// GetQuestionnaireWebhook アンケートのWebhookの取得
+// 別のアンケートのWebhookの場合もErrRecordNotFoundを返す
+func (*Webhook) GetQuestionnaireWebhook(ctx context.Context, questionnaireID int, webhookID int) (*QuestionnaireWebhooks, error) {
+	db, err := getTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	webhook := QuestionnaireWebhooks{}
+	err = db.
+		Where("id = ? AND questionnaire_id = ?", webhookID, questionnaireID).
+		Take(&webhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// DeleteQuestionnaireWebhook アンケートのWebhookの削除
+// 送信の記録を残すために論理削除し、未送信のものは送信しない
+func (*Webhook) DeleteQuestionnaireWebhook(ctx context.Context, questionnaireID int, webhookID int) error {
+	db, err := getTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	result := db.
+		Where("id = ? AND questionnaire_id = ?", webhookID, questionnaireID).
+		Delete(&QuestionnaireWebhooks{})
+	err = result.Error
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRecordDeleted
+	}
+
+	return nil
+}
+
+func isWebhookEvent(event string) bool {
+	for _, webhookEvent := range WebhookEvents {
+		if event == webhookEvent {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestQuestionnaireWebhooks(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+	ctx := context.Background()
+
+	questionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+	otherQuestionnaireID, err := questionnaireImpl.InsertQuestionnaire(ctx, "第1回集会らん☆ぷろ募集アンケート", "第1回集会らん☆ぷろ参加者募集", null.NewTime(time.Time{}, false), "public", false)
+	require.NoError(t, err)
+
+	webhookID, err := webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/webhook", "secret", []string{WebhookEventResponseSubmitted, WebhookEventQuestionnaireClosed})
+	require.NoError(t, err)
+
+	_, err = webhookImpl.InsertQuestionnaireWebhook(ctx, questionnaireID, "https://example.com/webhook", "secret", []string{"unknown"})
+	assertion.ErrorIs(err, ErrInvalidWebhookEvent, "invalid event")
+
+	webhooks, err := webhookImpl.GetQuestionnaireWebhooks(ctx, questionnaireID)
+	require.NoError(t, err)
+	if assertion.Len(webhooks, 1, "webhooks") {
+		assertion.Equal(webhookID, webhooks[0].ID, "id")
+		assertion.Equal("https://example.com/webhook", webhooks[0].URL, "url")
+		assertion.Equal("secret", webhooks[0].Secret, "secret")
+		assertion.Equal([]string{WebhookEventResponseSubmitted, WebhookEventQuestionnaireClosed}, webhooks[0].Events, "events")
+	}
+
+	webhook, err := webhookImpl.GetQuestionnaireWebhook(ctx, questionnaireID, webhookID)
+	require.NoError(t, err)
+	assertion.Equal(webhookID, webhook.ID, "id")
+
+	// 別のアンケートのWebhookは取得も削除もできない
+	_, err = webhookImpl.GetQuestionnaireWebhook(ctx, otherQuestionnaireID, webhookID)
+	assertion.ErrorIs(err, ErrRecordNotFound, "other questionnaire")
+	err = webhookImpl.DeleteQuestionnaireWebhook(ctx, otherQuestionnaireID, webhookID)
+	assertion.ErrorIs(err, ErrNoRecordDeleted, "other questionnaire")
+
+	err = webhookImpl.DeleteQuestionnaireWebhook(ctx, questionnaireID, webhookID)
+	require.NoError(t, err)
+
+	webhooks, err = webhookImpl.GetQuestionnaireWebhooks(ctx, questionnaireID)
+	require.NoError(t, err)
+	assertion.Len(webhooks, 0, "deleted")
+
+	_, err = webhookImpl.GetQuestionnaireWebhook(ctx, questionnaireID, webhookID)
+	assertion.ErrorIs(err, ErrRecordNotFound, "deleted")
+
+	err = webhookImpl.DeleteQuestionnaireWebhook(ctx, questionnaireID, webhookID)
+	assertion.ErrorIs(err, ErrNoRecordDeleted, "already deleted")
+}
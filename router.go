@@ -4,10 +4,11 @@ import (
 	"github.com/labstack/echo-contrib/prometheus"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/traPtitech/anke-to/router"
 )
 
 // SetRouting ルーティングの設定
-func SetRouting(port string) {
+func SetRouting(port string, messageConfig router.MessageConfig) {
 	e := echo.New()
 
 	// Middleware
@@ -16,7 +17,12 @@ func SetRouting(port string) {
 	p := prometheus.NewPrometheus("echo", nil)
 	p.Use(e)
 
-	api := InjectAPIServer()
+	authenticator, err := router.NewAuthenticator()
+	if err != nil {
+		panic(err)
+	}
+
+	api := InjectAPIServer(authenticator, messageConfig)
 
 	// Static Files
 	e.Static("/", "client/dist")
@@ -38,8 +44,19 @@ func SetRouting(port string) {
 			apiQuestionnnaires.GET("/:questionnaireID", api.GetQuestionnaire)
 			apiQuestionnnaires.PATCH("/:questionnaireID", api.EditQuestionnaire, api.QuestionnaireAdministratorAuthenticate)
 			apiQuestionnnaires.DELETE("/:questionnaireID", api.DeleteQuestionnaire, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.POST("/:questionnaireID/copy", api.CopyQuestionnaire, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.GET("/:questionnaireID/history", api.GetQuestionnaireHistory, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.GET("/:questionnaireID/message-templates", api.GetMessageTemplates, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.PUT("/:questionnaireID/message-templates/:kind", api.PutMessageTemplate, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.DELETE("/:questionnaireID/message-templates/:kind", api.DeleteMessageTemplate, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.POST("/:questionnaireID/message-templates/:kind/preview", api.PostMessagePreview, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.GET("/:questionnaireID/webhooks", api.GetWebhooks, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.POST("/:questionnaireID/webhooks", api.PostWebhook, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.DELETE("/:questionnaireID/webhooks/:webhookID", api.DeleteWebhook, api.QuestionnaireAdministratorAuthenticate)
+			apiQuestionnnaires.GET("/:questionnaireID/webhooks/:webhookID/deliveries", api.GetWebhookDeliveryLog, api.QuestionnaireAdministratorAuthenticate)
 			apiQuestionnnaires.GET("/:questionnaireID/questions", api.GetQuestions)
 			apiQuestionnnaires.POST("/:questionnaireID/questions", api.PostQuestionByQuestionnaireID)
+			apiQuestionnnaires.PUT("/:questionnaireID/questions", api.PutQuestions, api.QuestionnaireAdministratorAuthenticate)
 		}
 
 		apiQuestions := echoAPI.Group("/questions")
@@ -52,6 +69,9 @@ func SetRouting(port string) {
 		{
 			apiResponses.POST("", api.PostResponse)
 			apiResponses.GET("/:responseID", api.GetResponse, api.ResponseReadAuthenticate)
+			apiResponses.GET("/:responseID/revisions", api.GetResponseRevisions, api.ResponseRevisionReadAuthenticate)
+			apiResponses.GET("/:responseID/revisions/diff", api.GetResponseRevisionDiff, api.ResponseRevisionReadAuthenticate)
+			apiResponses.GET("/:responseID/revisions/:revision", api.GetResponseRevision, api.ResponseRevisionReadAuthenticate)
 			apiResponses.PATCH("/:responseID", api.EditResponse, api.RespondentAuthenticate)
 			apiResponses.DELETE("/:responseID", api.DeleteResponse, api.RespondentAuthenticate)
 		}
@@ -76,6 +96,27 @@ func SetRouting(port string) {
 		apiResults := echoAPI.Group("/results")
 		{
 			apiResults.GET("/:questionnaireID", api.GetResults, api.ResultAuthenticate)
+			apiResults.GET("/:questionnaireID/statistics", api.GetResultStatistics, api.ResultAuthenticate)
+			apiResults.GET("/:questionnaireID/export", api.ExportResults, api.ResultAuthenticate)
+		}
+
+		apiGroups := echoAPI.Group("/groups")
+		{
+			apiGroups.GET("", api.GetGroups)
+			apiGroups.PUT("", api.PutGroups, api.SystemAdministratorAuthenticate)
+		}
+
+		apiSystemAdmins := echoAPI.Group("/system-admins", api.SystemAdministratorAuthenticate)
+		{
+			apiSystemAdmins.GET("", api.GetSystemAdministrators)
+			apiSystemAdmins.POST("", api.PostSystemAdministrators)
+			apiSystemAdmins.DELETE("/:traQID", api.DeleteSystemAdministrator)
+		}
+
+		apiOutboxMessages := echoAPI.Group("/outbox-messages", api.SystemAdministratorAuthenticate)
+		{
+			apiOutboxMessages.GET("", api.GetOutbox)
+			apiOutboxMessages.POST("/:outboxMessageID/replay", api.PostOutboxReplay)
 		}
 	}
 
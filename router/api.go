@@ -8,16 +8,26 @@ type API struct {
 	*Response
 	*Result
 	*User
+	*Group
+	*SystemAdmin
+	*Outbox
+	*MessageTemplate
+	*Webhook
 }
 
 // NewAPI APIのコンストラクタ
-func NewAPI(middleware *Middleware, questionnaire *Questionnaire, question *Question, response *Response, result *Result, user *User) *API {
+func NewAPI(middleware *Middleware, questionnaire *Questionnaire, question *Question, response *Response, result *Result, user *User, group *Group, systemAdmin *SystemAdmin, outbox *Outbox, messageTemplate *MessageTemplate, webhook *Webhook) *API {
 	return &API{
-		Middleware:    middleware,
-		Questionnaire: questionnaire,
-		Question:      question,
-		Response:      response,
-		Result:        result,
-		User:          user,
+		Middleware:      middleware,
+		Questionnaire:   questionnaire,
+		Question:        question,
+		Response:        response,
+		Result:          result,
+		User:            user,
+		Group:           group,
+		SystemAdmin:     systemAdmin,
+		Outbox:          outbox,
+		MessageTemplate: messageTemplate,
+		Webhook:         webhook,
 	}
 }
@@ -32,6 +32,16 @@ var (
 	errMock = errors.New("Mock Error")
 )
 
+// newTestAuthenticator httptestのリクエストの送信元(192.0.2.1)をプロキシとして信頼するAuthenticator
+func newTestAuthenticator() Authenticator {
+	authenticator, err := NewHeaderAuthenticator([]string{"192.0.2.0/24"})
+	if err != nil {
+		panic(err)
+	}
+
+	return authenticator
+}
+
 func makePath(path string) string {
 	return rootPath + path
 }
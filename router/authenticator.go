@@ -0,0 +1,78 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Authenticator リクエストを送ったユーザーのtraQIDを特定する
+type Authenticator interface {
+	Authenticate(req *http.Request) (string, error)
+}
+
+var (
+	// ErrNoIdentity リクエストにユーザーの情報が含まれていない
+	ErrNoIdentity = errors.New("no identity")
+	// ErrUntrustedProxy 信頼していないプロキシからのリクエスト
+	ErrUntrustedProxy = errors.New("untrusted proxy")
+	// ErrInvalidToken トークンが不正
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// NewAuthenticator 環境変数ANKE-TO_AUTH_MODEに応じたAuthenticatorの生成
+func NewAuthenticator() (Authenticator, error) {
+	mode, ok := os.LookupEnv("ANKE-TO_AUTH_MODE")
+	if !ok {
+		mode = "header"
+	}
+
+	switch mode {
+	case "header":
+		cidrs, ok := os.LookupEnv("ANKE-TO_TRUSTED_PROXY_CIDR")
+		if !ok {
+			return nil, errors.New("ANKE-TO_TRUSTED_PROXY_CIDR is required in header auth mode")
+		}
+
+		return NewHeaderAuthenticator(splitEnvList(cidrs))
+	case "jwt":
+		jwksFile, ok := os.LookupEnv("ANKE-TO_JWKS_FILE")
+		if !ok {
+			return nil, errors.New("ANKE-TO_JWKS_FILE is required in jwt auth mode")
+		}
+		userClaim, ok := os.LookupEnv("ANKE-TO_JWT_USER_CLAIM")
+		if !ok {
+			userClaim = "name"
+		}
+
+		return NewJWTAuthenticator(
+			jwksFile,
+			os.Getenv("ANKE-TO_JWT_ISSUER"),
+			os.Getenv("ANKE-TO_JWT_AUDIENCE"),
+			userClaim,
+		)
+	case "dev":
+		userID, ok := os.LookupEnv("ANKE-TO_DEV_USER")
+		if !ok {
+			userID = "mds_boy"
+		}
+
+		return NewDevAuthenticator(userID), nil
+	}
+
+	return nil, fmt.Errorf("invalid ANKE-TO_AUTH_MODE: %s", mode)
+}
+
+func splitEnvList(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) != 0 {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,29 @@
+package router
+
+import "net/http"
+
+// DevAuthenticator 開発用のAuthenticator
+// X-Showcase-Userヘッダーをそのまま信頼し、なければ固定のユーザーとして扱う
+type DevAuthenticator struct {
+	userID string
+}
+
+// NewDevAuthenticator DevAuthenticatorのコンストラクタ
+func NewDevAuthenticator(userID string) *DevAuthenticator {
+	return &DevAuthenticator{
+		userID: userID,
+	}
+}
+
+// Authenticate ヘッダーのユーザーID、なければ固定のユーザーIDを返す
+func (da *DevAuthenticator) Authenticate(req *http.Request) (string, error) {
+	userID := req.Header.Get("X-Showcase-User")
+	if userID == "" {
+		userID = da.userID
+	}
+	if userID == "" {
+		return "", ErrNoIdentity
+	}
+
+	return userID, nil
+}
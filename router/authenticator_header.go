@@ -0,0 +1,63 @@
+package router
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// HeaderAuthenticator 信頼するプロキシが付与したX-Showcase-UserヘッダーからユーザーIDを取得する
+type HeaderAuthenticator struct {
+	trustedNets []*net.IPNet
+}
+
+// NewHeaderAuthenticator HeaderAuthenticatorのコンストラクタ
+func NewHeaderAuthenticator(trustedCIDRs []string) (*HeaderAuthenticator, error) {
+	if len(trustedCIDRs) == 0 {
+		return nil, fmt.Errorf("no trusted proxy CIDR")
+	}
+
+	trustedNets := make([]*net.IPNet, 0, len(trustedCIDRs))
+	for _, cidr := range trustedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse trusted proxy CIDR(%s): %w", cidr, err)
+		}
+		trustedNets = append(trustedNets, ipNet)
+	}
+
+	return &HeaderAuthenticator{
+		trustedNets: trustedNets,
+	}, nil
+}
+
+// Authenticate プロキシを経由していないリクエストのヘッダーは信頼しない
+func (ha *HeaderAuthenticator) Authenticate(req *http.Request) (string, error) {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", fmt.Errorf("invalid remote address(%s): %w", req.RemoteAddr, ErrUntrustedProxy)
+	}
+
+	isTrusted := false
+	for _, ipNet := range ha.trustedNets {
+		if ipNet.Contains(ip) {
+			isTrusted = true
+			break
+		}
+	}
+	if !isTrusted {
+		return "", fmt.Errorf("remote address(%s) is not trusted: %w", req.RemoteAddr, ErrUntrustedProxy)
+	}
+
+	userID := req.Header.Get("X-Showcase-User")
+	if userID == "" {
+		return "", ErrNoIdentity
+	}
+
+	return userID, nil
+}
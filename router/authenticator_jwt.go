@@ -0,0 +1,214 @@
+package router
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+// JWTAuthenticator Authorizationヘッダーの署名付きJWT(OIDCのIDトークンなど)からユーザーIDを取得する
+type JWTAuthenticator struct {
+	keys      map[string]interface{}
+	issuer    string
+	audience  string
+	userClaim string
+}
+
+// NewJWTAuthenticator JWTAuthenticatorのコンストラクタ
+// 検証に使う公開鍵はローカルのJWKSファイルから読み込む
+func NewJWTAuthenticator(jwksFile string, issuer string, audience string, userClaim string) (*JWTAuthenticator, error) {
+	f, err := os.Open(jwksFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open jwks file: %w", err)
+	}
+	defer f.Close()
+
+	var jwks jsonWebKeySet
+	err = json.NewDecoder(f).Decode(&jwks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode jwks file: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwk(kid: %s): %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing key in jwks file")
+	}
+
+	return &JWTAuthenticator{
+		keys:      keys,
+		issuer:    issuer,
+		audience:  audience,
+		userClaim: userClaim,
+	}, nil
+}
+
+// Authenticate Bearerトークンの署名と有効期限、iss、audを検証する
+func (ja *JWTAuthenticator) Authenticate(req *http.Request) (string, error) {
+	authorization := req.Header.Get(echo.HeaderAuthorization)
+	if authorization == "" {
+		return "", ErrNoIdentity
+	}
+
+	tokenString := strings.TrimPrefix(authorization, "Bearer ")
+	if tokenString == authorization {
+		return "", fmt.Errorf("authorization header is not bearer: %w", ErrInvalidToken)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, ja.keyFunc)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse token(%s): %w", err, ErrInvalidToken)
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return "", fmt.Errorf("token has no exp claim: %w", ErrInvalidToken)
+	}
+	if ja.issuer != "" && !claims.VerifyIssuer(ja.issuer, true) {
+		return "", fmt.Errorf("invalid issuer: %w", ErrInvalidToken)
+	}
+	if ja.audience != "" && !claims.VerifyAudience(ja.audience, true) {
+		return "", fmt.Errorf("invalid audience: %w", ErrInvalidToken)
+	}
+
+	userID, ok := claims[ja.userClaim].(string)
+	if !ok || userID == "" {
+		return "", ErrNoIdentity
+	}
+
+	return userID, nil
+}
+
+func (ja *JWTAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	var key interface{}
+	kid, ok := token.Header["kid"].(string)
+	if ok {
+		key, ok = ja.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown kid: %s", kid)
+		}
+	} else {
+		// kidがない場合は鍵が1つのときのみ受け付ける
+		if len(ja.keys) != 1 {
+			return nil, errors.New("kid is required")
+		}
+		for _, k := range ja.keys {
+			key = k
+		}
+	}
+
+	// 公開鍵をHMACの鍵として使われないよう、鍵の種類とアルゴリズムを一致させる
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return key, nil
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (jwk *jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode n: %w", err)
+		}
+		e, err := decodeBase64URLInt(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode e: %w", err)
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("too large e")
+		}
+
+		return &rsa.PublicKey{
+			N: n,
+			E: int(e.Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported crv: %s", jwk.Crv)
+		}
+
+		x, err := decodeBase64URLInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode x: %w", err)
+		}
+		y, err := decodeBase64URLInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     x,
+			Y:     y,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported kty: %s", jwk.Kty)
+}
+
+func decodeBase64URLInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
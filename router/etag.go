@@ -0,0 +1,85 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/guregu/null.v4"
+)
+
+var errInvalidETag = errors.New("invalid etag")
+
+// formatETag 更新日時からETagを作る
+// DBのtimestamp(6)型に合わせて、マイクロ秒単位のUNIX時間を使う
+// 更新日時を変えずに変わる内容がレスポンスに含まれる場合は、contentsのハッシュを"."の後ろにつける
+func formatETag(modifiedAt time.Time, contents ...string) string {
+	etag := strconv.FormatInt(modifiedAt.UnixMicro(), 10)
+	if len(contents) != 0 {
+		hash := sha256.Sum256([]byte(strings.Join(contents, "\x00")))
+		etag += "." + hex.EncodeToString(hash[:8])
+	}
+
+	return `"` + etag + `"`
+}
+
+// parseETag ETagから更新日時を取り出す
+// If-Matchでは更新日時だけを比べるので、contentsのハッシュは無視する
+func parseETag(etag string) (time.Time, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return time.Time{}, errInvalidETag
+	}
+
+	version := etag[1 : len(etag)-1]
+	if i := strings.Index(version, "."); i >= 0 {
+		version = version[:i]
+	}
+
+	unixMicro, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return time.Time{}, errInvalidETag
+	}
+
+	return time.UnixMicro(unixMicro), nil
+}
+
+// getIfMatch If-Matchヘッダーで指定された更新日時を取得する
+// 指定されていないか"*"の場合はどの版でも更新できるので、nullを返す
+func getIfMatch(c echo.Context) (null.Time, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if len(ifMatch) == 0 || ifMatch == "*" {
+		return null.NewTime(time.Time{}, false), nil
+	}
+
+	modifiedAt, err := parseETag(ifMatch)
+	if err != nil {
+		return null.NewTime(time.Time{}, false), err
+	}
+
+	return null.TimeFrom(modifiedAt), nil
+}
+
+// setETag ETagヘッダーを設定し、If-None-Matchヘッダーと一致する場合はtrueを返す
+func setETag(c echo.Context, modifiedAt time.Time, contents ...string) bool {
+	etag := formatETag(modifiedAt, contents...)
+	c.Response().Header().Set("ETag", etag)
+
+	ifNoneMatch := c.Request().Header.Get("If-None-Match")
+	if len(ifNoneMatch) == 0 {
+		return false
+	}
+
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,181 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestParseETag(t *testing.T) {
+	t.Parallel()
+
+	modifiedAt := time.Unix(1600000000, 123456000)
+
+	type test struct {
+		description string
+		etag        string
+		expect      time.Time
+		isErr       bool
+	}
+
+	testCases := []test{
+		{
+			description: "formatETagで作ったETagを読めるのでエラーなし",
+			etag:        formatETag(modifiedAt),
+			expect:      modifiedAt,
+		},
+		{
+			description: "弱いETagでもエラーなし",
+			etag:        "W/" + formatETag(modifiedAt),
+			expect:      modifiedAt,
+		},
+		{
+			description: "内容のハッシュがついていても更新日時を読めるのでエラーなし",
+			etag:        formatETag(modifiedAt, "mazrean"),
+			expect:      modifiedAt,
+		},
+		{
+			description: "ダブルクォートで囲まれていないのでエラー",
+			etag:        "1600000000",
+			isErr:       true,
+		},
+		{
+			description: "数値でないのでエラー",
+			etag:        `"abc"`,
+			isErr:       true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := parseETag(testCase.etag)
+		if testCase.isErr {
+			assert.ErrorIs(t, err, errInvalidETag, testCase.description, "error")
+			continue
+		}
+		if !assert.NoError(t, err, testCase.description, "no error") {
+			continue
+		}
+
+		assert.True(t, testCase.expect.Equal(actual), testCase.description, "modifiedAt")
+	}
+}
+
+func TestGetIfMatch(t *testing.T) {
+	t.Parallel()
+
+	modifiedAt := time.Unix(1600000000, 123456000)
+
+	type test struct {
+		description string
+		ifMatch     string
+		expect      null.Time
+		isErr       bool
+	}
+
+	testCases := []test{
+		{
+			description: "If-Matchがないのでnull",
+			expect:      null.NewTime(time.Time{}, false),
+		},
+		{
+			description: "If-Matchが*なのでnull",
+			ifMatch:     "*",
+			expect:      null.NewTime(time.Time{}, false),
+		},
+		{
+			description: "If-MatchのETagの更新日時",
+			ifMatch:     formatETag(modifiedAt),
+			expect:      null.TimeFrom(modifiedAt),
+		},
+		{
+			description: "If-Matchが不正なのでエラー",
+			ifMatch:     "invalid",
+			isErr:       true,
+		},
+	}
+
+	e := echo.New()
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodPatch, "/", nil)
+		if len(testCase.ifMatch) != 0 {
+			req.Header.Set("If-Match", testCase.ifMatch)
+		}
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		actual, err := getIfMatch(c)
+		if testCase.isErr {
+			assert.Error(t, err, testCase.description, "error")
+			continue
+		}
+		if !assert.NoError(t, err, testCase.description, "no error") {
+			continue
+		}
+
+		assert.Equal(t, testCase.expect.Valid, actual.Valid, testCase.description, "valid")
+		assert.True(t, testCase.expect.Time.Equal(actual.Time), testCase.description, "time")
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	t.Parallel()
+
+	modifiedAt := time.Unix(1600000000, 123456000)
+
+	type test struct {
+		description string
+		ifNoneMatch string
+		expect      bool
+	}
+
+	testCases := []test{
+		{
+			description: "If-None-Matchがないのでfalse",
+			expect:      false,
+		},
+		{
+			description: "If-None-Matchが一致するのでtrue",
+			ifNoneMatch: formatETag(modifiedAt),
+			expect:      true,
+		},
+		{
+			description: "If-None-Matchのいずれかが一致するのでtrue",
+			ifNoneMatch: `"1", ` + formatETag(modifiedAt),
+			expect:      true,
+		},
+		{
+			description: "If-None-Matchが一致しないのでfalse",
+			ifNoneMatch: formatETag(modifiedAt.Add(time.Second)),
+			expect:      false,
+		},
+		{
+			description: "更新日時が同じでも内容のハッシュが違うのでfalse",
+			ifNoneMatch: formatETag(modifiedAt, "mazrean"),
+			expect:      false,
+		},
+		{
+			description: "1秒以内の更新でもIf-None-Matchが一致しないのでfalse",
+			ifNoneMatch: formatETag(modifiedAt.Add(time.Microsecond)),
+			expect:      false,
+		},
+	}
+
+	e := echo.New()
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if len(testCase.ifNoneMatch) != 0 {
+			req.Header.Set("If-None-Match", testCase.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		actual := setETag(c, modifiedAt)
+
+		assert.Equal(t, testCase.expect, actual, testCase.description, "not modified")
+		assert.Equal(t, formatETag(modifiedAt), rec.Header().Get("ETag"), testCase.description, "etag")
+	}
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/anke-to/model"
+)
+
+// Group Groupの構造体
+type Group struct {
+	model.IGroup
+	model.ITransaction
+}
+
+// NewGroup Groupのコンストラクタ
+func NewGroup(group model.IGroup, transaction model.ITransaction) *Group {
+	return &Group{
+		IGroup:       group,
+		ITransaction: transaction,
+	}
+}
+
+// GetGroups GET /groups
+func (g *Group) GetGroups(c echo.Context) error {
+	groups, err := g.IGroup.GetGroups(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("failed to get groups: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, groups)
+}
+
+// PutGroupsRequest traQのグループ一覧
+type PutGroupsRequest []model.GroupInfo
+
+// PutGroups PUT /groups
+func (g *Group) PutGroups(c echo.Context) error {
+	req := PutGroupsRequest{}
+	err := c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind PutGroupsRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	validate, err := getValidator(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get validator: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	err = validate.VarCtx(c.Request().Context(), req, "dive")
+	if err != nil {
+		c.Logger().Infof("failed to validate: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	groupNames := make(map[string]struct{}, len(req))
+	for _, group := range req {
+		if _, ok := groupNames[group.Name]; ok {
+			c.Logger().Infof("duplicate group name: %s", group.Name)
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("duplicate group name: %s", group.Name))
+		}
+		groupNames[group.Name] = struct{}{}
+	}
+
+	err = g.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		err := g.SyncGroups(ctx, req)
+		if err != nil {
+			c.Logger().Errorf("failed to sync groups: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError
+		}
+
+		c.Logger().Errorf("failed to sync groups: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to sync groups")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// expandGroups グループ名をそのメンバーのtraQ IDに展開する
+// traPは全員を表す特別な値なので展開しない
+func expandGroups(ctx context.Context, group model.IGroup, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	groupNames := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != "traP" {
+			groupNames = append(groupNames, name)
+		}
+	}
+
+	memberMap, err := group.GetGroupMembersByNames(ctx, groupNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+
+	expanded := make([]string, 0, len(names))
+	added := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		userIDs, ok := memberMap[name]
+		if !ok || name == "traP" {
+			userIDs = []string{name}
+		}
+
+		for _, userID := range userIDs {
+			if _, ok := added[userID]; ok {
+				continue
+			}
+			added[userID] = struct{}{}
+			expanded = append(expanded, userID)
+		}
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,290 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+)
+
+func TestGetGroups(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+	mockTransaction := &model.MockTransaction{}
+
+	group := NewGroup(mockGroup, mockTransaction)
+
+	groups := []model.GroupInfo{
+		{
+			Groups: model.Groups{
+				ID:   "00000000-0000-0000-0000-000000000001",
+				Name: "SysAd",
+			},
+			Members: []string{"mazrean", "ryoha"},
+		},
+	}
+	sb := strings.Builder{}
+	err := json.NewEncoder(&sb).Encode(groups)
+	if err != nil {
+		t.Errorf("failed to encode groups: %v", err)
+		return
+	}
+
+	type test struct {
+		description    string
+		groups         []model.GroupInfo
+		getGroupsError error
+		statusCode     int
+		body           string
+	}
+
+	testCases := []test{
+		{
+			description:    "GetGroupsがエラーなので500",
+			getGroupsError: errMock,
+			statusCode:     http.StatusInternalServerError,
+		},
+		{
+			description: "グループ一覧がそのまま帰り200",
+			groups:      groups,
+			statusCode:  http.StatusOK,
+			body:        sb.String(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/groups", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/groups")
+
+		mockGroup.
+			EXPECT().
+			GetGroups(c.Request().Context()).
+			Return(testCase.groups, testCase.getGroupsError)
+
+		e.HTTPErrorHandler(group.GetGroups(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+		if testCase.statusCode == http.StatusOK {
+			assertion.Equalf(testCase.body, rec.Body.String(), testCase.description, "body")
+		}
+	}
+}
+
+func TestPutGroups(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+	mockTransaction := &model.MockTransaction{}
+
+	group := NewGroup(mockGroup, mockTransaction)
+
+	type test struct {
+		description     string
+		invalidRequest  bool
+		request         PutGroupsRequest
+		executesSync    bool
+		syncGroupsError error
+		statusCode      int
+	}
+
+	testCases := []test{
+		{
+			description:    "リクエストの形式が誤っているので400",
+			invalidRequest: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			description: "グループ名が空なので400",
+			request: PutGroupsRequest{
+				{
+					Groups:  model.Groups{ID: "00000000-0000-0000-0000-000000000001"},
+					Members: []string{},
+				},
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			description: "グループ名が重複しているので400",
+			request: PutGroupsRequest{
+				{
+					Groups:  model.Groups{ID: "00000000-0000-0000-0000-000000000001", Name: "SysAd"},
+					Members: []string{},
+				},
+				{
+					Groups:  model.Groups{ID: "00000000-0000-0000-0000-000000000002", Name: "SysAd"},
+					Members: []string{},
+				},
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			description: "SyncGroupsがエラーなので500",
+			request: PutGroupsRequest{
+				{
+					Groups:  model.Groups{ID: "00000000-0000-0000-0000-000000000001", Name: "SysAd"},
+					Members: []string{"mazrean"},
+				},
+			},
+			executesSync:    true,
+			syncGroupsError: errMock,
+			statusCode:      http.StatusInternalServerError,
+		},
+		{
+			description: "正常に同期できるので200",
+			request: PutGroupsRequest{
+				{
+					Groups:  model.Groups{ID: "00000000-0000-0000-0000-000000000001", Name: "SysAd"},
+					Members: []string{"mazrean"},
+				},
+			},
+			executesSync: true,
+			statusCode:   http.StatusOK,
+		},
+		{
+			description:  "グループが空でも200",
+			request:      PutGroupsRequest{},
+			executesSync: true,
+			statusCode:   http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var requestBody []byte
+		if testCase.invalidRequest {
+			requestBody = []byte("invalid")
+		} else {
+			var err error
+			requestBody, err = json.Marshal(testCase.request)
+			if err != nil {
+				t.Errorf("failed to marshal request: %v", err)
+				continue
+			}
+		}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/groups", bytes.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/groups")
+		c.Set(validatorKey, validator.New())
+
+		if testCase.executesSync {
+			mockGroup.
+				EXPECT().
+				SyncGroups(gomock.Any(), gomock.Any()).
+				Return(testCase.syncGroupsError)
+		}
+
+		e.HTTPErrorHandler(group.PutGroups(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+	}
+}
+
+func TestExpandGroups(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+
+	type test struct {
+		description   string
+		names         []string
+		executesGet   bool
+		groupNames    []string
+		memberMap     map[string][]string
+		getGroupError error
+		expect        []string
+		isErr         bool
+	}
+
+	testCases := []test{
+		{
+			description: "空ならそのまま",
+			names:       []string{},
+			expect:      []string{},
+		},
+		{
+			description: "グループ名がメンバーに展開される",
+			names:       []string{"SysAd", "temma"},
+			executesGet: true,
+			groupNames:  []string{"SysAd", "temma"},
+			memberMap: map[string][]string{
+				"SysAd": {"mazrean", "ryoha"},
+			},
+			expect: []string{"mazrean", "ryoha", "temma"},
+		},
+		{
+			description: "重複したユーザーは1つにまとめられる",
+			names:       []string{"ryoha", "SysAd"},
+			executesGet: true,
+			groupNames:  []string{"ryoha", "SysAd"},
+			memberMap: map[string][]string{
+				"SysAd": {"mazrean", "ryoha"},
+			},
+			expect: []string{"ryoha", "mazrean"},
+		},
+		{
+			description: "traPは展開されない",
+			names:       []string{"traP"},
+			executesGet: true,
+			groupNames:  []string{},
+			memberMap:   map[string][]string{},
+			expect:      []string{"traP"},
+		},
+		{
+			description:   "GetGroupMembersByNamesがエラーなのでエラー",
+			names:         []string{"SysAd"},
+			executesGet:   true,
+			groupNames:    []string{"SysAd"},
+			getGroupError: errMock,
+			isErr:         true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+
+		if testCase.executesGet {
+			mockGroup.
+				EXPECT().
+				GetGroupMembersByNames(ctx, testCase.groupNames).
+				Return(testCase.memberMap, testCase.getGroupError)
+		}
+
+		actual, err := expandGroups(ctx, mockGroup, testCase.names)
+		if testCase.isErr {
+			assertion.Error(err, testCase.description)
+			continue
+		}
+		if !assertion.NoError(err, testCase.description) {
+			continue
+		}
+
+		assertion.Equal(testCase.expect, actual, testCase.description)
+	}
+}
@@ -0,0 +1,263 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/traPtitech/anke-to/model"
+)
+
+// MessageTemplate MessageTemplateの構造体
+type MessageTemplate struct {
+	model.IQuestionnaire
+	model.IRespondent
+	model.IQuestion
+	model.IResponse
+	*MessageRenderer
+}
+
+// NewMessageTemplate MessageTemplateのコンストラクタ
+func NewMessageTemplate(questionnaire model.IQuestionnaire, respondent model.IRespondent, question model.IQuestion, response model.IResponse, messageRenderer *MessageRenderer) *MessageTemplate {
+	return &MessageTemplate{
+		IQuestionnaire:  questionnaire,
+		IRespondent:     respondent,
+		IQuestion:       question,
+		IResponse:       response,
+		MessageRenderer: messageRenderer,
+	}
+}
+
+type MessageTemplateResponse struct {
+	Kind      string    `json:"kind"`
+	Template  string    `json:"template"`
+	IsDefault bool      `json:"is_default"`
+	UpdatedAt null.Time `json:"updated_at"`
+}
+
+// GetMessageTemplates GET /questionnaires/:questionnaireID/message-templates
+func (mt *MessageTemplate) GetMessageTemplates(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	messageTemplates, err := mt.GetQuestionnaireMessageTemplates(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get message templates: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	messageTemplateMap := make(map[string]model.QuestionnaireMessageTemplates, len(messageTemplates))
+	for _, messageTemplate := range messageTemplates {
+		messageTemplateMap[messageTemplate.Kind] = messageTemplate
+	}
+
+	res := make([]MessageTemplateResponse, 0, len(model.MessageTemplateKinds))
+	for _, kind := range model.MessageTemplateKinds {
+		messageTemplate, ok := messageTemplateMap[kind]
+		if !ok {
+			res = append(res, MessageTemplateResponse{
+				Kind:      kind,
+				Template:  defaultMessageTemplates[kind],
+				IsDefault: true,
+				UpdatedAt: null.NewTime(messageTemplate.UpdatedAt, false),
+			})
+			continue
+		}
+
+		res = append(res, MessageTemplateResponse{
+			Kind:      kind,
+			Template:  messageTemplate.Template,
+			IsDefault: false,
+			UpdatedAt: null.TimeFrom(messageTemplate.UpdatedAt),
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+type PutMessageTemplateRequest struct {
+	Template string `json:"template"`
+}
+
+// PutMessageTemplate PUT /questionnaires/:questionnaireID/message-templates/:kind
+func (mt *MessageTemplate) PutMessageTemplate(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	kind := c.Param("kind")
+	if _, ok := defaultMessageTemplates[kind]; !ok {
+		c.Logger().Infof("invalid message template kind: %s", kind)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid kind: %s", kind))
+	}
+
+	req := PutMessageTemplateRequest{}
+	err = c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind PutMessageTemplateRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	err = mt.validateMessageTemplate(req.Template)
+	if err != nil {
+		c.Logger().Infof("invalid message template: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid template: %w", err))
+	}
+
+	err = mt.UpsertQuestionnaireMessageTemplate(c.Request().Context(), questionnaireID, kind, req.Template)
+	if err != nil {
+		c.Logger().Errorf("failed to upsert message template: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteMessageTemplate DELETE /questionnaires/:questionnaireID/message-templates/:kind
+func (mt *MessageTemplate) DeleteMessageTemplate(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	kind := c.Param("kind")
+	if _, ok := defaultMessageTemplates[kind]; !ok {
+		c.Logger().Infof("invalid message template kind: %s", kind)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid kind: %s", kind))
+	}
+
+	err = mt.DeleteQuestionnaireMessageTemplate(c.Request().Context(), questionnaireID, kind)
+	if errors.Is(err, model.ErrNoRecordDeleted) {
+		c.Logger().Infof("message template not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "message template not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to delete message template: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type PostMessagePreviewRequest struct {
+	// Template 指定しない場合はアンケートに設定されたテンプレートを使う
+	Template null.String `json:"template"`
+}
+
+type PostMessagePreviewResponse struct {
+	Message string `json:"message"`
+}
+
+// PostMessagePreview POST /questionnaires/:questionnaireID/message-templates/:kind/preview
+// traQには送信せずに、アンケートの現在の内容でメッセージを作って返す
+func (mt *MessageTemplate) PostMessagePreview(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	kind := c.Param("kind")
+	if _, ok := defaultMessageTemplates[kind]; !ok {
+		c.Logger().Infof("invalid message template kind: %s", kind)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid kind: %s", kind))
+	}
+
+	req := PostMessagePreviewRequest{}
+	err = c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind PostMessagePreviewRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	if req.Template.Valid {
+		err = mt.validateMessageTemplate(req.Template.String)
+		if err != nil {
+			c.Logger().Infof("invalid message template: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid template: %w", err))
+		}
+	}
+
+	questionnaire, targets, administrators, respondents, err := mt.GetQuestionnaireInfo(c.Request().Context(), questionnaireID)
+	if errors.Is(err, model.ErrRecordNotFound) {
+		c.Logger().Infof("questionnaire not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaire info: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	// プレビューでは対象者のうち回答していない人を未回答者とする
+	responded := make(map[string]struct{}, len(respondents))
+	for _, respondent := range respondents {
+		responded[respondent] = struct{}{}
+	}
+	nonRespondents := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if _, ok := responded[target]; !ok {
+			nonRespondents = append(nonRespondents, target)
+		}
+	}
+
+	data := MessageData{
+		QuestionnaireID: questionnaire.ID,
+		Title:           questionnaire.Title,
+		Description:     questionnaire.Description,
+		Administrators:  administrators,
+		Targets:         targets,
+		ResTimeLimit:    questionnaire.ResTimeLimit,
+		NonRespondents:  nonRespondents,
+	}
+
+	// 回答数などは締め切り後の結果の通知でしか使わないので、その場合だけ集計する
+	if kind == model.MessageTemplateKindClosing {
+		data.ResponseCount, err = mt.GetRespondentCount(c.Request().Context(), questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to get respondent count: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+		data.TargetCount = len(targets)
+		data.RespondedTargetCount = len(targets) - len(nonRespondents)
+
+		if questionnaire.ResSharedTo == "public" {
+			questions, err := mt.GetQuestions(c.Request().Context(), questionnaireID)
+			if err != nil {
+				c.Logger().Errorf("failed to get questions: %+v", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, err)
+			}
+
+			statistics, err := mt.GetQuestionStatistics(c.Request().Context(), questionnaireID)
+			if err != nil {
+				c.Logger().Errorf("failed to get question statistics: %+v", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, err)
+			}
+
+			data.QuestionSummaries = newQuestionSummaries(questions, statistics)
+		}
+	}
+
+	var message string
+	if req.Template.Valid {
+		message, err = mt.renderMessageTemplate(req.Template.String, data)
+	} else {
+		message, err = mt.RenderMessage(c.Request().Context(), kind, data)
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to render message: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, PostMessagePreviewResponse{
+		Message: message,
+	})
+}
@@ -0,0 +1,437 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestGetMessageTemplates(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+
+	messageTemplate := NewMessageTemplate(mockQuestionnaire, mockRespondent, mockQuestion, mockResponse, NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}))
+
+	type test struct {
+		description      string
+		messageTemplates []model.QuestionnaireMessageTemplates
+		getError         error
+		expectStatusCode int
+	}
+
+	testCases := []test{
+		{
+			description:      "テンプレートが設定されていなければデフォルトを返して200",
+			messageTemplates: []model.QuestionnaireMessageTemplates{},
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			description: "設定されたテンプレートを返して200",
+			messageTemplates: []model.QuestionnaireMessageTemplates{
+				{QuestionnaireID: 1, Kind: model.MessageTemplateKindReminder, Template: "{{.Title}}", UpdatedAt: time.Now()},
+			},
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			description:      "GetQuestionnaireMessageTemplatesがエラーなので500",
+			getError:         errors.New("error"),
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/questionnaires/1/message-templates", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID/message-templates")
+			c.SetParamNames("questionnaireID")
+			c.SetParamValues("1")
+
+			c.Set(questionnaireIDKey, 1)
+
+			mockMessageTemplate.
+				EXPECT().
+				GetQuestionnaireMessageTemplates(c.Request().Context(), 1).
+				Return(testCase.messageTemplates, testCase.getError)
+
+			e.HTTPErrorHandler(messageTemplate.GetMessageTemplates(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+			if testCase.expectStatusCode != http.StatusOK {
+				return
+			}
+
+			var res []MessageTemplateResponse
+			err := json.NewDecoder(rec.Body).Decode(&res)
+			if err != nil {
+				t.Errorf("failed to decode response body: %v", err)
+			}
+
+			customized := make(map[string]string, len(testCase.messageTemplates))
+			for _, messageTemplate := range testCase.messageTemplates {
+				customized[messageTemplate.Kind] = messageTemplate.Template
+			}
+
+			assert.Len(t, res, len(model.MessageTemplateKinds), "length")
+			for _, messageTemplate := range res {
+				text, ok := customized[messageTemplate.Kind]
+				if ok {
+					assert.False(t, messageTemplate.IsDefault, "is_default")
+					assert.Equal(t, text, messageTemplate.Template, "template")
+					assert.True(t, messageTemplate.UpdatedAt.Valid, "updated_at")
+				} else {
+					assert.True(t, messageTemplate.IsDefault, "is_default")
+					assert.Equal(t, defaultMessageTemplates[messageTemplate.Kind], messageTemplate.Template, "template")
+					assert.False(t, messageTemplate.UpdatedAt.Valid, "updated_at")
+				}
+			}
+		})
+	}
+}
+
+func TestPutMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+
+	messageTemplate := NewMessageTemplate(mockQuestionnaire, mockRespondent, mockQuestion, mockResponse, NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}))
+
+	type test struct {
+		description      string
+		kind             string
+		request          PutMessageTemplateRequest
+		executesUpsert   bool
+		upsertError      error
+		expectStatusCode int
+	}
+
+	testCases := []test{
+		{
+			description:      "正しいテンプレートなので204",
+			kind:             model.MessageTemplateKindAnnouncement,
+			request:          PutMessageTemplateRequest{Template: "{{.Title}} {{mention .Targets}}"},
+			executesUpsert:   true,
+			expectStatusCode: http.StatusNoContent,
+		},
+		{
+			description:      "存在しない種類なので400",
+			kind:             "unknown",
+			request:          PutMessageTemplateRequest{Template: "{{.Title}}"},
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "構文が誤っているので400",
+			kind:             model.MessageTemplateKindAnnouncement,
+			request:          PutMessageTemplateRequest{Template: "{{.Title"},
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "存在しない値を参照しているので400",
+			kind:             model.MessageTemplateKindReminder,
+			request:          PutMessageTemplateRequest{Template: "{{.Unknown}}"},
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "空文字列なので400",
+			kind:             model.MessageTemplateKindClosing,
+			request:          PutMessageTemplateRequest{Template: ""},
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "UpsertQuestionnaireMessageTemplateがエラーなので500",
+			kind:             model.MessageTemplateKindClosing,
+			request:          PutMessageTemplateRequest{Template: "{{.ResultURL}}"},
+			executesUpsert:   true,
+			upsertError:      errors.New("error"),
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			reqBody, err := json.Marshal(testCase.request)
+			if err != nil {
+				t.Fatalf("failed to marshal request: %v", err)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/questionnaires/1/message-templates/%s", testCase.kind), strings.NewReader(string(reqBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID/message-templates/:kind")
+			c.SetParamNames("questionnaireID", "kind")
+			c.SetParamValues("1", testCase.kind)
+
+			c.Set(questionnaireIDKey, 1)
+
+			if testCase.executesUpsert {
+				mockMessageTemplate.
+					EXPECT().
+					UpsertQuestionnaireMessageTemplate(c.Request().Context(), 1, testCase.kind, testCase.request.Template).
+					Return(testCase.upsertError)
+			}
+
+			e.HTTPErrorHandler(messageTemplate.PutMessageTemplate(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+		})
+	}
+}
+
+func TestDeleteMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+
+	messageTemplate := NewMessageTemplate(mockQuestionnaire, mockRespondent, mockQuestion, mockResponse, NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}))
+
+	type test struct {
+		description      string
+		kind             string
+		executesDelete   bool
+		deleteError      error
+		expectStatusCode int
+	}
+
+	testCases := []test{
+		{
+			description:      "エラーなしなので204",
+			kind:             model.MessageTemplateKindAnnouncement,
+			executesDelete:   true,
+			expectStatusCode: http.StatusNoContent,
+		},
+		{
+			description:      "存在しない種類なので400",
+			kind:             "unknown",
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "テンプレートが設定されていないので404",
+			kind:             model.MessageTemplateKindReminder,
+			executesDelete:   true,
+			deleteError:      model.ErrNoRecordDeleted,
+			expectStatusCode: http.StatusNotFound,
+		},
+		{
+			description:      "DeleteQuestionnaireMessageTemplateがエラーなので500",
+			kind:             model.MessageTemplateKindClosing,
+			executesDelete:   true,
+			deleteError:      errors.New("error"),
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/questionnaires/1/message-templates/%s", testCase.kind), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID/message-templates/:kind")
+			c.SetParamNames("questionnaireID", "kind")
+			c.SetParamValues("1", testCase.kind)
+
+			c.Set(questionnaireIDKey, 1)
+
+			if testCase.executesDelete {
+				mockMessageTemplate.
+					EXPECT().
+					DeleteQuestionnaireMessageTemplate(c.Request().Context(), 1, testCase.kind).
+					Return(testCase.deleteError)
+			}
+
+			e.HTTPErrorHandler(messageTemplate.DeleteMessageTemplate(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+		})
+	}
+}
+
+func TestPostMessagePreview(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+
+	messageTemplate := NewMessageTemplate(mockQuestionnaire, mockRespondent, mockQuestion, mockResponse, NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: "http://localhost:1323"}))
+
+	questionnaire := model.Questionnaires{
+		ID:           1,
+		Title:        "title",
+		Description:  "description",
+		ResTimeLimit: null.TimeFrom(time.Now().Add(time.Hour)),
+	}
+
+	type test struct {
+		description string
+		kind        string
+		request     string
+		// アンケートに設定されたテンプレート(空文字列の場合は設定なし)
+		storedTemplate      string
+		getInfoError        error
+		executesGetInfo     bool
+		executesGetTemplate bool
+		expectStatusCode    int
+		expectMessage       string
+	}
+
+	testCases := []test{
+		{
+			description:         "アンケートに設定されたテンプレートで作って200",
+			kind:                model.MessageTemplateKindAnnouncement,
+			request:             `{}`,
+			storedTemplate:      "{{.Title}} {{.ResponseURL}}",
+			executesGetInfo:     true,
+			executesGetTemplate: true,
+			expectStatusCode:    http.StatusOK,
+			expectMessage:       "title http://localhost:1323/responses/new/1",
+		},
+		{
+			description:         "テンプレートを指定しなければデフォルトのテンプレートで作って200",
+			kind:                model.MessageTemplateKindClosing,
+			request:             ``,
+			executesGetInfo:     true,
+			executesGetTemplate: true,
+			expectStatusCode:    http.StatusOK,
+			expectMessage: `### アンケート『[title](http://localhost:1323/questionnaires/1)』の回答が締め切られました
+#### 回答数
+1件
+#### 回答率
+50.0% (1/2人)
+#### 結果
+http://localhost:1323/results/1`,
+		},
+		{
+			description:      "指定したテンプレートで作って200",
+			kind:             model.MessageTemplateKindReminder,
+			request:          `{"template": "{{mention .NonRespondents}}"}`,
+			executesGetInfo:  true,
+			expectStatusCode: http.StatusOK,
+			expectMessage:    "@ryoha",
+		},
+		{
+			description:      "存在しない種類なので400",
+			kind:             "unknown",
+			request:          `{}`,
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "指定したテンプレートが誤っているので400",
+			kind:             model.MessageTemplateKindReminder,
+			request:          `{"template": "{{.Unknown}}"}`,
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "アンケートが存在しないので404",
+			kind:             model.MessageTemplateKindAnnouncement,
+			request:          `{}`,
+			getInfoError:     model.ErrRecordNotFound,
+			executesGetInfo:  true,
+			expectStatusCode: http.StatusNotFound,
+		},
+		{
+			description:      "GetQuestionnaireInfoがエラーなので500",
+			kind:             model.MessageTemplateKindAnnouncement,
+			request:          `{}`,
+			getInfoError:     errors.New("error"),
+			executesGetInfo:  true,
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/1/message-templates/%s/preview", testCase.kind), strings.NewReader(testCase.request))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID/message-templates/:kind/preview")
+			c.SetParamNames("questionnaireID", "kind")
+			c.SetParamValues(strconv.Itoa(questionnaire.ID), testCase.kind)
+
+			c.Set(questionnaireIDKey, questionnaire.ID)
+
+			if testCase.executesGetInfo {
+				mockQuestionnaire.
+					EXPECT().
+					GetQuestionnaireInfo(c.Request().Context(), questionnaire.ID).
+					Return(&questionnaire, []string{"mazrean", "ryoha"}, []string{"mazrean"}, []string{"mazrean"}, testCase.getInfoError)
+			}
+			if testCase.kind == model.MessageTemplateKindClosing && testCase.getInfoError == nil {
+				mockRespondent.
+					EXPECT().
+					GetRespondentCount(c.Request().Context(), questionnaire.ID).
+					Return(1, nil)
+			}
+			if testCase.executesGetTemplate {
+				var getTemplateError error
+				if len(testCase.storedTemplate) == 0 {
+					getTemplateError = model.ErrRecordNotFound
+				}
+				mockMessageTemplate.
+					EXPECT().
+					GetQuestionnaireMessageTemplate(c.Request().Context(), questionnaire.ID, testCase.kind).
+					Return(testCase.storedTemplate, getTemplateError)
+			}
+
+			e.HTTPErrorHandler(messageTemplate.PostMessagePreview(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+			if testCase.expectStatusCode != http.StatusOK {
+				return
+			}
+
+			var res PostMessagePreviewResponse
+			err := json.NewDecoder(rec.Body).Decode(&res)
+			if err != nil {
+				t.Errorf("failed to decode response body: %v", err)
+			}
+			assert.Equal(t, testCase.expectMessage, res.Message, "message")
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/traPtitech/anke-to/model"
+	"gopkg.in/guregu/null.v4"
+)
+
+// DefaultBaseURL anke-toのURLのデフォルト
+const DefaultBaseURL = "https://anke-to.trap.jp"
+
+// maxMessageTemplateLength メッセージのテンプレートの最大の長さ(traQのメッセージの最大の長さ)
+const maxMessageTemplateLength = 10000
+
+// maxSummaryOptions 締め切り後の結果の通知で質問ごとに載せる選択肢の数
+const maxSummaryOptions = 3
+
+// MessageConfig traQに送信するメッセージの設定
+type MessageConfig struct {
+	// BaseURL メッセージ中のリンクに使うanke-toのURL(末尾の/なし)
+	BaseURL string
+}
+
+// MessageData メッセージのテンプレートに渡す値
+type MessageData struct {
+	BaseURL         string
+	QuestionnaireID int
+	Title           string
+	Description     string
+	Administrators  []string
+	Targets         []string
+	ResTimeLimit    null.Time
+	// NonRespondents リマインドでメンションする未回答者
+	NonRespondents []string
+	// ResponseCount 締め切り後の結果の通知での回答数
+	ResponseCount int
+	// TargetCount 締め切り後の結果の通知での対象者の人数(traPは全てのユーザーに展開する)
+	TargetCount int
+	// RespondedTargetCount 締め切り後の結果の通知での対象者のうち回答した人数
+	RespondedTargetCount int
+	// QuestionSummaries 締め切り後の結果の通知での選択肢のある質問の集計(結果が全体に公開されている場合のみ)
+	QuestionSummaries []QuestionSummary
+}
+
+// QuestionSummary 選択肢のある質問の回答数の多い選択肢
+type QuestionSummary struct {
+	Body    string
+	Options []model.OptionCount
+}
+
+// ResponseRate 対象者の回答率(%)
+func (d MessageData) ResponseRate() float64 {
+	if d.TargetCount == 0 {
+		return 0
+	}
+
+	return float64(d.RespondedTargetCount) / float64(d.TargetCount) * 100
+}
+
+// QuestionnaireURL アンケートのページのURL
+func (d MessageData) QuestionnaireURL() string {
+	return fmt.Sprintf("%s/questionnaires/%d", d.BaseURL, d.QuestionnaireID)
+}
+
+// ResponseURL アンケートに回答するページのURL
+func (d MessageData) ResponseURL() string {
+	return fmt.Sprintf("%s/responses/new/%d", d.BaseURL, d.QuestionnaireID)
+}
+
+// ResultURL アンケートの結果のページのURL
+func (d MessageData) ResultURL() string {
+	return fmt.Sprintf("%s/results/%d", d.BaseURL, d.QuestionnaireID)
+}
+
+// messageTemplateFuncs メッセージのテンプレートで使える関数
+var messageTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"mention": func(userIDs []string) string {
+		if len(userIDs) == 0 {
+			return ""
+		}
+
+		return "@" + strings.Join(userIDs, " @")
+	},
+	"formatTime": func(t time.Time) string {
+		return t.Local().Format("2006/01/02 15:04")
+	},
+}
+
+// defaultMessageTemplates メッセージの種類ごとのデフォルトのテンプレート
+var defaultMessageTemplates = map[string]string{
+	model.MessageTemplateKindAnnouncement: `### アンケート『[{{.Title}}]({{.QuestionnaireURL}})』が作成されました
+#### 管理者
+{{join .Administrators ","}}
+#### 説明
+{{.Description}}
+#### 回答期限
+{{if .ResTimeLimit.Valid}}{{formatTime .ResTimeLimit.Time}}{{else}}なし{{end}}
+#### 対象者
+{{if .Targets}}{{mention .Targets}}{{else}}なし{{end}}
+#### 回答リンク
+{{.ResponseURL}}`,
+	model.MessageTemplateKindReminder: `### アンケート『[{{.Title}}]({{.QuestionnaireURL}})』の回答期限が近づいています
+#### 回答期限
+{{formatTime .ResTimeLimit.Time}}{{if .NonRespondents}}
+#### 未回答者
+{{mention .NonRespondents}}{{end}}
+#### 回答リンク
+{{.ResponseURL}}`,
+	model.MessageTemplateKindClosing: `### アンケート『[{{.Title}}]({{.QuestionnaireURL}})』の回答が締め切られました
+#### 回答数
+{{.ResponseCount}}件{{if .TargetCount}}
+#### 回答率
+{{printf "%.1f" .ResponseRate}}% ({{.RespondedTargetCount}}/{{.TargetCount}}人){{end}}{{range .QuestionSummaries}}
+#### {{.Body}}{{range .Options}}
+- {{.Body}}: {{.Count}}件{{end}}{{end}}
+#### 結果
+{{.ResultURL}}`,
+}
+
+// MessageRenderer traQに送信するメッセージをテンプレートから作る構造体
+type MessageRenderer struct {
+	model.IMessageTemplate
+	config MessageConfig
+}
+
+// NewMessageRenderer MessageRendererのコンストラクタ
+func NewMessageRenderer(messageTemplate model.IMessageTemplate, config MessageConfig) *MessageRenderer {
+	return &MessageRenderer{
+		IMessageTemplate: messageTemplate,
+		config:           config,
+	}
+}
+
+// RenderMessage アンケートのメッセージを作る
+// アンケートにテンプレートが設定されていればそれを、なければデフォルトのテンプレートを使う
+// 設定されたテンプレートで作れない場合も、告知などが止まらないようにデフォルトのテンプレートを使う
+func (r *MessageRenderer) RenderMessage(ctx context.Context, kind string, data MessageData) (string, error) {
+	defaultTemplate, ok := defaultMessageTemplates[kind]
+	if !ok {
+		return "", model.ErrInvalidMessageTemplateKind
+	}
+
+	text, err := r.GetQuestionnaireMessageTemplate(ctx, data.QuestionnaireID, kind)
+	if errors.Is(err, model.ErrRecordNotFound) {
+		return r.renderMessageTemplate(defaultTemplate, data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get message template: %w", err)
+	}
+
+	message, err := r.renderMessageTemplate(text, data)
+	if err != nil {
+		log.Printf("failed to render message template(questionnaire: %d, kind: %s), fallback to default: %+v", data.QuestionnaireID, kind, err)
+		return r.renderMessageTemplate(defaultTemplate, data)
+	}
+
+	return message, nil
+}
+
+// renderMessageTemplate テンプレートからメッセージを作る
+func (r *MessageRenderer) renderMessageTemplate(text string, data MessageData) (string, error) {
+	tmpl, err := parseMessageTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	data.BaseURL = r.config.BaseURL
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute message template: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// validateMessageTemplate 管理者が設定するテンプレートの確認
+// 存在しない値を参照していないかを、全ての値を埋めたデータで実際に作って確かめる
+func (r *MessageRenderer) validateMessageTemplate(text string) error {
+	if len(text) == 0 {
+		return errors.New("template is empty")
+	}
+	if len([]rune(text)) > maxMessageTemplateLength {
+		return fmt.Errorf("template is longer than %d characters", maxMessageTemplateLength)
+	}
+
+	_, err := r.renderMessageTemplate(text, MessageData{
+		QuestionnaireID:      1,
+		Title:                "title",
+		Description:          "description",
+		Administrators:       []string{"administrator"},
+		Targets:              []string{"target"},
+		ResTimeLimit:         null.TimeFrom(time.Now()),
+		NonRespondents:       []string{"target"},
+		ResponseCount:        1,
+		TargetCount:          1,
+		RespondedTargetCount: 1,
+		QuestionSummaries: []QuestionSummary{
+			{
+				Body: "question",
+				Options: []model.OptionCount{
+					{Body: "option", Count: 1, Percentage: 100},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// newQuestionSummaries 選択肢のある質問ごとに回答数の多い選択肢をまとめる
+// 回答数が同じ選択肢は元の順番のままにする
+func newQuestionSummaries(questions []model.Questions, statistics []model.QuestionStatistics) []QuestionSummary {
+	statisticsMap := make(map[int]model.QuestionStatistics, len(statistics))
+	for _, statistic := range statistics {
+		statisticsMap[statistic.QuestionID] = statistic
+	}
+
+	summaries := []QuestionSummary{}
+	for _, question := range questions {
+		statistic, ok := statisticsMap[question.ID]
+		if !ok || len(statistic.Options) == 0 {
+			continue
+		}
+
+		options := make([]model.OptionCount, len(statistic.Options))
+		copy(options, statistic.Options)
+		sort.SliceStable(options, func(i, j int) bool { return options[i].Count > options[j].Count })
+		if len(options) > maxSummaryOptions {
+			options = options[:maxSummaryOptions]
+		}
+
+		summaries = append(summaries, QuestionSummary{
+			Body:    question.Body,
+			Options: options,
+		})
+	}
+
+	return summaries
+}
+
+func parseMessageTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.
+		New("message").
+		Funcs(messageTemplateFuncs).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message template: %w", err)
+	}
+
+	return tmpl, nil
+}
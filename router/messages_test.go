@@ -0,0 +1,523 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestRenderMessage(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	resTimeLimit, err := time.ParseInLocation("2006/01/02 15:04", "2021/10/01 09:06", time.Local)
+	if err != nil {
+		t.Errorf("failed to parse time: %v", err)
+	}
+
+	data := MessageData{
+		QuestionnaireID: 1,
+		Title:           "title",
+		Description:     "description",
+		Administrators:  []string{"administrator1"},
+		Targets:         []string{"target1", "target2"},
+		ResTimeLimit:    null.TimeFrom(resTimeLimit),
+		NonRespondents:  []string{"target2"},
+	}
+
+	type test struct {
+		description string
+		baseURL     string
+		kind        string
+		data        MessageData
+		// アンケートに設定されたテンプレート(空文字列の場合は設定なし)
+		template         string
+		getTemplateError error
+		expectMessage    string
+		isErr            bool
+		err              error
+	}
+
+	testCases := []test{
+		{
+			description:   "設定されたテンプレートを使う",
+			baseURL:       DefaultBaseURL,
+			kind:          model.MessageTemplateKindAnnouncement,
+			data:          data,
+			template:      "{{.Title}} {{mention .Targets}} {{.ResponseURL}}",
+			expectMessage: "title @target1 @target2 https://anke-to.trap.jp/responses/new/1",
+		},
+		{
+			description:   "設定されたURLでリンクを作る",
+			baseURL:       "http://localhost:1323",
+			kind:          model.MessageTemplateKindAnnouncement,
+			data:          data,
+			template:      "{{.QuestionnaireURL}} {{.ResultURL}}",
+			expectMessage: "http://localhost:1323/questionnaires/1 http://localhost:1323/results/1",
+		},
+		{
+			description: "設定されたテンプレートで作れないのでデフォルトのテンプレートを使う",
+			baseURL:     DefaultBaseURL,
+			kind:        model.MessageTemplateKindClosing,
+			data:        data,
+			template:    "{{index .Targets 5}}",
+			expectMessage: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』の回答が締め切られました
+#### 回答数
+0件
+#### 結果
+https://anke-to.trap.jp/results/1`,
+		},
+		{
+			description: "回答率と選択肢の集計がある締め切り後の結果の通知のデフォルトのテンプレート",
+			baseURL:     DefaultBaseURL,
+			kind:        model.MessageTemplateKindClosing,
+			data: MessageData{
+				QuestionnaireID:      1,
+				Title:                "title",
+				ResponseCount:        3,
+				TargetCount:          3,
+				RespondedTargetCount: 2,
+				QuestionSummaries: []QuestionSummary{
+					{
+						Body: "question",
+						Options: []model.OptionCount{
+							{Body: "option1", Count: 2},
+							{Body: "option2", Count: 1},
+						},
+					},
+				},
+			},
+			expectMessage: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』の回答が締め切られました
+#### 回答数
+3件
+#### 回答率
+66.7% (2/3人)
+#### question
+- option1: 2件
+- option2: 1件
+#### 結果
+https://anke-to.trap.jp/results/1`,
+		},
+		{
+			description: "未回答者がいるリマインドのデフォルトのテンプレート",
+			baseURL:     DefaultBaseURL,
+			kind:        model.MessageTemplateKindReminder,
+			data:        data,
+			expectMessage: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』の回答期限が近づいています
+#### 回答期限
+2021/10/01 09:06
+#### 未回答者
+@target2
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+		},
+		{
+			description: "未回答者をメンションしないリマインドのデフォルトのテンプレート",
+			baseURL:     DefaultBaseURL,
+			kind:        model.MessageTemplateKindReminder,
+			data: MessageData{
+				QuestionnaireID: 1,
+				Title:           "title",
+				ResTimeLimit:    null.TimeFrom(resTimeLimit),
+			},
+			expectMessage: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』の回答期限が近づいています
+#### 回答期限
+2021/10/01 09:06
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+		},
+		{
+			description:      "GetQuestionnaireMessageTemplateがエラーなのでエラー",
+			baseURL:          DefaultBaseURL,
+			kind:             model.MessageTemplateKindAnnouncement,
+			data:             data,
+			getTemplateError: errors.New("failed to get message template"),
+			isErr:            true,
+		},
+		{
+			description: "存在しない種類なのでErrInvalidMessageTemplateKind",
+			baseURL:     DefaultBaseURL,
+			kind:        "unknown",
+			data:        data,
+			isErr:       true,
+			err:         model.ErrInvalidMessageTemplateKind,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+			r := NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: testCase.baseURL})
+
+			if _, ok := defaultMessageTemplates[testCase.kind]; ok {
+				getTemplateError := testCase.getTemplateError
+				if len(testCase.template) == 0 && getTemplateError == nil {
+					getTemplateError = model.ErrRecordNotFound
+				}
+				mockMessageTemplate.
+					EXPECT().
+					GetQuestionnaireMessageTemplate(gomock.Any(), testCase.data.QuestionnaireID, testCase.kind).
+					Return(testCase.template, getTemplateError)
+			}
+
+			message, err := r.RenderMessage(context.Background(), testCase.kind, testCase.data)
+			if testCase.isErr {
+				if testCase.err != nil {
+					assertion.ErrorIs(err, testCase.err, testCase.description, "error")
+				} else {
+					assertion.Error(err, testCase.description, "error")
+				}
+				return
+			}
+			if !assertion.NoError(err, testCase.description, "no error") {
+				return
+			}
+
+			assertion.Equal(testCase.expectMessage, message, testCase.description, "message")
+		})
+	}
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	r := NewMessageRenderer(nil, MessageConfig{BaseURL: DefaultBaseURL})
+
+	type test struct {
+		description string
+		template    string
+		isErr       bool
+	}
+
+	testCases := []test{
+		{
+			description: "デフォルトのテンプレートなのでエラーなし",
+			template:    defaultMessageTemplates[model.MessageTemplateKindAnnouncement],
+		},
+		{
+			description: "関数を使ってもエラーなし",
+			template:    "{{.Title}} {{join .Administrators \",\"}} {{formatTime .ResTimeLimit.Time}}",
+		},
+		{
+			description: "空文字列なのでエラー",
+			template:    "",
+			isErr:       true,
+		},
+		{
+			description: "構文が誤っているのでエラー",
+			template:    "{{.Title",
+			isErr:       true,
+		},
+		{
+			description: "存在しない値を参照しているのでエラー",
+			template:    "{{.Unknown}}",
+			isErr:       true,
+		},
+		{
+			description: "存在しない関数を使っているのでエラー",
+			template:    "{{unknown .Title}}",
+			isErr:       true,
+		},
+		{
+			description: "長すぎるのでエラー",
+			template:    strings.Repeat("あ", maxMessageTemplateLength+1),
+			isErr:       true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := r.validateMessageTemplate(testCase.template)
+		if testCase.isErr {
+			assertion.Error(err, testCase.description)
+		} else {
+			assertion.NoError(err, testCase.description)
+		}
+	}
+}
+
+func TestRenderMessageDefaultAnnouncement(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		questionnaireID int
+		title           string
+		description     string
+		administrators  []string
+		resTimeLimit    null.Time
+		targets         []string
+	}
+	type expect struct {
+		message string
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	tm, err := time.ParseInLocation("2006/01/02 15:04", "2021/10/01 09:06", time.Local)
+	if err != nil {
+		t.Errorf("failed to parse time: %v", err)
+	}
+
+	testCases := []test{
+		{
+			description: "通常の引数なので問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			description: "questionnaireIDが0でも問題なし",
+			args: args{
+				questionnaireID: 0,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/0)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/0`,
+			},
+		},
+		{
+			// 実際には発生しないけど念の為
+			description: "titleが空文字でも問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "",
+				description:     "description",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			description: "説明が空文字でも問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			description: "administrator複数人でも問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{"administrator1", "administrator2"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1,administrator2
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			// 実際には発生しないけど念の為
+			description: "administratorがいなくても問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			description: "回答期限なしでも問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.NewTime(time.Time{}, false),
+				targets:         []string{"target1"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+description
+#### 回答期限
+なし
+#### 対象者
+@target1
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			description: "対象者が複数人でも問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{"target1", "target2"},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+@target1 @target2
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+		{
+			description: "対象者がいなくても問題なし",
+			args: args{
+				questionnaireID: 1,
+				title:           "title",
+				description:     "description",
+				administrators:  []string{"administrator1"},
+				resTimeLimit:    null.TimeFrom(tm),
+				targets:         []string{},
+			},
+			expect: expect{
+				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
+#### 管理者
+administrator1
+#### 説明
+description
+#### 回答期限
+2021/10/01 09:06
+#### 対象者
+なし
+#### 回答リンク
+https://anke-to.trap.jp/responses/new/1`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+			r := NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL})
+
+			mockMessageTemplate.
+				EXPECT().
+				GetQuestionnaireMessageTemplate(gomock.Any(), testCase.args.questionnaireID, model.MessageTemplateKindAnnouncement).
+				Return("", model.ErrRecordNotFound)
+
+			message, err := r.RenderMessage(context.Background(), model.MessageTemplateKindAnnouncement, MessageData{
+				QuestionnaireID: testCase.args.questionnaireID,
+				Title:           testCase.args.title,
+				Description:     testCase.args.description,
+				Administrators:  testCase.args.administrators,
+				Targets:         testCase.args.targets,
+				ResTimeLimit:    testCase.args.resTimeLimit,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expect.message, message)
+		})
+	}
+}
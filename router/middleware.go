@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -18,15 +19,19 @@ type Middleware struct {
 	model.IRespondent
 	model.IQuestion
 	model.IQuestionnaire
+	model.ISystemAdmin
+	Authenticator
 }
 
 // NewMiddleware Middlewareのコンストラクタ
-func NewMiddleware(administrator model.IAdministrator, respondent model.IRespondent, question model.IQuestion, questionnaire model.IQuestionnaire) *Middleware {
+func NewMiddleware(administrator model.IAdministrator, respondent model.IRespondent, question model.IQuestion, questionnaire model.IQuestionnaire, systemAdmin model.ISystemAdmin, authenticator Authenticator) *Middleware {
 	return &Middleware{
 		IAdministrator: administrator,
 		IRespondent:    respondent,
 		IQuestion:      question,
 		IQuestionnaire: questionnaire,
+		ISystemAdmin:   systemAdmin,
+		Authenticator:  authenticator,
 	}
 }
 
@@ -47,16 +52,13 @@ func (*Middleware) SetValidatorMiddleware(next echo.HandlerFunc) echo.HandlerFun
 	}
 }
 
-/* 消せないアンケートの発生を防ぐための管理者
-暫定的にハードコーディングで対応*/
-var adminUserIDs = []string{"temma", "sappi_red", "ryoha", "mazrean", "xxarupakaxx", "asari"}
-
-// SetUserIDMiddleware X-Showcase-UserからユーザーIDを取得しセットする
-func (*Middleware) SetUserIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+// SetUserIDMiddleware AuthenticatorでユーザーIDを取得しセットする
+func (m *Middleware) SetUserIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		userID := c.Request().Header.Get("X-Showcase-User")
-		if userID == "" {
-			userID = "mds_boy"
+		userID, err := m.Authenticate(c.Request())
+		if err != nil {
+			c.Logger().Infof("failed to authenticate: %+v", err)
+			return echo.NewHTTPError(http.StatusUnauthorized, "You are not logged in")
 		}
 
 		c.Set(userIDKey, userID)
@@ -102,6 +104,28 @@ func (*Middleware) TrapRateLimitMiddlewareFunc() echo.MiddlewareFunc {
 	return middleware.RateLimiterWithConfig(config)
 }
 
+// SystemAdministratorAuthenticate anke-to全体の管理者かどうかの認証
+func (m *Middleware) SystemAdministratorAuthenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := getUserID(c)
+		if err != nil {
+			c.Logger().Errorf("failed to get userID: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+		}
+
+		isSystemAdmin, err := m.CheckSystemAdmin(c.Request().Context(), userID)
+		if err != nil {
+			c.Logger().Errorf("failed to check system admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are system administrator: %w", err))
+		}
+		if !isSystemAdmin {
+			return c.String(http.StatusForbidden, "You are not a system administrator.")
+		}
+
+		return next(c)
+	}
+}
+
 // QuestionnaireAdministratorAuthenticate アンケートの管理者かどうかの認証
 func (m *Middleware) QuestionnaireAdministratorAuthenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -118,13 +142,18 @@ func (m *Middleware) QuestionnaireAdministratorAuthenticate(next echo.HandlerFun
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid questionnaireID:%s(error: %w)", strQuestionnaireID, err))
 		}
 
-		for _, adminID := range adminUserIDs {
-			if userID == adminID {
-				c.Set(questionnaireIDKey, questionnaireID)
+		// 全体の管理者は全てのアンケートを管理できる
+		isSystemAdmin, err := m.CheckSystemAdmin(c.Request().Context(), userID)
+		if err != nil {
+			c.Logger().Errorf("failed to check system admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are system administrator: %w", err))
+		}
+		if isSystemAdmin {
+			c.Set(questionnaireIDKey, questionnaireID)
 
-				return next(c)
-			}
+			return next(c)
 		}
+
 		isAdmin, err := m.CheckQuestionnaireAdmin(c.Request().Context(), userID, questionnaireID)
 		if err != nil {
 			c.Logger().Errorf("failed to check questionnaire admin: %+v", err)
@@ -170,7 +199,12 @@ func (m *Middleware) ResponseReadAuthenticate(next echo.HandlerFunc) echo.Handle
 			c.Logger().Error("respondent is nil")
 			return echo.NewHTTPError(http.StatusInternalServerError)
 		}
-		if respondent.UserTraqid == userID {
+		isRespondent, err := m.isRespondent(c.Request().Context(), userID, respondent)
+		if err != nil {
+			c.Logger().Errorf("failed to check if you are a respondent: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are a respondent: %w", err))
+		}
+		if isRespondent {
 			return next(c)
 		}
 
@@ -182,6 +216,16 @@ func (m *Middleware) ResponseReadAuthenticate(next echo.HandlerFunc) echo.Handle
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("response not found:%d", responseID))
 		}
 
+		// 全体の管理者は全ての回答を閲覧できる
+		isSystemAdmin, err := m.CheckSystemAdmin(c.Request().Context(), userID)
+		if err != nil {
+			c.Logger().Errorf("failed to check system admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are system administrator: %w", err))
+		}
+		if isSystemAdmin {
+			return next(c)
+		}
+
 		// アンケートごとの回答閲覧権限チェック
 		responseReadPrivilegeInfo, err := m.GetResponseReadPrivilegeInfoByResponseID(c.Request().Context(), userID, responseID)
 		if errors.Is(err, model.ErrRecordNotFound) {
@@ -234,7 +278,12 @@ func (m *Middleware) RespondentAuthenticate(next echo.HandlerFunc) echo.HandlerF
 			c.Logger().Error("respondent is nil")
 			return echo.NewHTTPError(http.StatusInternalServerError)
 		}
-		if respondent.UserTraqid != userID {
+		isRespondent, err := m.isRespondent(c.Request().Context(), userID, respondent)
+		if err != nil {
+			c.Logger().Errorf("failed to check if you are a respondent: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are a respondent: %w", err))
+		}
+		if !isRespondent {
 			return c.String(http.StatusForbidden, "You are not a respondent of this response.")
 		}
 
@@ -244,6 +293,83 @@ func (m *Middleware) RespondentAuthenticate(next echo.HandlerFunc) echo.HandlerF
 	}
 }
 
+// ResponseRevisionReadAuthenticate 回答の過去の版を閲覧できるかの認証
+// 過去の版は回答者とアンケートの管理者のみ閲覧できる
+func (m *Middleware) ResponseRevisionReadAuthenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := getUserID(c)
+		if err != nil {
+			c.Logger().Errorf("failed to get userID: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+		}
+
+		strResponseID := c.Param("responseID")
+		responseID, err := strconv.Atoi(strResponseID)
+		if err != nil {
+			c.Logger().Infof("failed to convert responseID to int: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid responseID:%s(error: %w)", strResponseID, err))
+		}
+
+		// 回答者ならOK
+		respondent, err := m.GetRespondent(c.Request().Context(), responseID)
+		if errors.Is(err, model.ErrRecordNotFound) {
+			c.Logger().Infof("response not found: %+v", err)
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("response not found:%d", responseID))
+		}
+		if err != nil {
+			c.Logger().Errorf("failed to check if you are a respondent: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are a respondent: %w", err))
+		}
+		if respondent == nil {
+			c.Logger().Error("respondent is nil")
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		isRespondent, err := m.isRespondent(c.Request().Context(), userID, respondent)
+		if err != nil {
+			c.Logger().Errorf("failed to check if you are a respondent: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are a respondent: %w", err))
+		}
+		if isRespondent {
+			c.Set(responseIDKey, responseID)
+
+			return next(c)
+		}
+
+		// 回答者以外は一時保存の回答は閲覧できない
+		if !respondent.SubmittedAt.Valid {
+			c.Logger().Info("not submitted")
+
+			// Note: 一時保存の回答の存在もわかってはいけないので、Respondentが見つからない時と全く同じエラーを返す
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("response not found:%d", responseID))
+		}
+
+		// 全体の管理者は全ての回答を閲覧できる
+		isSystemAdmin, err := m.CheckSystemAdmin(c.Request().Context(), userID)
+		if err != nil {
+			c.Logger().Errorf("failed to check system admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are system administrator: %w", err))
+		}
+		if isSystemAdmin {
+			c.Set(responseIDKey, responseID)
+
+			return next(c)
+		}
+
+		isAdmin, err := m.CheckQuestionnaireAdmin(c.Request().Context(), userID, respondent.QuestionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to check questionnaire admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are administrator: %w", err))
+		}
+		if !isAdmin {
+			return c.String(http.StatusForbidden, "You do not have permission to view revisions of this response.")
+		}
+
+		c.Set(responseIDKey, responseID)
+
+		return next(c)
+	}
+}
+
 // QuestionAdministratorAuthenticate アンケートの管理者かどうかの認証
 func (m *Middleware) QuestionAdministratorAuthenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -260,13 +386,18 @@ func (m *Middleware) QuestionAdministratorAuthenticate(next echo.HandlerFunc) ec
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid questionID:%s(error: %w)", strQuestionID, err))
 		}
 
-		for _, adminID := range adminUserIDs {
-			if userID == adminID {
-				c.Set(questionIDKey, questionID)
+		// 全体の管理者は全てのアンケートを管理できる
+		isSystemAdmin, err := m.CheckSystemAdmin(c.Request().Context(), userID)
+		if err != nil {
+			c.Logger().Errorf("failed to check system admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are system administrator: %w", err))
+		}
+		if isSystemAdmin {
+			c.Set(questionIDKey, questionID)
 
-				return next(c)
-			}
+			return next(c)
 		}
+
 		isAdmin, err := m.CheckQuestionAdmin(c.Request().Context(), userID, questionID)
 		if err != nil {
 			c.Logger().Errorf("failed to check if you are a question administrator: %+v", err)
@@ -298,6 +429,16 @@ func (m *Middleware) ResultAuthenticate(next echo.HandlerFunc) echo.HandlerFunc
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid questionnaireID:%s(error: %w)", strQuestionnaireID, err))
 		}
 
+		// 全体の管理者は全てのアンケートの結果を閲覧できる
+		isSystemAdmin, err := m.CheckSystemAdmin(c.Request().Context(), userID)
+		if err != nil {
+			c.Logger().Errorf("failed to check system admin: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to check if you are system administrator: %w", err))
+		}
+		if isSystemAdmin {
+			return next(c)
+		}
+
 		responseReadPrivilegeInfo, err := m.GetResponseReadPrivilegeInfoByQuestionnaireID(c.Request().Context(), userID, questionnaireID)
 		if errors.Is(err, model.ErrRecordNotFound) {
 			c.Logger().Infof("response not found: %+v", err)
@@ -320,6 +461,24 @@ func (m *Middleware) ResultAuthenticate(next echo.HandlerFunc) echo.HandlerFunc
 	}
 }
 
+// isRespondent ユーザーが回答の回答者かの確認
+// 匿名のアンケートの回答はtraQIDを記録していないので、ハッシュで確認する
+func (m *Middleware) isRespondent(ctx context.Context, userID string, respondent *model.Respondents) (bool, error) {
+	if respondent.UserTraqid == userID {
+		return true, nil
+	}
+	if !respondent.UserHash.Valid {
+		return false, nil
+	}
+
+	isRespondent, err := m.CheckAnonymousRespondent(ctx, userID, respondent.ResponseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check anonymous respondent: %w", err)
+	}
+
+	return isRespondent, nil
+}
+
 func checkResponseReadPrivilege(responseReadPrivilegeInfo *model.ResponseReadPrivilegeInfo) (bool, error) {
 	switch responseReadPrivilegeInfo.ResSharedTo {
 	case "administrators":
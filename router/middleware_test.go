@@ -39,14 +39,17 @@ func TestSetUserIDMiddleware(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
-	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire)
+	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire, mockSystemAdmin, newTestAuthenticator())
 
 	type args struct {
-		userID string
+		userID     string
+		remoteAddr string
 	}
 	type expect struct {
-		userID interface{}
+		statusCode int
+		userID     interface{}
 	}
 	type test struct {
 		description string
@@ -61,16 +64,17 @@ func TestSetUserIDMiddleware(t *testing.T) {
 				userID: "mazrean",
 			},
 			expect: expect{
-				userID: "mazrean",
+				statusCode: http.StatusOK,
+				userID:     "mazrean",
 			},
 		},
 		{
-			description: "ユーザーIDが空なのでmds_boy",
+			description: "ユーザーIDが空なので401",
 			args: args{
 				userID: "",
 			},
 			expect: expect{
-				userID: "mds_boy",
+				statusCode: http.StatusUnauthorized,
 			},
 		},
 		{
@@ -79,7 +83,18 @@ func TestSetUserIDMiddleware(t *testing.T) {
 				userID: "-",
 			},
 			expect: expect{
-				userID: "-",
+				statusCode: http.StatusOK,
+				userID:     "-",
+			},
+		},
+		{
+			description: "信頼していないプロキシからのリクエストなので401",
+			args: args{
+				userID:     "mazrean",
+				remoteAddr: "198.51.100.1:1234",
+			},
+			expect: expect{
+				statusCode: http.StatusUnauthorized,
 			},
 		},
 	}
@@ -91,13 +106,19 @@ func TestSetUserIDMiddleware(t *testing.T) {
 		c := e.NewContext(req, rec)
 
 		req.Header.Set("X-Showcase-User", testCase.args.userID)
+		if testCase.args.remoteAddr != "" {
+			req.RemoteAddr = testCase.args.remoteAddr
+		}
 
+		isCalled := false
 		e.HTTPErrorHandler(middleware.SetUserIDMiddleware(func(c echo.Context) error {
+			isCalled = true
 			assertion.Equal(testCase.expect.userID, c.Get(userIDKey), testCase.description, "userID")
 			return c.NoContent(http.StatusOK)
 		})(c), c)
 
-		assertion.Equal(http.StatusOK, rec.Code, testCase.description, "status code")
+		assertion.Equal(testCase.expect.statusCode, rec.Code, testCase.description, "status code")
+		assertion.Equal(testCase.expect.statusCode == http.StatusOK, isCalled, testCase.description, "isCalled")
 	}
 }
 
@@ -113,8 +134,9 @@ func TestTraPMemberAuthenticate(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
-	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire)
+	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire, mockSystemAdmin, newTestAuthenticator())
 
 	type args struct {
 		userID string
@@ -181,17 +203,22 @@ func TestResponseReadAuthenticate(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
-	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire)
+	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire, mockSystemAdmin, newTestAuthenticator())
 
 	type args struct {
 		userID                                        string
 		respondent                                    *model.Respondents
 		GetRespondentError                            error
+		isAnonymousRespondent                         bool
+		CheckAnonymousRespondentError                 error
 		ExecutesResponseReadPrivilegeCheck            bool
 		haveReadPrivilege                             bool
 		GetResponseReadPrivilegeInfoByResponseIDError error
 		checkResponseReadPrivilegeError               error
+		isSystemAdmin                                 bool
+		CheckSystemAdminError                         error
 	}
 	type expect struct {
 		statusCode int
@@ -217,6 +244,52 @@ func TestResponseReadAuthenticate(t *testing.T) {
 				isCalled:   true,
 			},
 		},
+		{
+			description: "匿名のアンケートの回答の回答者である場合通す",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					ResponseID: 1,
+					UserHash:   null.StringFrom("hash"),
+				},
+				isAnonymousRespondent: true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "匿名のアンケートの回答の回答者でなく、submitされていない場合404",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					ResponseID:  1,
+					UserHash:    null.StringFrom("hash"),
+					SubmittedAt: null.Time{},
+				},
+				isAnonymousRespondent: false,
+			},
+			expect: expect{
+				statusCode: http.StatusNotFound,
+				isCalled:   false,
+			},
+		},
+		{
+			description: "CheckAnonymousRespondentがエラーの場合500",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					ResponseID: 1,
+					UserHash:   null.StringFrom("hash"),
+				},
+				CheckAnonymousRespondentError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+				isCalled:   false,
+			},
+		},
 		{
 			description: "GetRespondentがErrRecordNotFoundの場合404",
 			args: args{
@@ -263,6 +336,51 @@ func TestResponseReadAuthenticate(t *testing.T) {
 				isCalled:   false,
 			},
 		},
+		{
+			description: "この回答の回答者でなくてもsubmitされていて全体の管理者の場合通す",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					UserTraqid:  "user2",
+					SubmittedAt: null.NewTime(time.Now(), true),
+				},
+				isSystemAdmin: true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "全体の管理者でもsubmitされていない場合404",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					UserTraqid:  "user2",
+					SubmittedAt: null.Time{},
+				},
+				isSystemAdmin: true,
+			},
+			expect: expect{
+				statusCode: http.StatusNotFound,
+				isCalled:   false,
+			},
+		},
+		{
+			description: "CheckSystemAdminがエラーの場合500",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					UserTraqid:  "user2",
+					SubmittedAt: null.NewTime(time.Now(), true),
+				},
+				CheckSystemAdminError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+				isCalled:   false,
+			},
+		},
 		{
 			description: "この回答の回答者でなくてもsubmitされていてhaveReadPrivilegeがtrueの場合通す",
 			args: args{
@@ -379,6 +497,24 @@ func TestResponseReadAuthenticate(t *testing.T) {
 			EXPECT().
 			GetRespondent(c.Request().Context(), responseID).
 			Return(testCase.args.respondent, testCase.args.GetRespondentError)
+		if testCase.args.respondent != nil &&
+			testCase.args.respondent.UserTraqid != testCase.args.userID &&
+			testCase.args.respondent.UserHash.Valid {
+			mockRespondent.
+				EXPECT().
+				CheckAnonymousRespondent(c.Request().Context(), testCase.args.userID, responseID).
+				Return(testCase.args.isAnonymousRespondent, testCase.args.CheckAnonymousRespondentError)
+		}
+		if testCase.args.respondent != nil &&
+			testCase.args.respondent.UserTraqid != testCase.args.userID &&
+			!testCase.args.isAnonymousRespondent &&
+			testCase.args.CheckAnonymousRespondentError == nil &&
+			testCase.args.respondent.SubmittedAt.Valid {
+			mockSystemAdmin.
+				EXPECT().
+				CheckSystemAdmin(c.Request().Context(), testCase.args.userID).
+				Return(testCase.args.isSystemAdmin, testCase.args.CheckSystemAdminError)
+		}
 		if testCase.args.ExecutesResponseReadPrivilegeCheck {
 			mockQuestionnaire.
 				EXPECT().
@@ -395,6 +531,214 @@ func TestResponseReadAuthenticate(t *testing.T) {
 	}
 }
 
+func TestResponseRevisionReadAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
+
+	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire, mockSystemAdmin, newTestAuthenticator())
+
+	type args struct {
+		userID                          string
+		respondent                      *model.Respondents
+		GetRespondentError              error
+		isSystemAdmin                   bool
+		CheckSystemAdminError           error
+		executesCheckQuestionnaireAdmin bool
+		isAdmin                         bool
+		CheckQuestionnaireAdminError    error
+	}
+	type expect struct {
+		statusCode int
+		isCalled   bool
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "この回答の回答者である場合通す",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user1",
+				},
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "GetRespondentがErrRecordNotFoundの場合404",
+			args: args{
+				userID:             "user1",
+				GetRespondentError: model.ErrRecordNotFound,
+			},
+			expect: expect{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "GetRespondentがエラー(ErrRecordNotFound以外)の場合500",
+			args: args{
+				userID:             "user1",
+				GetRespondentError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "回答者以外はsubmitされていない場合404",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user2",
+				},
+			},
+			expect: expect{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "全体の管理者の場合通す",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user2",
+					SubmittedAt:     null.NewTime(time.Now(), true),
+				},
+				isSystemAdmin: true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "CheckSystemAdminがエラーの場合500",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user2",
+					SubmittedAt:     null.NewTime(time.Now(), true),
+				},
+				CheckSystemAdminError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "アンケートの管理者の場合通す",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user2",
+					SubmittedAt:     null.NewTime(time.Now(), true),
+				},
+				executesCheckQuestionnaireAdmin: true,
+				isAdmin:                         true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "回答が公開されていてもアンケートの管理者でない場合403",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user2",
+					SubmittedAt:     null.NewTime(time.Now(), true),
+				},
+				executesCheckQuestionnaireAdmin: true,
+			},
+			expect: expect{
+				statusCode: http.StatusForbidden,
+			},
+		},
+		{
+			description: "CheckQuestionnaireAdminがエラーの場合500",
+			args: args{
+				userID: "user1",
+				respondent: &model.Respondents{
+					QuestionnaireID: 1,
+					UserTraqid:      "user2",
+					SubmittedAt:     null.NewTime(time.Now(), true),
+				},
+				executesCheckQuestionnaireAdmin: true,
+				CheckQuestionnaireAdminError:    errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		responseID := 1
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/responses/:responseID/revisions")
+		c.SetParamNames("responseID")
+		c.SetParamValues(strconv.Itoa(responseID))
+		c.Set(userIDKey, testCase.args.userID)
+
+		mockRespondent.
+			EXPECT().
+			GetRespondent(c.Request().Context(), responseID).
+			Return(testCase.args.respondent, testCase.args.GetRespondentError)
+		if testCase.args.respondent != nil &&
+			testCase.args.respondent.UserTraqid != testCase.args.userID &&
+			testCase.args.respondent.SubmittedAt.Valid {
+			mockSystemAdmin.
+				EXPECT().
+				CheckSystemAdmin(c.Request().Context(), testCase.args.userID).
+				Return(testCase.args.isSystemAdmin, testCase.args.CheckSystemAdminError)
+		}
+		if testCase.args.executesCheckQuestionnaireAdmin {
+			mockAdministrator.
+				EXPECT().
+				CheckQuestionnaireAdmin(c.Request().Context(), testCase.args.userID, testCase.args.respondent.QuestionnaireID).
+				Return(testCase.args.isAdmin, testCase.args.CheckQuestionnaireAdminError)
+		}
+
+		callChecker := CallChecker{}
+
+		e.HTTPErrorHandler(middleware.ResponseRevisionReadAuthenticate(callChecker.Handler)(c), c)
+
+		assertion.Equalf(testCase.expect.statusCode, rec.Code, testCase.description, "status code")
+		assertion.Equalf(testCase.expect.isCalled, callChecker.IsCalled, testCase.description, "isCalled")
+		if testCase.expect.isCalled {
+			assertion.Equalf(responseID, c.Get(responseIDKey), testCase.description, "responseID")
+		}
+	}
+}
+
 func TestResultAuthenticate(t *testing.T) {
 	t.Parallel()
 
@@ -407,10 +751,13 @@ func TestResultAuthenticate(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
-	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire)
+	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire, mockSystemAdmin, newTestAuthenticator())
 
 	type args struct {
+		isSystemAdmin                                 bool
+		CheckSystemAdminError                         error
 		haveReadPrivilege                             bool
 		GetResponseReadPrivilegeInfoByResponseIDError error
 		checkResponseReadPrivilegeError               error
@@ -426,6 +773,26 @@ func TestResultAuthenticate(t *testing.T) {
 	}
 
 	testCases := []test{
+		{
+			description: "全体の管理者の場合通す",
+			args: args{
+				isSystemAdmin: true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "CheckSystemAdminがエラーの場合500",
+			args: args{
+				CheckSystemAdminError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+				isCalled:   false,
+			},
+		},
 		{
 			description: "haveReadPrivilegeがtrueの場合通す",
 			args: args{
@@ -508,10 +875,16 @@ func TestResultAuthenticate(t *testing.T) {
 		c.SetParamValues(strconv.Itoa(questionnaireID))
 		c.Set(userIDKey, userID)
 
-		mockQuestionnaire.
+		mockSystemAdmin.
 			EXPECT().
-			GetResponseReadPrivilegeInfoByQuestionnaireID(c.Request().Context(), userID, questionnaireID).
-			Return(&responseReadPrivilegeInfo, testCase.args.GetResponseReadPrivilegeInfoByResponseIDError)
+			CheckSystemAdmin(c.Request().Context(), userID).
+			Return(testCase.args.isSystemAdmin, testCase.args.CheckSystemAdminError)
+		if !testCase.args.isSystemAdmin && testCase.args.CheckSystemAdminError == nil {
+			mockQuestionnaire.
+				EXPECT().
+				GetResponseReadPrivilegeInfoByQuestionnaireID(c.Request().Context(), userID, questionnaireID).
+				Return(&responseReadPrivilegeInfo, testCase.args.GetResponseReadPrivilegeInfoByResponseIDError)
+		}
 
 		callChecker := CallChecker{}
 
@@ -522,6 +895,93 @@ func TestResultAuthenticate(t *testing.T) {
 	}
 }
 
+func TestSystemAdministratorAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
+
+	middleware := NewMiddleware(mockAdministrator, mockRespondent, mockQuestion, mockQuestionnaire, mockSystemAdmin, newTestAuthenticator())
+
+	type args struct {
+		isSystemAdmin         bool
+		CheckSystemAdminError error
+	}
+	type expect struct {
+		statusCode int
+		isCalled   bool
+	}
+	type test struct {
+		description string
+		args
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "全体の管理者の場合通す",
+			args: args{
+				isSystemAdmin: true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isCalled:   true,
+			},
+		},
+		{
+			description: "全体の管理者でない場合403",
+			args: args{
+				isSystemAdmin: false,
+			},
+			expect: expect{
+				statusCode: http.StatusForbidden,
+				isCalled:   false,
+			},
+		},
+		{
+			description: "CheckSystemAdminがエラーの場合500",
+			args: args{
+				CheckSystemAdminError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+				isCalled:   false,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		userID := "testUser"
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/system-admins", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/system-admins")
+		c.Set(userIDKey, userID)
+
+		mockSystemAdmin.
+			EXPECT().
+			CheckSystemAdmin(c.Request().Context(), userID).
+			Return(testCase.args.isSystemAdmin, testCase.args.CheckSystemAdminError)
+
+		callChecker := CallChecker{}
+
+		e.HTTPErrorHandler(middleware.SystemAdministratorAuthenticate(callChecker.Handler)(c), c)
+
+		assertion.Equalf(testCase.expect.statusCode, rec.Code, testCase.description, "status code")
+		assertion.Equalf(testCase.expect.isCalled, callChecker.IsCalled, testCase.description, "isCalled")
+	}
+}
+
 func TestCheckResponseReadPrivilege(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/anke-to/model"
+)
+
+// maxOutboxMessages 一覧で返すoutboxのメッセージの最大数
+const maxOutboxMessages = 100
+
+// Outbox Outboxの構造体
+type Outbox struct {
+	model.IOutbox
+}
+
+// NewOutbox Outboxのコンストラクタ
+func NewOutbox(outbox model.IOutbox) *Outbox {
+	return &Outbox{
+		IOutbox: outbox,
+	}
+}
+
+// GetOutbox GET /outbox-messages
+func (o *Outbox) GetOutbox(c echo.Context) error {
+	status := c.QueryParam("status")
+
+	outboxMessages, err := o.GetOutboxMessages(c.Request().Context(), status, maxOutboxMessages)
+	if errors.Is(err, model.ErrInvalidOutboxStatus) {
+		c.Logger().Infof("invalid status: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid status: %s", status))
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get outbox messages: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, outboxMessages)
+}
+
+// PostOutboxReplay POST /outbox-messages/:outboxMessageID/replay
+func (o *Outbox) PostOutboxReplay(c echo.Context) error {
+	strOutboxMessageID := c.Param("outboxMessageID")
+	outboxMessageID, err := strconv.Atoi(strOutboxMessageID)
+	if err != nil {
+		c.Logger().Infof("failed to convert outboxMessageID to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse outboxMessageID(%s) to integer: %w", strOutboxMessageID, err))
+	}
+
+	err = o.ReplayOutboxMessage(c.Request().Context(), outboxMessageID)
+	if errors.Is(err, model.ErrRecordNotFound) {
+		c.Logger().Infof("outbox message not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "outbox message not found")
+	}
+	if errors.Is(err, model.ErrNoRecordUpdated) {
+		c.Logger().Infof("outbox message is not dead: %+v", err)
+		return echo.NewHTTPError(http.StatusConflict, "only dead outbox messages can be replayed")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to replay outbox message: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/traq"
+)
+
+const (
+	// outboxBatchSize 1回の送信で取得するメッセージの最大数
+	outboxBatchSize = 20
+	// outboxMaxAttempts メッセージの送信を諦めるまでの試行回数
+	outboxMaxAttempts = 8
+	// outboxRetryBaseDelay 1回目の失敗から再送までの間隔
+	outboxRetryBaseDelay = 30 * time.Second
+	// outboxRetryMaxDelay 再送までの間隔の上限
+	outboxRetryMaxDelay = time.Hour
+	// outboxLeaseDuration 取得したメッセージを送信中にしておく時間
+	// この時間を過ぎても送信済みにならなかったメッセージは、プロセスが終了したとみなして再送する
+	outboxLeaseDuration = 10 * time.Minute
+)
+
+// OutboxDispatcher outboxのメッセージをtraQに送信する構造体
+// メッセージは送信中にしてから送信するので、複数のプロセスで動かしても同じメッセージを同時に送信しない
+// 送信済みにする前にプロセスが終了すると同じメッセージを再送することがある
+type OutboxDispatcher struct {
+	model.IOutbox
+	traq.IClient
+}
+
+// NewOutboxDispatcher OutboxDispatcherのコンストラクタ
+func NewOutboxDispatcher(outbox model.IOutbox, client traq.IClient) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		IOutbox: outbox,
+		IClient: client,
+	}
+}
+
+// Run intervalごとに送信するメッセージを確認して送信する
+// ctxがキャンセルされるまで返らない
+func (d *OutboxDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := d.dispatch(ctx)
+		if err != nil {
+			log.Printf("failed to dispatch outbox messages: %+v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch 送信時刻を過ぎたメッセージを送信中にして送信する
+// 1つのメッセージの送信に失敗しても、他のメッセージの送信は続ける
+func (d *OutboxDispatcher) dispatch(ctx context.Context) error {
+	messages, err := d.ClaimOutboxMessagesToDispatch(ctx, outboxBatchSize, time.Now().Add(outboxLeaseDuration))
+	if err != nil {
+		return fmt.Errorf("failed to get outbox messages to dispatch: %w", err)
+	}
+
+	for _, message := range messages {
+		err = d.dispatchMessage(ctx, message)
+		if err != nil {
+			log.Printf("failed to dispatch outbox message(%d): %+v", message.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// dispatchMessage メッセージを送信する
+// 失敗した場合は間隔を空けて再送し、outboxMaxAttempts回失敗したら送信を諦める
+func (d *OutboxDispatcher) dispatchMessage(ctx context.Context, message model.OutboxMessages) error {
+	var postErr error
+	if message.UserTraqid.Valid {
+		postErr = d.PostDirectMessage(message.UserTraqid.String, message.Message)
+	} else {
+		postErr = d.PostMessage(message.Message)
+	}
+	if postErr == nil {
+		err := d.UpdateOutboxMessageDelivered(ctx, message.ID, message.ClaimedBy.String)
+		if err != nil {
+			return fmt.Errorf("failed to update outbox message delivered: %w", err)
+		}
+
+		return nil
+	}
+
+	attempts := message.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		err := d.UpdateOutboxMessageDead(ctx, message.ID, message.ClaimedBy.String, postErr.Error())
+		if err != nil {
+			return fmt.Errorf("failed to update outbox message dead: %w", err)
+		}
+
+		return fmt.Errorf("gave up posting message after %d attempts: %w", attempts, postErr)
+	}
+
+	err := d.UpdateOutboxMessageRetry(ctx, message.ID, message.ClaimedBy.String, postErr.Error(), time.Now().Add(outboxRetryDelay(attempts)))
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message retry: %w", err)
+	}
+
+	return fmt.Errorf("failed to post message: %w", postErr)
+}
+
+// outboxRetryDelay attempts回失敗したメッセージを再送するまでの間隔
+// 失敗するごとに2倍にし、outboxRetryMaxDelayを超えないようにする
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxRetryMaxDelay {
+			return outboxRetryMaxDelay
+		}
+	}
+
+	return delay
+}
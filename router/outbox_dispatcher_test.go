@@ -0,0 +1,217 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+	"github.com/traPtitech/anke-to/traq"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestDispatch(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type test struct {
+		description string
+		message     model.OutboxMessages
+		// traQの代わりのサーバーが返すステータスコード
+		traqStatusCode int
+		// traQの代わりのサーバーに接続できない
+		traqDown    bool
+		expectRetry bool
+		expectDead  bool
+	}
+
+	// 送信中にしたときの値で、送信済みや再送にするときに同じ値を指定する
+	claimedBy := "claim-token"
+
+	testCases := []test{
+		{
+			description:    "送信に成功したので送信済みにする",
+			message:        model.OutboxMessages{ID: 1, Message: "message", Attempts: 0},
+			traqStatusCode: http.StatusNoContent,
+		},
+		{
+			description:    "DMの送信に成功したので送信済みにする",
+			message:        model.OutboxMessages{ID: 1, UserTraqid: null.StringFrom("mazrean"), Message: "message", Attempts: 0},
+			traqStatusCode: http.StatusCreated,
+		},
+		{
+			description:    "traQがDMの送信でエラーを返したので再送する",
+			message:        model.OutboxMessages{ID: 1, UserTraqid: null.StringFrom("mazrean"), Message: "message", Attempts: 0},
+			traqStatusCode: http.StatusForbidden,
+			expectRetry:    true,
+		},
+		{
+			description:    "再送で送信に成功したので送信済みにする",
+			message:        model.OutboxMessages{ID: 1, Message: "message", Attempts: outboxMaxAttempts - 1},
+			traqStatusCode: http.StatusNoContent,
+		},
+		{
+			description:    "traQがエラーを返したので再送する",
+			message:        model.OutboxMessages{ID: 1, Message: "message", Attempts: 0},
+			traqStatusCode: http.StatusInternalServerError,
+			expectRetry:    true,
+		},
+		{
+			description: "traQに接続できないので再送する",
+			message:     model.OutboxMessages{ID: 1, Message: "message", Attempts: 2},
+			traqDown:    true,
+			expectRetry: true,
+		},
+		{
+			description:    "再送の上限に達したので送信を諦める",
+			message:        model.OutboxMessages{ID: 1, Message: "message", Attempts: outboxMaxAttempts - 1},
+			traqStatusCode: http.StatusServiceUnavailable,
+			expectDead:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOutbox := mock_model.NewMockIOutbox(ctrl)
+
+			var receivedBody string
+			var receivedSignature string
+			var receivedDirectMessage string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read request body: %v", err)
+				}
+				receivedBody = string(body)
+				receivedSignature = r.Header.Get("X-TRAQ-Signature")
+
+				w.WriteHeader(testCase.traqStatusCode)
+			})
+			mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `[{"id":"user-uuid","name":%q,"bot":false}]`, r.URL.Query().Get("name"))
+			})
+			mux.HandleFunc("/users/user-uuid/messages", func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Content string `json:"content"`
+				}
+				err := json.NewDecoder(r.Body).Decode(&body)
+				if err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+				}
+				receivedDirectMessage = body.Content
+
+				w.WriteHeader(testCase.traqStatusCode)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			if testCase.traqDown {
+				server.Close()
+			}
+
+			client := traq.NewClientWithURL(traq.NewWebhookWithURL(server.URL+"/webhook", "secret"), server.URL, "token")
+			d := NewOutboxDispatcher(mockOutbox, client)
+
+			message := testCase.message
+			message.Status = model.OutboxStatusSending
+			message.ClaimedBy = null.StringFrom(claimedBy)
+
+			var leaseUntil time.Time
+			mockOutbox.
+				EXPECT().
+				ClaimOutboxMessagesToDispatch(gomock.Any(), outboxBatchSize, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ int, t time.Time) ([]model.OutboxMessages, error) {
+					leaseUntil = t
+					return []model.OutboxMessages{message}, nil
+				})
+
+			var nextAttemptAt time.Time
+			switch {
+			case testCase.expectRetry:
+				mockOutbox.
+					EXPECT().
+					UpdateOutboxMessageRetry(gomock.Any(), testCase.message.ID, claimedBy, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _ string, _ string, t time.Time) error {
+						nextAttemptAt = t
+						return nil
+					})
+			case testCase.expectDead:
+				mockOutbox.
+					EXPECT().
+					UpdateOutboxMessageDead(gomock.Any(), testCase.message.ID, claimedBy, gomock.Any()).
+					Return(nil)
+			default:
+				mockOutbox.
+					EXPECT().
+					UpdateOutboxMessageDelivered(gomock.Any(), testCase.message.ID, claimedBy).
+					Return(nil)
+			}
+
+			now := time.Now()
+			err := d.dispatch(context.Background())
+			assertion.NoError(err, testCase.description)
+			assertion.WithinDuration(now.Add(outboxLeaseDuration), leaseUntil, time.Second, testCase.description, "leaseUntil")
+
+			if !testCase.traqDown {
+				if testCase.message.UserTraqid.Valid {
+					assertion.Equal(testCase.message.Message, receivedDirectMessage, testCase.description, "direct message")
+				} else {
+					assertion.Equal(testCase.message.Message, receivedBody, testCase.description, "body")
+					assertion.NotEmpty(receivedSignature, testCase.description, "signature")
+				}
+			}
+
+			if testCase.expectRetry {
+				expectNextAttemptAt := now.Add(outboxRetryDelay(testCase.message.Attempts + 1))
+				assertion.WithinDuration(expectNextAttemptAt, nextAttemptAt, time.Second, testCase.description, "nextAttemptAt")
+			}
+		})
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type test struct {
+		attempts int
+		expect   time.Duration
+	}
+
+	testCases := []test{
+		{
+			attempts: 1,
+			expect:   outboxRetryBaseDelay,
+		},
+		{
+			attempts: 2,
+			expect:   2 * outboxRetryBaseDelay,
+		},
+		{
+			attempts: 3,
+			expect:   4 * outboxRetryBaseDelay,
+		},
+		{
+			attempts: 100,
+			expect:   outboxRetryMaxDelay,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assertion.Equal(testCase.expect, outboxRetryDelay(testCase.attempts), "attempts: %d", testCase.attempts)
+	}
+}
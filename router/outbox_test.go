@@ -0,0 +1,153 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+)
+
+func TestGetOutbox(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+
+	outbox := NewOutbox(mockOutbox)
+
+	type test struct {
+		description            string
+		status                 string
+		outboxMessages         []model.OutboxMessages
+		getOutboxMessagesError error
+		statusCode             int
+	}
+
+	testCases := []test{
+		{
+			description: "状態を指定しなくても200",
+			outboxMessages: []model.OutboxMessages{
+				{ID: 1, Message: "message", Status: model.OutboxStatusDelivered},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			description: "状態を指定しても200",
+			status:      model.OutboxStatusDead,
+			outboxMessages: []model.OutboxMessages{
+				{ID: 2, Message: "message", Status: model.OutboxStatusDead, Attempts: outboxMaxAttempts},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			description:            "存在しない状態なので400",
+			status:                 "unknown",
+			getOutboxMessagesError: model.ErrInvalidOutboxStatus,
+			statusCode:             http.StatusBadRequest,
+		},
+		{
+			description:            "GetOutboxMessagesがエラーなので500",
+			getOutboxMessagesError: errMock,
+			statusCode:             http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/outbox-messages?status="+testCase.status, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/outbox-messages")
+
+		mockOutbox.
+			EXPECT().
+			GetOutboxMessages(c.Request().Context(), testCase.status, maxOutboxMessages).
+			Return(testCase.outboxMessages, testCase.getOutboxMessagesError)
+
+		e.HTTPErrorHandler(outbox.GetOutbox(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+	}
+}
+
+func TestPostOutboxReplay(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+
+	outbox := NewOutbox(mockOutbox)
+
+	type test struct {
+		description              string
+		outboxMessageID          string
+		executesReplay           bool
+		replayOutboxMessageError error
+		statusCode               int
+	}
+
+	testCases := []test{
+		{
+			description:     "IDが数字でないので400",
+			outboxMessageID: "abc",
+			statusCode:      http.StatusBadRequest,
+		},
+		{
+			description:     "送信を諦めたメッセージなので204",
+			outboxMessageID: "1",
+			executesReplay:  true,
+			statusCode:      http.StatusNoContent,
+		},
+		{
+			description:              "メッセージが存在しないので404",
+			outboxMessageID:          "1",
+			executesReplay:           true,
+			replayOutboxMessageError: model.ErrRecordNotFound,
+			statusCode:               http.StatusNotFound,
+		},
+		{
+			description:              "送信を諦めたメッセージでないので409",
+			outboxMessageID:          "1",
+			executesReplay:           true,
+			replayOutboxMessageError: model.ErrNoRecordUpdated,
+			statusCode:               http.StatusConflict,
+		},
+		{
+			description:              "ReplayOutboxMessageがエラーなので500",
+			outboxMessageID:          "1",
+			executesReplay:           true,
+			replayOutboxMessageError: errMock,
+			statusCode:               http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/outbox-messages/"+testCase.outboxMessageID+"/replay", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/outbox-messages/:outboxMessageID/replay")
+		c.SetParamNames("outboxMessageID")
+		c.SetParamValues(testCase.outboxMessageID)
+
+		if testCase.executesReplay {
+			mockOutbox.
+				EXPECT().
+				ReplayOutboxMessage(c.Request().Context(), 1).
+				Return(testCase.replayOutboxMessageError)
+		}
+
+		e.HTTPErrorHandler(outbox.PostOutboxReplay(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+	}
+}
@@ -0,0 +1,137 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/traPtitech/anke-to/model"
+)
+
+// checkQuestionConditions 質問に設定する分岐条件が質問の種類・ページと矛盾しないかの確認
+func checkQuestionConditions(questionType string, pageNum int, options []string, conditions []model.QuestionConditions) error {
+	questionTypeInfo, _ := model.GetQuestionTypeInfo(questionType)
+	for _, condition := range conditions {
+		if condition.SkipToPage <= pageNum {
+			return fmt.Errorf("skip_to_page(%d) must be after page_num(%d)", condition.SkipToPage, pageNum)
+		}
+
+		switch condition.ConditionType {
+		case "option":
+			if !questionTypeInfo.HasOptions {
+				return fmt.Errorf("option condition is not available for %s", questionType)
+			}
+
+			if !condition.OptionBody.Valid {
+				return errors.New("option is required for option condition")
+			}
+			isOption := false
+			for _, option := range options {
+				if option == condition.OptionBody.String {
+					isOption = true
+					break
+				}
+			}
+			if !isOption {
+				return fmt.Errorf("option(%s) does not exist", condition.OptionBody.String)
+			}
+		case "range":
+			if !questionTypeInfo.IsNumeric {
+				return fmt.Errorf("range condition is not available for %s", questionType)
+			}
+
+			if !condition.MinValue.Valid && !condition.MaxValue.Valid {
+				return errors.New("min_value or max_value is required for range condition")
+			}
+			if condition.MinValue.Valid && condition.MaxValue.Valid && condition.MinValue.Float64 > condition.MaxValue.Float64 {
+				return errors.New("min_value must be less than or equal to max_value")
+			}
+		case "answered", "unanswered":
+		default:
+			return fmt.Errorf("invalid condition type: %s", condition.ConditionType)
+		}
+	}
+
+	return nil
+}
+
+// calcHiddenQuestions 回答と分岐条件から、飛ばされたページにあり回答されるべきでない質問を求める
+// answersは質問IDごとの回答(選択肢の質問なら選んだ選択肢、それ以外なら回答本文)
+func calcHiddenQuestions(questions []model.Questions, conditions []model.QuestionConditions, answers map[int][]string) map[int]struct{} {
+	conditionMap := make(map[int][]model.QuestionConditions, len(conditions))
+	for _, condition := range conditions {
+		conditionMap[condition.QuestionID] = append(conditionMap[condition.QuestionID], condition)
+	}
+
+	sortedQuestions := make([]model.Questions, len(questions))
+	copy(sortedQuestions, questions)
+	sort.SliceStable(sortedQuestions, func(i, j int) bool {
+		if sortedQuestions[i].PageNum != sortedQuestions[j].PageNum {
+			return sortedQuestions[i].PageNum < sortedQuestions[j].PageNum
+		}
+		return sortedQuestions[i].QuestionNum < sortedQuestions[j].QuestionNum
+	})
+
+	// 分岐は後ろのページにしか飛ばないので、前のページから順に見れば
+	// 飛ばされたページの質問の回答で分岐することはない
+	skippedPages := map[int]struct{}{}
+	hiddenQuestions := map[int]struct{}{}
+	for _, question := range sortedQuestions {
+		if _, ok := skippedPages[question.PageNum]; ok {
+			hiddenQuestions[question.ID] = struct{}{}
+			continue
+		}
+
+		for _, condition := range conditionMap[question.ID] {
+			if !matchQuestionCondition(condition, answers[question.ID]) {
+				continue
+			}
+
+			for page := question.PageNum + 1; page < condition.SkipToPage; page++ {
+				skippedPages[page] = struct{}{}
+			}
+		}
+	}
+
+	return hiddenQuestions
+}
+
+func matchQuestionCondition(condition model.QuestionConditions, answer []string) bool {
+	values := make([]string, 0, len(answer))
+	for _, value := range answer {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	switch condition.ConditionType {
+	case "option":
+		for _, value := range values {
+			if value == condition.OptionBody.String {
+				return true
+			}
+		}
+	case "range":
+		if len(values) == 0 {
+			return false
+		}
+		value, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return false
+		}
+		if condition.MinValue.Valid && value < condition.MinValue.Float64 {
+			return false
+		}
+		if condition.MaxValue.Valid && value > condition.MaxValue.Float64 {
+			return false
+		}
+		return true
+	case "answered":
+		return len(values) != 0
+	case "unanswered":
+		return len(values) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,233 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestCheckQuestionConditions(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type test struct {
+		description  string
+		questionType string
+		pageNum      int
+		options      []string
+		conditions   []model.QuestionConditions
+		isErr        bool
+	}
+
+	testCases := []test{
+		{
+			description:  "分岐条件がなくてもエラーなし",
+			questionType: "Text",
+			pageNum:      1,
+			conditions:   []model.QuestionConditions{},
+		},
+		{
+			description:  "選択肢の分岐条件なのでエラーなし",
+			questionType: "MultipleChoice",
+			pageNum:      1,
+			options:      []string{"a", "b"},
+			conditions: []model.QuestionConditions{
+				{ConditionType: "option", OptionBody: null.StringFrom("a"), SkipToPage: 3},
+			},
+		},
+		{
+			description:  "存在しない選択肢なのでエラー",
+			questionType: "Checkbox",
+			pageNum:      1,
+			options:      []string{"a", "b"},
+			conditions: []model.QuestionConditions{
+				{ConditionType: "option", OptionBody: null.StringFrom("c"), SkipToPage: 3},
+			},
+			isErr: true,
+		},
+		{
+			description:  "選択肢がnullなのでエラー",
+			questionType: "Checkbox",
+			pageNum:      1,
+			options:      []string{"a", "b"},
+			conditions: []model.QuestionConditions{
+				{ConditionType: "option", SkipToPage: 3},
+			},
+			isErr: true,
+		},
+		{
+			description:  "選択肢のない質問に選択肢の分岐条件なのでエラー",
+			questionType: "Text",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "option", OptionBody: null.StringFrom("a"), SkipToPage: 3},
+			},
+			isErr: true,
+		},
+		{
+			description:  "数値の範囲の分岐条件なのでエラーなし",
+			questionType: "Number",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "range", MinValue: null.FloatFrom(0), MaxValue: null.FloatFrom(10), SkipToPage: 2},
+			},
+		},
+		{
+			description:  "範囲の片方だけでもエラーなし",
+			questionType: "LinearScale",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "range", MinValue: null.FloatFrom(3), SkipToPage: 2},
+			},
+		},
+		{
+			description:  "範囲が両方nullなのでエラー",
+			questionType: "Number",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "range", SkipToPage: 2},
+			},
+			isErr: true,
+		},
+		{
+			description:  "min_valueがmax_valueより大きいのでエラー",
+			questionType: "Number",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "range", MinValue: null.FloatFrom(10), MaxValue: null.FloatFrom(0), SkipToPage: 2},
+			},
+			isErr: true,
+		},
+		{
+			description:  "数値でない質問に範囲の分岐条件なのでエラー",
+			questionType: "Text",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "range", MinValue: null.FloatFrom(0), SkipToPage: 2},
+			},
+			isErr: true,
+		},
+		{
+			description:  "分岐先が同じページなのでエラー",
+			questionType: "Text",
+			pageNum:      2,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "answered", SkipToPage: 2},
+			},
+			isErr: true,
+		},
+		{
+			description:  "分岐条件の種類が不正なのでエラー",
+			questionType: "Text",
+			pageNum:      1,
+			conditions: []model.QuestionConditions{
+				{ConditionType: "invalid", SkipToPage: 2},
+			},
+			isErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := checkQuestionConditions(testCase.questionType, testCase.pageNum, testCase.options, testCase.conditions)
+		if testCase.isErr {
+			assertion.Error(err, testCase.description)
+		} else {
+			assertion.NoError(err, testCase.description)
+		}
+	}
+}
+
+func TestCalcHiddenQuestions(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	questions := []model.Questions{
+		{ID: 1, PageNum: 1, QuestionNum: 1, Type: "MultipleChoice"},
+		{ID: 2, PageNum: 1, QuestionNum: 2, Type: "Number"},
+		{ID: 3, PageNum: 2, QuestionNum: 1, Type: "Text"},
+		{ID: 4, PageNum: 3, QuestionNum: 1, Type: "Text"},
+		{ID: 5, PageNum: 4, QuestionNum: 1, Type: "Text"},
+	}
+
+	type test struct {
+		description string
+		conditions  []model.QuestionConditions
+		answers     map[int][]string
+		expect      map[int]struct{}
+	}
+
+	testCases := []test{
+		{
+			description: "分岐条件がないので全て回答する",
+			conditions:  []model.QuestionConditions{},
+			answers:     map[int][]string{1: {"a"}},
+			expect:      map[int]struct{}{},
+		},
+		{
+			description: "選択肢が一致したので間のページを飛ばす",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 1, ConditionType: "option", OptionBody: null.StringFrom("a"), SkipToPage: 4},
+			},
+			answers: map[int][]string{1: {"a"}},
+			expect:  map[int]struct{}{3: {}, 4: {}},
+		},
+		{
+			description: "選択肢が一致しないので飛ばさない",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 1, ConditionType: "option", OptionBody: null.StringFrom("a"), SkipToPage: 4},
+			},
+			answers: map[int][]string{1: {"b"}},
+			expect:  map[int]struct{}{},
+		},
+		{
+			description: "数値が範囲内なので飛ばす",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 2, ConditionType: "range", MinValue: null.FloatFrom(5), SkipToPage: 3},
+			},
+			answers: map[int][]string{2: {"5"}},
+			expect:  map[int]struct{}{3: {}},
+		},
+		{
+			description: "数値が範囲外なので飛ばさない",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 2, ConditionType: "range", MinValue: null.FloatFrom(5), SkipToPage: 3},
+			},
+			answers: map[int][]string{2: {"4.5"}},
+			expect:  map[int]struct{}{},
+		},
+		{
+			description: "未回答なので飛ばす",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 3, ConditionType: "unanswered", SkipToPage: 4},
+			},
+			answers: map[int][]string{3: {""}},
+			expect:  map[int]struct{}{4: {}},
+		},
+		{
+			description: "回答済みなので飛ばす",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 3, ConditionType: "answered", SkipToPage: 4},
+			},
+			answers: map[int][]string{3: {"hoge"}},
+			expect:  map[int]struct{}{4: {}},
+		},
+		{
+			description: "飛ばされたページの質問の分岐条件は使われない",
+			conditions: []model.QuestionConditions{
+				{QuestionID: 1, ConditionType: "option", OptionBody: null.StringFrom("a"), SkipToPage: 3},
+				{QuestionID: 3, ConditionType: "unanswered", SkipToPage: 4},
+			},
+			answers: map[int][]string{1: {"a"}},
+			expect:  map[int]struct{}{3: {}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual := calcHiddenQuestions(questions, testCase.conditions, testCase.answers)
+		assertion.Equal(testCase.expect, actual, testCase.description)
+	}
+}
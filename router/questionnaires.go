@@ -5,16 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
+	"sort"
 	"strconv"
-	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
 	"gopkg.in/guregu/null.v4"
 
 	"github.com/traPtitech/anke-to/model"
-	"github.com/traPtitech/anke-to/traq"
 )
 
 // Questionnaire Questionnaireの構造体
@@ -26,8 +25,13 @@ type Questionnaire struct {
 	model.IOption
 	model.IScaleLabel
 	model.IValidation
+	model.IQuestionCondition
+	model.IGroup
 	model.ITransaction
-	traq.IWebhook
+	model.IAudit
+	model.IOutbox
+	*MessageRenderer
+	*WebhookEventPublisher
 }
 
 const MaxTitleLength = 50
@@ -41,19 +45,29 @@ func NewQuestionnaire(
 	option model.IOption,
 	scaleLabel model.IScaleLabel,
 	validation model.IValidation,
+	questionCondition model.IQuestionCondition,
+	group model.IGroup,
 	transaction model.ITransaction,
-	webhook traq.IWebhook,
+	audit model.IAudit,
+	outbox model.IOutbox,
+	messageRenderer *MessageRenderer,
+	webhookEventPublisher *WebhookEventPublisher,
 ) *Questionnaire {
 	return &Questionnaire{
-		IQuestionnaire: questionnaire,
-		ITarget:        target,
-		IAdministrator: administrator,
-		IQuestion:      question,
-		IOption:        option,
-		IScaleLabel:    scaleLabel,
-		IValidation:    validation,
-		ITransaction:   transaction,
-		IWebhook:       webhook,
+		IQuestionnaire:        questionnaire,
+		ITarget:               target,
+		IAdministrator:        administrator,
+		IQuestion:             question,
+		IOption:               option,
+		IScaleLabel:           scaleLabel,
+		IValidation:           validation,
+		IQuestionCondition:    questionCondition,
+		IGroup:                group,
+		ITransaction:          transaction,
+		IAudit:                audit,
+		IOutbox:               outbox,
+		MessageRenderer:       messageRenderer,
+		WebhookEventPublisher: webhookEventPublisher,
 	}
 }
 
@@ -62,6 +76,7 @@ type GetQuestionnairesQueryParam struct {
 	Search      string `validate:"omitempty"`
 	Page        string `validate:"omitempty,number,min=0"`
 	Nontargeted string `validate:"omitempty,boolean"`
+	Template    string `validate:"omitempty,boolean"`
 }
 
 // GetQuestionnaires GET /questionnaires
@@ -76,12 +91,14 @@ func (q *Questionnaire) GetQuestionnaires(c echo.Context) error {
 	search := c.QueryParam("search")
 	page := c.QueryParam("page")
 	nontargeted := c.QueryParam("nontargeted")
+	template := c.QueryParam("template")
 
 	p := GetQuestionnairesQueryParam{
 		Sort:        sort,
 		Search:      search,
 		Page:        page,
 		Nontargeted: nontargeted,
+		Template:    template,
 	}
 
 	validate, err := getValidator(c)
@@ -120,7 +137,17 @@ func (q *Questionnaire) GetQuestionnaires(c echo.Context) error {
 		nontargetedBool = false
 	}
 
-	questionnaires, pageMax, err := q.IQuestionnaire.GetQuestionnaires(c.Request().Context(), userID, sort, search, pageNum, nontargetedBool)
+	// テンプレートは通常のアンケートとは別の一覧にする
+	var templateBool bool
+	if len(template) != 0 {
+		templateBool, err = strconv.ParseBool(template)
+		if err != nil {
+			c.Logger().Infof("failed to convert template to bool: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to convert the string query parameter 'template'(%s) to bool: %w", template, err))
+		}
+	}
+
+	questionnaires, pageMax, err := q.IQuestionnaire.GetQuestionnaires(c.Request().Context(), userID, sort, search, pageNum, nontargetedBool, templateBool)
 	if err != nil {
 		if errors.Is(err, model.ErrTooLargePageNum) || errors.Is(err, model.ErrInvalidRegex) {
 			c.Logger().Infof("failed to get questionnaires: %+v", err)
@@ -141,20 +168,77 @@ func (q *Questionnaire) GetQuestionnaires(c echo.Context) error {
 }
 
 type PostAndEditQuestionnaireRequest struct {
-	Title          string    `json:"title" validate:"required,max=50"`
-	Description    string    `json:"description"`
-	ResTimeLimit   null.Time `json:"res_time_limit"`
-	ResSharedTo    string    `json:"res_shared_to" validate:"required,oneof=administrators respondents public"`
-	Targets        []string  `json:"targets" validate:"dive,max=32"`
-	Administrators []string  `json:"administrators" validate:"required,min=1,dive,max=32"`
+	Title               string          `json:"title" validate:"required,max=50"`
+	Description         string          `json:"description"`
+	ResTimeLimit        null.Time       `json:"res_time_limit"`
+	ResStartAt          null.Time       `json:"res_start_at"`
+	IsClosed            null.Bool       `json:"is_closed"`
+	ResSharedTo         string          `json:"res_shared_to" validate:"required"`
+	Targets             []string        `json:"targets" validate:"dive,max=32"`
+	Administrators      []string        `json:"administrators" validate:"required,min=1,dive,max=32"`
+	IsTemplate          bool            `json:"is_template"`
+	IsAnonymous         null.Bool       `json:"is_anonymous"`
+	MaxResponsesPerUser optionalNullInt `json:"max_responses_per_user"`
+	MaxTotalResponses   optionalNullInt `json:"max_total_responses"`
+	DisableReminders    null.Bool       `json:"disable_reminders"`
+}
+
+// optionalNullInt 省略された場合とnullが指定された場合を区別できるnull.Int
+type optionalNullInt struct {
+	null.Int
+	// Present JSONで値(nullを含む)が指定されたか
+	Present bool
+}
+
+// UnmarshalJSON nullが指定された場合も指定されたものとして扱う
+func (i *optionalNullInt) UnmarshalJSON(data []byte) error {
+	i.Present = true
+
+	return i.Int.UnmarshalJSON(data)
+}
+
+// validResponseLimits 回答数の上限が指定されている場合は1以上か
+func (req *PostAndEditQuestionnaireRequest) validResponseLimits() bool {
+	if req.MaxResponsesPerUser.Valid && req.MaxResponsesPerUser.Int64 < 1 {
+		return false
+	}
+	if req.MaxTotalResponses.Valid && req.MaxTotalResponses.Int64 < 1 {
+		return false
+	}
+
+	return true
+}
+
+// validSchedule 回答開始日時と回答期限が指定されている場合は回答開始日時が先か
+func (req *PostAndEditQuestionnaireRequest) validSchedule() bool {
+	if req.ResStartAt.Valid && req.ResTimeLimit.Valid {
+		return req.ResStartAt.Time.Before(req.ResTimeLimit.Time)
+	}
+
+	return true
+}
+
+// isOpenAt 指定した時刻にアンケートが回答を受け付けているか
+func (req *PostAndEditQuestionnaireRequest) isOpenAt(now time.Time) bool {
+	if req.IsClosed.ValueOrZero() {
+		return false
+	}
+
+	return !req.ResStartAt.Valid || !req.ResStartAt.Time.After(now)
 }
 
 // PostQuestionnaire POST /questionnaires
 func (q *Questionnaire) PostQuestionnaire(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
 	req := PostAndEditQuestionnaireRequest{}
 
 	// JSONを構造体につける
-	err := c.Bind(&req)
+	err = c.Bind(&req)
 	if err != nil {
 		c.Logger().Infof("failed to bind PostAndEditQuestionnaireRequest: %+v", err)
 		return echo.NewHTTPError(http.StatusBadRequest)
@@ -172,6 +256,21 @@ func (q *Questionnaire) PostQuestionnaire(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if !model.IsResShareType(req.ResSharedTo) {
+		c.Logger().Infof("unknown res_shared_to: %s", req.ResSharedTo)
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown res_shared_to")
+	}
+
+	if !req.validResponseLimits() {
+		c.Logger().Infof("invalid response limits: %+v, %+v", req.MaxResponsesPerUser, req.MaxTotalResponses)
+		return echo.NewHTTPError(http.StatusBadRequest, "response limits must be positive")
+	}
+
+	if !req.validSchedule() {
+		c.Logger().Infof("invalid schedule: %+v, %+v", req.ResStartAt, req.ResTimeLimit)
+		return echo.NewHTTPError(http.StatusBadRequest, "res_start_at must be before res_time_limit")
+	}
+
 	if req.ResTimeLimit.Valid {
 		isBefore := req.ResTimeLimit.ValueOrZero().Before(time.Now())
 		if isBefore {
@@ -180,38 +279,109 @@ func (q *Questionnaire) PostQuestionnaire(c echo.Context) error {
 		}
 	}
 
+	targets, err := expandGroups(c.Request().Context(), q.IGroup, req.Targets)
+	if err != nil {
+		c.Logger().Errorf("failed to expand targets: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	administrators, err := expandGroups(c.Request().Context(), q.IGroup, req.Administrators)
+	if err != nil {
+		c.Logger().Errorf("failed to expand administrators: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
 	var questionnaireID int
 	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
-		questionnaireID, err = q.InsertQuestionnaire(ctx, req.Title, req.Description, req.ResTimeLimit, req.ResSharedTo)
+		questionnaireID, err = q.InsertQuestionnaire(ctx, req.Title, req.Description, req.ResTimeLimit, req.ResSharedTo, req.IsTemplate)
 		if err != nil {
 			c.Logger().Errorf("failed to insert a questionnaire: %+v", err)
 			return err
 		}
 
-		err := q.InsertTargets(ctx, questionnaireID, req.Targets)
+		if req.IsAnonymous.ValueOrZero() {
+			err = q.UpdateQuestionnaireAnonymous(ctx, questionnaireID, true)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire anonymous: %+v", err)
+				return err
+			}
+		}
+
+		if req.MaxResponsesPerUser.Valid || req.MaxTotalResponses.Valid {
+			err = q.UpdateQuestionnaireResponseLimits(ctx, questionnaireID, req.MaxResponsesPerUser.Int, req.MaxTotalResponses.Int)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire response limits: %+v", err)
+				return err
+			}
+		}
+
+		if req.ResStartAt.Valid || req.IsClosed.ValueOrZero() {
+			err = q.UpdateQuestionnaireSchedule(ctx, questionnaireID, req.ResStartAt, req.IsClosed.ValueOrZero())
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire schedule: %+v", err)
+				return err
+			}
+		}
+
+		if req.DisableReminders.ValueOrZero() {
+			err = q.UpdateQuestionnaireReminders(ctx, questionnaireID, true)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire reminders: %+v", err)
+				return err
+			}
+		}
+
+		err := q.InsertTargets(ctx, questionnaireID, targets)
 		if err != nil {
 			c.Logger().Errorf("failed to insert targets: %+v", err)
 			return err
 		}
 
-		err = q.InsertAdministrators(ctx, questionnaireID, req.Administrators)
+		err = q.InsertAdministrators(ctx, questionnaireID, administrators)
 		if err != nil {
 			c.Logger().Errorf("failed to insert administrators: %+v", err)
 			return err
 		}
 
-		message := createQuestionnaireMessage(
-			questionnaireID,
-			req.Title,
-			req.Description,
-			req.Administrators,
-			req.ResTimeLimit,
-			req.Targets,
-		)
-		err = q.PostMessage(message)
+		after := newQuestionnaireAuditState(req.Title, req.Description, req.ResTimeLimit, req.ResStartAt, req.IsClosed.ValueOrZero(), req.ResSharedTo, req.IsTemplate, req.IsAnonymous.ValueOrZero(), req.MaxResponsesPerUser.Int, req.MaxTotalResponses.Int, req.DisableReminders.ValueOrZero(), targets, administrators)
+		err = q.InsertAuditEvent(ctx, userID, questionnaireID, model.AuditTargetQuestionnaire, questionnaireID, model.AuditActionCreate, nil, after)
+		if err != nil {
+			c.Logger().Errorf("failed to insert audit event: %+v", err)
+			return err
+		}
+
+		// テンプレートは回答を集めないため、traQに告知しない
+		if req.IsTemplate {
+			return nil
+		}
+
+		// 回答開始前のアンケートは回答開始時にSchedulerが告知する
+		if !req.isOpenAt(time.Now()) {
+			return nil
+		}
+
+		err = q.UpdateQuestionnaireAnnounced(ctx, questionnaireID)
 		if err != nil {
-			c.Logger().Errorf("failed to post message: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to post message to traQ")
+			c.Logger().Errorf("failed to update questionnaire announced: %+v", err)
+			return err
+		}
+
+		message, err := q.RenderMessage(ctx, model.MessageTemplateKindAnnouncement, MessageData{
+			QuestionnaireID: questionnaireID,
+			Title:           req.Title,
+			Description:     req.Description,
+			Administrators:  req.Administrators,
+			Targets:         req.Targets,
+			ResTimeLimit:    req.ResTimeLimit,
+		})
+		if err != nil {
+			c.Logger().Errorf("failed to render message: %+v", err)
+			return err
+		}
+		_, err = q.InsertOutboxMessage(ctx, message)
+		if err != nil {
+			c.Logger().Errorf("failed to insert outbox message: %+v", err)
+			return err
 		}
 
 		return nil
@@ -221,6 +391,10 @@ func (q *Questionnaire) PostQuestionnaire(c echo.Context) error {
 		if errors.As(err, &httpError) {
 			return httpError
 		}
+		if errors.Is(err, model.ErrInvalidResShareType) {
+			c.Logger().Infof("invalid res_shared_to: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "res_shared_to is not available")
+		}
 
 		c.Logger().Errorf("failed to create questionnaire: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create a questionnaire")
@@ -228,16 +402,23 @@ func (q *Questionnaire) PostQuestionnaire(c echo.Context) error {
 
 	now := time.Now()
 	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"questionnaireID": questionnaireID,
-		"title":           req.Title,
-		"description":     req.Description,
-		"res_time_limit":  req.ResTimeLimit,
-		"deleted_at":      "NULL",
-		"created_at":      now.Format(time.RFC3339),
-		"modified_at":     now.Format(time.RFC3339),
-		"res_shared_to":   req.ResSharedTo,
-		"targets":         req.Targets,
-		"administrators":  req.Administrators,
+		"questionnaireID":        questionnaireID,
+		"title":                  req.Title,
+		"description":            req.Description,
+		"res_time_limit":         req.ResTimeLimit,
+		"res_start_at":           req.ResStartAt,
+		"is_closed":              req.IsClosed.ValueOrZero(),
+		"deleted_at":             "NULL",
+		"created_at":             now.Format(time.RFC3339),
+		"modified_at":            now.Format(time.RFC3339),
+		"res_shared_to":          req.ResSharedTo,
+		"is_template":            req.IsTemplate,
+		"is_anonymous":           req.IsAnonymous.ValueOrZero(),
+		"max_responses_per_user": req.MaxResponsesPerUser.Int,
+		"max_total_responses":    req.MaxTotalResponses.Int,
+		"disable_reminders":      req.DisableReminders.ValueOrZero(),
+		"targets":                targets,
+		"administrators":         administrators,
 	})
 }
 
@@ -260,17 +441,32 @@ func (q *Questionnaire) GetQuestionnaire(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
+	// 回答が送信されてもmodified_atは変わらないので、回答者の一覧もETagに含める
+	sortedRespondents := make([]string, len(respondents))
+	copy(sortedRespondents, respondents)
+	sort.Strings(sortedRespondents)
+	if setETag(c, questionnaire.ModifiedAt, sortedRespondents...) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"questionnaireID": questionnaire.ID,
-		"title":           questionnaire.Title,
-		"description":     questionnaire.Description,
-		"res_time_limit":  questionnaire.ResTimeLimit,
-		"created_at":      questionnaire.CreatedAt.Format(time.RFC3339),
-		"modified_at":     questionnaire.ModifiedAt.Format(time.RFC3339),
-		"res_shared_to":   questionnaire.ResSharedTo,
-		"targets":         targets,
-		"administrators":  administrators,
-		"respondents":     respondents,
+		"questionnaireID":        questionnaire.ID,
+		"title":                  questionnaire.Title,
+		"description":            questionnaire.Description,
+		"res_time_limit":         questionnaire.ResTimeLimit,
+		"res_start_at":           questionnaire.ResStartAt,
+		"is_closed":              questionnaire.IsClosed,
+		"created_at":             questionnaire.CreatedAt.Format(time.RFC3339),
+		"modified_at":            questionnaire.ModifiedAt.Format(time.RFC3339),
+		"res_shared_to":          questionnaire.ResSharedTo,
+		"is_template":            questionnaire.IsTemplate,
+		"is_anonymous":           questionnaire.IsAnonymous,
+		"max_responses_per_user": questionnaire.MaxResponsesPerUser,
+		"max_total_responses":    questionnaire.MaxTotalResponses,
+		"disable_reminders":      questionnaire.DisableReminders,
+		"targets":                targets,
+		"administrators":         administrators,
+		"respondents":            respondents,
 	})
 }
 
@@ -300,6 +496,12 @@ func (q *Questionnaire) PostQuestionByQuestionnaireID(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	questionType, ok := model.GetQuestionTypeInfo(req.QuestionType)
+	if !ok {
+		c.Logger().Infof("unknown question type: %s", req.QuestionType)
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown question_type")
+	}
+
 	// 重複したquestionNumを持つ質問をPOSTできないように
 	questionNumAlreadyExists, err := q.CheckQuestionNum(c.Request().Context(), questionnaireID, req.QuestionNum)
 	if err != nil {
@@ -310,56 +512,93 @@ func (q *Questionnaire) PostQuestionByQuestionnaireID(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
-	switch req.QuestionType {
-	case "Text":
-		// 正規表現のチェック
-		if _, err := regexp.Compile(req.RegexPattern); err != nil {
-			c.Logger().Info("invalid regex pattern: %+v", err)
-			return echo.NewHTTPError(http.StatusBadRequest)
-		}
-	case "Number":
-		// 数字か，min<=maxになってるか
-		if err := q.CheckNumberValid(req.MinBound, req.MaxBound); err != nil {
-			c.Logger().Info("invalid number: %+v", err)
-			return echo.NewHTTPError(http.StatusBadRequest, err)
-		}
+	if err := checkQuestionValidation(q.IValidation, questionType, req); err != nil {
+		c.Logger().Infof("invalid question: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	lastID, err := q.InsertQuestion(c.Request().Context(), questionnaireID, req.PageNum, req.QuestionNum, req.QuestionType, req.Body, req.IsRequired)
-	if err != nil {
-		c.Logger().Errorf("failed to insert question: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	if err := checkQuestionConditions(req.QuestionType, req.PageNum, req.Options, req.Conditions); err != nil {
+		c.Logger().Infof("invalid conditions: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	switch req.QuestionType {
-	case "MultipleChoice", "Checkbox", "Dropdown":
-		for i, v := range req.Options {
-			if err := q.InsertOption(c.Request().Context(), lastID, i+1, v); err != nil {
-				c.Logger().Errorf("failed to insert option: %+v", err)
-				return echo.NewHTTPError(http.StatusInternalServerError, err)
+	var lastID int
+	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		// 質問の追加もアンケートの版を進める
+		_, err := q.UpdateQuestionnaireModifiedAt(ctx, questionnaireID, null.NewTime(time.Time{}, false))
+		if err != nil {
+			c.Logger().Errorf("failed to update questionnaire modified_at: %+v", err)
+			return err
+		}
+
+		lastID, err = q.InsertQuestion(ctx, questionnaireID, req.PageNum, req.QuestionNum, req.QuestionType, req.Body, req.IsRequired)
+		if err != nil {
+			c.Logger().Errorf("failed to insert question: %+v", err)
+			return err
+		}
+
+		switch {
+		case questionType.HasOptions:
+			for i, v := range req.Options {
+				if err := q.InsertOption(ctx, lastID, i+1, v); err != nil {
+					c.Logger().Errorf("failed to insert option: %+v", err)
+					return err
+				}
+			}
+		case questionType.HasScaleLabel:
+			if err := q.InsertScaleLabel(ctx, lastID,
+				model.ScaleLabels{
+					ScaleLabelLeft:  req.ScaleLabelLeft,
+					ScaleLabelRight: req.ScaleLabelRight,
+					ScaleMax:        req.ScaleMax,
+					ScaleMin:        req.ScaleMin,
+				}); err != nil {
+				c.Logger().Errorf("failed to insert scale label: %+v", err)
+				return err
+			}
+		case questionType.HasRegexPattern || questionType.HasBounds:
+			if err := q.InsertValidation(ctx, lastID,
+				model.Validations{
+					RegexPattern: req.RegexPattern,
+					MinBound:     req.MinBound,
+					MaxBound:     req.MaxBound,
+				}); err != nil {
+				c.Logger().Errorf("failed to insert validation: %+v", err)
+				return err
 			}
 		}
-	case "LinearScale":
-		if err := q.InsertScaleLabel(c.Request().Context(), lastID,
-			model.ScaleLabels{
-				ScaleLabelLeft:  req.ScaleLabelLeft,
-				ScaleLabelRight: req.ScaleLabelRight,
-				ScaleMax:        req.ScaleMax,
-				ScaleMin:        req.ScaleMin,
-			}); err != nil {
-			c.Logger().Errorf("failed to insert scale label: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+
+		if questionType.MultipleSelection {
+			if err := q.InsertValidation(ctx, lastID,
+				model.Validations{
+					MinSelections: req.MinSelections,
+					MaxSelections: req.MaxSelections,
+				}); err != nil {
+				c.Logger().Errorf("failed to insert validation: %+v", err)
+				return err
+			}
 		}
-	case "Text", "Number":
-		if err := q.InsertValidation(c.Request().Context(), lastID,
-			model.Validations{
-				RegexPattern: req.RegexPattern,
-				MinBound:     req.MinBound,
-				MaxBound:     req.MaxBound,
-			}); err != nil {
-			c.Logger().Errorf("failed to insert validation: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+
+		if req.Conditions == nil {
+			req.Conditions = []model.QuestionConditions{}
+		}
+		if err := q.InsertQuestionConditions(ctx, lastID, req.Conditions); err != nil {
+			c.Logger().Errorf("failed to insert question conditions: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidQuestionType) {
+			c.Logger().Infof("inactive question type: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "question_type is not available")
 		}
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusCreated, map[string]interface{}{
@@ -377,22 +616,45 @@ func (q *Questionnaire) PostQuestionByQuestionnaireID(c echo.Context) error {
 		"regex_pattern":     req.RegexPattern,
 		"min_bound":         req.MinBound,
 		"max_bound":         req.MaxBound,
+		"min_selections":    req.MinSelections,
+		"max_selections":    req.MaxSelections,
+		"conditions":        req.Conditions,
 	})
 }
 
-// EditQuestionnaire PATCH /questionnaires/:questionnaireID
-func (q *Questionnaire) EditQuestionnaire(c echo.Context) error {
+type PutQuestionsRequest struct {
+	Questions []PutQuestion `json:"questions" validate:"dive"`
+}
+
+// PutQuestion 一括更新する質問
+// QuestionIDが0の質問は新しく作成する
+type PutQuestion struct {
+	QuestionID int `json:"questionID" validate:"min=0"`
+	PostAndEditQuestionRequest
+}
+
+// PutQuestions PUT /questionnaires/:questionnaireID/questions
+func (q *Questionnaire) PutQuestions(c echo.Context) error {
 	questionnaireID, err := getQuestionnaireID(c)
 	if err != nil {
 		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
-	req := PostAndEditQuestionnaireRequest{}
-
-	err = c.Bind(&req)
+	ifMatch, err := getIfMatch(c)
 	if err != nil {
-		c.Logger().Infof("failed to bind PostAndEditQuestionnaireRequest: %+v", err)
+		c.Logger().Infof("invalid If-Match: %+v", err)
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid If-Match header")
+	}
+	// 指定されていない質問は全て削除するので、版を確認しない置き換えは許可しない
+	if !ifMatch.Valid {
+		c.Logger().Info("If-Match is required")
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required")
+	}
+
+	req := PutQuestionsRequest{}
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Infof("failed to bind PutQuestionsRequest: %+v", err)
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
@@ -408,77 +670,115 @@ func (q *Questionnaire) EditQuestionnaire(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
-		err = q.UpdateQuestionnaire(ctx, req.Title, req.Description, req.ResTimeLimit, req.ResSharedTo, questionnaireID)
-		if err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
-			c.Logger().Errorf("failed to update questionnaire: %+v", err)
-			return err
-		}
+	questionTypes := make([]model.QuestionTypeInfo, 0, len(req.Questions))
+	questionIDs := make(map[int]struct{}, len(req.Questions))
+	for i := range req.Questions {
+		question := &req.Questions[i]
 
-		err = q.DeleteTargets(ctx, questionnaireID)
-		if err != nil {
-			c.Logger().Errorf("failed to delete targets: %+v", err)
-			return err
+		questionType, ok := model.GetQuestionTypeInfo(question.QuestionType)
+		if !ok {
+			c.Logger().Infof("unknown question type: %s", question.QuestionType)
+			return echo.NewHTTPError(http.StatusBadRequest, "unknown question_type")
 		}
+		questionTypes = append(questionTypes, questionType)
 
-		err = q.InsertTargets(ctx, questionnaireID, req.Targets)
-		if err != nil {
-			c.Logger().Errorf("failed to insert targets: %+v", err)
-			return err
+		if question.QuestionID != 0 {
+			if _, ok := questionIDs[question.QuestionID]; ok {
+				c.Logger().Infof("duplicated questionID: %d", question.QuestionID)
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("duplicated questionID: %d", question.QuestionID))
+			}
+			questionIDs[question.QuestionID] = struct{}{}
 		}
 
-		err = q.DeleteAdministrators(ctx, questionnaireID)
-		if err != nil {
-			c.Logger().Errorf("failed to delete administrators: %+v", err)
-			return err
+		if err := checkQuestionValidation(q.IValidation, questionType, question.PostAndEditQuestionRequest); err != nil {
+			c.Logger().Infof("invalid question: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, err)
 		}
 
-		err = q.InsertAdministrators(ctx, questionnaireID, req.Administrators)
-		if err != nil {
-			c.Logger().Errorf("failed to insert administrators: %+v", err)
-			return err
+		if err := checkQuestionConditions(question.QuestionType, question.PageNum, question.Options, question.Conditions); err != nil {
+			c.Logger().Infof("invalid conditions: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 
-		return nil
-	})
-	if err != nil {
-		var httpError *echo.HTTPError
-		if errors.As(err, &httpError) {
-			return httpError
+		// 質問の順番はリストの順番にする
+		question.QuestionnaireID = questionnaireID
+		question.QuestionNum = i + 1
+		if question.Conditions == nil {
+			question.Conditions = []model.QuestionConditions{}
 		}
-
-		c.Logger().Errorf("failed to update questionnaire: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update a questionnaire")
-	}
-
-	return c.NoContent(http.StatusOK)
-}
-
-// DeleteQuestionnaire DELETE /questionnaires/:questionnaireID
-func (q *Questionnaire) DeleteQuestionnaire(c echo.Context) error {
-	questionnaireID, err := getQuestionnaireID(c)
-	if err != nil {
-		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
+	var modifiedAt time.Time
 	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
-		err = q.IQuestionnaire.DeleteQuestionnaire(c.Request().Context(), questionnaireID)
+		// If-Matchの版から変更されていれば更新しない
+		modifiedAt, err = q.UpdateQuestionnaireModifiedAt(ctx, questionnaireID, ifMatch)
 		if err != nil {
-			c.Logger().Errorf("failed to delete questionnaire: %+v", err)
+			c.Logger().Infof("failed to update modified_at: %+v", err)
 			return err
 		}
 
-		err = q.DeleteTargets(c.Request().Context(), questionnaireID)
+		currentQuestions, err := q.IQuestion.GetQuestions(ctx, questionnaireID)
 		if err != nil {
-			c.Logger().Errorf("failed to delete targets: %+v", err)
+			c.Logger().Errorf("failed to get questions: %+v", err)
 			return err
 		}
+		currentQuestionIDs := make(map[int]struct{}, len(currentQuestions))
+		for _, question := range currentQuestions {
+			currentQuestionIDs[question.ID] = struct{}{}
+		}
 
-		err = q.DeleteAdministrators(c.Request().Context(), questionnaireID)
-		if err != nil {
-			c.Logger().Errorf("failed to delete administrators: %+v", err)
-			return err
+		for i := range req.Questions {
+			question := &req.Questions[i]
+
+			if question.QuestionID == 0 {
+				question.QuestionID, err = q.InsertQuestion(ctx, questionnaireID, question.PageNum, question.QuestionNum, question.QuestionType, question.Body, question.IsRequired)
+				if err != nil {
+					c.Logger().Errorf("failed to insert question: %+v", err)
+					return err
+				}
+			} else {
+				if _, ok := currentQuestionIDs[question.QuestionID]; !ok {
+					c.Logger().Infof("question(%d) is not in questionnaire(%d)", question.QuestionID, questionnaireID)
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("question(%d) is not in the questionnaire", question.QuestionID))
+				}
+
+				err = q.UpdateQuestion(ctx, questionnaireID, question.PageNum, question.QuestionNum, question.QuestionType, question.Body, question.IsRequired, question.QuestionID)
+				if err != nil {
+					c.Logger().Errorf("failed to update question: %+v", err)
+					return err
+				}
+
+				// 質問の種類が変わることもあるため、選択肢などは作り直す
+				err = q.deleteQuestionDetails(ctx, question.QuestionID)
+				if err != nil {
+					c.Logger().Errorf("failed to delete question details: %+v", err)
+					return err
+				}
+			}
+
+			err = q.insertQuestionDetails(ctx, question.QuestionID, questionTypes[i], question.PostAndEditQuestionRequest)
+			if err != nil {
+				c.Logger().Errorf("failed to insert question details: %+v", err)
+				return err
+			}
+		}
+
+		for _, question := range currentQuestions {
+			if _, ok := questionIDs[question.ID]; ok {
+				continue
+			}
+
+			err = q.IQuestion.DeleteQuestion(ctx, question.ID)
+			if err != nil {
+				c.Logger().Errorf("failed to delete question: %+v", err)
+				return err
+			}
+
+			err = q.deleteQuestionDetails(ctx, question.ID)
+			if err != nil {
+				c.Logger().Errorf("failed to delete question details: %+v", err)
+				return err
+			}
 		}
 
 		return nil
@@ -488,74 +788,872 @@ func (q *Questionnaire) DeleteQuestionnaire(c echo.Context) error {
 		if errors.As(err, &httpError) {
 			return httpError
 		}
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+		if errors.Is(err, model.ErrConflict) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "questionnaire has been modified")
+		}
+		if errors.Is(err, model.ErrInvalidQuestionType) {
+			return echo.NewHTTPError(http.StatusBadRequest, "question_type is not available")
+		}
 
-		c.Logger().Errorf("failed to delete questionnaire: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete a questionnaire")
+		c.Logger().Errorf("failed to put questions: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to put questions")
 	}
 
-	return c.NoContent(http.StatusOK)
+	questions := make([]map[string]interface{}, 0, len(req.Questions))
+	for _, question := range req.Questions {
+		questions = append(questions, map[string]interface{}{
+			"questionID":        question.QuestionID,
+			"question_type":     question.QuestionType,
+			"question_num":      question.QuestionNum,
+			"page_num":          question.PageNum,
+			"body":              question.Body,
+			"is_required":       question.IsRequired,
+			"options":           question.Options,
+			"scale_label_right": question.ScaleLabelRight,
+			"scale_label_left":  question.ScaleLabelLeft,
+			"scale_max":         question.ScaleMax,
+			"scale_min":         question.ScaleMin,
+			"regex_pattern":     question.RegexPattern,
+			"min_bound":         question.MinBound,
+			"max_bound":         question.MaxBound,
+			"min_selections":    question.MinSelections,
+			"max_selections":    question.MaxSelections,
+			"conditions":        question.Conditions,
+		})
+	}
+
+	setETag(c, modifiedAt)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"questions": questions,
+	})
 }
 
-// GetQuestions GET /questionnaires/:questionnaireID/questions
-func (q *Questionnaire) GetQuestions(c echo.Context) error {
-	strQuestionnaireID := c.Param("questionnaireID")
-	questionnaireID, err := strconv.Atoi(strQuestionnaireID)
-	if err != nil {
-		c.Logger().Infof("failed to convert questionnaireID to int: %+v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid questionnaireID:%s(error: %w)", strQuestionnaireID, err))
+// insertQuestionDetails 質問の種類に応じて選択肢、目盛り、回答の制限、分岐条件を追加する
+func (q *Questionnaire) insertQuestionDetails(ctx context.Context, questionID int, questionType model.QuestionTypeInfo, req PostAndEditQuestionRequest) error {
+	switch {
+	case questionType.HasOptions:
+		for i, v := range req.Options {
+			if err := q.InsertOption(ctx, questionID, i+1, v); err != nil {
+				return fmt.Errorf("failed to insert option: %w", err)
+			}
+		}
+	case questionType.HasScaleLabel:
+		if err := q.InsertScaleLabel(ctx, questionID,
+			model.ScaleLabels{
+				ScaleLabelLeft:  req.ScaleLabelLeft,
+				ScaleLabelRight: req.ScaleLabelRight,
+				ScaleMax:        req.ScaleMax,
+				ScaleMin:        req.ScaleMin,
+			}); err != nil {
+			return fmt.Errorf("failed to insert scale label: %w", err)
+		}
+	case questionType.HasRegexPattern || questionType.HasBounds:
+		if err := q.InsertValidation(ctx, questionID,
+			model.Validations{
+				RegexPattern: req.RegexPattern,
+				MinBound:     req.MinBound,
+				MaxBound:     req.MaxBound,
+			}); err != nil {
+			return fmt.Errorf("failed to insert validation: %w", err)
+		}
 	}
 
-	allquestions, err := q.IQuestion.GetQuestions(c.Request().Context(), questionnaireID)
-	if err != nil {
-		c.Logger().Errorf("failed to get questions: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	if questionType.MultipleSelection {
+		if err := q.InsertValidation(ctx, questionID,
+			model.Validations{
+				MinSelections: req.MinSelections,
+				MaxSelections: req.MaxSelections,
+			}); err != nil {
+			return fmt.Errorf("failed to insert validation: %w", err)
+		}
 	}
 
-	if len(allquestions) == 0 {
-		c.Logger().Info("no questions")
-		return echo.NewHTTPError(http.StatusNotFound)
+	if err := q.InsertQuestionConditions(ctx, questionID, req.Conditions); err != nil {
+		return fmt.Errorf("failed to insert question conditions: %w", err)
 	}
 
-	type questionInfo struct {
-		QuestionID      int      `json:"questionID"`
-		PageNum         int      `json:"page_num"`
-		QuestionNum     int      `json:"question_num"`
-		QuestionType    string   `json:"question_type"`
-		Body            string   `json:"body"`
-		IsRequired      bool     `json:"is_required"`
-		CreatedAt       string   `json:"created_at"`
-		Options         []string `json:"options"`
-		ScaleLabelRight string   `json:"scale_label_right"`
-		ScaleLabelLeft  string   `json:"scale_label_left"`
-		ScaleMin        int      `json:"scale_min"`
-		ScaleMax        int      `json:"scale_max"`
-		RegexPattern    string   `json:"regex_pattern"`
-		MinBound        string   `json:"min_bound"`
-		MaxBound        string   `json:"max_bound"`
+	return nil
+}
+
+// deleteQuestionDetails 質問の選択肢、目盛り、回答の制限、分岐条件を削除する
+func (q *Questionnaire) deleteQuestionDetails(ctx context.Context, questionID int) error {
+	if err := q.DeleteOptions(ctx, questionID); err != nil {
+		return fmt.Errorf("failed to delete options: %w", err)
 	}
-	var ret []questionInfo
 
-	optionIDs := []int{}
-	scaleLabelIDs := []int{}
-	validationIDs := []int{}
-	for _, question := range allquestions {
-		switch question.Type {
-		case "MultipleChoice", "Checkbox", "Dropdown":
-			optionIDs = append(optionIDs, question.ID)
-		case "LinearScale":
-			scaleLabelIDs = append(scaleLabelIDs, question.ID)
-		case "Text", "Number":
-			validationIDs = append(validationIDs, question.ID)
-		}
+	if err := q.DeleteScaleLabel(ctx, questionID); err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
+		return fmt.Errorf("failed to delete scale label: %w", err)
 	}
 
-	options, err := q.GetOptions(c.Request().Context(), optionIDs)
-	if err != nil {
-		c.Logger().Errorf("failed to get options: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	if err := q.DeleteValidation(ctx, questionID); err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
+		return fmt.Errorf("failed to delete validation: %w", err)
 	}
-	optionMap := make(map[int][]string, len(options))
-	for _, option := range options {
+
+	if err := q.DeleteQuestionConditions(ctx, questionID); err != nil {
+		return fmt.Errorf("failed to delete question conditions: %w", err)
+	}
+
+	return nil
+}
+
+// EditQuestionnaire PATCH /questionnaires/:questionnaireID
+func (q *Questionnaire) EditQuestionnaire(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	ifMatch, err := getIfMatch(c)
+	if err != nil {
+		c.Logger().Infof("invalid If-Match: %+v", err)
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid If-Match header")
+	}
+
+	req := PostAndEditQuestionnaireRequest{}
+
+	err = c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind PostAndEditQuestionnaireRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	validate, err := getValidator(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get validator: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	err = validate.StructCtx(c.Request().Context(), req)
+	if err != nil {
+		c.Logger().Infof("failed to validate: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if !model.IsResShareType(req.ResSharedTo) {
+		c.Logger().Infof("unknown res_shared_to: %s", req.ResSharedTo)
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown res_shared_to")
+	}
+
+	if !req.validResponseLimits() {
+		c.Logger().Infof("invalid response limits: %+v, %+v", req.MaxResponsesPerUser, req.MaxTotalResponses)
+		return echo.NewHTTPError(http.StatusBadRequest, "response limits must be positive")
+	}
+
+	if !req.validSchedule() {
+		c.Logger().Infof("invalid schedule: %+v, %+v", req.ResStartAt, req.ResTimeLimit)
+		return echo.NewHTTPError(http.StatusBadRequest, "res_start_at must be before res_time_limit")
+	}
+
+	targets, err := expandGroups(c.Request().Context(), q.IGroup, req.Targets)
+	if err != nil {
+		c.Logger().Errorf("failed to expand targets: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	administrators, err := expandGroups(c.Request().Context(), q.IGroup, req.Administrators)
+	if err != nil {
+		c.Logger().Errorf("failed to expand administrators: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		// If-Matchの版から変更されていれば更新しない
+		if ifMatch.Valid {
+			_, err = q.UpdateQuestionnaireModifiedAt(ctx, questionnaireID, ifMatch)
+			if err != nil {
+				c.Logger().Infof("failed to check modified_at: %+v", err)
+				return err
+			}
+		}
+
+		before, err := q.getQuestionnaireAuditState(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to get questionnaire audit state: %+v", err)
+			return err
+		}
+
+		err = q.UpdateQuestionnaire(ctx, req.Title, req.Description, req.ResTimeLimit, req.ResSharedTo, req.IsTemplate, questionnaireID)
+		if err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
+			c.Logger().Errorf("failed to update questionnaire: %+v", err)
+			return err
+		}
+
+		// 省略された場合は匿名の設定を変更しない
+		isAnonymous := before.IsAnonymous
+		if req.IsAnonymous.Valid {
+			err = q.UpdateQuestionnaireAnonymous(ctx, questionnaireID, req.IsAnonymous.Bool)
+			if err != nil {
+				c.Logger().Infof("failed to update questionnaire anonymous: %+v", err)
+				return err
+			}
+			isAnonymous = req.IsAnonymous.Bool
+		}
+
+		// 省略された上限は変更しない
+		maxResponsesPerUser := before.MaxResponsesPerUser
+		if req.MaxResponsesPerUser.Present {
+			maxResponsesPerUser = req.MaxResponsesPerUser.Int
+		}
+		maxTotalResponses := before.MaxTotalResponses
+		if req.MaxTotalResponses.Present {
+			maxTotalResponses = req.MaxTotalResponses.Int
+		}
+		if req.MaxResponsesPerUser.Present || req.MaxTotalResponses.Present {
+			err = q.UpdateQuestionnaireResponseLimits(ctx, questionnaireID, maxResponsesPerUser, maxTotalResponses)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire response limits: %+v", err)
+				return err
+			}
+		}
+
+		// 省略された場合は締め切りの状態を変更しない
+		isClosed := before.IsClosed
+		if req.IsClosed.Valid {
+			isClosed = req.IsClosed.Bool
+		}
+		err = q.UpdateQuestionnaireSchedule(ctx, questionnaireID, req.ResStartAt, isClosed)
+		if err != nil {
+			c.Logger().Errorf("failed to update questionnaire schedule: %+v", err)
+			return err
+		}
+
+		// 省略された場合はリマインドの設定を変更しない
+		disableReminders := before.DisableReminders
+		if req.DisableReminders.Valid {
+			err = q.UpdateQuestionnaireReminders(ctx, questionnaireID, req.DisableReminders.Bool)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire reminders: %+v", err)
+				return err
+			}
+			disableReminders = req.DisableReminders.Bool
+		}
+
+		err = q.DeleteTargets(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to delete targets: %+v", err)
+			return err
+		}
+
+		err = q.InsertTargets(ctx, questionnaireID, targets)
+		if err != nil {
+			c.Logger().Errorf("failed to insert targets: %+v", err)
+			return err
+		}
+
+		err = q.DeleteAdministrators(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to delete administrators: %+v", err)
+			return err
+		}
+
+		err = q.InsertAdministrators(ctx, questionnaireID, administrators)
+		if err != nil {
+			c.Logger().Errorf("failed to insert administrators: %+v", err)
+			return err
+		}
+
+		after := newQuestionnaireAuditState(req.Title, req.Description, req.ResTimeLimit, req.ResStartAt, isClosed, req.ResSharedTo, req.IsTemplate, isAnonymous, maxResponsesPerUser, maxTotalResponses, disableReminders, targets, administrators)
+		err = q.InsertAuditEvent(ctx, userID, questionnaireID, model.AuditTargetQuestionnaire, questionnaireID, model.AuditActionUpdate, before, after)
+		if err != nil {
+			c.Logger().Errorf("failed to insert audit event: %+v", err)
+			return err
+		}
+
+		err = q.PublishWebhookEvent(ctx, questionnaireID, model.WebhookEventQuestionnaireUpdated, after)
+		if err != nil {
+			c.Logger().Errorf("failed to publish webhook event: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError
+		}
+		if errors.Is(err, model.ErrInvalidResShareType) {
+			c.Logger().Infof("invalid res_shared_to: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "res_shared_to is not available")
+		}
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+		if errors.Is(err, model.ErrConflict) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "questionnaire has been modified")
+		}
+		if errors.Is(err, model.ErrAnonymityLocked) {
+			return echo.NewHTTPError(http.StatusBadRequest, "anonymity cannot be turned off once responses exist")
+		}
+
+		c.Logger().Errorf("failed to update questionnaire: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update a questionnaire")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// DeleteQuestionnaire DELETE /questionnaires/:questionnaireID
+func (q *Questionnaire) DeleteQuestionnaire(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		before, err := q.getQuestionnaireAuditState(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to get questionnaire audit state: %+v", err)
+			return err
+		}
+
+		err = q.IQuestionnaire.DeleteQuestionnaire(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to delete questionnaire: %+v", err)
+			return err
+		}
+
+		err = q.DeleteTargets(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to delete targets: %+v", err)
+			return err
+		}
+
+		err = q.DeleteAdministrators(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to delete administrators: %+v", err)
+			return err
+		}
+
+		err = q.InsertAuditEvent(ctx, userID, questionnaireID, model.AuditTargetQuestionnaire, questionnaireID, model.AuditActionDelete, before, nil)
+		if err != nil {
+			c.Logger().Errorf("failed to insert audit event: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError
+		}
+
+		c.Logger().Errorf("failed to delete questionnaire: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete a questionnaire")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// questionnaireAuditState 監査ログに記録するアンケートの状態
+type questionnaireAuditState struct {
+	Title               string    `json:"title"`
+	Description         string    `json:"description"`
+	ResTimeLimit        null.Time `json:"res_time_limit"`
+	ResStartAt          null.Time `json:"res_start_at"`
+	IsClosed            bool      `json:"is_closed"`
+	ResSharedTo         string    `json:"res_shared_to"`
+	IsTemplate          bool      `json:"is_template"`
+	IsAnonymous         bool      `json:"is_anonymous"`
+	MaxResponsesPerUser null.Int  `json:"max_responses_per_user"`
+	MaxTotalResponses   null.Int  `json:"max_total_responses"`
+	DisableReminders    bool      `json:"disable_reminders"`
+	Targets             []string  `json:"targets"`
+	Administrators      []string  `json:"administrators"`
+}
+
+// newQuestionnaireAuditState 監査ログに記録するアンケートの状態を作る
+// DBから取得した場合とリクエストの場合で差分が出ないように、時刻と対象者・管理者の順番を揃える
+func newQuestionnaireAuditState(title string, description string, resTimeLimit null.Time, resStartAt null.Time, isClosed bool, resSharedTo string, isTemplate bool, isAnonymous bool, maxResponsesPerUser null.Int, maxTotalResponses null.Int, disableReminders bool, targets []string, administrators []string) questionnaireAuditState {
+	if resTimeLimit.Valid {
+		resTimeLimit = null.TimeFrom(resTimeLimit.Time.UTC().Truncate(time.Second))
+	}
+	if resStartAt.Valid {
+		resStartAt = null.TimeFrom(resStartAt.Time.UTC().Truncate(time.Second))
+	}
+
+	sortedTargets := make([]string, len(targets))
+	copy(sortedTargets, targets)
+	sort.Strings(sortedTargets)
+
+	sortedAdministrators := make([]string, len(administrators))
+	copy(sortedAdministrators, administrators)
+	sort.Strings(sortedAdministrators)
+
+	return questionnaireAuditState{
+		Title:               title,
+		Description:         description,
+		ResTimeLimit:        resTimeLimit,
+		ResStartAt:          resStartAt,
+		IsClosed:            isClosed,
+		ResSharedTo:         resSharedTo,
+		IsTemplate:          isTemplate,
+		IsAnonymous:         isAnonymous,
+		MaxResponsesPerUser: maxResponsesPerUser,
+		MaxTotalResponses:   maxTotalResponses,
+		DisableReminders:    disableReminders,
+		Targets:             sortedTargets,
+		Administrators:      sortedAdministrators,
+	}
+}
+
+// getQuestionnaireAuditState 監査ログに記録するアンケートの現在の状態を取得する
+func (q *Questionnaire) getQuestionnaireAuditState(ctx context.Context, questionnaireID int) (questionnaireAuditState, error) {
+	questionnaire, targets, administrators, _, err := q.GetQuestionnaireInfo(ctx, questionnaireID)
+	if err != nil {
+		return questionnaireAuditState{}, fmt.Errorf("failed to get questionnaire info: %w", err)
+	}
+
+	return newQuestionnaireAuditState(
+		questionnaire.Title,
+		questionnaire.Description,
+		questionnaire.ResTimeLimit,
+		questionnaire.ResStartAt,
+		questionnaire.IsClosed,
+		questionnaire.ResSharedTo,
+		questionnaire.IsTemplate,
+		questionnaire.IsAnonymous,
+		questionnaire.MaxResponsesPerUser,
+		questionnaire.MaxTotalResponses,
+		questionnaire.DisableReminders,
+		targets,
+		administrators,
+	), nil
+}
+
+// GetQuestionnaireHistory GET /questionnaires/:questionnaireID/history
+func (q *Questionnaire) GetQuestionnaireHistory(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	auditEvents, err := q.GetAuditEvents(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get audit events: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, auditEvents)
+}
+
+type CopyQuestionnaireRequest struct {
+	Title        null.String `json:"title"`
+	ResTimeLimit null.Time   `json:"res_time_limit"`
+	IsTemplate   bool        `json:"is_template"`
+}
+
+// CopyQuestionnaire POST /questionnaires/:questionnaireID/copy
+func (q *Questionnaire) CopyQuestionnaire(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
+	req := CopyQuestionnaireRequest{}
+	err = c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind CopyQuestionnaireRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	if req.Title.Valid {
+		titleLength := utf8.RuneCountInString(req.Title.String)
+		if titleLength == 0 || titleLength > MaxTitleLength {
+			c.Logger().Infof("invalid title: %s", req.Title.String)
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("title must be 1 to %d characters", MaxTitleLength))
+		}
+	}
+	if req.ResTimeLimit.Valid && req.ResTimeLimit.Time.Before(time.Now()) {
+		c.Logger().Infof("invalid resTimeLimit: %+v", req.ResTimeLimit)
+		return echo.NewHTTPError(http.StatusBadRequest, "res time limit is before now")
+	}
+
+	var (
+		newQuestionnaireID int
+		newQuestionnaire   *model.Questionnaires
+		newTargets         []string
+		newAdministrators  []string
+	)
+	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		questionnaire, targets, administrators, _, err := q.GetQuestionnaireInfo(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to get questionnaire: %+v", err)
+			return err
+		}
+
+		title := questionnaire.Title
+		if req.Title.Valid {
+			title = req.Title.String
+		}
+
+		// 指定がなければ元のアンケートの回答期限を使うが、過ぎている場合は期限なしにする
+		resTimeLimit := req.ResTimeLimit
+		if !resTimeLimit.Valid && questionnaire.ResTimeLimit.Valid && questionnaire.ResTimeLimit.Time.After(time.Now()) {
+			resTimeLimit = questionnaire.ResTimeLimit
+		}
+
+		// コピーした人が編集できるように、管理者に加える
+		newAdministrators = administrators
+		if !isAdministrator(userID, administrators) {
+			newAdministrators = append(newAdministrators, userID)
+		}
+		newTargets = targets
+
+		newQuestionnaireID, err = q.InsertQuestionnaire(ctx, title, questionnaire.Description, resTimeLimit, questionnaire.ResSharedTo, req.IsTemplate)
+		if err != nil {
+			c.Logger().Errorf("failed to insert a questionnaire: %+v", err)
+			return err
+		}
+
+		// 匿名のアンケートのコピーも匿名にする
+		if questionnaire.IsAnonymous {
+			err = q.UpdateQuestionnaireAnonymous(ctx, newQuestionnaireID, true)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire anonymous: %+v", err)
+				return err
+			}
+		}
+
+		if questionnaire.MaxResponsesPerUser.Valid || questionnaire.MaxTotalResponses.Valid {
+			err = q.UpdateQuestionnaireResponseLimits(ctx, newQuestionnaireID, questionnaire.MaxResponsesPerUser, questionnaire.MaxTotalResponses)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire response limits: %+v", err)
+				return err
+			}
+		}
+
+		if questionnaire.DisableReminders {
+			err = q.UpdateQuestionnaireReminders(ctx, newQuestionnaireID, true)
+			if err != nil {
+				c.Logger().Errorf("failed to update questionnaire reminders: %+v", err)
+				return err
+			}
+		}
+
+		messageTemplates, err := q.GetQuestionnaireMessageTemplates(ctx, questionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to get message templates: %+v", err)
+			return err
+		}
+		for _, messageTemplate := range messageTemplates {
+			err = q.UpsertQuestionnaireMessageTemplate(ctx, newQuestionnaireID, messageTemplate.Kind, messageTemplate.Template)
+			if err != nil {
+				c.Logger().Errorf("failed to upsert message template: %+v", err)
+				return err
+			}
+		}
+
+		err = q.InsertTargets(ctx, newQuestionnaireID, newTargets)
+		if err != nil {
+			c.Logger().Errorf("failed to insert targets: %+v", err)
+			return err
+		}
+
+		err = q.InsertAdministrators(ctx, newQuestionnaireID, newAdministrators)
+		if err != nil {
+			c.Logger().Errorf("failed to insert administrators: %+v", err)
+			return err
+		}
+
+		err = q.copyQuestions(ctx, questionnaireID, newQuestionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to copy questions: %+v", err)
+			return err
+		}
+
+		newQuestionnaire = &model.Questionnaires{
+			ID:                  newQuestionnaireID,
+			Title:               title,
+			Description:         questionnaire.Description,
+			ResTimeLimit:        resTimeLimit,
+			ResSharedTo:         questionnaire.ResSharedTo,
+			IsTemplate:          req.IsTemplate,
+			IsAnonymous:         questionnaire.IsAnonymous,
+			MaxResponsesPerUser: questionnaire.MaxResponsesPerUser,
+			MaxTotalResponses:   questionnaire.MaxTotalResponses,
+			DisableReminders:    questionnaire.DisableReminders,
+		}
+
+		// テンプレートは回答を集めないため、traQに告知しない
+		if req.IsTemplate {
+			return nil
+		}
+
+		err = q.UpdateQuestionnaireAnnounced(ctx, newQuestionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to update questionnaire announced: %+v", err)
+			return err
+		}
+
+		message, err := q.RenderMessage(ctx, model.MessageTemplateKindAnnouncement, MessageData{
+			QuestionnaireID: newQuestionnaireID,
+			Title:           title,
+			Description:     questionnaire.Description,
+			Administrators:  newAdministrators,
+			Targets:         newTargets,
+			ResTimeLimit:    resTimeLimit,
+		})
+		if err != nil {
+			c.Logger().Errorf("failed to render message: %+v", err)
+			return err
+		}
+		_, err = q.InsertOutboxMessage(ctx, message)
+		if err != nil {
+			c.Logger().Errorf("failed to insert outbox message: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError
+		}
+		if errors.Is(err, model.ErrRecordNotFound) {
+			c.Logger().Infof("questionnaire not found: %+v", err)
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+		if errors.Is(err, model.ErrInvalidResShareType) {
+			c.Logger().Infof("invalid res_shared_to: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "res_shared_to is not available")
+		}
+		if errors.Is(err, model.ErrInvalidQuestionType) {
+			c.Logger().Infof("inactive question type: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "question_type is not available")
+		}
+
+		c.Logger().Errorf("failed to copy questionnaire: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to copy a questionnaire")
+	}
+
+	now := time.Now()
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"questionnaireID":        newQuestionnaire.ID,
+		"title":                  newQuestionnaire.Title,
+		"description":            newQuestionnaire.Description,
+		"res_time_limit":         newQuestionnaire.ResTimeLimit,
+		"deleted_at":             "NULL",
+		"created_at":             now.Format(time.RFC3339),
+		"modified_at":            now.Format(time.RFC3339),
+		"res_shared_to":          newQuestionnaire.ResSharedTo,
+		"is_template":            newQuestionnaire.IsTemplate,
+		"is_anonymous":           newQuestionnaire.IsAnonymous,
+		"max_responses_per_user": newQuestionnaire.MaxResponsesPerUser,
+		"max_total_responses":    newQuestionnaire.MaxTotalResponses,
+		"targets":                newTargets,
+		"administrators":         newAdministrators,
+	})
+}
+
+// copyQuestions アンケートの質問を選択肢、目盛り、回答の制限、分岐条件ごと別のアンケートにコピーする
+func (q *Questionnaire) copyQuestions(ctx context.Context, questionnaireID int, newQuestionnaireID int) error {
+	questions, err := q.IQuestion.GetQuestions(ctx, questionnaireID)
+	if err != nil {
+		return fmt.Errorf("failed to get questions: %w", err)
+	}
+	if len(questions) == 0 {
+		return nil
+	}
+
+	questionIDs := make([]int, 0, len(questions))
+	optionIDs := []int{}
+	scaleLabelIDs := []int{}
+	validationIDs := []int{}
+	for _, question := range questions {
+		questionIDs = append(questionIDs, question.ID)
+
+		questionType, _ := model.GetQuestionTypeInfo(question.Type)
+		if questionType.HasOptions {
+			optionIDs = append(optionIDs, question.ID)
+		}
+		if questionType.HasScaleLabel {
+			scaleLabelIDs = append(scaleLabelIDs, question.ID)
+		}
+		if questionType.HasValidation() {
+			validationIDs = append(validationIDs, question.ID)
+		}
+	}
+
+	options, err := q.GetOptions(ctx, optionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get options: %w", err)
+	}
+	optionMap := make(map[int][]model.Options, len(optionIDs))
+	for _, option := range options {
+		optionMap[option.QuestionID] = append(optionMap[option.QuestionID], option)
+	}
+
+	scaleLabels, err := q.GetScaleLabels(ctx, scaleLabelIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get scale labels: %w", err)
+	}
+	scaleLabelMap := make(map[int]model.ScaleLabels, len(scaleLabels))
+	for _, label := range scaleLabels {
+		scaleLabelMap[label.QuestionID] = label
+	}
+
+	validations, err := q.GetValidations(ctx, validationIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get validations: %w", err)
+	}
+	validationMap := make(map[int]model.Validations, len(validations))
+	for _, validation := range validations {
+		validationMap[validation.QuestionID] = validation
+	}
+
+	conditions, err := q.GetQuestionConditions(ctx, questionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get question conditions: %w", err)
+	}
+	conditionMap := make(map[int][]model.QuestionConditions, len(conditions))
+	for _, condition := range conditions {
+		conditionMap[condition.QuestionID] = append(conditionMap[condition.QuestionID], condition)
+	}
+
+	for _, question := range questions {
+		newQuestionID, err := q.InsertQuestion(ctx, newQuestionnaireID, question.PageNum, question.QuestionNum, question.Type, question.Body, question.IsRequired)
+		if err != nil {
+			return fmt.Errorf("failed to insert question(%d): %w", question.ID, err)
+		}
+
+		for _, option := range optionMap[question.ID] {
+			err := q.InsertOption(ctx, newQuestionID, option.OptionNum, option.Body)
+			if err != nil {
+				return fmt.Errorf("failed to insert option(question: %d): %w", question.ID, err)
+			}
+		}
+
+		if label, ok := scaleLabelMap[question.ID]; ok {
+			err := q.InsertScaleLabel(ctx, newQuestionID, label)
+			if err != nil {
+				return fmt.Errorf("failed to insert scale label(question: %d): %w", question.ID, err)
+			}
+		}
+
+		if validation, ok := validationMap[question.ID]; ok {
+			err := q.InsertValidation(ctx, newQuestionID, validation)
+			if err != nil {
+				return fmt.Errorf("failed to insert validation(question: %d): %w", question.ID, err)
+			}
+		}
+
+		// 分岐条件は選択肢の本文とページ番号で指定されているため、そのままコピーできる
+		if questionConditions, ok := conditionMap[question.ID]; ok {
+			err := q.InsertQuestionConditions(ctx, newQuestionID, questionConditions)
+			if err != nil {
+				return fmt.Errorf("failed to insert question conditions(question: %d): %w", question.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isAdministrator(userID string, administrators []string) bool {
+	for _, administrator := range administrators {
+		if administrator == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetQuestions GET /questionnaires/:questionnaireID/questions
+func (q *Questionnaire) GetQuestions(c echo.Context) error {
+	strQuestionnaireID := c.Param("questionnaireID")
+	questionnaireID, err := strconv.Atoi(strQuestionnaireID)
+	if err != nil {
+		c.Logger().Infof("failed to convert questionnaireID to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid questionnaireID:%s(error: %w)", strQuestionnaireID, err))
+	}
+
+	allquestions, err := q.IQuestion.GetQuestions(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get questions: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	if len(allquestions) == 0 {
+		c.Logger().Info("no questions")
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	type questionInfo struct {
+		QuestionID      int                        `json:"questionID"`
+		PageNum         int                        `json:"page_num"`
+		QuestionNum     int                        `json:"question_num"`
+		QuestionType    string                     `json:"question_type"`
+		Body            string                     `json:"body"`
+		IsRequired      bool                       `json:"is_required"`
+		CreatedAt       string                     `json:"created_at"`
+		Options         []string                   `json:"options"`
+		ScaleLabelRight string                     `json:"scale_label_right"`
+		ScaleLabelLeft  string                     `json:"scale_label_left"`
+		ScaleMin        int                        `json:"scale_min"`
+		ScaleMax        int                        `json:"scale_max"`
+		RegexPattern    string                     `json:"regex_pattern"`
+		MinBound        string                     `json:"min_bound"`
+		MaxBound        string                     `json:"max_bound"`
+		MinSelections   null.Int                   `json:"min_selections"`
+		MaxSelections   null.Int                   `json:"max_selections"`
+		Conditions      []model.QuestionConditions `json:"conditions"`
+	}
+	var ret []questionInfo
+
+	optionIDs := []int{}
+	scaleLabelIDs := []int{}
+	validationIDs := []int{}
+	for _, question := range allquestions {
+		questionType, _ := model.GetQuestionTypeInfo(question.Type)
+		if questionType.HasOptions {
+			optionIDs = append(optionIDs, question.ID)
+		}
+		if questionType.HasScaleLabel {
+			scaleLabelIDs = append(scaleLabelIDs, question.ID)
+		}
+		// Checkboxは選択肢に加えて選択数の制限を持つ
+		if questionType.HasValidation() {
+			validationIDs = append(validationIDs, question.ID)
+		}
+	}
+
+	options, err := q.GetOptions(c.Request().Context(), optionIDs)
+	if err != nil {
+		c.Logger().Errorf("failed to get options: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	optionMap := make(map[int][]string, len(options))
+	for _, option := range options {
 		optionMap[option.QuestionID] = append(optionMap[option.QuestionID], option.Body)
 	}
 
@@ -579,24 +1677,40 @@ func (q *Questionnaire) GetQuestions(c echo.Context) error {
 		validationMap[validation.QuestionID] = validation
 	}
 
+	questionIDs := make([]int, 0, len(allquestions))
+	for _, question := range allquestions {
+		questionIDs = append(questionIDs, question.ID)
+	}
+	conditions, err := q.GetQuestionConditions(c.Request().Context(), questionIDs)
+	if err != nil {
+		c.Logger().Errorf("failed to get question conditions: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	conditionMap := make(map[int][]model.QuestionConditions, len(conditions))
+	for _, condition := range conditions {
+		conditionMap[condition.QuestionID] = append(conditionMap[condition.QuestionID], condition)
+	}
+
 	for _, v := range allquestions {
 		options := []string{}
 		scalelabel := model.ScaleLabels{}
 		validation := model.Validations{}
-		switch v.Type {
-		case "MultipleChoice", "Checkbox", "Dropdown":
+		questionType, _ := model.GetQuestionTypeInfo(v.Type)
+		if questionType.HasOptions {
 			var ok bool
 			options, ok = optionMap[v.ID]
 			if !ok {
 				options = []string{}
 			}
-		case "LinearScale":
+		}
+		if questionType.HasScaleLabel {
 			var ok bool
 			scalelabel, ok = scaleLabelMap[v.ID]
 			if !ok {
 				scalelabel = model.ScaleLabels{}
 			}
-		case "Text", "Number":
+		}
+		if questionType.HasValidation() {
 			var ok bool
 			validation, ok = validationMap[v.ID]
 			if !ok {
@@ -604,6 +1718,11 @@ func (q *Questionnaire) GetQuestions(c echo.Context) error {
 			}
 		}
 
+		questionConditions, ok := conditionMap[v.ID]
+		if !ok {
+			questionConditions = []model.QuestionConditions{}
+		}
+
 		ret = append(ret,
 			questionInfo{
 				QuestionID:      v.ID,
@@ -621,46 +1740,12 @@ func (q *Questionnaire) GetQuestions(c echo.Context) error {
 				RegexPattern:    validation.RegexPattern,
 				MinBound:        validation.MinBound,
 				MaxBound:        validation.MaxBound,
+				MinSelections:   validation.MinSelections,
+				MaxSelections:   validation.MaxSelections,
+				Conditions:      questionConditions,
 			},
 		)
 	}
 
 	return c.JSON(http.StatusOK, ret)
 }
-
-func createQuestionnaireMessage(questionnaireID int, title string, description string, administrators []string, resTimeLimit null.Time, targets []string) string {
-	var resTimeLimitText string
-	if resTimeLimit.Valid {
-		resTimeLimitText = resTimeLimit.Time.Local().Format("2006/01/02 15:04")
-	} else {
-		resTimeLimitText = "なし"
-	}
-
-	var targetsMentionText string
-	if len(targets) == 0 {
-		targetsMentionText = "なし"
-	} else {
-		targetsMentionText = "@" + strings.Join(targets, " @")
-	}
-
-	return fmt.Sprintf(
-		`### アンケート『[%s](https://anke-to.trap.jp/questionnaires/%d)』が作成されました
-#### 管理者
-%s
-#### 説明
-%s
-#### 回答期限
-%s
-#### 対象者
-%s
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/%d`,
-		title,
-		questionnaireID,
-		strings.Join(administrators, ","),
-		description,
-		resTimeLimitText,
-		targetsMentionText,
-		questionnaireID,
-	)
-}
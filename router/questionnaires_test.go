@@ -2,6 +2,7 @@ package router
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,7 +20,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/traPtitech/anke-to/model"
 	"github.com/traPtitech/anke-to/model/mock_model"
-	"github.com/traPtitech/anke-to/traq/mock_traq"
 	"gopkg.in/guregu/null.v4"
 )
 
@@ -120,12 +120,12 @@ func TestPostAndEditQuestionnaireValidate(t *testing.T) {
 			},
 		},
 		{
-			description: "resSharedToがadministrators、respondents、publicのいずれでもないのでエラー",
+			description: "resSharedToが空なのでエラー",
 			request: &PostAndEditQuestionnaireRequest{
 				Title:          "第1回集会らん☆ぷろ募集アンケート",
 				Description:    "第1回集会らん☆ぷろ参加者募集",
 				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "test",
+				ResSharedTo:    "",
 				Targets:        []string{},
 				Administrators: []string{"mazrean"},
 			},
@@ -384,8 +384,13 @@ func TestPostQuestionnaire(t *testing.T) {
 	mockOption := mock_model.NewMockIOption(ctrl)
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
 	mockTransaction := &model.MockTransaction{}
-	mockWebhook := mock_traq.NewMockIWebhook(ctrl)
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	questionnaire := NewQuestionnaire(
 		mockQuestionnaire,
@@ -395,9 +400,24 @@ func TestPostQuestionnaire(t *testing.T) {
 		mockOption,
 		mockScaleLabel,
 		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
 		mockTransaction,
-		mockWebhook,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
+	mockGroup.
+		EXPECT().
+		GetGroupMembersByNames(gomock.Any(), gomock.Any()).
+		Return(map[string][]string{}, nil).
+		AnyTimes()
+	mockMessageTemplate.
+		EXPECT().
+		GetQuestionnaireMessageTemplate(gomock.Any(), gomock.Any(), model.MessageTemplateKindAnnouncement).
+		Return("", model.ErrRecordNotFound).
+		AnyTimes()
 
 	type expect struct {
 		statusCode int
@@ -411,7 +431,8 @@ func TestPostQuestionnaire(t *testing.T) {
 		InsertQuestionnaireError  error
 		InsertTargetsError        error
 		InsertAdministratorsError error
-		PostMessageError          error
+		InsertAuditEventError     error
+		InsertOutboxMessageError  error
 		expect
 	}
 
@@ -430,6 +451,20 @@ func TestPostQuestionnaire(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 			},
 		},
+		{
+			description: "resSharedToが登録されていないので400",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "test",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 		{
 			description: "resTimeLimitが誤っているので400",
 			request: PostAndEditQuestionnaireRequest{
@@ -495,7 +530,7 @@ func TestPostQuestionnaire(t *testing.T) {
 			},
 		},
 		{
-			description: "PostMessageがエラーなので500",
+			description: "InsertAuditEventがエラーなので500",
 			request: PostAndEditQuestionnaireRequest{
 				Title:          "第1回集会らん☆ぷろ募集アンケート",
 				Description:    "第1回集会らん☆ぷろ参加者募集",
@@ -504,9 +539,26 @@ func TestPostQuestionnaire(t *testing.T) {
 				Targets:        []string{},
 				Administrators: []string{"mazrean"},
 			},
-			ExecutesCreation: true,
-			questionnaireID:  1,
-			PostMessageError: errors.New("PostMessageError"),
+			ExecutesCreation:      true,
+			questionnaireID:       1,
+			InsertAuditEventError: errors.New("InsertAuditEventError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "InsertOutboxMessageがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:         true,
+			questionnaireID:          1,
+			InsertOutboxMessageError: errors.New("InsertOutboxMessageError"),
 			expect: expect{
 				statusCode: http.StatusInternalServerError,
 			},
@@ -559,6 +611,89 @@ func TestPostQuestionnaire(t *testing.T) {
 				statusCode: http.StatusCreated,
 			},
 		},
+		{
+			description: "テンプレートはtraQに告知せずに201",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+				IsTemplate:     true,
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "回答開始前はtraQに告知せずに201",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResStartAt:     null.TimeFrom(time.Now().Add(time.Hour)),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "締め切られていればtraQに告知せずに201",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				IsClosed:       null.BoolFrom(true),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "リマインドを無効にしても201",
+			request: PostAndEditQuestionnaireRequest{
+				Title:            "第1回集会らん☆ぷろ募集アンケート",
+				Description:      "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:     null.TimeFrom(time.Now().Add(24 * time.Hour)),
+				ResSharedTo:      "public",
+				DisableReminders: null.BoolFrom(true),
+				Targets:          []string{},
+				Administrators:   []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "回答開始日時が回答期限より後なので400",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.TimeFrom(time.Now().Add(time.Hour)),
+				ResStartAt:     null.TimeFrom(time.Now().Add(2 * time.Hour)),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -582,6 +717,7 @@ func TestPostQuestionnaire(t *testing.T) {
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			c := e.NewContext(req, rec)
 
+			c.Set(userIDKey, "userID1")
 			c.Set(validatorKey, validator.New())
 
 			if testCase.ExecutesCreation {
@@ -601,9 +737,33 @@ func TestPostQuestionnaire(t *testing.T) {
 						testCase.request.Description,
 						mockTimeLimit,
 						testCase.request.ResSharedTo,
+						testCase.request.IsTemplate,
 					).
 					Return(testCase.questionnaireID, testCase.InsertQuestionnaireError)
 
+				if testCase.InsertQuestionnaireError == nil && (testCase.request.ResStartAt.Valid || testCase.request.IsClosed.ValueOrZero()) {
+					mockQuestionnaire.
+						EXPECT().
+						UpdateQuestionnaireSchedule(
+							c.Request().Context(),
+							testCase.questionnaireID,
+							gomock.Any(),
+							testCase.request.IsClosed.ValueOrZero(),
+						).
+						Return(nil)
+				}
+
+				if testCase.InsertQuestionnaireError == nil && testCase.request.DisableReminders.ValueOrZero() {
+					mockQuestionnaire.
+						EXPECT().
+						UpdateQuestionnaireReminders(
+							c.Request().Context(),
+							testCase.questionnaireID,
+							true,
+						).
+						Return(nil)
+				}
+
 				if testCase.InsertQuestionnaireError == nil {
 					mockTarget.
 						EXPECT().
@@ -625,10 +785,30 @@ func TestPostQuestionnaire(t *testing.T) {
 							Return(testCase.InsertAdministratorsError)
 
 						if testCase.InsertAdministratorsError == nil {
-							mockWebhook.
+							mockAudit.
+								EXPECT().
+								InsertAuditEvent(
+									c.Request().Context(),
+									"userID1",
+									testCase.questionnaireID,
+									model.AuditTargetQuestionnaire,
+									testCase.questionnaireID,
+									model.AuditActionCreate,
+									nil,
+									gomock.Any(),
+								).
+								Return(testCase.InsertAuditEventError)
+						}
+
+						if testCase.InsertAdministratorsError == nil && testCase.InsertAuditEventError == nil && !testCase.request.IsTemplate && testCase.request.isOpenAt(time.Now()) {
+							mockQuestionnaire.
+								EXPECT().
+								UpdateQuestionnaireAnnounced(c.Request().Context(), testCase.questionnaireID).
+								Return(nil)
+							mockOutbox.
 								EXPECT().
-								PostMessage(gomock.Any()).
-								Return(testCase.PostMessageError)
+								InsertOutboxMessage(c.Request().Context(), gomock.Any()).
+								Return(1, testCase.InsertOutboxMessageError)
 						}
 					}
 				}
@@ -659,6 +839,7 @@ func TestPostQuestionnaire(t *testing.T) {
 					assert.Nil(t, questionnaire["res_time_limit"], "resTimeLimit nil")
 				}
 				assert.Equal(t, testCase.request.ResSharedTo, questionnaire["res_shared_to"], "resSharedTo")
+				assert.Equal(t, testCase.request.IsTemplate, questionnaire["is_template"], "isTemplate")
 
 				strCreatedAt, ok := questionnaire["created_at"].(string)
 				assert.True(t, ok, "created_at convert")
@@ -691,8 +872,13 @@ func TestPostQuestionByQuestionnaireID(t *testing.T) {
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockOption := mock_model.NewMockIOption(ctrl)
 	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
 	mockTransaction := &model.MockTransaction{}
-	mockWebhook := mock_traq.NewMockIWebhook(ctrl)
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	questionnaire := NewQuestionnaire(
 		mockQuestionnaire,
@@ -702,9 +888,19 @@ func TestPostQuestionByQuestionnaireID(t *testing.T) {
 		mockOption,
 		mockScaleLabel,
 		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
 		mockTransaction,
-		mockWebhook,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
+	mockGroup.
+		EXPECT().
+		GetGroupMembersByNames(gomock.Any(), gomock.Any()).
+		Return(map[string][]string{}, nil).
+		AnyTimes()
 
 	type expect struct {
 		statusCode int
@@ -719,11 +915,14 @@ func TestPostQuestionByQuestionnaireID(t *testing.T) {
 		questionnaireID          string
 		validator                string
 		questionNumExists        bool
+		UpdateModifiedAtError    error
 		InsertQuestionError      error
 		InsertOptionError        error
 		InsertValidationError    error
 		InsertScaleLabelError    error
 		CheckNumberValid         error
+		CheckSelectionValid      error
+		CheckDateTimeValid       error
 		CheckQuestionNumError    error
 		expect
 	}
@@ -754,6 +953,23 @@ func TestPostQuestionByQuestionnaireID(t *testing.T) {
 				statusCode: http.StatusCreated,
 			},
 		},
+		{
+			description: "アンケートが存在しないので404",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Text",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+			},
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			UpdateModifiedAtError:    model.ErrRecordNotFound,
+			expect: expect{
+				statusCode: http.StatusNotFound,
+			},
+		},
 		{
 			description: "questionIDが0でも201",
 			request: PostAndEditQuestionRequest{
@@ -957,6 +1173,42 @@ func TestPostQuestionByQuestionnaireID(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 			},
 		},
+		{
+			description: "存在しない質問の種類で400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Unknown",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+			},
+			ExecutesCreation:         false,
+			ExecutesCheckQuestionNum: false,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "新しい質問で使えない質問の種類で400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Dropdown",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+				Options:      []string{"arupaka", "mazrean"},
+			},
+			InsertQuestionError:      model.ErrInvalidQuestionType,
+			ExecutesCreation:         true,
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 		{
 			description: "InsertQuestionがエラーで500",
 			request: PostAndEditQuestionRequest{
@@ -1132,126 +1384,1644 @@ func TestPostQuestionByQuestionnaireID(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 			},
 		},
-	}
-
-	for _, test := range testCases {
-		t.Run(test.description, func(t *testing.T) {
-			var request io.Reader
-			if test.invalidRequest {
-				request = strings.NewReader("test")
-			} else {
-				buf := bytes.NewBuffer(nil)
-				err := json.NewEncoder(buf).Encode(test.request)
-				if err != nil {
-					t.Errorf("failed to encode request: %w", err)
-				}
-
-				request = buf
-			}
-
-			e := echo.New()
-			var req *http.Request
-			intQuestionnaireID, err := strconv.Atoi(test.questionnaireID)
-			if err != nil {
-				req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%s/questions", test.questionnaireID), request)
-			} else {
-				req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%d/questions", intQuestionnaireID), request)
-			}
-
-			rec := httptest.NewRecorder()
-			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-			c := e.NewContext(req, rec)
-			c.SetParamNames("questionnaireID")
-
-			c.SetParamValues(test.questionnaireID)
-
-			c.Set(questionnaireIDKey, test.request.QuestionnaireID)
-			if test.validator != "" {
-				c.Set(test.validator, validator.New())
-			} else {
-				c.Set(validatorKey, validator.New())
-			}
-
-			if test.ExecutesCheckQuestionNum {
-				mockQuestion.
-					EXPECT().
-					CheckQuestionNum(c.Request().Context(), intQuestionnaireID, test.request.QuestionNum).
-					Return(test.questionNumExists, test.CheckQuestionNumError)
-			}
-			if test.ExecutesCreation {
-				mockQuestion.
-					EXPECT().
-					InsertQuestion(c.Request().Context(), intQuestionnaireID, test.request.PageNum, test.request.QuestionNum, test.request.QuestionType, test.request.Body, test.request.IsRequired).
-					Return(test.questionID, test.InsertQuestionError)
-			}
-			if test.InsertQuestionError == nil && test.request.QuestionType == "LinearScale" {
-				mockScaleLabel.
-					EXPECT().
-					InsertScaleLabel(c.Request().Context(), test.questionID, model.ScaleLabels{
-						ScaleLabelRight: test.request.ScaleLabelRight,
-						ScaleLabelLeft:  test.request.ScaleLabelLeft,
-						ScaleMin:        test.request.ScaleMin,
-						ScaleMax:        test.request.ScaleMax,
-					}).
-					Return(test.InsertScaleLabelError)
-			}
-			if test.InsertQuestionError == nil && (test.request.QuestionType == "MultipleChoice" || test.request.QuestionType == "Checkbox" || test.request.QuestionType == "Dropdown") {
-				for i, option := range test.request.Options {
-					mockOption.
-						EXPECT().
-						InsertOption(c.Request().Context(), test.questionID, i+1, option).
-						Return(test.InsertOptionError)
-				}
-			}
-			if test.request.QuestionType == "Number" {
-				mockValidation.
-					EXPECT().
-					CheckNumberValid(test.request.MinBound, test.request.MaxBound).
-					Return(test.CheckNumberValid)
-			}
-			if test.ExecutesCreation && test.InsertQuestionError == nil && test.CheckNumberValid == nil && (test.request.QuestionType == "Text" || test.request.QuestionType == "Number") {
-				mockValidation.
-					EXPECT().
-					InsertValidation(c.Request().Context(), test.questionID, model.Validations{
-						RegexPattern: test.request.RegexPattern,
-						MinBound:     test.request.MinBound,
-						MaxBound:     test.request.MaxBound,
-					}).
-					Return(test.InsertValidationError)
-			}
-
-			e.HTTPErrorHandler(questionnaire.PostQuestionByQuestionnaireID(c), c)
-
-			assert.Equal(t, test.expect.statusCode, rec.Code, "status code")
-
-			if test.expect.statusCode == http.StatusCreated {
-				var question map[string]interface{}
-				err := json.NewDecoder(rec.Body).Decode(&question)
-				if err != nil {
-					t.Errorf("failed to decode response body: %v", err)
+		{
+			description: "分岐条件があっても201",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "MultipleChoice",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+				Options:      []string{"arupaka", "mazrean"},
+				Conditions: []model.QuestionConditions{
+					{
+						ConditionType: "option",
+						OptionBody:    null.StringFrom("arupaka"),
+						SkipToPage:    3,
+					},
+				},
+			},
+			ExecutesCreation:         true,
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "分岐先が質問のページ以前なので400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Text",
+				QuestionNum:  1,
+				PageNum:      2,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+				Conditions: []model.QuestionConditions{
+					{
+						ConditionType: "answered",
+						SkipToPage:    2,
+					},
+				},
+			},
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "選択肢のない質問に選択肢の分岐条件があるので400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Text",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+				Conditions: []model.QuestionConditions{
+					{
+						ConditionType: "option",
+						OptionBody:    null.StringFrom("arupaka"),
+						SkipToPage:    3,
+					},
+				},
+			},
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "分岐条件の種類が不正なので400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Text",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "発表タイトル",
+				IsRequired:   true,
+				Conditions: []model.QuestionConditions{
+					{
+						ConditionType: "invalid",
+						SkipToPage:    3,
+					},
+				},
+			},
+			questionID:      1,
+			questionnaireID: "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "選択数の制限があるCheckboxなので201",
+			request: PostAndEditQuestionRequest{
+				QuestionType:  "Checkbox",
+				QuestionNum:   1,
+				PageNum:       1,
+				Body:          "所属を希望する班",
+				IsRequired:    true,
+				Options:       []string{"SysAd", "Game", "Sound"},
+				MinSelections: null.IntFrom(1),
+				MaxSelections: null.IntFrom(2),
+			},
+			ExecutesCreation:         true,
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "CheckSelectionValidがエラーなので400",
+			request: PostAndEditQuestionRequest{
+				QuestionType:  "Checkbox",
+				QuestionNum:   1,
+				PageNum:       1,
+				Body:          "所属を希望する班",
+				IsRequired:    true,
+				Options:       []string{"SysAd", "Game", "Sound"},
+				MinSelections: null.IntFrom(3),
+				MaxSelections: null.IntFrom(2),
+			},
+			ExecutesCheckQuestionNum: true,
+			CheckSelectionValid:      model.ErrInvalidSelections,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "日付の範囲があるDateなので201",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Date",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "参加できる日",
+				IsRequired:   true,
+				MinBound:     "2021-04-01",
+				MaxBound:     "2021-04-30",
+			},
+			ExecutesCreation:         true,
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "範囲のないDateTimeなので201",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "DateTime",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "到着予定時刻",
+				IsRequired:   false,
+			},
+			ExecutesCreation:         true,
+			ExecutesCheckQuestionNum: true,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusCreated,
+			},
+		},
+		{
+			description: "CheckDateTimeValidがエラーなので400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Time",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "集合時刻",
+				IsRequired:   true,
+				MinBound:     "18:00",
+				MaxBound:     "09:00",
+			},
+			ExecutesCheckQuestionNum: true,
+			CheckDateTimeValid:       model.ErrInvalidDateTime,
+			questionID:               1,
+			questionnaireID:          "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "範囲が日時でも数値でもないので400",
+			request: PostAndEditQuestionRequest{
+				QuestionType: "Date",
+				QuestionNum:  1,
+				PageNum:      1,
+				Body:         "参加できる日",
+				IsRequired:   true,
+				MinBound:     "April 1st",
+			},
+			questionID:      1,
+			questionnaireID: "1",
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var request io.Reader
+			if test.invalidRequest {
+				request = strings.NewReader("test")
+			} else {
+				buf := bytes.NewBuffer(nil)
+				err := json.NewEncoder(buf).Encode(test.request)
+				if err != nil {
+					t.Errorf("failed to encode request: %w", err)
+				}
+
+				request = buf
+			}
+
+			e := echo.New()
+			var req *http.Request
+			intQuestionnaireID, err := strconv.Atoi(test.questionnaireID)
+			if err != nil {
+				req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%s/questions", test.questionnaireID), request)
+			} else {
+				req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%d/questions", intQuestionnaireID), request)
+			}
+
+			rec := httptest.NewRecorder()
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(req, rec)
+			c.SetParamNames("questionnaireID")
+
+			c.SetParamValues(test.questionnaireID)
+
+			c.Set(questionnaireIDKey, test.request.QuestionnaireID)
+			if test.validator != "" {
+				c.Set(test.validator, validator.New())
+			} else {
+				c.Set(validatorKey, validator.New())
+			}
+
+			if test.ExecutesCheckQuestionNum {
+				mockQuestion.
+					EXPECT().
+					CheckQuestionNum(c.Request().Context(), intQuestionnaireID, test.request.QuestionNum).
+					Return(test.questionNumExists, test.CheckQuestionNumError)
+			}
+			// 質問の追加もアンケートの版を進める
+			if test.ExecutesCreation || test.UpdateModifiedAtError != nil {
+				mockQuestionnaire.
+					EXPECT().
+					UpdateQuestionnaireModifiedAt(c.Request().Context(), intQuestionnaireID, null.NewTime(time.Time{}, false)).
+					Return(time.Now(), test.UpdateModifiedAtError)
+			}
+			if test.ExecutesCreation {
+				mockQuestion.
+					EXPECT().
+					InsertQuestion(c.Request().Context(), intQuestionnaireID, test.request.PageNum, test.request.QuestionNum, test.request.QuestionType, test.request.Body, test.request.IsRequired).
+					Return(test.questionID, test.InsertQuestionError)
+			}
+			if test.InsertQuestionError == nil && test.request.QuestionType == "LinearScale" {
+				mockScaleLabel.
+					EXPECT().
+					InsertScaleLabel(c.Request().Context(), test.questionID, model.ScaleLabels{
+						ScaleLabelRight: test.request.ScaleLabelRight,
+						ScaleLabelLeft:  test.request.ScaleLabelLeft,
+						ScaleMin:        test.request.ScaleMin,
+						ScaleMax:        test.request.ScaleMax,
+					}).
+					Return(test.InsertScaleLabelError)
+			}
+			if test.ExecutesCreation && test.InsertQuestionError == nil && (test.request.QuestionType == "MultipleChoice" || test.request.QuestionType == "Checkbox" || test.request.QuestionType == "Dropdown") {
+				for i, option := range test.request.Options {
+					mockOption.
+						EXPECT().
+						InsertOption(c.Request().Context(), test.questionID, i+1, option).
+						Return(test.InsertOptionError)
+				}
+			}
+			if test.request.QuestionType == "Number" {
+				mockValidation.
+					EXPECT().
+					CheckNumberValid(test.request.MinBound, test.request.MaxBound).
+					Return(test.CheckNumberValid)
+			}
+			if test.ExecutesCreation && test.InsertQuestionError == nil && test.CheckNumberValid == nil && (test.request.QuestionType == "Text" || test.request.QuestionType == "Number") {
+				mockValidation.
+					EXPECT().
+					InsertValidation(c.Request().Context(), test.questionID, model.Validations{
+						RegexPattern: test.request.RegexPattern,
+						MinBound:     test.request.MinBound,
+						MaxBound:     test.request.MaxBound,
+					}).
+					Return(test.InsertValidationError)
+			}
+			isDateTime := test.request.QuestionType == "Date" || test.request.QuestionType == "Time" || test.request.QuestionType == "DateTime"
+			if isDateTime && test.ExecutesCheckQuestionNum {
+				mockValidation.
+					EXPECT().
+					CheckDateTimeValid(test.request.QuestionType, test.request.MinBound, test.request.MaxBound).
+					Return(test.CheckDateTimeValid)
+			}
+			if test.ExecutesCreation && test.InsertQuestionError == nil && isDateTime {
+				mockValidation.
+					EXPECT().
+					InsertValidation(c.Request().Context(), test.questionID, model.Validations{
+						MinBound: test.request.MinBound,
+						MaxBound: test.request.MaxBound,
+					}).
+					Return(test.InsertValidationError)
+			}
+			if test.request.QuestionType == "Checkbox" {
+				mockValidation.
+					EXPECT().
+					CheckSelectionValid(test.request.MinSelections, test.request.MaxSelections).
+					Return(test.CheckSelectionValid)
+			}
+			if test.ExecutesCreation && test.InsertQuestionError == nil && test.request.QuestionType == "Checkbox" {
+				mockValidation.
+					EXPECT().
+					InsertValidation(c.Request().Context(), test.questionID, model.Validations{
+						MinSelections: test.request.MinSelections,
+						MaxSelections: test.request.MaxSelections,
+					}).
+					Return(test.InsertValidationError)
+			}
+			if test.expect.statusCode == http.StatusCreated {
+				conditions := test.request.Conditions
+				if conditions == nil {
+					conditions = []model.QuestionConditions{}
+				}
+				mockQuestionCondition.
+					EXPECT().
+					InsertQuestionConditions(c.Request().Context(), test.questionID, conditions).
+					Return(nil)
+			}
+
+			e.HTTPErrorHandler(questionnaire.PostQuestionByQuestionnaireID(c), c)
+
+			assert.Equal(t, test.expect.statusCode, rec.Code, "status code")
+
+			if test.expect.statusCode == http.StatusCreated {
+				var question map[string]interface{}
+				err := json.NewDecoder(rec.Body).Decode(&question)
+				if err != nil {
+					t.Errorf("failed to decode response body: %v", err)
+				}
+
+				assert.Equal(t, float64(test.questionID), question["questionID"], "questionID")
+				assert.Equal(t, test.request.QuestionType, question["question_type"], "question_type")
+				assert.Equal(t, float64(test.request.QuestionNum), question["question_num"], "question_num")
+				assert.Equal(t, float64(test.request.PageNum), question["page_num"], "page_num")
+				assert.Equal(t, test.request.Body, question["body"], "body")
+				assert.Equal(t, test.request.IsRequired, question["is_required"], "is_required")
+				assert.ElementsMatch(t, test.request.Options, question["options"], "options")
+				assert.Equal(t, test.request.ScaleLabelRight, question["scale_label_right"], "scale_label_right")
+				assert.Equal(t, test.request.ScaleLabelLeft, question["scale_label_left"], "scale_label_left")
+				assert.Equal(t, float64(test.request.ScaleMax), question["scale_max"], "scale_max")
+				assert.Equal(t, float64(test.request.ScaleMin), question["scale_min"], "scale_min")
+				assert.Equal(t, test.request.RegexPattern, question["regex_pattern"], "regex_pattern")
+				assert.Equal(t, test.request.MinBound, question["min_bound"], "min_bound")
+				assert.Equal(t, test.request.MaxBound, question["max_bound"], "max_bound")
+
+			}
+		})
+	}
+}
+
+func TestPutQuestions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockTarget := mock_model.NewMockITarget(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
+	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+
+	questionnaire := NewQuestionnaire(
+		mockQuestionnaire,
+		mockTarget,
+		mockAdministrator,
+		mockQuestion,
+		mockOption,
+		mockScaleLabel,
+		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
+		mockTransaction,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
+	)
+
+	mockValidation.
+		EXPECT().
+		CheckNumberValid(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	questionnaireID := 1
+	modifiedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	newModifiedAt := time.Now().Truncate(time.Microsecond)
+	currentQuestions := []model.Questions{
+		{ID: 1, QuestionnaireID: questionnaireID, PageNum: 1, QuestionNum: 1, Type: "Text", Body: "名前"},
+		{ID: 2, QuestionnaireID: questionnaireID, PageNum: 1, QuestionNum: 2, Type: "MultipleChoice", Body: "参加しますか"},
+	}
+
+	type expect struct {
+		statusCode int
+	}
+	type test struct {
+		description                   string
+		ifMatch                       string
+		request                       PutQuestionsRequest
+		UpdateQuestionnaireModifiedAt bool
+		UpdateModifiedAtError         error
+		GetQuestions                  bool
+		executesUpdate                bool
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "並び替え、更新、追加、削除をまとめてできるので200",
+			ifMatch:     formatETag(modifiedAt),
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{
+					{
+						QuestionID: 2,
+						PostAndEditQuestionRequest: PostAndEditQuestionRequest{
+							QuestionType: "MultipleChoice",
+							PageNum:      1,
+							Body:         "参加しますか",
+							Options:      []string{"はい", "いいえ"},
+						},
+					},
+					{
+						PostAndEditQuestionRequest: PostAndEditQuestionRequest{
+							QuestionType: "Number",
+							PageNum:      1,
+							Body:         "学年",
+							IsRequired:   true,
+							MinBound:     "1",
+							MaxBound:     "6",
+						},
+					},
+				},
+			},
+			UpdateQuestionnaireModifiedAt: true,
+			GetQuestions:                  true,
+			executesUpdate:                true,
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "If-Matchの版から更新されているので412",
+			ifMatch:     formatETag(modifiedAt),
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{},
+			},
+			UpdateQuestionnaireModifiedAt: true,
+			UpdateModifiedAtError:         model.ErrConflict,
+			expect: expect{
+				statusCode: http.StatusPreconditionFailed,
+			},
+		},
+		{
+			description: "アンケートが存在しないので404",
+			ifMatch:     formatETag(modifiedAt),
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{},
+			},
+			UpdateQuestionnaireModifiedAt: true,
+			UpdateModifiedAtError:         model.ErrRecordNotFound,
+			expect: expect{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "他のアンケートの質問なので400",
+			ifMatch:     formatETag(modifiedAt),
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{
+					{
+						QuestionID: 100,
+						PostAndEditQuestionRequest: PostAndEditQuestionRequest{
+							QuestionType: "TextArea",
+							PageNum:      1,
+							Body:         "感想",
+						},
+					},
+				},
+			},
+			UpdateQuestionnaireModifiedAt: true,
+			GetQuestions:                  true,
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "questionIDが重複しているので400",
+			ifMatch:     formatETag(modifiedAt),
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{
+					{
+						QuestionID: 1,
+						PostAndEditQuestionRequest: PostAndEditQuestionRequest{
+							QuestionType: "TextArea",
+							PageNum:      1,
+							Body:         "感想",
+						},
+					},
+					{
+						QuestionID: 1,
+						PostAndEditQuestionRequest: PostAndEditQuestionRequest{
+							QuestionType: "TextArea",
+							PageNum:      1,
+							Body:         "感想",
+						},
+					},
+				},
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "存在しない質問の種類なので400",
+			ifMatch:     formatETag(modifiedAt),
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{
+					{
+						PostAndEditQuestionRequest: PostAndEditQuestionRequest{
+							QuestionType: "Unknown",
+							PageNum:      1,
+							Body:         "感想",
+						},
+					},
+				},
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "If-Matchがないので428",
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{},
+			},
+			expect: expect{
+				statusCode: http.StatusPreconditionRequired,
+			},
+		},
+		{
+			description: "If-Matchが*なので428",
+			ifMatch:     "*",
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{},
+			},
+			expect: expect{
+				statusCode: http.StatusPreconditionRequired,
+			},
+		},
+		{
+			description: "If-Matchが不正なので412",
+			ifMatch:     "invalid",
+			request: PutQuestionsRequest{
+				Questions: []PutQuestion{},
+			},
+			expect: expect{
+				statusCode: http.StatusPreconditionFailed,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			err := json.NewEncoder(buf).Encode(testCase.request)
+			if err != nil {
+				t.Errorf("failed to encode request: %v", err)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/questionnaires/%d/questions", questionnaireID), buf)
+			rec := httptest.NewRecorder()
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if len(testCase.ifMatch) != 0 {
+				req.Header.Set("If-Match", testCase.ifMatch)
+			}
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID/questions")
+			c.SetParamNames("questionnaireID")
+			c.SetParamValues(strconv.Itoa(questionnaireID))
+
+			c.Set(questionnaireIDKey, questionnaireID)
+			c.Set(validatorKey, validator.New())
+
+			if testCase.UpdateQuestionnaireModifiedAt {
+				mockQuestionnaire.
+					EXPECT().
+					UpdateQuestionnaireModifiedAt(gomock.Any(), questionnaireID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, expectedModifiedAt null.Time) (time.Time, error) {
+						assert.True(t, expectedModifiedAt.Valid, "If-Match valid")
+						assert.True(t, modifiedAt.Equal(expectedModifiedAt.Time), "If-Match modified_at")
+						return newModifiedAt, testCase.UpdateModifiedAtError
+					})
+			}
+
+			if testCase.GetQuestions {
+				mockQuestion.
+					EXPECT().
+					GetQuestions(gomock.Any(), questionnaireID).
+					Return(currentQuestions, nil)
+			}
+
+			if testCase.executesUpdate {
+				mockQuestion.
+					EXPECT().
+					UpdateQuestion(gomock.Any(), questionnaireID, 1, 1, "MultipleChoice", "参加しますか", false, 2).
+					Return(nil)
+				mockOption.
+					EXPECT().
+					DeleteOptions(gomock.Any(), 2).
+					Return(nil)
+				mockScaleLabel.
+					EXPECT().
+					DeleteScaleLabel(gomock.Any(), 2).
+					Return(model.ErrNoRecordDeleted)
+				mockValidation.
+					EXPECT().
+					DeleteValidation(gomock.Any(), 2).
+					Return(model.ErrNoRecordDeleted)
+				mockQuestionCondition.
+					EXPECT().
+					DeleteQuestionConditions(gomock.Any(), 2).
+					Return(nil)
+				mockOption.
+					EXPECT().
+					InsertOption(gomock.Any(), 2, 1, "はい").
+					Return(nil)
+				mockOption.
+					EXPECT().
+					InsertOption(gomock.Any(), 2, 2, "いいえ").
+					Return(nil)
+				mockQuestionCondition.
+					EXPECT().
+					InsertQuestionConditions(gomock.Any(), 2, []model.QuestionConditions{}).
+					Return(nil)
+
+				mockQuestion.
+					EXPECT().
+					InsertQuestion(gomock.Any(), questionnaireID, 1, 2, "Number", "学年", true).
+					Return(3, nil)
+				mockValidation.
+					EXPECT().
+					InsertValidation(gomock.Any(), 3, model.Validations{MinBound: "1", MaxBound: "6"}).
+					Return(nil)
+				mockQuestionCondition.
+					EXPECT().
+					InsertQuestionConditions(gomock.Any(), 3, []model.QuestionConditions{}).
+					Return(nil)
+
+				mockQuestion.
+					EXPECT().
+					DeleteQuestion(gomock.Any(), 1).
+					Return(nil)
+				mockOption.
+					EXPECT().
+					DeleteOptions(gomock.Any(), 1).
+					Return(nil)
+				mockScaleLabel.
+					EXPECT().
+					DeleteScaleLabel(gomock.Any(), 1).
+					Return(model.ErrNoRecordDeleted)
+				mockValidation.
+					EXPECT().
+					DeleteValidation(gomock.Any(), 1).
+					Return(nil)
+				mockQuestionCondition.
+					EXPECT().
+					DeleteQuestionConditions(gomock.Any(), 1).
+					Return(nil)
+			}
+
+			e.HTTPErrorHandler(questionnaire.PutQuestions(c), c)
+
+			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
+
+			if testCase.expect.statusCode == http.StatusOK {
+				var res struct {
+					Questions []struct {
+						QuestionID  int `json:"questionID"`
+						QuestionNum int `json:"question_num"`
+					} `json:"questions"`
+				}
+				err := json.NewDecoder(rec.Body).Decode(&res)
+				if err != nil {
+					t.Errorf("failed to decode response body: %v", err)
+				}
+
+				assert.Equal(t, formatETag(newModifiedAt), rec.Header().Get("ETag"), "ETag")
+				if assert.Len(t, res.Questions, 2, "questions") {
+					assert.Equal(t, 2, res.Questions[0].QuestionID, "questionID")
+					assert.Equal(t, 1, res.Questions[0].QuestionNum, "question_num")
+					assert.Equal(t, 3, res.Questions[1].QuestionID, "questionID")
+					assert.Equal(t, 2, res.Questions[1].QuestionNum, "question_num")
+				}
+			}
+		})
+	}
+}
+
+func TestEditQuestionnaire(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockTarget := mock_model.NewMockITarget(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
+	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+
+	questionnaire := NewQuestionnaire(
+		mockQuestionnaire,
+		mockTarget,
+		mockAdministrator,
+		mockQuestion,
+		mockOption,
+		mockScaleLabel,
+		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
+		mockTransaction,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
+	)
+	mockGroup.
+		EXPECT().
+		GetGroupMembersByNames(gomock.Any(), gomock.Any()).
+		Return(map[string][]string{}, nil).
+		AnyTimes()
+
+	type expect struct {
+		statusCode          int
+		isAnonymous         bool
+		isClosed            bool
+		disableReminders    bool
+		maxResponsesPerUser null.Int
+		maxTotalResponses   null.Int
+	}
+	type test struct {
+		description                  string
+		invalidRequest               bool
+		request                      PostAndEditQuestionnaireRequest
+		omittedFields                []string
+		ExecutesCreation             bool
+		questionnaireID              int
+		questionnaire                model.Questionnaires
+		InsertQuestionnaireError     error
+		UpdateAnonymousError         error
+		DeleteTargetsError           error
+		InsertTargetsError           error
+		DeleteAdministratorsError    error
+		InsertAdministratorsError    error
+		InsertAuditEventError        error
+		InsertOutboxMessageError     error
+		InsertWebhookDeliveriesError error
+		expect
+	}
+
+	testCases := []test{
+		{
+			description:    "リクエストの形式が誤っているので400",
+			invalidRequest: true,
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "validationで落ちるので400",
+			request:     PostAndEditQuestionnaireRequest{},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "resSharedToが登録されていないので400",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "test",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "InsertQuestionnaireがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:         true,
+			InsertQuestionnaireError: errors.New("InsertQuestionnaireError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "回答があるアンケートの匿名を解除しようとしたので400",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				IsAnonymous:    null.BoolFrom(false),
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:     true,
+			questionnaireID:      1,
+			questionnaire:        model.Questionnaires{IsAnonymous: true},
+			UpdateAnonymousError: model.ErrAnonymityLocked,
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "匿名にしても200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				IsAnonymous:    null.BoolFrom(true),
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode:  http.StatusOK,
+				isAnonymous: true,
+			},
+		},
+		{
+			description: "is_anonymousを省略したので匿名の設定を変更せずに200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			questionnaire:    model.Questionnaires{IsAnonymous: true},
+			expect: expect{
+				statusCode:  http.StatusOK,
+				isAnonymous: true,
+			},
+		},
+		{
+			description: "回答数の上限が1未満なので400",
+			request: PostAndEditQuestionnaireRequest{
+				Title:               "第1回集会らん☆ぷろ募集アンケート",
+				Description:         "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:        null.NewTime(time.Time{}, false),
+				ResSharedTo:         "public",
+				MaxResponsesPerUser: optionalNullInt{Int: null.IntFrom(0)},
+				Targets:             []string{},
+				Administrators:      []string{"mazrean"},
+			},
+			questionnaireID: 1,
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "回答数の上限を設定しても200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:               "第1回集会らん☆ぷろ募集アンケート",
+				Description:         "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:        null.NewTime(time.Time{}, false),
+				ResSharedTo:         "public",
+				MaxResponsesPerUser: optionalNullInt{Int: null.IntFrom(1)},
+				MaxTotalResponses:   optionalNullInt{Int: null.IntFrom(30)},
+				Targets:             []string{},
+				Administrators:      []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode:          http.StatusOK,
+				maxResponsesPerUser: null.IntFrom(1),
+				maxTotalResponses:   null.IntFrom(30),
+			},
+		},
+		{
+			description: "回答数の上限をnullにすると上限をなくして200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			questionnaire: model.Questionnaires{
+				MaxResponsesPerUser: null.IntFrom(1),
+				MaxTotalResponses:   null.IntFrom(30),
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "回答数の上限を省略したので上限を変更せずに200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			omittedFields:    []string{"max_responses_per_user", "max_total_responses"},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			questionnaire: model.Questionnaires{
+				MaxResponsesPerUser: null.IntFrom(1),
+				MaxTotalResponses:   null.IntFrom(30),
+			},
+			expect: expect{
+				statusCode:          http.StatusOK,
+				maxResponsesPerUser: null.IntFrom(1),
+				maxTotalResponses:   null.IntFrom(30),
+			},
+		},
+		{
+			description: "全体の回答数の上限を省略したのでその上限だけ変更せずに200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:               "第1回集会らん☆ぷろ募集アンケート",
+				Description:         "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:        null.NewTime(time.Time{}, false),
+				ResSharedTo:         "public",
+				MaxResponsesPerUser: optionalNullInt{Int: null.IntFrom(2)},
+				Targets:             []string{},
+				Administrators:      []string{"mazrean"},
+			},
+			omittedFields:    []string{"max_total_responses"},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			questionnaire: model.Questionnaires{
+				MaxResponsesPerUser: null.IntFrom(1),
+				MaxTotalResponses:   null.IntFrom(30),
+			},
+			expect: expect{
+				statusCode:          http.StatusOK,
+				maxResponsesPerUser: null.IntFrom(2),
+				maxTotalResponses:   null.IntFrom(30),
+			},
+		},
+		{
+			description: "締め切っても200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				IsClosed:       null.BoolFrom(true),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusOK,
+				isClosed:   true,
+			},
+		},
+		{
+			description: "is_closedを省略したので締め切りの状態を変更せずに200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			questionnaire:    model.Questionnaires{IsClosed: true},
+			expect: expect{
+				statusCode: http.StatusOK,
+				isClosed:   true,
+			},
+		},
+		{
+			description: "リマインドを止めても200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:            "第1回集会らん☆ぷろ募集アンケート",
+				Description:      "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:     null.NewTime(time.Time{}, false),
+				ResSharedTo:      "public",
+				Targets:          []string{},
+				Administrators:   []string{"mazrean"},
+				DisableReminders: null.BoolFrom(true),
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode:       http.StatusOK,
+				disableReminders: true,
+			},
+		},
+		{
+			description: "disable_remindersを省略したのでリマインドの設定を変更せずに200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			questionnaire:    model.Questionnaires{DisableReminders: true},
+			expect: expect{
+				statusCode:       http.StatusOK,
+				disableReminders: true,
+			},
+		},
+		{
+			description: "DeleteTargetsがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:   true,
+			questionnaireID:    1,
+			DeleteTargetsError: errors.New("DeleteTargetsError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "InsertTargetsがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:   true,
+			questionnaireID:    1,
+			InsertTargetsError: errors.New("InsertTargetsError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "DeleteAdministratorsがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:          true,
+			questionnaireID:           1,
+			DeleteAdministratorsError: errors.New("DeleteAdministratorsError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "InsertAdministratorsがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:          true,
+			questionnaireID:           1,
+			InsertAdministratorsError: errors.New("InsertAdministratorsError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "InsertAuditEventがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:      true,
+			questionnaireID:       1,
+			InsertAuditEventError: errors.New("InsertAuditEventError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "InsertWebhookDeliveriesがエラーなので500",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation:             true,
+			questionnaireID:              1,
+			InsertWebhookDeliveriesError: errors.New("InsertWebhookDeliveriesError"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "一般的なリクエストなので200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Time{}, false),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "resTimeLimitが現在時刻より前でも200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Now().Add(-24*time.Hour), true),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "回答期限が設定されていてもでも200",
+			request: PostAndEditQuestionnaireRequest{
+				Title:          "第1回集会らん☆ぷろ募集アンケート",
+				Description:    "第1回集会らん☆ぷろ参加者募集",
+				ResTimeLimit:   null.NewTime(time.Now().Add(24*time.Hour), true),
+				ResSharedTo:    "public",
+				Targets:        []string{},
+				Administrators: []string{"mazrean"},
+			},
+			ExecutesCreation: true,
+			questionnaireID:  1,
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			var request io.Reader
+			if testCase.invalidRequest {
+				request = strings.NewReader("test")
+			} else {
+				buf := bytes.NewBuffer(nil)
+				err := json.NewEncoder(buf).Encode(testCase.request)
+				if err != nil {
+					t.Errorf("failed to encode request: %v", err)
+				}
+
+				if len(testCase.omittedFields) != 0 {
+					body := map[string]interface{}{}
+					err = json.NewDecoder(buf).Decode(&body)
+					if err != nil {
+						t.Errorf("failed to decode request: %v", err)
+					}
+
+					for _, field := range testCase.omittedFields {
+						delete(body, field)
+					}
+
+					buf.Reset()
+					err = json.NewEncoder(buf).Encode(body)
+					if err != nil {
+						t.Errorf("failed to encode request: %v", err)
+					}
+				}
+
+				request = buf
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%d", testCase.questionnaireID), request)
+			rec := httptest.NewRecorder()
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(req, rec)
+			c.SetParamNames("questionnaireID")
+			c.SetParamValues(strconv.Itoa(testCase.questionnaireID))
+
+			c.Set(userIDKey, "userID1")
+			c.Set(questionnaireIDKey, testCase.questionnaireID)
+			c.Set(validatorKey, validator.New())
+
+			if testCase.ExecutesCreation {
+				// 時刻は完全一致しないためその対応
+				var mockTimeLimit interface{}
+				if testCase.request.ResTimeLimit.Valid {
+					mockTimeLimit = gomock.Any()
+				} else {
+					mockTimeLimit = testCase.request.ResTimeLimit
+				}
+
+				currentQuestionnaire := testCase.questionnaire
+				currentQuestionnaire.ID = testCase.questionnaireID
+				mockQuestionnaire.
+					EXPECT().
+					GetQuestionnaireInfo(c.Request().Context(), testCase.questionnaireID).
+					Return(&currentQuestionnaire, []string{}, []string{}, []string{}, nil)
+
+				mockQuestionnaire.
+					EXPECT().
+					UpdateQuestionnaire(
+						c.Request().Context(),
+						testCase.request.Title,
+						testCase.request.Description,
+						mockTimeLimit,
+						testCase.request.ResSharedTo,
+						testCase.request.IsTemplate,
+						testCase.questionnaireID,
+					).
+					Return(testCase.InsertQuestionnaireError)
+
+				if testCase.InsertQuestionnaireError == nil && testCase.request.IsAnonymous.Valid {
+					mockQuestionnaire.
+						EXPECT().
+						UpdateQuestionnaireAnonymous(
+							c.Request().Context(),
+							testCase.questionnaireID,
+							testCase.request.IsAnonymous.Bool,
+						).
+						Return(testCase.UpdateAnonymousError)
+				}
+
+				if testCase.InsertQuestionnaireError == nil && testCase.UpdateAnonymousError == nil {
+					omitted := map[string]bool{}
+					for _, field := range testCase.omittedFields {
+						omitted[field] = true
+					}
+					if !omitted["max_responses_per_user"] || !omitted["max_total_responses"] {
+						mockQuestionnaire.
+							EXPECT().
+							UpdateQuestionnaireResponseLimits(
+								c.Request().Context(),
+								testCase.questionnaireID,
+								testCase.expect.maxResponsesPerUser,
+								testCase.expect.maxTotalResponses,
+							).
+							Return(nil)
+					}
+
+					mockQuestionnaire.
+						EXPECT().
+						UpdateQuestionnaireSchedule(
+							c.Request().Context(),
+							testCase.questionnaireID,
+							gomock.Any(),
+							testCase.expect.isClosed,
+						).
+						Return(nil)
+
+					if testCase.request.DisableReminders.Valid {
+						mockQuestionnaire.
+							EXPECT().
+							UpdateQuestionnaireReminders(
+								c.Request().Context(),
+								testCase.questionnaireID,
+								testCase.request.DisableReminders.Bool,
+							).
+							Return(nil)
+					}
+
+					mockTarget.
+						EXPECT().
+						DeleteTargets(
+							c.Request().Context(),
+							testCase.questionnaireID,
+						).
+						Return(testCase.DeleteTargetsError)
+
+					if testCase.DeleteTargetsError == nil {
+						mockTarget.
+							EXPECT().
+							InsertTargets(
+								c.Request().Context(),
+								testCase.questionnaireID,
+								testCase.request.Targets,
+							).
+							Return(testCase.InsertTargetsError)
+
+						if testCase.InsertTargetsError == nil {
+							mockAdministrator.
+								EXPECT().
+								DeleteAdministrators(
+									c.Request().Context(),
+									testCase.questionnaireID,
+								).
+								Return(testCase.DeleteAdministratorsError)
+
+							if testCase.DeleteAdministratorsError == nil {
+								mockAdministrator.
+									EXPECT().
+									InsertAdministrators(
+										c.Request().Context(),
+										testCase.questionnaireID,
+										testCase.request.Administrators,
+									).
+									Return(testCase.InsertAdministratorsError)
+
+								if testCase.InsertAdministratorsError == nil {
+									mockAudit.
+										EXPECT().
+										InsertAuditEvent(
+											c.Request().Context(),
+											"userID1",
+											testCase.questionnaireID,
+											model.AuditTargetQuestionnaire,
+											testCase.questionnaireID,
+											model.AuditActionUpdate,
+											gomock.Any(),
+											gomock.Any(),
+										).
+										DoAndReturn(func(_ context.Context, _ string, _ int, _ string, _ int, _ string, _ interface{}, after interface{}) error {
+											state, ok := after.(questionnaireAuditState)
+											if !ok {
+												t.Errorf("unexpected after: %+v", after)
+												return testCase.InsertAuditEventError
+											}
+
+											assert.Equal(t, testCase.expect.isAnonymous, state.IsAnonymous, "is_anonymous")
+											assert.Equal(t, testCase.expect.isClosed, state.IsClosed, "is_closed")
+											assert.Equal(t, testCase.expect.disableReminders, state.DisableReminders, "disable_reminders")
+											assert.Equal(t, testCase.expect.maxResponsesPerUser, state.MaxResponsesPerUser, "max_responses_per_user")
+											assert.Equal(t, testCase.expect.maxTotalResponses, state.MaxTotalResponses, "max_total_responses")
+
+											return testCase.InsertAuditEventError
+										})
+
+									if testCase.InsertAuditEventError == nil {
+										mockWebhookDelivery.
+											EXPECT().
+											InsertWebhookDeliveries(
+												c.Request().Context(),
+												testCase.questionnaireID,
+												model.WebhookEventQuestionnaireUpdated,
+												gomock.Any(),
+											).
+											Return(testCase.InsertWebhookDeliveriesError)
+									}
+								}
+							}
+						}
+					}
 				}
+			}
+
+			e.HTTPErrorHandler(questionnaire.EditQuestionnaire(c), c)
+
+			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
+		})
+	}
+}
+
+func TestDeleteQuestionnaire(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockTarget := mock_model.NewMockITarget(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
+	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+
+	questionnaire := NewQuestionnaire(
+		mockQuestionnaire,
+		mockTarget,
+		mockAdministrator,
+		mockQuestion,
+		mockOption,
+		mockScaleLabel,
+		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
+		mockTransaction,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
+	)
+	mockGroup.
+		EXPECT().
+		GetGroupMembersByNames(gomock.Any(), gomock.Any()).
+		Return(map[string][]string{}, nil).
+		AnyTimes()
+
+	type expect struct {
+		statusCode int
+	}
+	type test struct {
+		description               string
+		questionnaireID           int
+		GetQuestionnaireInfoError error
+		DeleteQuestionnaireError  error
+		DeleteTargetsError        error
+		DeleteAdministratorsError error
+		InsertAuditEventError     error
+		expect
+	}
+
+	testCases := []test{
+		{
+			description:               "エラーなしなので200",
+			questionnaireID:           1,
+			DeleteQuestionnaireError:  nil,
+			DeleteTargetsError:        nil,
+			DeleteAdministratorsError: nil,
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description:               "questionnaireIDが0でも200",
+			questionnaireID:           0,
+			DeleteQuestionnaireError:  nil,
+			DeleteTargetsError:        nil,
+			DeleteAdministratorsError: nil,
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description:               "DeleteQuestionnaireがエラーなので500",
+			questionnaireID:           1,
+			DeleteQuestionnaireError:  errors.New("error"),
+			DeleteTargetsError:        nil,
+			DeleteAdministratorsError: nil,
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description:               "DeleteTargetsがエラーなので500",
+			questionnaireID:           1,
+			DeleteQuestionnaireError:  nil,
+			DeleteTargetsError:        errors.New("error"),
+			DeleteAdministratorsError: nil,
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description:               "DeleteAdministratorsがエラーなので500",
+			questionnaireID:           1,
+			DeleteQuestionnaireError:  nil,
+			DeleteTargetsError:        nil,
+			DeleteAdministratorsError: errors.New("error"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description:               "GetQuestionnaireInfoがエラーなので500",
+			questionnaireID:           1,
+			GetQuestionnaireInfoError: errors.New("error"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description:           "InsertAuditEventがエラーなので500",
+			questionnaireID:       1,
+			InsertAuditEventError: errors.New("error"),
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+	}
 
-				assert.Equal(t, float64(test.questionID), question["questionID"], "questionID")
-				assert.Equal(t, test.request.QuestionType, question["question_type"], "question_type")
-				assert.Equal(t, float64(test.request.QuestionNum), question["question_num"], "question_num")
-				assert.Equal(t, float64(test.request.PageNum), question["page_num"], "page_num")
-				assert.Equal(t, test.request.Body, question["body"], "body")
-				assert.Equal(t, test.request.IsRequired, question["is_required"], "is_required")
-				assert.ElementsMatch(t, test.request.Options, question["options"], "options")
-				assert.Equal(t, test.request.ScaleLabelRight, question["scale_label_right"], "scale_label_right")
-				assert.Equal(t, test.request.ScaleLabelLeft, question["scale_label_left"], "scale_label_left")
-				assert.Equal(t, float64(test.request.ScaleMax), question["scale_max"], "scale_max")
-				assert.Equal(t, float64(test.request.ScaleMin), question["scale_min"], "scale_min")
-				assert.Equal(t, test.request.RegexPattern, question["regex_pattern"], "regex_pattern")
-				assert.Equal(t, test.request.MinBound, question["min_bound"], "min_bound")
-				assert.Equal(t, test.request.MaxBound, question["max_bound"], "max_bound")
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/questionnaire/%d", testCase.questionnaireID), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaire_id")
+			c.SetParamNames("questionnaire_id")
+			c.SetParamValues(strconv.Itoa(testCase.questionnaireID))
+
+			c.Set(userIDKey, "userID1")
+			c.Set(questionnaireIDKey, testCase.questionnaireID)
+
+			mockQuestionnaire.
+				EXPECT().
+				GetQuestionnaireInfo(c.Request().Context(), testCase.questionnaireID).
+				Return(&model.Questionnaires{ID: testCase.questionnaireID}, []string{}, []string{}, []string{}, testCase.GetQuestionnaireInfoError)
+
+			if testCase.GetQuestionnaireInfoError == nil {
+				mockQuestionnaire.
+					EXPECT().
+					DeleteQuestionnaire(
+						c.Request().Context(),
+						testCase.questionnaireID,
+					).
+					Return(testCase.DeleteQuestionnaireError)
+			}
+
+			if testCase.GetQuestionnaireInfoError == nil && testCase.DeleteQuestionnaireError == nil {
+				mockTarget.
+					EXPECT().
+					DeleteTargets(
+						c.Request().Context(),
+						testCase.questionnaireID,
+					).
+					Return(testCase.DeleteTargetsError)
+
+				if testCase.DeleteTargetsError == nil {
+					mockAdministrator.
+						EXPECT().
+						DeleteAdministrators(
+							c.Request().Context(),
+							testCase.questionnaireID,
+						).
+						Return(testCase.DeleteAdministratorsError)
 
+					if testCase.DeleteAdministratorsError == nil {
+						mockAudit.
+							EXPECT().
+							InsertAuditEvent(
+								c.Request().Context(),
+								"userID1",
+								testCase.questionnaireID,
+								model.AuditTargetQuestionnaire,
+								testCase.questionnaireID,
+								model.AuditActionDelete,
+								gomock.Any(),
+								nil,
+							).
+							Return(testCase.InsertAuditEventError)
+					}
+				}
 			}
+
+			e.HTTPErrorHandler(questionnaire.DeleteQuestionnaire(c), c)
+
+			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
 		})
 	}
 }
 
-func TestEditQuestionnaire(t *testing.T) {
+func TestGetQuestionnaire(t *testing.T) {
 	t.Parallel()
 
 	ctrl := gomock.NewController(t)
@@ -1264,8 +3034,13 @@ func TestEditQuestionnaire(t *testing.T) {
 	mockOption := mock_model.NewMockIOption(ctrl)
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
 	mockTransaction := &model.MockTransaction{}
-	mockWebhook := mock_traq.NewMockIWebhook(ctrl)
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	questionnaire := NewQuestionnaire(
 		mockQuestionnaire,
@@ -1275,275 +3050,307 @@ func TestEditQuestionnaire(t *testing.T) {
 		mockOption,
 		mockScaleLabel,
 		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
 		mockTransaction,
-		mockWebhook,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
 
+	modifiedAt := time.Unix(1600000000, 123456000)
+
 	type expect struct {
 		statusCode int
 	}
 	type test struct {
 		description               string
-		invalidRequest            bool
-		request                   PostAndEditQuestionnaireRequest
-		ExecutesCreation          bool
 		questionnaireID           int
-		InsertQuestionnaireError  error
-		DeleteTargetsError        error
-		InsertTargetsError        error
-		DeleteAdministratorsError error
-		InsertAdministratorsError error
-		PostMessageError          error
+		respondents               []string
+		ifNoneMatch               string
+		GetQuestionnaireInfoError error
 		expect
 	}
 
 	testCases := []test{
 		{
-			description:    "リクエストの形式が誤っているので400",
-			invalidRequest: true,
+			description:     "If-None-Matchがないので200",
+			questionnaireID: 1,
+			respondents:     []string{"mazrean", "xxarupakaxx"},
 			expect: expect{
-				statusCode: http.StatusBadRequest,
+				statusCode: http.StatusOK,
 			},
 		},
 		{
-			description: "validationで落ちるので400",
-			request:     PostAndEditQuestionnaireRequest{},
+			description:     "回答者も変わっていないので304",
+			questionnaireID: 1,
+			respondents:     []string{"mazrean", "xxarupakaxx"},
+			ifNoneMatch:     formatETag(modifiedAt, "mazrean", "xxarupakaxx"),
 			expect: expect{
-				statusCode: http.StatusBadRequest,
+				statusCode: http.StatusNotModified,
 			},
 		},
 		{
-			description: "InsertQuestionnaireがエラーなので500",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation:         true,
-			InsertQuestionnaireError: errors.New("InsertQuestionnaireError"),
+			description:     "回答者の順番が違っても304",
+			questionnaireID: 1,
+			respondents:     []string{"xxarupakaxx", "mazrean"},
+			ifNoneMatch:     formatETag(modifiedAt, "mazrean", "xxarupakaxx"),
 			expect: expect{
-				statusCode: http.StatusInternalServerError,
+				statusCode: http.StatusNotModified,
 			},
 		},
 		{
-			description: "DeleteTargetsがエラーなので500",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation:   true,
-			questionnaireID:    1,
-			DeleteTargetsError: errors.New("DeleteTargetsError"),
+			description:     "modified_atが同じでも回答者が増えたので200",
+			questionnaireID: 1,
+			respondents:     []string{"mazrean", "xxarupakaxx"},
+			ifNoneMatch:     formatETag(modifiedAt, "mazrean"),
 			expect: expect{
-				statusCode: http.StatusInternalServerError,
+				statusCode: http.StatusOK,
 			},
 		},
 		{
-			description: "InsertTargetsがエラーなので500",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation:   true,
-			questionnaireID:    1,
-			InsertTargetsError: errors.New("InsertTargetsError"),
+			description:     "modified_atだけのETagでは回答者が一致しないので200",
+			questionnaireID: 1,
+			respondents:     []string{"mazrean"},
+			ifNoneMatch:     formatETag(modifiedAt),
 			expect: expect{
-				statusCode: http.StatusInternalServerError,
+				statusCode: http.StatusOK,
 			},
 		},
 		{
-			description: "DeleteAdministratorsがエラーなので500",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation:          true,
+			description:               "アンケートが存在しないので404",
 			questionnaireID:           1,
-			DeleteAdministratorsError: errors.New("DeleteAdministratorsError"),
+			GetQuestionnaireInfoError: model.ErrRecordNotFound,
 			expect: expect{
-				statusCode: http.StatusInternalServerError,
+				statusCode: http.StatusNotFound,
 			},
 		},
 		{
-			description: "InsertAdministratorsがエラーなので500",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation:          true,
+			description:               "GetQuestionnaireInfoがエラーなので500",
 			questionnaireID:           1,
-			InsertAdministratorsError: errors.New("InsertAdministratorsError"),
+			GetQuestionnaireInfoError: errors.New("error"),
 			expect: expect{
 				statusCode: http.StatusInternalServerError,
 			},
 		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/questionnaires/%d", testCase.questionnaireID), nil)
+			if len(testCase.ifNoneMatch) != 0 {
+				req.Header.Set("If-None-Match", testCase.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID")
+			c.SetParamNames("questionnaireID")
+			c.SetParamValues(strconv.Itoa(testCase.questionnaireID))
+
+			mockQuestionnaire.
+				EXPECT().
+				GetQuestionnaireInfo(c.Request().Context(), testCase.questionnaireID).
+				Return(&model.Questionnaires{
+					ID:         testCase.questionnaireID,
+					ModifiedAt: modifiedAt,
+				}, []string{}, []string{}, testCase.respondents, testCase.GetQuestionnaireInfoError)
+
+			e.HTTPErrorHandler(questionnaire.GetQuestionnaire(c), c)
+
+			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
+			if testCase.GetQuestionnaireInfoError == nil {
+				actualModifiedAt, err := parseETag(rec.Header().Get("ETag"))
+				assert.NoError(t, err, "parse ETag")
+				assert.True(t, modifiedAt.Equal(actualModifiedAt), "ETag modifiedAt")
+			}
+		})
+	}
+}
+
+func TestGetQuestionnaireHistory(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockTarget := mock_model.NewMockITarget(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
+	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+
+	questionnaire := NewQuestionnaire(
+		mockQuestionnaire,
+		mockTarget,
+		mockAdministrator,
+		mockQuestion,
+		mockOption,
+		mockScaleLabel,
+		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
+		mockTransaction,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
+	)
+
+	type expect struct {
+		statusCode  int
+		auditEvents []model.AuditEvents
+	}
+	type test struct {
+		description         string
+		questionnaireID     int
+		auditEvents         []model.AuditEvents
+		GetAuditEventsError error
+		expect
+	}
+
+	auditEvents := []model.AuditEvents{
 		{
-			description: "一般的なリクエストなので200",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Time{}, false),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation: true,
-			questionnaireID:  1,
-			expect: expect{
-				statusCode: http.StatusOK,
-			},
+			ID:              2,
+			QuestionnaireID: 1,
+			TargetType:      model.AuditTargetQuestion,
+			TargetID:        3,
+			UserTraqid:      "mazrean",
+			Action:          model.AuditActionDelete,
+			Diff:            json.RawMessage(`{"body":{"before":"質問","after":null}}`),
+			CreatedAt:       time.Now().Truncate(time.Second),
 		},
 		{
-			description: "resTimeLimitが現在時刻より前でも200",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Now().Add(-24*time.Hour), true),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
-			},
-			ExecutesCreation: true,
-			questionnaireID:  1,
+			ID:              1,
+			QuestionnaireID: 1,
+			TargetType:      model.AuditTargetQuestionnaire,
+			TargetID:        1,
+			UserTraqid:      "mazrean",
+			Action:          model.AuditActionCreate,
+			Diff:            json.RawMessage(`{"title":{"before":null,"after":"アンケート"}}`),
+			CreatedAt:       time.Now().Add(-time.Hour).Truncate(time.Second),
+		},
+	}
+
+	testCases := []test{
+		{
+			description:     "エラーなしなので200",
+			questionnaireID: 1,
+			auditEvents:     auditEvents,
 			expect: expect{
-				statusCode: http.StatusOK,
+				statusCode:  http.StatusOK,
+				auditEvents: auditEvents,
 			},
 		},
 		{
-			description: "回答期限が設定されていてもでも200",
-			request: PostAndEditQuestionnaireRequest{
-				Title:          "第1回集会らん☆ぷろ募集アンケート",
-				Description:    "第1回集会らん☆ぷろ参加者募集",
-				ResTimeLimit:   null.NewTime(time.Now().Add(24*time.Hour), true),
-				ResSharedTo:    "public",
-				Targets:        []string{},
-				Administrators: []string{"mazrean"},
+			description:     "監査ログがなくても200",
+			questionnaireID: 2,
+			auditEvents:     []model.AuditEvents{},
+			expect: expect{
+				statusCode:  http.StatusOK,
+				auditEvents: []model.AuditEvents{},
 			},
-			ExecutesCreation: true,
-			questionnaireID:  1,
+		},
+		{
+			description:         "GetAuditEventsがエラーなので500",
+			questionnaireID:     1,
+			GetAuditEventsError: errors.New("error"),
 			expect: expect{
-				statusCode: http.StatusOK,
+				statusCode: http.StatusInternalServerError,
 			},
 		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.description, func(t *testing.T) {
-			var request io.Reader
-			if testCase.invalidRequest {
-				request = strings.NewReader("test")
-			} else {
-				buf := bytes.NewBuffer(nil)
-				err := json.NewEncoder(buf).Encode(testCase.request)
-				if err != nil {
-					t.Errorf("failed to encode request: %v", err)
-				}
-
-				request = buf
-			}
-
 			e := echo.New()
-			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%d", testCase.questionnaireID), request)
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/questionnaires/%d/history", testCase.questionnaireID), nil)
 			rec := httptest.NewRecorder()
-			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			c := e.NewContext(req, rec)
+			c.SetPath("/questionnaires/:questionnaireID/history")
 			c.SetParamNames("questionnaireID")
 			c.SetParamValues(strconv.Itoa(testCase.questionnaireID))
 
 			c.Set(questionnaireIDKey, testCase.questionnaireID)
-			c.Set(validatorKey, validator.New())
-
-			if testCase.ExecutesCreation {
-				// 時刻は完全一致しないためその対応
-				var mockTimeLimit interface{}
-				if testCase.request.ResTimeLimit.Valid {
-					mockTimeLimit = gomock.Any()
-				} else {
-					mockTimeLimit = testCase.request.ResTimeLimit
-				}
-
-				mockQuestionnaire.
-					EXPECT().
-					UpdateQuestionnaire(
-						c.Request().Context(),
-						testCase.request.Title,
-						testCase.request.Description,
-						mockTimeLimit,
-						testCase.request.ResSharedTo,
-						testCase.questionnaireID,
-					).
-					Return(testCase.InsertQuestionnaireError)
 
-				if testCase.InsertQuestionnaireError == nil {
-					mockTarget.
-						EXPECT().
-						DeleteTargets(
-							c.Request().Context(),
-							testCase.questionnaireID,
-						).
-						Return(testCase.DeleteTargetsError)
+			mockAudit.
+				EXPECT().
+				GetAuditEvents(c.Request().Context(), testCase.questionnaireID).
+				Return(testCase.auditEvents, testCase.GetAuditEventsError)
 
-					if testCase.DeleteTargetsError == nil {
-						mockTarget.
-							EXPECT().
-							InsertTargets(
-								c.Request().Context(),
-								testCase.questionnaireID,
-								testCase.request.Targets,
-							).
-							Return(testCase.InsertTargetsError)
+			e.HTTPErrorHandler(questionnaire.GetQuestionnaireHistory(c), c)
 
-						if testCase.InsertTargetsError == nil {
-							mockAdministrator.
-								EXPECT().
-								DeleteAdministrators(
-									c.Request().Context(),
-									testCase.questionnaireID,
-								).
-								Return(testCase.DeleteAdministratorsError)
+			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
+			if testCase.expect.statusCode != http.StatusOK {
+				return
+			}
 
-							if testCase.DeleteAdministratorsError == nil {
-								mockAdministrator.
-									EXPECT().
-									InsertAdministrators(
-										c.Request().Context(),
-										testCase.questionnaireID,
-										testCase.request.Administrators,
-									).
-									Return(testCase.InsertAdministratorsError)
-							}
-						}
-					}
+			var actualAuditEvents []model.AuditEvents
+			err := json.NewDecoder(rec.Body).Decode(&actualAuditEvents)
+			if err != nil {
+				t.Errorf("failed to decode response body: %v", err)
+			}
+			assert.Len(t, actualAuditEvents, len(testCase.expect.auditEvents), "length")
+			for i, auditEvent := range testCase.expect.auditEvents {
+				if i >= len(actualAuditEvents) {
+					break
 				}
+				assert.Equal(t, auditEvent.ID, actualAuditEvents[i].ID, "id")
+				assert.Equal(t, auditEvent.UserTraqid, actualAuditEvents[i].UserTraqid, "user")
+				assert.Equal(t, auditEvent.Action, actualAuditEvents[i].Action, "action")
+				assert.JSONEq(t, string(auditEvent.Diff), string(actualAuditEvents[i].Diff), "diff")
 			}
-
-			e.HTTPErrorHandler(questionnaire.EditQuestionnaire(c), c)
-
-			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
 		})
 	}
 }
 
-func TestDeleteQuestionnaire(t *testing.T) {
+func TestNewQuestionnaireAuditState(t *testing.T) {
+	t.Parallel()
+
+	resTimeLimit := time.Date(2022, 4, 1, 12, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	actual := newQuestionnaireAuditState(
+		"title",
+		"description",
+		null.TimeFrom(resTimeLimit.Add(500*time.Millisecond)),
+		null.TimeFrom(resTimeLimit.Add(-time.Hour+500*time.Millisecond)),
+		true,
+		"public",
+		false,
+		true,
+		null.IntFrom(1),
+		null.NewInt(0, false),
+		true,
+		[]string{"ryoha", "mazrean"},
+		[]string{"mazrean"},
+	)
+
+	assert.Equal(t, []string{"mazrean", "ryoha"}, actual.Targets, "targets are sorted")
+	assert.Equal(t, []string{"mazrean"}, actual.Administrators, "administrators")
+	assert.True(t, actual.IsAnonymous, "is_anonymous")
+	assert.Equal(t, null.IntFrom(1), actual.MaxResponsesPerUser, "max_responses_per_user")
+	assert.Equal(t, time.UTC, actual.ResTimeLimit.Time.Location(), "res_time_limit is UTC")
+	assert.True(t, resTimeLimit.Equal(actual.ResTimeLimit.Time), "res_time_limit is truncated")
+	assert.True(t, resTimeLimit.Add(-time.Hour).Equal(actual.ResStartAt.Time), "res_start_at is truncated")
+	assert.True(t, actual.IsClosed, "is_closed")
+	assert.True(t, actual.DisableReminders, "disable_reminders")
+
+	noLimit := newQuestionnaireAuditState("title", "description", null.NewTime(time.Time{}, false), null.NewTime(time.Time{}, false), false, "public", false, false, null.NewInt(0, false), null.NewInt(0, false), false, nil, nil)
+	assert.False(t, noLimit.ResTimeLimit.Valid, "no res_time_limit")
+	assert.Equal(t, []string{}, noLimit.Targets, "nil targets")
+}
+
+func TestCopyQuestionnaire(t *testing.T) {
 	t.Parallel()
 
 	ctrl := gomock.NewController(t)
@@ -1556,8 +3363,13 @@ func TestDeleteQuestionnaire(t *testing.T) {
 	mockOption := mock_model.NewMockIOption(ctrl)
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockGroup := mock_model.NewMockIGroup(ctrl)
 	mockTransaction := &model.MockTransaction{}
-	mockWebhook := mock_traq.NewMockIWebhook(ctrl)
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockOutbox := mock_model.NewMockIOutbox(ctrl)
+	mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	questionnaire := NewQuestionnaire(
 		mockQuestionnaire,
@@ -1567,69 +3379,145 @@ func TestDeleteQuestionnaire(t *testing.T) {
 		mockOption,
 		mockScaleLabel,
 		mockValidation,
+		mockQuestionCondition,
+		mockGroup,
 		mockTransaction,
-		mockWebhook,
+		mockAudit,
+		mockOutbox,
+		NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL}),
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
 
+	questions := []model.Questions{
+		{ID: 1, QuestionnaireID: 1, PageNum: 1, QuestionNum: 1, Type: "Text", Body: "名前", IsRequired: true},
+		{ID: 2, QuestionnaireID: 1, PageNum: 1, QuestionNum: 2, Type: "MultipleChoice", Body: "参加しますか"},
+		{ID: 3, QuestionnaireID: 1, PageNum: 2, QuestionNum: 1, Type: "LinearScale", Body: "満足度"},
+	}
+	options := []model.Options{
+		{ID: 1, QuestionID: 2, OptionNum: 1, Body: "はい"},
+		{ID: 2, QuestionID: 2, OptionNum: 2, Body: "いいえ"},
+	}
+	scaleLabel := model.ScaleLabels{QuestionID: 3, ScaleLabelLeft: "不満", ScaleLabelRight: "満足", ScaleMin: 1, ScaleMax: 5}
+	validation := model.Validations{QuestionID: 1, RegexPattern: "^.+$"}
+	conditions := []model.QuestionConditions{
+		{ID: 1, QuestionID: 2, ConditionType: "option", OptionBody: null.StringFrom("いいえ"), SkipToPage: 3},
+	}
+
 	type expect struct {
-		statusCode int
+		statusCode     int
+		title          string
+		isTemplate     bool
+		administrators []string
 	}
 	type test struct {
 		description               string
+		userID                    string
 		questionnaireID           int
-		DeleteQuestionnaireError  error
-		DeleteTargetsError        error
-		DeleteAdministratorsError error
+		newQuestionnaireID        int
+		request                   CopyQuestionnaireRequest
+		GetQuestionnaireInfoError error
+		InsertQuestionnaireError  error
+		executesCopy              bool
 		expect
 	}
 
 	testCases := []test{
 		{
-			description:               "エラーなしなので200",
-			questionnaireID:           1,
-			DeleteQuestionnaireError:  nil,
-			DeleteTargetsError:        nil,
-			DeleteAdministratorsError: nil,
+			description:        "エラーなしなので201",
+			userID:             "mazrean",
+			questionnaireID:    1,
+			newQuestionnaireID: 2,
+			request:            CopyQuestionnaireRequest{},
+			executesCopy:       true,
 			expect: expect{
-				statusCode: http.StatusOK,
+				statusCode:     http.StatusCreated,
+				title:          "第1回集会らん☆ぷろ募集アンケート",
+				administrators: []string{"mazrean"},
 			},
 		},
 		{
-			description:               "questionnaireIDが0でも200",
-			questionnaireID:           0,
-			DeleteQuestionnaireError:  nil,
-			DeleteTargetsError:        nil,
-			DeleteAdministratorsError: nil,
+			description:        "タイトルと回答期限を指定しても201",
+			userID:             "mazrean",
+			questionnaireID:    1,
+			newQuestionnaireID: 2,
+			request: CopyQuestionnaireRequest{
+				Title:        null.StringFrom("第2回集会らん☆ぷろ募集アンケート"),
+				ResTimeLimit: null.TimeFrom(time.Now().Add(48 * time.Hour)),
+			},
+			executesCopy: true,
 			expect: expect{
-				statusCode: http.StatusOK,
+				statusCode:     http.StatusCreated,
+				title:          "第2回集会らん☆ぷろ募集アンケート",
+				administrators: []string{"mazrean"},
 			},
 		},
 		{
-			description:               "DeleteQuestionnaireがエラーなので500",
-			questionnaireID:           1,
-			DeleteQuestionnaireError:  errors.New("error"),
-			DeleteTargetsError:        nil,
-			DeleteAdministratorsError: nil,
+			description:        "管理者以外がコピーすると管理者に加わって201",
+			userID:             "xxarupakaxx",
+			questionnaireID:    1,
+			newQuestionnaireID: 2,
+			request:            CopyQuestionnaireRequest{},
+			executesCopy:       true,
 			expect: expect{
-				statusCode: http.StatusInternalServerError,
+				statusCode:     http.StatusCreated,
+				title:          "第1回集会らん☆ぷろ募集アンケート",
+				administrators: []string{"mazrean", "xxarupakaxx"},
 			},
 		},
 		{
-			description:               "DeleteTargetsがエラーなので500",
-			questionnaireID:           1,
-			DeleteQuestionnaireError:  nil,
-			DeleteTargetsError:        errors.New("error"),
-			DeleteAdministratorsError: nil,
+			description:        "テンプレートとしてコピーするとtraQに告知せずに201",
+			userID:             "mazrean",
+			questionnaireID:    1,
+			newQuestionnaireID: 2,
+			request: CopyQuestionnaireRequest{
+				IsTemplate: true,
+			},
+			executesCopy: true,
 			expect: expect{
-				statusCode: http.StatusInternalServerError,
+				statusCode:     http.StatusCreated,
+				title:          "第1回集会らん☆ぷろ募集アンケート",
+				isTemplate:     true,
+				administrators: []string{"mazrean"},
 			},
 		},
 		{
-			description:               "DeleteAdministratorsがエラーなので500",
+			description:     "タイトルが長すぎるので400",
+			userID:          "mazrean",
+			questionnaireID: 1,
+			request: CopyQuestionnaireRequest{
+				Title: null.StringFrom(strings.Repeat("a", 51)),
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description:     "回答期限が過去なので400",
+			userID:          "mazrean",
+			questionnaireID: 1,
+			request: CopyQuestionnaireRequest{
+				ResTimeLimit: null.TimeFrom(time.Now().Add(-24 * time.Hour)),
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description:               "アンケートが存在しないので404",
+			userID:                    "mazrean",
 			questionnaireID:           1,
-			DeleteQuestionnaireError:  nil,
-			DeleteTargetsError:        nil,
-			DeleteAdministratorsError: errors.New("error"),
+			request:                   CopyQuestionnaireRequest{},
+			GetQuestionnaireInfoError: model.ErrRecordNotFound,
+			expect: expect{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description:              "InsertQuestionnaireがエラーなので500",
+			userID:                   "mazrean",
+			questionnaireID:          1,
+			request:                  CopyQuestionnaireRequest{},
+			InsertQuestionnaireError: errors.New("error"),
 			expect: expect{
 				statusCode: http.StatusInternalServerError,
 			},
@@ -1638,309 +3526,161 @@ func TestDeleteQuestionnaire(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.description, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			err := json.NewEncoder(buf).Encode(testCase.request)
+			if err != nil {
+				t.Errorf("failed to encode request: %v", err)
+			}
+
 			e := echo.New()
-			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/questionnaire/%d", testCase.questionnaireID), nil)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/questionnaires/%d/copy", testCase.questionnaireID), buf)
 			rec := httptest.NewRecorder()
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			c := e.NewContext(req, rec)
-			c.SetPath("/questionnaires/:questionnaire_id")
-			c.SetParamNames("questionnaire_id")
+			c.SetPath("/questionnaires/:questionnaireID/copy")
+			c.SetParamNames("questionnaireID")
 			c.SetParamValues(strconv.Itoa(testCase.questionnaireID))
 
 			c.Set(questionnaireIDKey, testCase.questionnaireID)
+			c.Set(userIDKey, testCase.userID)
 
-			mockQuestionnaire.
-				EXPECT().
-				DeleteQuestionnaire(
-					c.Request().Context(),
-					testCase.questionnaireID,
-				).
-				Return(testCase.DeleteQuestionnaireError)
+			if testCase.expect.statusCode != http.StatusBadRequest {
+				mockQuestionnaire.
+					EXPECT().
+					GetQuestionnaireInfo(gomock.Any(), testCase.questionnaireID).
+					Return(&model.Questionnaires{
+						ID:           testCase.questionnaireID,
+						Title:        "第1回集会らん☆ぷろ募集アンケート",
+						Description:  "第1回集会らん☆ぷろ参加者募集",
+						ResTimeLimit: null.TimeFrom(time.Now().Add(24 * time.Hour)),
+						ResSharedTo:  "public",
+					}, []string{"traP"}, []string{"mazrean"}, []string{}, testCase.GetQuestionnaireInfoError)
+			}
 
-			if testCase.DeleteQuestionnaireError == nil {
-				mockTarget.
+			if testCase.GetQuestionnaireInfoError == nil && testCase.expect.statusCode != http.StatusBadRequest {
+				mockQuestionnaire.
 					EXPECT().
-					DeleteTargets(
-						c.Request().Context(),
-						testCase.questionnaireID,
+					InsertQuestionnaire(
+						gomock.Any(),
+						gomock.Any(),
+						"第1回集会らん☆ぷろ参加者募集",
+						gomock.Any(),
+						"public",
+						testCase.request.IsTemplate,
 					).
-					Return(testCase.DeleteTargetsError)
+					Return(testCase.newQuestionnaireID, testCase.InsertQuestionnaireError)
+			}
 
-				if testCase.DeleteTargetsError == nil {
-					mockAdministrator.
+			if testCase.executesCopy {
+				mockTarget.
+					EXPECT().
+					InsertTargets(gomock.Any(), testCase.newQuestionnaireID, []string{"traP"}).
+					Return(nil)
+				mockAdministrator.
+					EXPECT().
+					InsertAdministrators(gomock.Any(), testCase.newQuestionnaireID, testCase.expect.administrators).
+					Return(nil)
+				// アンケートに設定されたメッセージのテンプレートも複製する
+				mockMessageTemplate.
+					EXPECT().
+					GetQuestionnaireMessageTemplates(gomock.Any(), testCase.questionnaireID).
+					Return([]model.QuestionnaireMessageTemplates{
+						{QuestionnaireID: testCase.questionnaireID, Kind: model.MessageTemplateKindAnnouncement, Template: "{{.Title}}"},
+					}, nil)
+				mockMessageTemplate.
+					EXPECT().
+					UpsertQuestionnaireMessageTemplate(gomock.Any(), testCase.newQuestionnaireID, model.MessageTemplateKindAnnouncement, "{{.Title}}").
+					Return(nil)
+
+				mockQuestion.
+					EXPECT().
+					GetQuestions(gomock.Any(), testCase.questionnaireID).
+					Return(questions, nil)
+				mockOption.
+					EXPECT().
+					GetOptions(gomock.Any(), []int{2}).
+					Return(options, nil)
+				mockScaleLabel.
+					EXPECT().
+					GetScaleLabels(gomock.Any(), []int{3}).
+					Return([]model.ScaleLabels{scaleLabel}, nil)
+				mockValidation.
+					EXPECT().
+					GetValidations(gomock.Any(), []int{1}).
+					Return([]model.Validations{validation}, nil)
+				mockQuestionCondition.
+					EXPECT().
+					GetQuestionConditions(gomock.Any(), []int{1, 2, 3}).
+					Return(conditions, nil)
+
+				for i, question := range questions {
+					mockQuestion.
 						EXPECT().
-						DeleteAdministrators(
-							c.Request().Context(),
-							testCase.questionnaireID,
-						).
-						Return(testCase.DeleteAdministratorsError)
+						InsertQuestion(gomock.Any(), testCase.newQuestionnaireID, question.PageNum, question.QuestionNum, question.Type, question.Body, question.IsRequired).
+						Return(10+i+1, nil)
+				}
+				for _, option := range options {
+					mockOption.
+						EXPECT().
+						InsertOption(gomock.Any(), 12, option.OptionNum, option.Body).
+						Return(nil)
+				}
+				mockScaleLabel.
+					EXPECT().
+					InsertScaleLabel(gomock.Any(), 13, scaleLabel).
+					Return(nil)
+				mockValidation.
+					EXPECT().
+					InsertValidation(gomock.Any(), 11, validation).
+					Return(nil)
+				mockQuestionCondition.
+					EXPECT().
+					InsertQuestionConditions(gomock.Any(), 12, conditions).
+					Return(nil)
+
+				if !testCase.request.IsTemplate {
+					mockQuestionnaire.
+						EXPECT().
+						UpdateQuestionnaireAnnounced(gomock.Any(), gomock.Any()).
+						Return(nil)
+					mockMessageTemplate.
+						EXPECT().
+						GetQuestionnaireMessageTemplate(gomock.Any(), testCase.newQuestionnaireID, model.MessageTemplateKindAnnouncement).
+						Return("{{.Title}}", nil)
+					mockOutbox.
+						EXPECT().
+						InsertOutboxMessage(gomock.Any(), testCase.expect.title).
+						Return(1, nil)
 				}
 			}
 
-			e.HTTPErrorHandler(questionnaire.DeleteQuestionnaire(c), c)
+			e.HTTPErrorHandler(questionnaire.CopyQuestionnaire(c), c)
 
 			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
-		})
-	}
-}
-
-func TestCreateQuestionnaireMessage(t *testing.T) {
-	t.Parallel()
-
-	type args struct {
-		questionnaireID int
-		title           string
-		description     string
-		administrators  []string
-		resTimeLimit    null.Time
-		targets         []string
-	}
-	type expect struct {
-		message string
-	}
-	type test struct {
-		description string
-		args
-		expect
-	}
 
-	tm, err := time.ParseInLocation("2006/01/02 15:04", "2021/10/01 09:06", time.Local)
-	if err != nil {
-		t.Errorf("failed to parse time: %v", err)
-	}
+			if testCase.expect.statusCode == http.StatusCreated {
+				var questionnaire map[string]interface{}
+				err := json.NewDecoder(rec.Body).Decode(&questionnaire)
+				if err != nil {
+					t.Errorf("failed to decode response body: %v", err)
+				}
 
-	testCases := []test{
-		{
-			description: "通常の引数なので問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			description: "questionnaireIDが0でも問題なし",
-			args: args{
-				questionnaireID: 0,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/0)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/0`,
-			},
-		},
-		{
-			// 実際には発生しないけど念の為
-			description: "titleが空文字でも問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "",
-				description:     "description",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			description: "説明が空文字でも問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			description: "administrator複数人でも問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{"administrator1", "administrator2"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1,administrator2
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			// 実際には発生しないけど念の為
-			description: "administratorがいなくても問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			description: "回答期限なしでも問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.NewTime(time.Time{}, false),
-				targets:         []string{"target1"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-description
-#### 回答期限
-なし
-#### 対象者
-@target1
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			description: "対象者が複数人でも問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{"target1", "target2"},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-@target1 @target2
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-		{
-			description: "対象者がいなくても問題なし",
-			args: args{
-				questionnaireID: 1,
-				title:           "title",
-				description:     "description",
-				administrators:  []string{"administrator1"},
-				resTimeLimit:    null.TimeFrom(tm),
-				targets:         []string{},
-			},
-			expect: expect{
-				message: `### アンケート『[title](https://anke-to.trap.jp/questionnaires/1)』が作成されました
-#### 管理者
-administrator1
-#### 説明
-description
-#### 回答期限
-2021/10/01 09:06
-#### 対象者
-なし
-#### 回答リンク
-https://anke-to.trap.jp/responses/new/1`,
-			},
-		},
-	}
+				assert.Equal(t, float64(testCase.newQuestionnaireID), questionnaire["questionnaireID"], "questionnaireID")
+				assert.Equal(t, testCase.expect.title, questionnaire["title"], "title")
+				assert.Equal(t, testCase.expect.isTemplate, questionnaire["is_template"], "isTemplate")
+				assert.ElementsMatch(t, []string{"traP"}, questionnaire["targets"], "targets")
+				assert.ElementsMatch(t, testCase.expect.administrators, questionnaire["administrators"], "administrators")
 
-	for _, testCase := range testCases {
-		t.Run(testCase.description, func(t *testing.T) {
-			message := createQuestionnaireMessage(
-				testCase.args.questionnaireID,
-				testCase.args.title,
-				testCase.args.description,
-				testCase.args.administrators,
-				testCase.args.resTimeLimit,
-				testCase.args.targets,
-			)
-
-			assert.Equal(t, testCase.expect.message, message)
+				strResTimeLimit, ok := questionnaire["res_time_limit"].(string)
+				assert.True(t, ok, "res_time_limit convert")
+				resTimeLimit, err := time.Parse(time.RFC3339, strResTimeLimit)
+				assert.NoError(t, err, "res_time_limit parse")
+				if testCase.request.ResTimeLimit.Valid {
+					assert.WithinDuration(t, testCase.request.ResTimeLimit.Time, resTimeLimit, 2*time.Second, "resTimeLimit")
+				} else {
+					assert.WithinDuration(t, time.Now().Add(24*time.Hour), resTimeLimit, 2*time.Second, "resTimeLimit")
+				}
+			}
 		})
 	}
 }
@@ -1,11 +1,15 @@
 package router
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"regexp"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
 
 	"github.com/traPtitech/anke-to/model"
 )
@@ -16,43 +20,99 @@ type Question struct {
 	model.IQuestion
 	model.IOption
 	model.IScaleLabel
+	model.IQuestionCondition
+	model.IQuestionnaire
+	model.ITransaction
+	model.IAudit
 }
 
 // NewQuestion Questionのコンストラクタ
-func NewQuestion(validation model.IValidation, question model.IQuestion, option model.IOption, scaleLabel model.IScaleLabel) *Question {
+func NewQuestion(validation model.IValidation, question model.IQuestion, option model.IOption, scaleLabel model.IScaleLabel, questionCondition model.IQuestionCondition, questionnaire model.IQuestionnaire, transaction model.ITransaction, audit model.IAudit) *Question {
 	return &Question{
-		IValidation: validation,
-		IQuestion:   question,
-		IOption:     option,
-		IScaleLabel: scaleLabel,
+		IValidation:        validation,
+		IQuestion:          question,
+		IOption:            option,
+		IScaleLabel:        scaleLabel,
+		IQuestionCondition: questionCondition,
+		IQuestionnaire:     questionnaire,
+		ITransaction:       transaction,
+		IAudit:             audit,
 	}
 }
 
 type PostAndEditQuestionRequest struct {
-	QuestionnaireID int      `json:"questionnaireID" validate:"min=0"`
-	QuestionType    string   `json:"question_type" validate:"required,oneof=Text TextArea Number MultipleChoice Checkbox LinearScale"`
-	QuestionNum     int      `json:"question_num" validate:"min=0"`
-	PageNum         int      `json:"page_num" validate:"min=0"`
-	Body            string   `json:"body" validate:"required"`
-	IsRequired      bool     `json:"is_required"`
-	Options         []string `json:"options" validate:"required_if=QuestionType Checkbox,required_if=QuestionType MultipleChoice,dive,max=50"`
-	ScaleLabelRight string   `json:"scale_label_right" validate:"max=50"`
-	ScaleLabelLeft  string   `json:"scale_label_left" validate:"max=50"`
-	ScaleMin        int      `json:"scale_min"`
-	ScaleMax        int      `json:"scale_max" validate:"gtecsfield=ScaleMin"`
-	RegexPattern    string   `json:"regex_pattern"`
-	MinBound        string   `json:"min_bound" validate:"omitempty,number"`
-	MaxBound        string   `json:"max_bound" validate:"omitempty,number"`
+	QuestionnaireID int                        `json:"questionnaireID" validate:"min=0"`
+	QuestionType    string                     `json:"question_type" validate:"required"`
+	QuestionNum     int                        `json:"question_num" validate:"min=0"`
+	PageNum         int                        `json:"page_num" validate:"min=0"`
+	Body            string                     `json:"body" validate:"required"`
+	IsRequired      bool                       `json:"is_required"`
+	Options         []string                   `json:"options" validate:"required_if=QuestionType Checkbox,required_if=QuestionType MultipleChoice,dive,max=50"`
+	ScaleLabelRight string                     `json:"scale_label_right" validate:"max=50"`
+	ScaleLabelLeft  string                     `json:"scale_label_left" validate:"max=50"`
+	ScaleMin        int                        `json:"scale_min"`
+	ScaleMax        int                        `json:"scale_max" validate:"gtecsfield=ScaleMin"`
+	RegexPattern    string                     `json:"regex_pattern"`
+	MinBound        string                     `json:"min_bound" validate:"omitempty,number|datetime=2006-01-02|datetime=15:04|datetime=15:04:05|datetime=2006-01-02T15:04:05Z07:00|datetime=2006-01-02T15:04Z07:00|datetime=2006-01-02T15:04:05|datetime=2006-01-02T15:04"`
+	MaxBound        string                     `json:"max_bound" validate:"omitempty,number|datetime=2006-01-02|datetime=15:04|datetime=15:04:05|datetime=2006-01-02T15:04:05Z07:00|datetime=2006-01-02T15:04Z07:00|datetime=2006-01-02T15:04:05|datetime=2006-01-02T15:04"`
+	MinSelections   null.Int                   `json:"min_selections"`
+	MaxSelections   null.Int                   `json:"max_selections"`
+	Conditions      []model.QuestionConditions `json:"conditions" validate:"dive"`
+}
+
+// checkQuestionValidation 質問の種類に応じて，回答の制限の指定が有効か確認する
+func checkQuestionValidation(validation model.IValidation, questionType model.QuestionTypeInfo, req PostAndEditQuestionRequest) error {
+	if questionType.HasRegexPattern {
+		// 正規表現のチェック
+		if _, err := regexp.Compile(req.RegexPattern); err != nil {
+			return fmt.Errorf("invalid regex pattern: %w", err)
+		}
+	}
+
+	if questionType.HasBounds {
+		if questionType.IsDateTime {
+			// 日時として解釈できるか，min<=maxになってるか
+			if err := validation.CheckDateTimeValid(req.QuestionType, req.MinBound, req.MaxBound); err != nil {
+				return fmt.Errorf("invalid datetime: %w", err)
+			}
+		} else {
+			// 数字か，min<=maxになってるか
+			if err := validation.CheckNumberValid(req.MinBound, req.MaxBound); err != nil {
+				return fmt.Errorf("invalid number: %w", err)
+			}
+		}
+	}
+
+	if questionType.MultipleSelection {
+		// 選択数の制限が0以上で，min<=maxになってるか
+		if err := validation.CheckSelectionValid(req.MinSelections, req.MaxSelections); err != nil {
+			return fmt.Errorf("invalid selections: %w", err)
+		}
+	}
+
+	return nil
 }
 
 // EditQuestion PATCH /questions/:id
 func (q *Question) EditQuestion(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
 	questionID, err := getQuestionID(c)
 	if err != nil {
 		c.Logger().Errorf("failed to get question id: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get questionID: %w", err))
 	}
 
+	ifMatch, err := getIfMatch(c)
+	if err != nil {
+		c.Logger().Infof("invalid If-Match: %+v", err)
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid If-Match header")
+	}
+
 	req := PostAndEditQuestionRequest{}
 
 	if err := c.Bind(&req); err != nil {
@@ -60,54 +120,125 @@ func (q *Question) EditQuestion(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
-	switch req.QuestionType {
-	case "Text":
-		//正規表現のチェック
-		if _, err := regexp.Compile(req.RegexPattern); err != nil {
-			c.Logger().Infof("invalid regex pattern: %+v", err)
-			return echo.NewHTTPError(http.StatusBadRequest)
-		}
-	case "Number":
-		//数字か，min<=maxになってるか
-		if err := q.CheckNumberValid(req.MinBound, req.MaxBound); err != nil {
-			c.Logger().Info("invalid number: %+v", err)
-			return echo.NewHTTPError(http.StatusBadRequest, err)
-		}
+	questionType, ok := model.GetQuestionTypeInfo(req.QuestionType)
+	if !ok {
+		c.Logger().Infof("unknown question type: %s", req.QuestionType)
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown question_type")
 	}
 
-	err = q.UpdateQuestion(c.Request().Context(), req.QuestionnaireID, req.PageNum, req.QuestionNum, req.QuestionType, req.Body, req.IsRequired, questionID)
-	if err != nil {
-		c.Logger().Errorf("failed to update question: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	if err := checkQuestionValidation(q.IValidation, questionType, req); err != nil {
+		c.Logger().Infof("invalid question: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	switch req.QuestionType {
-	case "MultipleChoice", "Checkbox", "Dropdown":
-		if err := q.UpdateOptions(c.Request().Context(), req.Options, questionID); err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
-			c.Logger().Errorf("failed to update options: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+	if err := checkQuestionConditions(req.QuestionType, req.PageNum, req.Options, req.Conditions); err != nil {
+		c.Logger().Infof("invalid conditions: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		before, err := q.getQuestionAuditState(ctx, questionID)
+		if err != nil {
+			c.Logger().Errorf("failed to get question audit state: %+v", err)
+			return err
+		}
+
+		// 質問を他のアンケートに移すことはできない
+		if req.QuestionnaireID != before.QuestionnaireID {
+			c.Logger().Infof("questionnaireID does not match: %d, %d", req.QuestionnaireID, before.QuestionnaireID)
+			return echo.NewHTTPError(http.StatusBadRequest, "questionnaireID does not match the question")
+		}
+
+		// 質問の変更もアンケートの版を進める
+		// If-Matchの版から変更されていれば更新しない
+		_, err = q.UpdateQuestionnaireModifiedAt(ctx, before.QuestionnaireID, ifMatch)
+		if err != nil {
+			c.Logger().Infof("failed to update questionnaire modified_at: %+v", err)
+			return err
+		}
+
+		err = q.UpdateQuestion(ctx, before.QuestionnaireID, req.PageNum, req.QuestionNum, req.QuestionType, req.Body, req.IsRequired, questionID)
+		if err != nil {
+			c.Logger().Errorf("failed to update question: %+v", err)
+			return err
+		}
+
+		switch {
+		case questionType.HasOptions:
+			if err := q.UpdateOptions(ctx, req.Options, questionID); err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
+				c.Logger().Errorf("failed to update options: %+v", err)
+				return err
+			}
+		case questionType.HasScaleLabel:
+			if err := q.UpdateScaleLabel(ctx, questionID,
+				model.ScaleLabels{
+					ScaleLabelLeft:  req.ScaleLabelLeft,
+					ScaleLabelRight: req.ScaleLabelRight,
+					ScaleMax:        req.ScaleMax,
+					ScaleMin:        req.ScaleMin,
+				}); err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
+				c.Logger().Errorf("failed to update scale label: %+v", err)
+				return err
+			}
+		case questionType.HasRegexPattern || questionType.HasBounds:
+			if err := q.UpdateValidation(ctx, questionID,
+				model.Validations{
+					RegexPattern: req.RegexPattern,
+					MinBound:     req.MinBound,
+					MaxBound:     req.MaxBound,
+				}); err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
+				c.Logger().Errorf("failed to update validation: %+v", err)
+				return err
+			}
+		}
+
+		if questionType.MultipleSelection {
+			// 選択数の制限のない既存のCheckboxの質問にはvalidationがないため、置き換える
+			if err := q.DeleteValidation(ctx, questionID); err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
+				c.Logger().Errorf("failed to delete validation: %+v", err)
+				return err
+			}
+			if err := q.InsertValidation(ctx, questionID,
+				model.Validations{
+					MinSelections: req.MinSelections,
+					MaxSelections: req.MaxSelections,
+				}); err != nil {
+				c.Logger().Errorf("failed to insert validation: %+v", err)
+				return err
+			}
+		}
+
+		if err := q.UpdateQuestionConditions(ctx, questionID, req.Conditions); err != nil {
+			c.Logger().Errorf("failed to update question conditions: %+v", err)
+			return err
+		}
+
+		err = q.InsertAuditEvent(ctx, userID, before.QuestionnaireID, model.AuditTargetQuestion, questionID, model.AuditActionUpdate, before, newQuestionAuditState(req))
+		if err != nil {
+			c.Logger().Errorf("failed to insert audit event: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError
 		}
-	case "LinearScale":
-		if err := q.UpdateScaleLabel(c.Request().Context(), questionID,
-			model.ScaleLabels{
-				ScaleLabelLeft:  req.ScaleLabelLeft,
-				ScaleLabelRight: req.ScaleLabelRight,
-				ScaleMax:        req.ScaleMax,
-				ScaleMin:        req.ScaleMin,
-			}); err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
-			c.Logger().Errorf("failed to update scale label: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
 		}
-	case "Text", "Number":
-		if err := q.UpdateValidation(c.Request().Context(), questionID,
-			model.Validations{
-				RegexPattern: req.RegexPattern,
-				MinBound:     req.MinBound,
-				MaxBound:     req.MaxBound,
-			}); err != nil && !errors.Is(err, model.ErrNoRecordUpdated) {
-			c.Logger().Errorf("failed to update validation: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		if errors.Is(err, model.ErrConflict) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "questionnaire has been modified")
 		}
+		if errors.Is(err, model.ErrInvalidQuestionType) {
+			c.Logger().Infof("inactive question type: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "question_type is not available")
+		}
+
+		c.Logger().Errorf("failed to edit question: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
 	return c.NoContent(http.StatusOK)
@@ -115,31 +246,175 @@ func (q *Question) EditQuestion(c echo.Context) error {
 
 // DeleteQuestion DELETE /questions/:id
 func (q *Question) DeleteQuestion(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
 	questionID, err := getQuestionID(c)
 	if err != nil {
 		c.Logger().Errorf("failed to get question id: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get questionID: %w", err))
 	}
 
-	if err := q.IQuestion.DeleteQuestion(c.Request().Context(), questionID); err != nil {
-		c.Logger().Errorf("failed to delete question: %+v", err)
+	err = q.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		before, err := q.getQuestionAuditState(ctx, questionID)
+		if err != nil {
+			c.Logger().Errorf("failed to get question audit state: %+v", err)
+			return err
+		}
+
+		// 質問の削除もアンケートの版を進める
+		_, err = q.UpdateQuestionnaireModifiedAt(ctx, before.QuestionnaireID, null.NewTime(time.Time{}, false))
+		if err != nil {
+			c.Logger().Errorf("failed to update questionnaire modified_at: %+v", err)
+			return err
+		}
+
+		if err := q.IQuestion.DeleteQuestion(ctx, questionID); err != nil {
+			c.Logger().Errorf("failed to delete question: %+v", err)
+			return err
+		}
+
+		if err := q.DeleteOptions(ctx, questionID); err != nil {
+			c.Logger().Errorf("failed to delete options: %+v", err)
+			return err
+		}
+
+		if err := q.DeleteScaleLabel(ctx, questionID); err != nil {
+			c.Logger().Errorf("failed to delete scale label: %+v", err)
+			return err
+		}
+
+		if err := q.DeleteValidation(ctx, questionID); err != nil {
+			c.Logger().Errorf("failed to delete validation: %+v", err)
+			return err
+		}
+
+		if err := q.DeleteQuestionConditions(ctx, questionID); err != nil {
+			c.Logger().Errorf("failed to delete question conditions: %+v", err)
+			return err
+		}
+
+		err = q.InsertAuditEvent(ctx, userID, before.QuestionnaireID, model.AuditTargetQuestion, questionID, model.AuditActionDelete, before, nil)
+		if err != nil {
+			c.Logger().Errorf("failed to insert audit event: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	if err := q.DeleteOptions(c.Request().Context(), questionID); err != nil {
-		c.Logger().Errorf("failed to delete options: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	return c.NoContent(http.StatusOK)
+}
+
+// newQuestionAuditState 監査ログに記録する質問の状態を作る
+// 質問の種類に関係のない項目は保存されないため、空にする
+func newQuestionAuditState(req PostAndEditQuestionRequest) PostAndEditQuestionRequest {
+	questionType, _ := model.GetQuestionTypeInfo(req.QuestionType)
+
+	state := PostAndEditQuestionRequest{
+		QuestionnaireID: req.QuestionnaireID,
+		QuestionType:    req.QuestionType,
+		QuestionNum:     req.QuestionNum,
+		PageNum:         req.PageNum,
+		Body:            req.Body,
+		IsRequired:      req.IsRequired,
+		Options:         []string{},
+		Conditions:      []model.QuestionConditions{},
+	}
+	if questionType.HasOptions && req.Options != nil {
+		state.Options = req.Options
+	}
+	if questionType.HasScaleLabel {
+		state.ScaleLabelRight = req.ScaleLabelRight
+		state.ScaleLabelLeft = req.ScaleLabelLeft
+		state.ScaleMin = req.ScaleMin
+		state.ScaleMax = req.ScaleMax
+	}
+	if questionType.HasRegexPattern {
+		state.RegexPattern = req.RegexPattern
+	}
+	if questionType.HasBounds {
+		state.MinBound = req.MinBound
+		state.MaxBound = req.MaxBound
+	}
+	if questionType.MultipleSelection {
+		state.MinSelections = req.MinSelections
+		state.MaxSelections = req.MaxSelections
+	}
+	if req.Conditions != nil {
+		state.Conditions = req.Conditions
 	}
 
-	if err := q.DeleteScaleLabel(c.Request().Context(), questionID); err != nil {
-		c.Logger().Errorf("failed to delete scale label: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	return state
+}
+
+// getQuestionAuditState 監査ログに記録する質問の現在の状態を取得する
+func (q *Question) getQuestionAuditState(ctx context.Context, questionID int) (PostAndEditQuestionRequest, error) {
+	question, err := q.GetQuestion(ctx, questionID)
+	if err != nil {
+		return PostAndEditQuestionRequest{}, fmt.Errorf("failed to get question: %w", err)
 	}
 
-	if err := q.DeleteValidation(c.Request().Context(), questionID); err != nil {
-		c.Logger().Errorf("failed to delete validation: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	state := PostAndEditQuestionRequest{
+		QuestionnaireID: question.QuestionnaireID,
+		QuestionType:    question.Type,
+		QuestionNum:     question.QuestionNum,
+		PageNum:         question.PageNum,
+		Body:            question.Body,
+		IsRequired:      question.IsRequired,
 	}
 
-	return c.NoContent(http.StatusOK)
+	questionType, _ := model.GetQuestionTypeInfo(question.Type)
+	if questionType.HasOptions {
+		options, err := q.GetOptions(ctx, []int{questionID})
+		if err != nil {
+			return PostAndEditQuestionRequest{}, fmt.Errorf("failed to get options: %w", err)
+		}
+		for _, option := range options {
+			state.Options = append(state.Options, option.Body)
+		}
+	}
+	if questionType.HasScaleLabel {
+		scaleLabels, err := q.GetScaleLabels(ctx, []int{questionID})
+		if err != nil {
+			return PostAndEditQuestionRequest{}, fmt.Errorf("failed to get scale labels: %w", err)
+		}
+		if len(scaleLabels) != 0 {
+			state.ScaleLabelRight = scaleLabels[0].ScaleLabelRight
+			state.ScaleLabelLeft = scaleLabels[0].ScaleLabelLeft
+			state.ScaleMin = scaleLabels[0].ScaleMin
+			state.ScaleMax = scaleLabels[0].ScaleMax
+		}
+	}
+	if questionType.HasValidation() {
+		validations, err := q.GetValidations(ctx, []int{questionID})
+		if err != nil {
+			return PostAndEditQuestionRequest{}, fmt.Errorf("failed to get validations: %w", err)
+		}
+		if len(validations) != 0 {
+			state.RegexPattern = validations[0].RegexPattern
+			state.MinBound = validations[0].MinBound
+			state.MaxBound = validations[0].MaxBound
+			state.MinSelections = validations[0].MinSelections
+			state.MaxSelections = validations[0].MaxSelections
+		}
+	}
+
+	conditions, err := q.GetQuestionConditions(ctx, []int{questionID})
+	if err != nil {
+		return PostAndEditQuestionRequest{}, fmt.Errorf("failed to get question conditions: %w", err)
+	}
+	state.Conditions = conditions
+
+	return newQuestionAuditState(state), nil
 }
@@ -1,10 +1,21 @@
 package router
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+	"gopkg.in/guregu/null.v4"
 )
 
 func TestPostQuestionValidate(t *testing.T) {
@@ -1430,3 +1441,124 @@ func TestPostQuestionValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestEditQuestion(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidation := mock_model.NewMockIValidation(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+
+	question := NewQuestion(
+		mockValidation,
+		mockQuestion,
+		mockOption,
+		mockScaleLabel,
+		mockQuestionCondition,
+		mockQuestionnaire,
+		mockTransaction,
+		mockAudit,
+	)
+
+	questionID := 1
+	questionnaireID := 1
+	otherQuestionnaireID := 2
+	modifiedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	mockQuestion.
+		EXPECT().
+		GetQuestion(gomock.Any(), questionID).
+		Return(&model.Questions{ID: questionID, QuestionnaireID: questionnaireID, PageNum: 1, QuestionNum: 1, Type: "Text", Body: "発表タイトル"}, nil).
+		AnyTimes()
+	mockValidation.
+		EXPECT().
+		GetValidations(gomock.Any(), []int{questionID}).
+		Return([]model.Validations{}, nil).
+		AnyTimes()
+	mockQuestionCondition.
+		EXPECT().
+		GetQuestionConditions(gomock.Any(), []int{questionID}).
+		Return([]model.QuestionConditions{}, nil).
+		AnyTimes()
+
+	type expect struct {
+		statusCode int
+	}
+	type test struct {
+		description                   string
+		request                       PostAndEditQuestionRequest
+		UpdateQuestionnaireModifiedAt bool
+		expect
+	}
+
+	testCases := []test{
+		{
+			description: "保存されているアンケートと異なるquestionnaireIDなので400",
+			request: PostAndEditQuestionRequest{
+				QuestionnaireID: otherQuestionnaireID,
+				QuestionType:    "Text",
+				QuestionNum:     1,
+				PageNum:         1,
+				Body:            "発表タイトル",
+			},
+			expect: expect{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "保存されているアンケートの版がIf-Matchから変更されているので412",
+			request: PostAndEditQuestionRequest{
+				QuestionnaireID: questionnaireID,
+				QuestionType:    "Text",
+				QuestionNum:     1,
+				PageNum:         1,
+				Body:            "発表タイトル",
+			},
+			UpdateQuestionnaireModifiedAt: true,
+			expect: expect{
+				statusCode: http.StatusPreconditionFailed,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			err := json.NewEncoder(buf).Encode(testCase.request)
+			if err != nil {
+				t.Errorf("failed to encode request: %v", err)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/questions/%d", questionID), buf)
+			rec := httptest.NewRecorder()
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("If-Match", formatETag(modifiedAt))
+			c := e.NewContext(req, rec)
+
+			c.Set(userIDKey, "mazrean")
+			c.Set(questionIDKey, questionID)
+			c.Set(validatorKey, validator.New())
+
+			// If-Matchはリクエストではなく保存されている質問のアンケートの版と比べる
+			if testCase.UpdateQuestionnaireModifiedAt {
+				mockQuestionnaire.
+					EXPECT().
+					UpdateQuestionnaireModifiedAt(gomock.Any(), questionnaireID, null.TimeFrom(modifiedAt)).
+					Return(time.Time{}, model.ErrConflict)
+			}
+
+			e.HTTPErrorHandler(question.EditQuestion(c), c)
+
+			assert.Equal(t, testCase.expect.statusCode, rec.Code, "status code")
+		})
+	}
+}
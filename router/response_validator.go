@@ -0,0 +1,170 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/traPtitech/anke-to/model"
+	"gopkg.in/guregu/null.v4"
+)
+
+// ResponseError 質問ごとの回答の誤り
+type ResponseError struct {
+	QuestionID int    `json:"questionID"`
+	Message    string `json:"message"`
+}
+
+// ResponseErrors 回答の誤りの一覧(400のレスポンスボディ)
+type ResponseErrors struct {
+	Message string          `json:"message"`
+	Errors  []ResponseError `json:"errors"`
+}
+
+// checkResponseBody 回答がアンケートの質問と選択肢に合っているかの確認
+// 他のアンケートの質問への回答、同じ質問への複数の回答、質問の種類の不一致、存在しない選択肢、単一選択の質問での複数選択、解釈できない日時を誤りとする
+func checkResponseBody(questions []model.Questions, options []model.Options, body []model.ResponseBody) []ResponseError {
+	questionMap := make(map[int]model.Questions, len(questions))
+	for _, question := range questions {
+		questionMap[question.ID] = question
+	}
+
+	optionMap := make(map[int]map[string]struct{}, len(questions))
+	for _, option := range options {
+		if _, ok := optionMap[option.QuestionID]; !ok {
+			optionMap[option.QuestionID] = map[string]struct{}{}
+		}
+		optionMap[option.QuestionID][option.Body] = struct{}{}
+	}
+
+	responseErrors := []ResponseError{}
+	answeredCounts := make(map[int]int, len(body))
+	for _, responseBody := range body {
+		// 同じ質問への回答が複数あると、単一選択や選択数の制限を回答を分けてすり抜けられるため誤りとする
+		answeredCounts[responseBody.QuestionID]++
+		if answeredCounts[responseBody.QuestionID] > 1 {
+			if answeredCounts[responseBody.QuestionID] == 2 {
+				responseErrors = append(responseErrors, ResponseError{
+					QuestionID: responseBody.QuestionID,
+					Message:    "this question is answered more than once",
+				})
+			}
+			continue
+		}
+
+		question, ok := questionMap[responseBody.QuestionID]
+		if !ok {
+			responseErrors = append(responseErrors, ResponseError{
+				QuestionID: responseBody.QuestionID,
+				Message:    "this question does not belong to the questionnaire",
+			})
+			continue
+		}
+
+		if responseBody.QuestionType != question.Type {
+			responseErrors = append(responseErrors, ResponseError{
+				QuestionID: responseBody.QuestionID,
+				Message:    fmt.Sprintf("question_type must be %s", question.Type),
+			})
+			continue
+		}
+
+		questionType, _ := model.GetQuestionTypeInfo(question.Type)
+		switch {
+		case questionType.HasOptions:
+			chosenNum := 0
+			for _, option := range responseBody.OptionResponse {
+				if option == "" {
+					continue
+				}
+				chosenNum++
+
+				if _, ok := optionMap[question.ID][option]; !ok {
+					responseErrors = append(responseErrors, ResponseError{
+						QuestionID: responseBody.QuestionID,
+						Message:    fmt.Sprintf("unknown option: %s", option),
+					})
+				}
+			}
+
+			if !questionType.MultipleSelection && chosenNum > 1 {
+				responseErrors = append(responseErrors, ResponseError{
+					QuestionID: responseBody.QuestionID,
+					Message:    "only one option can be chosen",
+				})
+			}
+		case questionType.IsDateTime:
+			if !responseBody.Body.Valid || strings.TrimSpace(responseBody.Body.String) == "" {
+				continue
+			}
+
+			if _, err := model.ParseDateTimeResponse(question.Type, responseBody.Body.String); err != nil {
+				responseErrors = append(responseErrors, ResponseError{
+					QuestionID: responseBody.QuestionID,
+					Message:    fmt.Sprintf("invalid %s: %s", strings.ToLower(question.Type), responseBody.Body.String),
+				})
+			}
+		}
+	}
+
+	return responseErrors
+}
+
+// checkRequiredResponses 回答必須の質問に空でない回答があるかの確認
+func checkRequiredResponses(questions []model.Questions, body []model.ResponseBody) []ResponseError {
+	bodyMap := make(map[int]model.ResponseBody, len(body))
+	for _, responseBody := range body {
+		bodyMap[responseBody.QuestionID] = responseBody
+	}
+
+	responseErrors := []ResponseError{}
+	for _, question := range questions {
+		if !question.IsRequired {
+			continue
+		}
+
+		responseBody, ok := bodyMap[question.ID]
+		if ok && isAnswered(question.Type, responseBody) {
+			continue
+		}
+
+		responseErrors = append(responseErrors, ResponseError{
+			QuestionID: question.ID,
+			Message:    "this question is required",
+		})
+	}
+
+	return responseErrors
+}
+
+func isAnswered(questionType string, responseBody model.ResponseBody) bool {
+	if info, _ := model.GetQuestionTypeInfo(questionType); info.HasOptions {
+		for _, option := range responseBody.OptionResponse {
+			if option != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	return responseBody.Body.Valid && strings.TrimSpace(responseBody.Body.String) != ""
+}
+
+// normalizeResponseBody Date,Time,DateTimeの回答を正規化した形式にそろえる
+// checkResponseBodyで解釈できることを確認した後に呼ぶ
+func normalizeResponseBody(body []model.ResponseBody) []model.ResponseBody {
+	for i, responseBody := range body {
+		if questionType, _ := model.GetQuestionTypeInfo(responseBody.QuestionType); questionType.IsDateTime {
+			if !responseBody.Body.Valid || strings.TrimSpace(responseBody.Body.String) == "" {
+				continue
+			}
+
+			value, err := model.NormalizeDateTimeResponse(responseBody.QuestionType, responseBody.Body.String)
+			if err != nil {
+				continue
+			}
+			body[i].Body = null.StringFrom(value)
+		}
+	}
+
+	return body
+}
@@ -0,0 +1,224 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestCheckResponseBody(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	questions := []model.Questions{
+		{ID: 1, Type: "Text"},
+		{ID: 2, Type: "MultipleChoice"},
+		{ID: 3, Type: "Checkbox"},
+		{ID: 4, Type: "Date"},
+		{ID: 5, Type: "DateTime"},
+	}
+	options := []model.Options{
+		{QuestionID: 2, OptionNum: 1, Body: "a"},
+		{QuestionID: 2, OptionNum: 2, Body: "b"},
+		{QuestionID: 3, OptionNum: 1, Body: "a"},
+		{QuestionID: 3, OptionNum: 2, Body: "b"},
+	}
+
+	type test struct {
+		description string
+		body        []model.ResponseBody
+		expect      []ResponseError
+	}
+
+	testCases := []test{
+		{
+			description: "正しい回答なのでエラーなし",
+			body: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("回答")},
+				{QuestionID: 2, QuestionType: "MultipleChoice", OptionResponse: []string{"a"}},
+				{QuestionID: 3, QuestionType: "Checkbox", OptionResponse: []string{"a", "b"}},
+			},
+			expect: []ResponseError{},
+		},
+		{
+			description: "アンケートにない質問なのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 6, QuestionType: "Text", Body: null.StringFrom("回答")},
+			},
+			expect: []ResponseError{
+				{QuestionID: 6, Message: "this question does not belong to the questionnaire"},
+			},
+		},
+		{
+			description: "同じ質問への回答が複数あるのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 2, QuestionType: "MultipleChoice", OptionResponse: []string{"a"}},
+				{QuestionID: 2, QuestionType: "MultipleChoice", OptionResponse: []string{"b"}},
+				{QuestionID: 3, QuestionType: "Checkbox", OptionResponse: []string{"a"}},
+				{QuestionID: 3, QuestionType: "Checkbox", OptionResponse: []string{"b"}},
+				{QuestionID: 3, QuestionType: "Checkbox", OptionResponse: []string{"a"}},
+			},
+			expect: []ResponseError{
+				{QuestionID: 2, Message: "this question is answered more than once"},
+				{QuestionID: 3, Message: "this question is answered more than once"},
+			},
+		},
+		{
+			description: "質問の種類が異なるのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Number", Body: null.StringFrom("1")},
+			},
+			expect: []ResponseError{
+				{QuestionID: 1, Message: "question_type must be Text"},
+			},
+		},
+		{
+			description: "存在しない選択肢なのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 3, QuestionType: "Checkbox", OptionResponse: []string{"a", "c"}},
+			},
+			expect: []ResponseError{
+				{QuestionID: 3, Message: "unknown option: c"},
+			},
+		},
+		{
+			description: "MultipleChoiceで複数選択しているのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 2, QuestionType: "MultipleChoice", OptionResponse: []string{"a", "b"}},
+			},
+			expect: []ResponseError{
+				{QuestionID: 2, Message: "only one option can be chosen"},
+			},
+		},
+		{
+			description: "ISO 8601の日時なのでエラーなし",
+			body: []model.ResponseBody{
+				{QuestionID: 4, QuestionType: "Date", Body: null.StringFrom("2021-04-01")},
+				{QuestionID: 5, QuestionType: "DateTime", Body: null.StringFrom("2021-04-01T09:30+09:00")},
+			},
+			expect: []ResponseError{},
+		},
+		{
+			description: "日付として解釈できないのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 4, QuestionType: "Date", Body: null.StringFrom("2021/04/01")},
+			},
+			expect: []ResponseError{
+				{QuestionID: 4, Message: "invalid date: 2021/04/01"},
+			},
+		},
+		{
+			description: "未回答の日付は無視される",
+			body: []model.ResponseBody{
+				{QuestionID: 4, QuestionType: "Date", Body: null.StringFrom("")},
+			},
+			expect: []ResponseError{},
+		},
+		{
+			description: "空の選択肢は無視される",
+			body: []model.ResponseBody{
+				{QuestionID: 2, QuestionType: "MultipleChoice", OptionResponse: []string{"a", ""}},
+			},
+			expect: []ResponseError{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual := checkResponseBody(questions, options, testCase.body)
+		assertion.Equal(testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestCheckRequiredResponses(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	questions := []model.Questions{
+		{ID: 1, Type: "Text", IsRequired: true},
+		{ID: 2, Type: "Checkbox", IsRequired: true},
+		{ID: 3, Type: "Number", IsRequired: false},
+	}
+
+	type test struct {
+		description string
+		body        []model.ResponseBody
+		expect      []ResponseError
+	}
+
+	testCases := []test{
+		{
+			description: "必須の質問が全て回答されているのでエラーなし",
+			body: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("回答")},
+				{QuestionID: 2, QuestionType: "Checkbox", OptionResponse: []string{"a"}},
+			},
+			expect: []ResponseError{},
+		},
+		{
+			description: "必須でない質問は回答されていなくてもエラーなし",
+			body: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("回答")},
+				{QuestionID: 2, QuestionType: "Checkbox", OptionResponse: []string{"a"}},
+				{QuestionID: 3, QuestionType: "Number", Body: null.NewString("", false)},
+			},
+			expect: []ResponseError{},
+		},
+		{
+			description: "空白のみの回答は未回答としてエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("  ")},
+				{QuestionID: 2, QuestionType: "Checkbox", OptionResponse: []string{"a"}},
+			},
+			expect: []ResponseError{
+				{QuestionID: 1, Message: "this question is required"},
+			},
+		},
+		{
+			description: "選択肢が選ばれていないのでエラー",
+			body: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("回答")},
+				{QuestionID: 2, QuestionType: "Checkbox", OptionResponse: []string{}},
+			},
+			expect: []ResponseError{
+				{QuestionID: 2, Message: "this question is required"},
+			},
+		},
+		{
+			description: "回答がないので全ての必須の質問がエラー",
+			body:        []model.ResponseBody{},
+			expect: []ResponseError{
+				{QuestionID: 1, Message: "this question is required"},
+				{QuestionID: 2, Message: "this question is required"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual := checkRequiredResponses(questions, testCase.body)
+		assertion.Equal(testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestNormalizeResponseBody(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	body := []model.ResponseBody{
+		{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("09:30")},
+		{QuestionID: 2, QuestionType: "Time", Body: null.StringFrom("09:30")},
+		{QuestionID: 3, QuestionType: "Date", Body: null.StringFrom("")},
+		{QuestionID: 4, QuestionType: "Date", Body: null.StringFrom("2021-04-01")},
+	}
+
+	actual := normalizeResponseBody(body)
+
+	assertion.Equal(null.StringFrom("09:30"), actual[0].Body, "Text")
+	assertion.Equal(null.StringFrom("09:30:00"), actual[1].Body, "Time")
+	assertion.Equal(null.StringFrom(""), actual[2].Body, "empty Date")
+	assertion.Equal(null.StringFrom("2021-04-01"), actual[3].Body, "Date")
+}
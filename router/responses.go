@@ -1,9 +1,11 @@
 package router
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -21,16 +23,30 @@ type Response struct {
 	model.IScaleLabel
 	model.IRespondent
 	model.IResponse
+	model.IQuestion
+	model.IOption
+	model.IQuestionCondition
+	model.ITransaction
+	model.IAudit
+	model.IResponseRevision
+	*WebhookEventPublisher
 }
 
 // NewResponse Responseのコンストラクタ
-func NewResponse(questionnaire model.IQuestionnaire, validation model.IValidation, scaleLabel model.IScaleLabel, respondent model.IRespondent, response model.IResponse) *Response {
+func NewResponse(questionnaire model.IQuestionnaire, validation model.IValidation, scaleLabel model.IScaleLabel, respondent model.IRespondent, response model.IResponse, question model.IQuestion, option model.IOption, questionCondition model.IQuestionCondition, transaction model.ITransaction, audit model.IAudit, responseRevision model.IResponseRevision, webhookEventPublisher *WebhookEventPublisher) *Response {
 	return &Response{
-		IQuestionnaire: questionnaire,
-		IValidation:    validation,
-		IScaleLabel:    scaleLabel,
-		IRespondent:    respondent,
-		IResponse:      response,
+		IQuestionnaire:        questionnaire,
+		IValidation:           validation,
+		IScaleLabel:           scaleLabel,
+		IRespondent:           respondent,
+		IResponse:             response,
+		IQuestion:             question,
+		IOption:               option,
+		IQuestionCondition:    questionCondition,
+		ITransaction:          transaction,
+		IAudit:                audit,
+		IResponseRevision:     responseRevision,
+		WebhookEventPublisher: webhookEventPublisher,
 	}
 }
 
@@ -41,6 +57,147 @@ type Responses struct {
 	Body        []model.ResponseBody `json:"body" validate:"required,dive"`
 }
 
+// validateResponses 回答がアンケートの質問・選択肢と合っているかを確認し、分岐で飛ばされた質問への回答を取り除いて返す
+// 回答の誤りはResponseErrorsとして返す
+func (r *Response) validateResponses(ctx context.Context, req Responses) ([]model.ResponseBody, *ResponseErrors, error) {
+	questions, err := r.GetQuestions(ctx, req.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+
+	choiceQuestionIDs := []int{}
+	for _, question := range questions {
+		if questionType, _ := model.GetQuestionTypeInfo(question.Type); questionType.HasOptions {
+			choiceQuestionIDs = append(choiceQuestionIDs, question.ID)
+		}
+	}
+	options, err := r.GetOptions(ctx, choiceQuestionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get options: %w", err)
+	}
+
+	responseErrors := checkResponseBody(questions, options, req.Body)
+	if len(responseErrors) != 0 {
+		return nil, &ResponseErrors{
+			Message: "invalid responses",
+			Errors:  responseErrors,
+		}, nil
+	}
+
+	// 日時の表記の違いで分岐や集計が変わらないようにそろえてから保存する
+	req.Body = normalizeResponseBody(req.Body)
+
+	// 分岐で飛ばされたページの質問への回答は保存しない
+	body, visibleQuestions, err := r.dropHiddenResponses(ctx, questions, req.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to drop hidden responses: %w", err)
+	}
+
+	// 一時保存でなければ回答必須の質問が全て回答されているか確認する
+	if !req.Temporarily {
+		responseErrors := checkRequiredResponses(visibleQuestions, body)
+		if len(responseErrors) != 0 {
+			return nil, &ResponseErrors{
+				Message: "required questions are not answered",
+				Errors:  responseErrors,
+			}, nil
+		}
+	}
+
+	return body, nil, nil
+}
+
+// checkResponseValidation 質問の種類に応じて，回答がvalidationの制限を満たしているか確認する
+func (r *Response) checkResponseValidation(c echo.Context, validation model.Validations, body model.ResponseBody) error {
+	questionType, _ := model.GetQuestionTypeInfo(body.QuestionType)
+
+	switch {
+	case questionType.HasRegexPattern:
+		if err := r.CheckTextValidation(validation, body.Body.ValueOrZero()); err != nil {
+			if errors.Is(err, model.ErrTextMatching) {
+				c.Logger().Infof("invalid text: %+v", err)
+				return echo.NewHTTPError(http.StatusBadRequest, err)
+			}
+			c.Logger().Errorf("invalid text: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	case questionType.HasBounds && questionType.IsDateTime:
+		if err := r.CheckDateTimeValidation(validation, body.QuestionType, body.Body.ValueOrZero()); err != nil {
+			if errors.Is(err, model.ErrDateTimeBoundary) {
+				c.Logger().Infof("invalid datetime: %+v", err)
+				return echo.NewHTTPError(http.StatusBadRequest, err)
+			}
+			c.Logger().Errorf("invalid datetime: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	case questionType.HasBounds:
+		if err := r.CheckNumberValidation(validation, body.Body.ValueOrZero()); err != nil {
+			if errors.Is(err, model.ErrInvalidNumber) {
+				c.Logger().Errorf("invalid number: %+v", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, err)
+			}
+			c.Logger().Infof("invalid number: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+	case questionType.MultipleSelection:
+		if err := r.CheckSelectionValidation(validation, body.OptionResponse); err != nil {
+			if errors.Is(err, model.ErrSelectionCount) {
+				c.Logger().Infof("invalid selections: %+v", err)
+				return echo.NewHTTPError(http.StatusBadRequest, err)
+			}
+			c.Logger().Errorf("invalid selections: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	}
+
+	return nil
+}
+
+// dropHiddenResponses 回答と分岐条件から飛ばされたページを求め、そのページの質問への回答を取り除く
+// 飛ばされなかった(回答すべき)質問の一覧も返す
+func (r *Response) dropHiddenResponses(ctx context.Context, questions []model.Questions, body []model.ResponseBody) ([]model.ResponseBody, []model.Questions, error) {
+	questionIDs := make([]int, 0, len(questions))
+	for _, question := range questions {
+		questionIDs = append(questionIDs, question.ID)
+	}
+	conditions, err := r.GetQuestionConditions(ctx, questionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get question conditions: %w", err)
+	}
+	if len(conditions) == 0 {
+		return body, questions, nil
+	}
+
+	answers := make(map[int][]string, len(body))
+	for _, responseBody := range body {
+		if questionType, _ := model.GetQuestionTypeInfo(responseBody.QuestionType); questionType.HasOptions {
+			answers[responseBody.QuestionID] = responseBody.OptionResponse
+		} else if responseBody.Body.Valid {
+			answers[responseBody.QuestionID] = []string{responseBody.Body.String}
+		}
+	}
+
+	hiddenQuestions := calcHiddenQuestions(questions, conditions, answers)
+
+	visibleBody := make([]model.ResponseBody, 0, len(body))
+	for _, responseBody := range body {
+		if _, ok := hiddenQuestions[responseBody.QuestionID]; ok {
+			continue
+		}
+		visibleBody = append(visibleBody, responseBody)
+	}
+
+	visibleQuestions := make([]model.Questions, 0, len(questions))
+	for _, question := range questions {
+		if _, ok := hiddenQuestions[question.ID]; ok {
+			continue
+		}
+		visibleQuestions = append(visibleQuestions, question)
+	}
+
+	return visibleBody, visibleQuestions, nil
+}
+
 // PostResponse POST /responses
 func (r *Response) PostResponse(c echo.Context) error {
 	userID, err := getUserID(c)
@@ -84,6 +241,39 @@ func (r *Response) PostResponse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusMethodNotAllowed)
 	}
 
+	// テンプレートへの回答は許可しない
+	isTemplate, err := r.CheckQuestionnaireTemplate(c.Request().Context(), req.ID)
+	if err != nil {
+		c.Logger().Errorf("failed to check questionnaire template: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if isTemplate {
+		c.Logger().Info("template questionnaire")
+		return echo.NewHTTPError(http.StatusMethodNotAllowed)
+	}
+
+	// 回答開始前と締め切られた後の回答は許可しない
+	isOpen, err := r.CheckQuestionnaireOpen(c.Request().Context(), req.ID)
+	if err != nil {
+		c.Logger().Errorf("failed to check questionnaire open: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if !isOpen {
+		c.Logger().Info("questionnaire is not open")
+		return echo.NewHTTPError(http.StatusMethodNotAllowed)
+	}
+
+	var responseErrors *ResponseErrors
+	req.Body, responseErrors, err = r.validateResponses(c.Request().Context(), req)
+	if err != nil {
+		c.Logger().Errorf("failed to validate responses: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if responseErrors != nil {
+		c.Logger().Infof("invalid responses: %+v", responseErrors)
+		return echo.NewHTTPError(http.StatusBadRequest, responseErrors)
+	}
+
 	// validationsのパターンマッチ
 	questionIDs := make([]int, 0, len(req.Body))
 	QuestionTypes := make(map[int]model.ResponseBody, len(req.Body))
@@ -102,32 +292,14 @@ func (r *Response) PostResponse(c echo.Context) error {
 	// パターンマッチしてエラーなら返す
 	for _, validation := range validations {
 		body := QuestionTypes[validation.QuestionID]
-		switch body.QuestionType {
-		case "Number":
-			if err := r.CheckNumberValidation(validation, body.Body.ValueOrZero()); err != nil {
-				if errors.Is(err, model.ErrInvalidNumber) {
-					c.Logger().Errorf("invalid number: %+v", err)
-					return echo.NewHTTPError(http.StatusInternalServerError, err)
-				}
-				c.Logger().Infof("invalid number: %+v", err)
-				return echo.NewHTTPError(http.StatusBadRequest, err)
-			}
-		case "Text":
-			if err := r.CheckTextValidation(validation, body.Body.ValueOrZero()); err != nil {
-				if errors.Is(err, model.ErrTextMatching) {
-					c.Logger().Infof("invalid text: %+v", err)
-					return echo.NewHTTPError(http.StatusBadRequest, err)
-				}
-				c.Logger().Errorf("invalid text: %+v", err)
-				return echo.NewHTTPError(http.StatusInternalServerError, err)
-			}
+		if err := r.checkResponseValidation(c, validation, body); err != nil {
+			return err
 		}
 	}
 
 	scaleLabelIDs := []int{}
 	for _, body := range req.Body {
-		switch body.QuestionType {
-		case "LinearScale":
+		if questionType, _ := model.GetQuestionTypeInfo(body.QuestionType); questionType.HasScaleLabel {
 			scaleLabelIDs = append(scaleLabelIDs, body.QuestionID)
 		}
 	}
@@ -144,8 +316,7 @@ func (r *Response) PostResponse(c echo.Context) error {
 
 	// LinearScaleのパターンマッチ
 	for _, body := range req.Body {
-		switch body.QuestionType {
-		case "LinearScale":
+		if questionType, _ := model.GetQuestionTypeInfo(body.QuestionType); questionType.HasScaleLabel {
 			label, ok := scaleLabelMap[body.QuestionID]
 			if !ok {
 				label = model.ScaleLabels{}
@@ -165,23 +336,16 @@ func (r *Response) PostResponse(c echo.Context) error {
 		submittedAt = time.Now()
 	}
 
-	responseID, err := r.InsertRespondent(c.Request().Context(), userID, req.ID, null.NewTime(submittedAt, !req.Temporarily))
-	if err != nil {
-		c.Logger().Errorf("failed to insert respondent: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
-	}
-
 	responseMetas := make([]*model.ResponseMeta, 0, len(req.Body))
 	for _, body := range req.Body {
-		switch body.QuestionType {
-		case "MultipleChoice", "Checkbox", "Dropdown":
+		if questionType, _ := model.GetQuestionTypeInfo(body.QuestionType); questionType.HasOptions {
 			for _, option := range body.OptionResponse {
 				responseMetas = append(responseMetas, &model.ResponseMeta{
 					QuestionID: body.QuestionID,
 					Data:       option,
 				})
 			}
-		default:
+		} else {
 			responseMetas = append(responseMetas, &model.ResponseMeta{
 				QuestionID: body.QuestionID,
 				Data:       body.Body.ValueOrZero(),
@@ -189,12 +353,64 @@ func (r *Response) PostResponse(c echo.Context) error {
 		}
 	}
 
-	if len(responseMetas) > 0 {
-		err = r.InsertResponses(c.Request().Context(), responseID, responseMetas)
+	var responseID int
+	err = r.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		// 同時に回答されても上限を超えないように、回答の追加と同じトランザクションで確認する
+		err = r.CheckResponseLimits(ctx, userID, req.ID)
+		if err != nil {
+			c.Logger().Infof("failed to check response limits: %+v", err)
+			return err
+		}
+
+		responseID, err = r.InsertRespondent(ctx, userID, req.ID, null.NewTime(submittedAt, !req.Temporarily))
 		if err != nil {
-			c.Logger().Errorf("failed to insert responses: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to insert responses: %w", err))
+			c.Logger().Errorf("failed to insert respondent: %+v", err)
+			return err
+		}
+
+		revision, err := r.InsertResponseRevision(ctx, responseID, null.NewTime(submittedAt, !req.Temporarily))
+		if err != nil {
+			c.Logger().Errorf("failed to insert response revision: %+v", err)
+			return err
+		}
+
+		if len(responseMetas) > 0 {
+			err = r.InsertResponses(ctx, responseID, revision, responseMetas)
+			if err != nil {
+				c.Logger().Errorf("failed to insert responses: %+v", err)
+				return fmt.Errorf("failed to insert responses: %w", err)
+			}
 		}
+
+		// 一時保存の回答はWebhookで送信しない
+		if !req.Temporarily {
+			respondentDetail, err := r.GetRespondentDetail(ctx, responseID, null.NewInt(0, false))
+			if err != nil {
+				c.Logger().Errorf("failed to get respondent detail: %+v", err)
+				return err
+			}
+
+			err = r.PublishWebhookEvent(ctx, req.ID, model.WebhookEventResponseSubmitted, respondentDetail)
+			if err != nil {
+				c.Logger().Errorf("failed to publish webhook event: %+v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrTooManyResponsesPerUser) {
+			return echo.NewHTTPError(http.StatusConflict, "you have already responded to this questionnaire, edit your response instead")
+		}
+		if errors.Is(err, model.ErrTooManyResponses) {
+			return echo.NewHTTPError(http.StatusConflict, "this questionnaire has reached the maximum number of responses")
+		}
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusCreated, map[string]interface{}{
@@ -215,7 +431,7 @@ func (r *Response) GetResponse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse responseID(%s) to integer: %w", strResponseID, err))
 	}
 
-	respondentDetail, err := r.GetRespondentDetail(c.Request().Context(), responseID)
+	respondentDetail, err := r.GetRespondentDetail(c.Request().Context(), responseID, null.NewInt(0, false))
 	if errors.Is(err, model.ErrRecordNotFound) {
 		c.Logger().Infof("response not found: %+v", err)
 		return echo.NewHTTPError(http.StatusNotFound, "response not found")
@@ -225,6 +441,10 @@ func (r *Response) GetResponse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
+	if setETag(c, respondentDetail.ModifiedAt) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, respondentDetail)
 }
 
@@ -236,13 +456,35 @@ func (r *Response) EditResponse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get responseID: %w", err))
 	}
 
+	ifMatch, err := getIfMatch(c)
+	if err != nil {
+		c.Logger().Infof("invalid If-Match: %+v", err)
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid If-Match header")
+	}
+
 	req := Responses{}
 	if err := c.Bind(&req); err != nil {
 		c.Logger().Infof("failed to bind Responses: %+v", err)
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
-	limit, err := r.GetQuestionnaireLimit(c.Request().Context(), req.ID)
+	// RespondentAuthenticateは回答者であることしか確認しないので、アンケートは保存されている回答から決める
+	respondentDetail, err := r.GetRespondentDetail(c.Request().Context(), responseID, null.NewInt(0, false))
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			c.Logger().Infof("response not found: %+v", err)
+			return echo.NewHTTPError(http.StatusNotFound, "response not found")
+		}
+		c.Logger().Errorf("failed to get respondent detail: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	questionnaireID := respondentDetail.QuestionnaireID
+	if req.ID != questionnaireID {
+		c.Logger().Infof("questionnaireID does not match: %d, %d", req.ID, questionnaireID)
+		return echo.NewHTTPError(http.StatusBadRequest, "questionnaireID does not match the response")
+	}
+
+	limit, err := r.GetQuestionnaireLimit(c.Request().Context(), questionnaireID)
 	if err != nil {
 		if errors.Is(err, model.ErrRecordNotFound) {
 			c.Logger().Infof("questionnaire not found: %+v", err)
@@ -258,6 +500,28 @@ func (r *Response) EditResponse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusMethodNotAllowed)
 	}
 
+	// 締め切られた後の回答の変更は許可しない
+	isOpen, err := r.CheckQuestionnaireOpen(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to check questionnaire open: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if !isOpen {
+		c.Logger().Info("questionnaire is not open")
+		return echo.NewHTTPError(http.StatusMethodNotAllowed)
+	}
+
+	var responseErrors *ResponseErrors
+	req.Body, responseErrors, err = r.validateResponses(c.Request().Context(), req)
+	if err != nil {
+		c.Logger().Errorf("failed to validate responses: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if responseErrors != nil {
+		c.Logger().Infof("invalid responses: %+v", responseErrors)
+		return echo.NewHTTPError(http.StatusBadRequest, responseErrors)
+	}
+
 	// validationsのパターンマッチ
 	questionIDs := make([]int, 0, len(req.Body))
 	QuestionTypes := make(map[int]model.ResponseBody, len(req.Body))
@@ -276,32 +540,14 @@ func (r *Response) EditResponse(c echo.Context) error {
 	// パターンマッチしてエラーなら返す
 	for _, validation := range validations {
 		body := QuestionTypes[validation.QuestionID]
-		switch body.QuestionType {
-		case "Number":
-			if err := r.CheckNumberValidation(validation, body.Body.ValueOrZero()); err != nil {
-				if errors.Is(err, model.ErrInvalidNumber) {
-					c.Logger().Errorf("invalid number: %+v", err)
-					return echo.NewHTTPError(http.StatusInternalServerError, err)
-				}
-				c.Logger().Infof("invalid number: %+v", err)
-				return echo.NewHTTPError(http.StatusBadRequest, err)
-			}
-		case "Text":
-			if err := r.CheckTextValidation(validation, body.Body.ValueOrZero()); err != nil {
-				if errors.Is(err, model.ErrTextMatching) {
-					c.Logger().Infof("invalid text: %+v", err)
-					return echo.NewHTTPError(http.StatusBadRequest, err)
-				}
-				c.Logger().Errorf("invalid text: %+v", err)
-				return echo.NewHTTPError(http.StatusInternalServerError, err)
-			}
+		if err := r.checkResponseValidation(c, validation, body); err != nil {
+			return err
 		}
 	}
 
 	scaleLabelIDs := []int{}
 	for _, body := range req.Body {
-		switch body.QuestionType {
-		case "LinearScale":
+		if questionType, _ := model.GetQuestionTypeInfo(body.QuestionType); questionType.HasScaleLabel {
 			scaleLabelIDs = append(scaleLabelIDs, body.QuestionID)
 		}
 	}
@@ -318,8 +564,7 @@ func (r *Response) EditResponse(c echo.Context) error {
 
 	// LinearScaleのパターンマッチ
 	for _, body := range req.Body {
-		switch body.QuestionType {
-		case "LinearScale":
+		if questionType, _ := model.GetQuestionTypeInfo(body.QuestionType); questionType.HasScaleLabel {
 			label, ok := scaleLabelMap[body.QuestionID]
 			if !ok {
 				label = &model.ScaleLabels{}
@@ -331,31 +576,16 @@ func (r *Response) EditResponse(c echo.Context) error {
 		}
 	}
 
-	if !req.Temporarily {
-		err := r.UpdateSubmittedAt(c.Request().Context(), responseID)
-		if err != nil {
-			c.Logger().Errorf("failed to update submitted at: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to update sbmitted_at: %w", err))
-		}
-	}
-
-	//全消し&追加(レコード数爆発しそう)
-	if err := r.IResponse.DeleteResponse(c.Request().Context(), responseID); err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
-		c.Logger().Errorf("failed to delete response: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
-	}
-
 	responseMetas := make([]*model.ResponseMeta, 0, len(req.Body))
 	for _, body := range req.Body {
-		switch body.QuestionType {
-		case "MultipleChoice", "Checkbox", "Dropdown":
+		if questionType, _ := model.GetQuestionTypeInfo(body.QuestionType); questionType.HasOptions {
 			for _, option := range body.OptionResponse {
 				responseMetas = append(responseMetas, &model.ResponseMeta{
 					QuestionID: body.QuestionID,
 					Data:       option,
 				})
 			}
-		default:
+		} else {
 			responseMetas = append(responseMetas, &model.ResponseMeta{
 				QuestionID: body.QuestionID,
 				Data:       body.Body.ValueOrZero(),
@@ -363,12 +593,79 @@ func (r *Response) EditResponse(c echo.Context) error {
 		}
 	}
 
-	if len(responseMetas) > 0 {
-		err = r.InsertResponses(c.Request().Context(), responseID, responseMetas)
+	err = r.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		// If-Matchの版から変更されていれば更新しない
+		_, err := r.UpdateRespondentModifiedAt(ctx, responseID, ifMatch)
+		if err != nil {
+			c.Logger().Infof("failed to update respondent modified_at: %+v", err)
+			return err
+		}
+
+		// 一時保存していた回答を提出した場合は、変更ではなく提出としてWebhookで送信する
+		webhookEvent := model.WebhookEventResponseUpdated
+		if !req.Temporarily {
+			before, err := r.GetRespondentDetail(ctx, responseID, null.NewInt(0, false))
+			if err != nil {
+				c.Logger().Errorf("failed to get respondent detail: %+v", err)
+				return err
+			}
+			if !before.SubmittedAt.Valid {
+				webhookEvent = model.WebhookEventResponseSubmitted
+			}
+
+			err = r.UpdateSubmittedAt(ctx, responseID)
+			if err != nil {
+				c.Logger().Errorf("failed to update submitted at: %+v", err)
+				return fmt.Errorf("failed to update sbmitted_at: %w", err)
+			}
+		}
+
+		// 変更前の版の回答は論理削除して残し、新しい版として追加する
+		if err := r.IResponse.DeleteResponse(ctx, responseID); err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
+			c.Logger().Errorf("failed to delete response: %+v", err)
+			return err
+		}
+
+		revision, err := r.InsertResponseRevision(ctx, responseID, null.NewTime(time.Now(), !req.Temporarily))
 		if err != nil {
-			c.Logger().Errorf("failed to insert responses: %+v", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to insert responses: %w", err))
+			c.Logger().Errorf("failed to insert response revision: %+v", err)
+			return err
+		}
+
+		if len(responseMetas) > 0 {
+			err = r.InsertResponses(ctx, responseID, revision, responseMetas)
+			if err != nil {
+				c.Logger().Errorf("failed to insert responses: %+v", err)
+				return fmt.Errorf("failed to insert responses: %w", err)
+			}
 		}
+
+		// 一時保存の回答はWebhookで送信しない
+		if !req.Temporarily {
+			after, err := r.GetRespondentDetail(ctx, responseID, null.NewInt(0, false))
+			if err != nil {
+				c.Logger().Errorf("failed to get respondent detail: %+v", err)
+				return err
+			}
+
+			err = r.PublishWebhookEvent(ctx, after.QuestionnaireID, webhookEvent, after)
+			if err != nil {
+				c.Logger().Errorf("failed to publish webhook event: %+v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "response not found")
+		}
+		if errors.Is(err, model.ErrConflict) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "response has been modified")
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
 	return c.NoContent(http.StatusOK)
@@ -376,6 +673,12 @@ func (r *Response) EditResponse(c echo.Context) error {
 
 // DeleteResponse DELETE /responses/:responseID
 func (r *Response) DeleteResponse(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get userID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get userID: %w", err))
+	}
+
 	responseID, err := getResponseID(c)
 	if err != nil {
 		c.Logger().Errorf("failed to get response id: %+v", err)
@@ -398,17 +701,210 @@ func (r *Response) DeleteResponse(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusMethodNotAllowed)
 	}
 
-	err = r.DeleteRespondent(c.Request().Context(), responseID)
+	err = r.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		before, err := r.GetRespondentDetail(ctx, responseID, null.NewInt(0, false))
+		if err != nil {
+			c.Logger().Errorf("failed to get respondent detail: %+v", err)
+			return err
+		}
+
+		err = r.DeleteRespondent(ctx, responseID)
+		if err != nil {
+			c.Logger().Errorf("failed to delete respondent: %+v", err)
+			return err
+		}
+
+		err = r.IResponse.DeleteResponse(ctx, responseID)
+		if err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
+			c.Logger().Errorf("failed to delete response: %+v", err)
+			return err
+		}
+
+		// 匿名のアンケートでは削除した人から回答者がわかってしまうので記録しない
+		isAnonymous, err := r.CheckQuestionnaireAnonymous(ctx, before.QuestionnaireID)
+		if err != nil {
+			c.Logger().Errorf("failed to check questionnaire anonymous: %+v", err)
+			return err
+		}
+		actor := userID
+		if isAnonymous {
+			actor = ""
+		}
+
+		err = r.InsertAuditEvent(ctx, actor, before.QuestionnaireID, model.AuditTargetResponse, responseID, model.AuditActionDelete, before, nil)
+		if err != nil {
+			c.Logger().Errorf("failed to insert audit event: %+v", err)
+			return err
+		}
+
+		// 提出していない回答はWebhookで送信していないので、削除も送信しない
+		if before.SubmittedAt.Valid {
+			err = r.PublishWebhookEvent(ctx, before.QuestionnaireID, model.WebhookEventResponseDeleted, before)
+			if err != nil {
+				c.Logger().Errorf("failed to publish webhook event: %+v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		c.Logger().Errorf("failed to delete respondent: %+v", err)
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	err = r.IResponse.DeleteResponse(c.Request().Context(), responseID)
-	if err != nil && !errors.Is(err, model.ErrNoRecordDeleted) {
-		c.Logger().Errorf("failed to delete response: %+v", err)
+	return c.NoContent(http.StatusOK)
+}
+
+// ResponseRevisionDiff 回答の2つの版の間で変わった質問ごとの回答
+type ResponseRevisionDiff struct {
+	QuestionID   int                    `json:"questionID"`
+	QuestionType string                 `json:"question_type"`
+	Before       ResponseRevisionAnswer `json:"before"`
+	After        ResponseRevisionAnswer `json:"after"`
+}
+
+// ResponseRevisionAnswer 版ごとの質問への回答
+type ResponseRevisionAnswer struct {
+	Body           null.String `json:"response"`
+	OptionResponse []string    `json:"option_response"`
+}
+
+// GetResponseRevisions GET /responses/:responseID/revisions
+func (r *Response) GetResponseRevisions(c echo.Context) error {
+	responseID, err := getResponseID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get responseID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get responseID: %w", err))
+	}
+
+	responseRevisions, err := r.IResponseRevision.GetResponseRevisions(c.Request().Context(), responseID)
+	if err != nil {
+		c.Logger().Errorf("failed to get response revisions: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	return c.NoContent(http.StatusOK)
+	return c.JSON(http.StatusOK, responseRevisions)
+}
+
+// GetResponseRevision GET /responses/:responseID/revisions/:revision
+func (r *Response) GetResponseRevision(c echo.Context) error {
+	responseID, err := getResponseID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get responseID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get responseID: %w", err))
+	}
+
+	strRevision := c.Param("revision")
+	revision, err := strconv.Atoi(strRevision)
+	if err != nil {
+		c.Logger().Infof("failed to convert revision to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse revision(%s) to integer: %w", strRevision, err))
+	}
+
+	respondentDetail, err := r.GetRespondentDetail(c.Request().Context(), responseID, null.NewInt(int64(revision), true))
+	if errors.Is(err, model.ErrRecordNotFound) {
+		c.Logger().Infof("response revision not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "response revision not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get respondent detail: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, respondentDetail)
+}
+
+// GetResponseRevisionDiff GET /responses/:responseID/revisions/diff
+func (r *Response) GetResponseRevisionDiff(c echo.Context) error {
+	responseID, err := getResponseID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get responseID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to get responseID: %w", err))
+	}
+
+	strFrom := c.QueryParam("from")
+	from, err := strconv.Atoi(strFrom)
+	if err != nil {
+		c.Logger().Infof("failed to convert from to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse from(%s) to integer: %w", strFrom, err))
+	}
+
+	strTo := c.QueryParam("to")
+	to, err := strconv.Atoi(strTo)
+	if err != nil {
+		c.Logger().Infof("failed to convert to to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse to(%s) to integer: %w", strTo, err))
+	}
+
+	fromDetail, err := r.GetRespondentDetail(c.Request().Context(), responseID, null.NewInt(int64(from), true))
+	if errors.Is(err, model.ErrRecordNotFound) {
+		c.Logger().Infof("response revision not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "response revision not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get respondent detail: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	toDetail, err := r.GetRespondentDetail(c.Request().Context(), responseID, null.NewInt(int64(to), true))
+	if errors.Is(err, model.ErrRecordNotFound) {
+		c.Logger().Infof("response revision not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "response revision not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get respondent detail: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"responseID": responseID,
+		"from":       from,
+		"to":         to,
+		"diff":       diffResponseRevisions(fromDetail.Responses, toDetail.Responses),
+	})
+}
+
+// diffResponseRevisions 2つの版の回答を比べ、回答が変わった質問のみを返す
+func diffResponseRevisions(before []model.ResponseBody, after []model.ResponseBody) []ResponseRevisionDiff {
+	beforeMap := make(map[int]model.ResponseBody, len(before))
+	for _, responseBody := range before {
+		beforeMap[responseBody.QuestionID] = responseBody
+	}
+
+	diffs := []ResponseRevisionDiff{}
+	for _, afterBody := range after {
+		beforeBody := beforeMap[afterBody.QuestionID]
+
+		beforeAnswer := ResponseRevisionAnswer{
+			Body:           beforeBody.Body,
+			OptionResponse: beforeBody.OptionResponse,
+		}
+		if beforeAnswer.OptionResponse == nil {
+			beforeAnswer.OptionResponse = []string{}
+		}
+		afterAnswer := ResponseRevisionAnswer{
+			Body:           afterBody.Body,
+			OptionResponse: afterBody.OptionResponse,
+		}
+		if afterAnswer.OptionResponse == nil {
+			afterAnswer.OptionResponse = []string{}
+		}
+
+		if reflect.DeepEqual(beforeAnswer, afterAnswer) {
+			continue
+		}
+
+		diffs = append(diffs, ResponseRevisionDiff{
+			QuestionID:   afterBody.QuestionID,
+			QuestionType: afterBody.QuestionType,
+			Before:       beforeAnswer,
+			After:        afterAnswer,
+		})
+	}
+
+	return diffs
 }
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -496,6 +497,16 @@ func TestPostResponse(t *testing.T) {
 	responseIDFailure := 0
 
 	questionnaireIDLimit := 2
+	questionnaireIDNumber := 4
+	questionnaireIDLinearScale := 5
+	questionnaireIDChoice := 6
+	questionnaireIDCheckbox := 7
+	questionnaireIDDate := 8
+	questionnaireIDRequired := 3
+	questionnaireIDTemplate := 9
+	questionnaireIDUserLimit := 10
+	questionnaireIDTotalLimit := 11
+	questionnaireIDClosed := 12
 
 	validation :=
 		model.Validations{
@@ -521,9 +532,16 @@ func TestPostResponse(t *testing.T) {
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockRespondent := mock_model.NewMockIRespondent(ctrl)
 	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockResponseRevision := mock_model.NewMockIResponseRevision(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	r := NewResponse(
 		mockQuestionnaire,
@@ -531,13 +549,82 @@ func TestPostResponse(t *testing.T) {
 		mockScaleLabel,
 		mockRespondent,
 		mockResponse,
+		mockQuestion,
+		mockOption,
+		mockQuestionCondition,
+		mockTransaction,
+		mockAudit,
+		mockResponseRevision,
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
 	m := NewMiddleware(
 		mockAdministrator,
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		NewDevAuthenticator("mds_boy"),
 	)
+	// Question
+	// GetQuestions
+	// required
+	mockQuestion.EXPECT().
+		GetQuestions(gomock.Any(), questionnaireIDRequired).
+		Return([]model.Questions{
+			{
+				ID:              questionIDSuccess,
+				QuestionnaireID: questionnaireIDRequired,
+				PageNum:         1,
+				QuestionNum:     1,
+				Type:            "Text",
+				IsRequired:      true,
+			},
+		}, nil).AnyTimes()
+	// response limits
+	for _, questionnaireID := range []int{questionnaireIDUserLimit, questionnaireIDTotalLimit, questionnaireIDClosed} {
+		mockQuestion.EXPECT().
+			GetQuestions(gomock.Any(), questionnaireID).
+			Return([]model.Questions{}, nil).AnyTimes()
+	}
+	questionTypes := map[int]string{
+		questionnaireIDSuccess:     "Text",
+		questionnaireIDNumber:      "Number",
+		questionnaireIDLinearScale: "LinearScale",
+		questionnaireIDChoice:      "MultipleChoice",
+		questionnaireIDCheckbox:    "Checkbox",
+		questionnaireIDDate:        "Date",
+	}
+	for questionnaireID, questionType := range questionTypes {
+		mockQuestion.EXPECT().
+			GetQuestions(gomock.Any(), questionnaireID).
+			Return([]model.Questions{
+				{
+					ID:              questionIDSuccess,
+					QuestionnaireID: questionnaireID,
+					PageNum:         1,
+					QuestionNum:     1,
+					Type:            questionType,
+				},
+			}, nil).AnyTimes()
+	}
+	// Option
+	// GetOptions
+	// choice
+	mockOption.EXPECT().
+		GetOptions(gomock.Any(), []int{questionIDSuccess}).
+		Return([]model.Options{
+			{ID: 1, QuestionID: questionIDSuccess, OptionNum: 1, Body: "a"},
+			{ID: 2, QuestionID: questionIDSuccess, OptionNum: 2, Body: "b"},
+		}, nil).AnyTimes()
+	mockOption.EXPECT().
+		GetOptions(gomock.Any(), []int{}).
+		Return([]model.Options{}, nil).AnyTimes()
+	// QuestionCondition
+	// GetQuestionConditions
+	mockQuestionCondition.EXPECT().
+		GetQuestionConditions(gomock.Any(), gomock.Any()).
+		Return([]model.QuestionConditions{}, nil).AnyTimes()
+
 	// Questionnaire
 	// GetQuestionnaireLimit
 	// success
@@ -552,6 +639,57 @@ func TestPostResponse(t *testing.T) {
 	mockQuestionnaire.EXPECT().
 		GetQuestionnaireLimit(gomock.Any(), questionnaireIDLimit).
 		Return(null.TimeFrom(nowTime.Add(-time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDNumber).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDLinearScale).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDChoice).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDCheckbox).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDDate).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	// required
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDRequired).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	// template
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDTemplate).
+		Return(null.NewTime(time.Time{}, false), nil).AnyTimes()
+	// response limits
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDUserLimit).
+		Return(null.NewTime(time.Time{}, false), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDTotalLimit).
+		Return(null.NewTime(time.Time{}, false), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDClosed).
+		Return(null.NewTime(time.Time{}, false), nil).AnyTimes()
+	// CheckQuestionnaireTemplate
+	// template
+	mockQuestionnaire.EXPECT().
+		CheckQuestionnaireTemplate(gomock.Any(), questionnaireIDTemplate).
+		Return(true, nil).AnyTimes()
+	// not template
+	mockQuestionnaire.EXPECT().
+		CheckQuestionnaireTemplate(gomock.Any(), gomock.Any()).
+		Return(false, nil).AnyTimes()
+	// CheckQuestionnaireOpen
+	// closed
+	mockQuestionnaire.EXPECT().
+		CheckQuestionnaireOpen(gomock.Any(), questionnaireIDClosed).
+		Return(false, nil).AnyTimes()
+	// open
+	mockQuestionnaire.EXPECT().
+		CheckQuestionnaireOpen(gomock.Any(), gomock.Any()).
+		Return(true, nil).AnyTimes()
 
 	// Validation
 	// GetValidations
@@ -586,6 +724,10 @@ func TestPostResponse(t *testing.T) {
 	mockValidation.EXPECT().
 		CheckTextValidation(validation, "success case").
 		Return(nil).AnyTimes()
+	// empty
+	mockValidation.EXPECT().
+		CheckTextValidation(validation, "").
+		Return(nil).AnyTimes()
 	// ErrTextMatching
 	mockValidation.EXPECT().
 		CheckTextValidation(validation, "ErrTextMatching").
@@ -595,6 +737,26 @@ func TestPostResponse(t *testing.T) {
 		CheckTextValidation(validation, "InternalServerError").
 		Return(errMock).AnyTimes()
 
+	// CheckSelectionValidation
+	// success
+	mockValidation.EXPECT().
+		CheckSelectionValidation(validation, []string{"a"}).
+		Return(nil).AnyTimes()
+	// ErrSelectionCount
+	mockValidation.EXPECT().
+		CheckSelectionValidation(validation, []string{"a", "b"}).
+		Return(model.ErrSelectionCount).AnyTimes()
+
+	// CheckDateTimeValidation
+	// success
+	mockValidation.EXPECT().
+		CheckDateTimeValidation(validation, "Date", "2021-04-01").
+		Return(nil).AnyTimes()
+	// ErrDateTimeBoundary
+	mockValidation.EXPECT().
+		CheckDateTimeValidation(validation, "Date", "2021-05-01").
+		Return(model.ErrDateTimeBoundary).AnyTimes()
+
 	// ScaleLabel
 	// GetScaleLabels
 	// success
@@ -621,6 +783,19 @@ func TestPostResponse(t *testing.T) {
 		Return(errMock).AnyTimes()
 
 	// Respondent
+	// CheckResponseLimits
+	// user limit
+	mockRespondent.EXPECT().
+		CheckResponseLimits(gomock.Any(), string(userOne), questionnaireIDUserLimit).
+		Return(model.ErrTooManyResponsesPerUser).AnyTimes()
+	// total limit
+	mockRespondent.EXPECT().
+		CheckResponseLimits(gomock.Any(), string(userOne), questionnaireIDTotalLimit).
+		Return(model.ErrTooManyResponses).AnyTimes()
+	// no limit
+	mockRespondent.EXPECT().
+		CheckResponseLimits(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
 	// InsertRespondent
 	// success
 	mockRespondent.EXPECT().
@@ -630,16 +805,56 @@ func TestPostResponse(t *testing.T) {
 	mockRespondent.EXPECT().
 		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDFailure, gomock.Any()).
 		Return(responseIDFailure, nil).AnyTimes()
+	// required
+	mockRespondent.EXPECT().
+		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDRequired, gomock.Any()).
+		Return(responseIDSuccess, nil).AnyTimes()
+	mockRespondent.EXPECT().
+		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDNumber, gomock.Any()).
+		Return(responseIDSuccess, nil).AnyTimes()
+	mockRespondent.EXPECT().
+		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDLinearScale, gomock.Any()).
+		Return(responseIDSuccess, nil).AnyTimes()
+	mockRespondent.EXPECT().
+		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDChoice, gomock.Any()).
+		Return(responseIDSuccess, nil).AnyTimes()
+	mockRespondent.EXPECT().
+		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDCheckbox, gomock.Any()).
+		Return(responseIDSuccess, nil).AnyTimes()
+	mockRespondent.EXPECT().
+		InsertRespondent(gomock.Any(), string(userOne), questionnaireIDDate, gomock.Any()).
+		Return(responseIDSuccess, nil).AnyTimes()
+
+	// ResponseRevision
+	// InsertResponseRevision
+	mockResponseRevision.EXPECT().
+		InsertResponseRevision(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(1, nil).AnyTimes()
+
+	// GetRespondentDetail
+	mockRespondent.EXPECT().
+		GetRespondentDetail(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(model.RespondentDetail{
+			ResponseID:      responseIDSuccess,
+			QuestionnaireID: questionnaireIDSuccess,
+			SubmittedAt:     null.TimeFrom(nowTime),
+		}, nil).AnyTimes()
+
+	// WebhookDelivery
+	// InsertWebhookDeliveries
+	mockWebhookDelivery.EXPECT().
+		InsertWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
 
 	// Response
 	// InsertResponses
 	// success
 	mockResponse.EXPECT().
-		InsertResponses(gomock.Any(), responseIDSuccess, gomock.Any()).
+		InsertResponses(gomock.Any(), responseIDSuccess, gomock.Any(), gomock.Any()).
 		Return(nil).AnyTimes()
 	// failure
 	mockResponse.EXPECT().
-		InsertResponses(gomock.Any(), responseIDFailure, gomock.Any()).
+		InsertResponses(gomock.Any(), responseIDFailure, gomock.Any(), gomock.Any()).
 		Return(errMock).AnyTimes()
 
 	// responseID, err := mockRespondent.
@@ -790,12 +1005,76 @@ func TestPostResponse(t *testing.T) {
 				code:  http.StatusMethodNotAllowed,
 			},
 		},
+		{
+			description: "template",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDTemplate,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body:            []responseBody{},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			description: "closed",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDClosed,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body:            []responseBody{},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			description: "user response limit exceeded",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDUserLimit,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body:            []responseBody{},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusConflict,
+			},
+		},
+		{
+			description: "total response limit exceeded",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDTotalLimit,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body:            []responseBody{},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusConflict,
+			},
+		},
 		{
 			description: "valid number",
 			request: request{
 				user: userOne,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDNumber,
 					Temporarily:     false,
 					Submitted_at:    time.Now(),
 					Body: []responseBody{
@@ -819,7 +1098,7 @@ func TestPostResponse(t *testing.T) {
 			request: request{
 				user: userOne,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDNumber,
 					Temporarily:     false,
 					Submitted_at:    time.Now(),
 					Body: []responseBody{
@@ -842,7 +1121,7 @@ func TestPostResponse(t *testing.T) {
 			request: request{
 				user: userOne,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDNumber,
 					Temporarily:     false,
 					Submitted_at:    time.Now(),
 					Body: []responseBody{
@@ -935,7 +1214,7 @@ func TestPostResponse(t *testing.T) {
 			request: request{
 				user: userOne,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDLinearScale,
 					Submitted_at:    time.Now(),
 					Temporarily:     false,
 					Body: []responseBody{
@@ -959,7 +1238,7 @@ func TestPostResponse(t *testing.T) {
 			request: request{
 				user: userOne,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDLinearScale,
 					Temporarily:     false,
 					Submitted_at:    time.Now(),
 					Body: []responseBody{
@@ -977,6 +1256,310 @@ func TestPostResponse(t *testing.T) {
 				code:  http.StatusBadRequest,
 			},
 		},
+		{
+			description: "required question is answered",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDRequired,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Text",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr:      false,
+				code:       http.StatusCreated,
+				responseID: responseIDSuccess,
+			},
+		},
+		{
+			description: "required question is not answered",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDRequired,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Text",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "required question is not answered but temporarily",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDRequired,
+					Temporarily:     true,
+					Submitted_at:    time.Time{},
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Text",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr:      false,
+				code:       http.StatusCreated,
+				responseID: responseIDSuccess,
+			},
+		},
+		{
+			description: "valid option",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDChoice,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "MultipleChoice",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{"a"},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr:      false,
+				code:       http.StatusCreated,
+				responseID: responseIDSuccess,
+			},
+		},
+		{
+			description: "unknown option",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDChoice,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "MultipleChoice",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{"c"},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "multiple options for MultipleChoice",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDChoice,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "MultipleChoice",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{"a", "b"},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "question type mismatch",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDSuccess,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Number",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "question of another questionnaire",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDSuccess,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess + 1,
+							QuestionType:   "Text",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "valid selections",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDCheckbox,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Checkbox",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{"a"},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr:      false,
+				code:       http.StatusCreated,
+				responseID: responseIDSuccess,
+			},
+		},
+		{
+			description: "too many selections",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDCheckbox,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Checkbox",
+							Body:           null.StringFrom(""),
+							OptionResponse: []string{"a", "b"},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "valid date",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDDate,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Date",
+							Body:           null.StringFrom("2021-04-01"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr:      false,
+				code:       http.StatusCreated,
+				responseID: responseIDSuccess,
+			},
+		},
+		{
+			description: "unparsable date",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDDate,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Date",
+							Body:           null.StringFrom("2021/04/01"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "date out of bounds",
+			request: request{
+				user: userOne,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDDate,
+					Temporarily:     false,
+					Submitted_at:    time.Now(),
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Date",
+							Body:           null.StringFrom("2021-05-01"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
 	}
 
 	e := echo.New()
@@ -1060,9 +1643,16 @@ func TestGetResponse(t *testing.T) {
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockRespondent := mock_model.NewMockIRespondent(ctrl)
 	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockResponseRevision := mock_model.NewMockIResponseRevision(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	r := NewResponse(
 		mockQuestionnaire,
@@ -1070,27 +1660,36 @@ func TestGetResponse(t *testing.T) {
 		mockScaleLabel,
 		mockRespondent,
 		mockResponse,
+		mockQuestion,
+		mockOption,
+		mockQuestionCondition,
+		mockTransaction,
+		mockAudit,
+		mockResponseRevision,
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
 	m := NewMiddleware(
 		mockAdministrator,
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		NewDevAuthenticator("mds_boy"),
 	)
 
 	// Respondent
 	// InsertRespondent
 	// success
 	mockRespondent.EXPECT().
-		GetRespondentDetail(gomock.Any(), responseIDSuccess).
+		GetRespondentDetail(gomock.Any(), responseIDSuccess, gomock.Any()).
 		Return(respondentDetail, nil).AnyTimes()
 	// failure
 	mockRespondent.EXPECT().
-		GetRespondentDetail(gomock.Any(), responseIDFailure).
+		GetRespondentDetail(gomock.Any(), responseIDFailure, gomock.Any()).
 		Return(model.RespondentDetail{}, errMock).AnyTimes()
 	// NotFound
 	mockRespondent.EXPECT().
-		GetRespondentDetail(gomock.Any(), responseIDNotFound).
+		GetRespondentDetail(gomock.Any(), responseIDNotFound, gomock.Any()).
 		Return(model.RespondentDetail{}, model.ErrRecordNotFound).AnyTimes()
 
 	type request struct {
@@ -1203,6 +1802,14 @@ func TestEditResponse(t *testing.T) {
 	responseIDFailure := 0
 
 	questionnaireIDLimit := 2
+	questionnaireIDNumber := 4
+	questionnaireIDLinearScale := 5
+
+	responseIDLimit := 2
+	responseIDNumber := 4
+	responseIDLinearScale := 5
+
+	conflictModifiedAt := nowTime.Add(-time.Hour)
 
 	validation :=
 		model.Validations{
@@ -1228,9 +1835,16 @@ func TestEditResponse(t *testing.T) {
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockRespondent := mock_model.NewMockIRespondent(ctrl)
 	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockResponseRevision := mock_model.NewMockIResponseRevision(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	r := NewResponse(
 		mockQuestionnaire,
@@ -1238,13 +1852,53 @@ func TestEditResponse(t *testing.T) {
 		mockScaleLabel,
 		mockRespondent,
 		mockResponse,
+		mockQuestion,
+		mockOption,
+		mockQuestionCondition,
+		mockTransaction,
+		mockAudit,
+		mockResponseRevision,
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
 	m := NewMiddleware(
 		mockAdministrator,
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		NewDevAuthenticator("mds_boy"),
 	)
+	// Question
+	// GetQuestions
+	questionTypes := map[int]string{
+		questionnaireIDSuccess:     "Text",
+		questionnaireIDNumber:      "Number",
+		questionnaireIDLinearScale: "LinearScale",
+	}
+	for questionnaireID, questionType := range questionTypes {
+		mockQuestion.EXPECT().
+			GetQuestions(gomock.Any(), questionnaireID).
+			Return([]model.Questions{
+				{
+					ID:              questionIDSuccess,
+					QuestionnaireID: questionnaireID,
+					PageNum:         1,
+					QuestionNum:     1,
+					Type:            questionType,
+				},
+			}, nil).AnyTimes()
+	}
+	// Option
+	// GetOptions
+	mockOption.EXPECT().
+		GetOptions(gomock.Any(), []int{}).
+		Return([]model.Options{}, nil).AnyTimes()
+	// QuestionCondition
+	// GetQuestionConditions
+	mockQuestionCondition.EXPECT().
+		GetQuestionConditions(gomock.Any(), gomock.Any()).
+		Return([]model.QuestionConditions{}, nil).AnyTimes()
+
 	// Questionnaire
 	// GetQuestionnaireLimit
 	// success
@@ -1259,6 +1913,16 @@ func TestEditResponse(t *testing.T) {
 	mockQuestionnaire.EXPECT().
 		GetQuestionnaireLimit(gomock.Any(), questionnaireIDLimit).
 		Return(null.TimeFrom(nowTime.Add(-time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDNumber).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	mockQuestionnaire.EXPECT().
+		GetQuestionnaireLimit(gomock.Any(), questionnaireIDLinearScale).
+		Return(null.TimeFrom(nowTime.Add(time.Minute)), nil).AnyTimes()
+	// CheckQuestionnaireOpen
+	mockQuestionnaire.EXPECT().
+		CheckQuestionnaireOpen(gomock.Any(), gomock.Any()).
+		Return(true, nil).AnyTimes()
 
 	// Validation
 	// GetValidations
@@ -1342,22 +2006,65 @@ func TestEditResponse(t *testing.T) {
 	mockRespondent.EXPECT().
 		UpdateSubmittedAt(gomock.Any(), gomock.Any()).
 		Return(nil).AnyTimes()
+	// UpdateRespondentModifiedAt
+	// conflict
+	mockRespondent.EXPECT().
+		UpdateRespondentModifiedAt(gomock.Any(), responseIDSuccess, null.TimeFrom(time.UnixMicro(conflictModifiedAt.UnixMicro()))).
+		Return(time.Time{}, model.ErrConflict).AnyTimes()
+	// success
+	mockRespondent.EXPECT().
+		UpdateRespondentModifiedAt(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nowTime, nil).AnyTimes()
+
+	// ResponseRevision
+	// InsertResponseRevision
+	mockResponseRevision.EXPECT().
+		InsertResponseRevision(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(1, nil).AnyTimes()
+
+	// GetRespondentDetail
+	respondentQuestionnaireIDs := map[int]int{
+		responseIDSuccess:     questionnaireIDSuccess,
+		responseIDFailure:     questionnaireIDSuccess,
+		responseIDLimit:       questionnaireIDLimit,
+		responseIDNumber:      questionnaireIDNumber,
+		responseIDLinearScale: questionnaireIDLinearScale,
+	}
+	for responseID, questionnaireID := range respondentQuestionnaireIDs {
+		mockRespondent.EXPECT().
+			GetRespondentDetail(gomock.Any(), responseID, gomock.Any()).
+			Return(model.RespondentDetail{
+				ResponseID:      responseID,
+				QuestionnaireID: questionnaireID,
+				SubmittedAt:     null.TimeFrom(nowTime),
+			}, nil).AnyTimes()
+	}
+
+	// WebhookDelivery
+	// InsertWebhookDeliveries
+	mockWebhookDelivery.EXPECT().
+		InsertWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
 
 	// Response
 	// InsertResponses
 	// success
-	mockResponse.EXPECT().
-		InsertResponses(gomock.Any(), responseIDSuccess, gomock.Any()).
-		Return(nil).AnyTimes()
+	for _, responseID := range []int{responseIDSuccess, responseIDNumber, responseIDLinearScale} {
+		mockResponse.EXPECT().
+			InsertResponses(gomock.Any(), responseID, gomock.Any(), gomock.Any()).
+			Return(nil).AnyTimes()
+	}
 	// failure
 	mockResponse.EXPECT().
-		InsertResponses(gomock.Any(), responseIDFailure, gomock.Any()).
+		InsertResponses(gomock.Any(), responseIDFailure, gomock.Any(), gomock.Any()).
 		Return(errMock).AnyTimes()
 	// DeleteResponse
 	// success
-	mockResponse.EXPECT().
-		DeleteResponse(gomock.Any(), responseIDSuccess).
-		Return(nil).AnyTimes()
+	for _, responseID := range []int{responseIDSuccess, responseIDNumber, responseIDLinearScale} {
+		mockResponse.EXPECT().
+			DeleteResponse(gomock.Any(), responseID).
+			Return(nil).AnyTimes()
+	}
 	// failure
 	mockResponse.EXPECT().
 		DeleteResponse(gomock.Any(), responseIDFailure).
@@ -1371,6 +2078,7 @@ func TestEditResponse(t *testing.T) {
 	type request struct {
 		user             users
 		responseID       int
+		ifMatch          string
 		isBadRequestBody bool
 		requestBody      responseRequestBody
 	}
@@ -1385,10 +2093,34 @@ func TestEditResponse(t *testing.T) {
 	}
 	testCases := []test{
 		{
-			description: "success",
+			description: "success",
+			request: request{
+				user:       userOne,
+				responseID: responseIDSuccess,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDSuccess,
+					Temporarily:     false,
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Text",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: false,
+				code:  http.StatusOK,
+			},
+		},
+		{
+			description: "If-Match matches",
 			request: request{
 				user:       userOne,
 				responseID: responseIDSuccess,
+				ifMatch:    formatETag(nowTime),
 				requestBody: responseRequestBody{
 					QuestionnaireID: questionnaireIDSuccess,
 					Temporarily:     false,
@@ -1407,6 +2139,47 @@ func TestEditResponse(t *testing.T) {
 				code:  http.StatusOK,
 			},
 		},
+		{
+			description: "stale If-Match",
+			request: request{
+				user:       userOne,
+				responseID: responseIDSuccess,
+				ifMatch:    formatETag(conflictModifiedAt),
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDSuccess,
+					Temporarily:     false,
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Text",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusPreconditionFailed,
+			},
+		},
+		{
+			description: "invalid If-Match",
+			request: request{
+				user:       userOne,
+				responseID: responseIDSuccess,
+				ifMatch:    "invalid",
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDSuccess,
+					Temporarily:     false,
+					Body:            []responseBody{},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusPreconditionFailed,
+			},
+		},
 		{
 			description: "true Temporarily",
 			request: request{
@@ -1461,7 +2234,7 @@ func TestEditResponse(t *testing.T) {
 			description: "limit exceeded",
 			request: request{
 				user:       userOne,
-				responseID: responseIDSuccess,
+				responseID: responseIDLimit,
 				requestBody: responseRequestBody{
 					QuestionnaireID: questionnaireIDLimit,
 					Temporarily:     false,
@@ -1477,9 +2250,9 @@ func TestEditResponse(t *testing.T) {
 			description: "valid number",
 			request: request{
 				user:       userOne,
-				responseID: responseIDSuccess,
+				responseID: responseIDNumber,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDNumber,
 					Temporarily:     false,
 					Body: []responseBody{
 						{
@@ -1500,9 +2273,9 @@ func TestEditResponse(t *testing.T) {
 			description: "invalid number",
 			request: request{
 				user:       userOne,
-				responseID: responseIDSuccess,
+				responseID: responseIDNumber,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDNumber,
 					Temporarily:     false,
 					Body: []responseBody{
 						{
@@ -1523,9 +2296,9 @@ func TestEditResponse(t *testing.T) {
 			description: "BadRequest number",
 			request: request{
 				user:       userOne,
-				responseID: responseIDSuccess,
+				responseID: responseIDNumber,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDNumber,
 					Temporarily:     false,
 					Body: []responseBody{
 						{
@@ -1615,9 +2388,9 @@ func TestEditResponse(t *testing.T) {
 			description: "valid LinearScale",
 			request: request{
 				user:       userOne,
-				responseID: responseIDSuccess,
+				responseID: responseIDLinearScale,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDLinearScale,
 					Temporarily:     false,
 					Body: []responseBody{
 						{
@@ -1638,9 +2411,9 @@ func TestEditResponse(t *testing.T) {
 			description: "invalid LinearScale",
 			request: request{
 				user:       userOne,
-				responseID: responseIDSuccess,
+				responseID: responseIDLinearScale,
 				requestBody: responseRequestBody{
-					QuestionnaireID: questionnaireIDSuccess,
+					QuestionnaireID: questionnaireIDLinearScale,
 					Temporarily:     false,
 					Body: []responseBody{
 						{
@@ -1657,6 +2430,52 @@ func TestEditResponse(t *testing.T) {
 				code:  http.StatusBadRequest,
 			},
 		},
+		{
+			description: "questionnaireID does not match the response",
+			request: request{
+				user:       userOne,
+				responseID: responseIDSuccess,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDNumber,
+					Temporarily:     false,
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Number",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
+		{
+			description: "answer to an expired questionnaire sent with the ID of an open questionnaire",
+			request: request{
+				user:       userOne,
+				responseID: responseIDLimit,
+				requestBody: responseRequestBody{
+					QuestionnaireID: questionnaireIDSuccess,
+					Temporarily:     false,
+					Body: []responseBody{
+						{
+							QuestionID:     questionIDSuccess,
+							QuestionType:   "Text",
+							Body:           null.StringFrom("success case"),
+							OptionResponse: []string{},
+						},
+					},
+				},
+			},
+			expect: expect{
+				isErr: true,
+				code:  http.StatusBadRequest,
+			},
+		},
 		{
 			description: "response does not exist",
 			request: request{
@@ -1703,7 +2522,14 @@ func TestEditResponse(t *testing.T) {
 		if testCase.request.isBadRequestBody {
 			requestStr = "badRequestBody"
 		}
-		rec := createRecorder(e, testCase.request.user, methodPatch, makePath(fmt.Sprint("/responses/", testCase.request.responseID)), typeJSON, requestStr)
+		req := httptest.NewRequest(http.MethodPatch, makePath(fmt.Sprint("/responses/", testCase.request.responseID)), strings.NewReader(requestStr))
+		req.Header.Set(echo.HeaderContentType, string(typeJSON))
+		req.Header.Set(userHeader, string(testCase.request.user))
+		if len(testCase.request.ifMatch) != 0 {
+			req.Header.Set("If-Match", testCase.request.ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
 
 		assertion.Equal(testCase.expect.code, rec.Code, testCase.description, "status code")
 	}
@@ -1721,6 +2547,13 @@ func TestDeleteResponse(t *testing.T) {
 	mockScaleLabel := mock_model.NewMockIScaleLabel(ctrl)
 	mockRespondent := mock_model.NewMockIRespondent(ctrl)
 	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+	mockQuestionCondition := mock_model.NewMockIQuestionCondition(ctrl)
+	mockTransaction := &model.MockTransaction{}
+	mockAudit := mock_model.NewMockIAudit(ctrl)
+	mockResponseRevision := mock_model.NewMockIResponseRevision(ctrl)
+	mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
 
 	r := NewResponse(
 		mockQuestionnaire,
@@ -1728,14 +2561,27 @@ func TestDeleteResponse(t *testing.T) {
 		mockScaleLabel,
 		mockRespondent,
 		mockResponse,
+		mockQuestion,
+		mockOption,
+		mockQuestionCondition,
+		mockTransaction,
+		mockAudit,
+		mockResponseRevision,
+		NewWebhookEventPublisher(mockWebhookDelivery),
 	)
 
 	type request struct {
-		QuestionnaireLimit         null.Time
-		GetQuestionnaireLimitError error
-		ExecutesDeletion           bool
-		DeleteRespondentError      error
-		DeleteResponseError        error
+		QuestionnaireLimit               null.Time
+		GetQuestionnaireLimitError       error
+		ExecutesDeletion                 bool
+		GetRespondentDetailError         error
+		DeleteRespondentError            error
+		DeleteResponseError              error
+		IsAnonymous                      bool
+		CheckQuestionnaireAnonymousError error
+		InsertAuditEventError            error
+		IsSubmitted                      bool
+		InsertWebhookDeliveriesError     error
 	}
 	type expect struct {
 		statusCode int
@@ -1838,11 +2684,79 @@ func TestDeleteResponse(t *testing.T) {
 				statusCode: http.StatusInternalServerError,
 			},
 		},
+		{
+			description: "GetRespondentDetailがエラーRecordNotFoundを吐くので404",
+			request: request{
+				QuestionnaireLimit:       null.NewTime(time.Time{}, false),
+				ExecutesDeletion:         true,
+				GetRespondentDetailError: model.ErrRecordNotFound,
+			},
+			expect: expect{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "InsertAuditEventがエラーを吐くので500",
+			request: request{
+				QuestionnaireLimit:    null.NewTime(time.Time{}, false),
+				ExecutesDeletion:      true,
+				InsertAuditEventError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "匿名のアンケートの回答でも200",
+			request: request{
+				QuestionnaireLimit: null.NewTime(time.Time{}, false),
+				ExecutesDeletion:   true,
+				IsAnonymous:        true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "提出済みの回答ならWebhookへの送信を追加して200",
+			request: request{
+				QuestionnaireLimit: null.NewTime(time.Time{}, false),
+				ExecutesDeletion:   true,
+				IsSubmitted:        true,
+			},
+			expect: expect{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "InsertWebhookDeliveriesがエラーを吐くので500",
+			request: request{
+				QuestionnaireLimit:           null.NewTime(time.Time{}, false),
+				ExecutesDeletion:             true,
+				IsSubmitted:                  true,
+				InsertWebhookDeliveriesError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "CheckQuestionnaireAnonymousがエラーを吐くので500",
+			request: request{
+				QuestionnaireLimit:               null.NewTime(time.Time{}, false),
+				ExecutesDeletion:                 true,
+				CheckQuestionnaireAnonymousError: errors.New("error"),
+			},
+			expect: expect{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
 		userID := "userID1"
 		responseID := 1
+		questionnaireID := 1
 
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/responses/%d", responseID), nil)
@@ -1859,6 +2773,16 @@ func TestDeleteResponse(t *testing.T) {
 			GetQuestionnaireLimitByResponseID(gomock.Any(), responseID).
 			Return(testCase.request.QuestionnaireLimit, testCase.request.GetQuestionnaireLimitError)
 		if testCase.request.ExecutesDeletion {
+			submittedAt := null.NewTime(time.Time{}, false)
+			if testCase.request.IsSubmitted {
+				submittedAt = null.TimeFrom(time.Now())
+			}
+			mockRespondent.
+				EXPECT().
+				GetRespondentDetail(gomock.Any(), responseID, gomock.Any()).
+				Return(model.RespondentDetail{ResponseID: responseID, QuestionnaireID: questionnaireID, SubmittedAt: submittedAt}, testCase.request.GetRespondentDetailError)
+		}
+		if testCase.request.ExecutesDeletion && testCase.request.GetRespondentDetailError == nil {
 			mockRespondent.
 				EXPECT().
 				DeleteRespondent(gomock.Any(), responseID).
@@ -1869,6 +2793,30 @@ func TestDeleteResponse(t *testing.T) {
 					DeleteResponse(c.Request().Context(), responseID).
 					Return(testCase.request.DeleteResponseError)
 			}
+			if testCase.request.DeleteRespondentError == nil && testCase.request.DeleteResponseError == nil {
+				mockQuestionnaire.
+					EXPECT().
+					CheckQuestionnaireAnonymous(gomock.Any(), questionnaireID).
+					Return(testCase.request.IsAnonymous, testCase.request.CheckQuestionnaireAnonymousError)
+			}
+			if testCase.request.DeleteRespondentError == nil && testCase.request.DeleteResponseError == nil && testCase.request.CheckQuestionnaireAnonymousError == nil {
+				// 匿名のアンケートでは削除した人を記録しない
+				actor := userID
+				if testCase.request.IsAnonymous {
+					actor = ""
+				}
+				mockAudit.
+					EXPECT().
+					InsertAuditEvent(gomock.Any(), actor, questionnaireID, model.AuditTargetResponse, responseID, model.AuditActionDelete, gomock.Any(), nil).
+					Return(testCase.request.InsertAuditEventError)
+			}
+			if testCase.request.InsertAuditEventError == nil && testCase.request.IsSubmitted {
+				// 提出前の回答の削除は外部に送信しない
+				mockWebhookDelivery.
+					EXPECT().
+					InsertWebhookDeliveries(gomock.Any(), questionnaireID, model.WebhookEventResponseDeleted, gomock.Any()).
+					Return(testCase.request.InsertWebhookDeliveriesError)
+			}
 		}
 
 		e.HTTPErrorHandler(r.DeleteResponse(c), c)
@@ -1876,3 +2824,280 @@ func TestDeleteResponse(t *testing.T) {
 		assertion.Equal(testCase.expect.statusCode, rec.Code, testCase.description, "status code")
 	}
 }
+
+func TestGetResponseRevisions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResponseRevision := mock_model.NewMockIResponseRevision(ctrl)
+
+	r := NewResponse(nil, nil, nil, nil, nil, nil, nil, nil, &model.MockTransaction{}, nil, mockResponseRevision, nil)
+
+	responseRevisions := []model.ResponseRevisions{
+		{
+			ResponseID: 1,
+			Revision:   1,
+			CreatedAt:  time.Now().Add(-time.Hour).Truncate(time.Second),
+		},
+		{
+			ResponseID:  1,
+			Revision:    2,
+			SubmittedAt: null.NewTime(time.Now().Truncate(time.Second), true),
+			CreatedAt:   time.Now().Truncate(time.Second),
+		},
+	}
+
+	type test struct {
+		description               string
+		responseRevisions         []model.ResponseRevisions
+		GetResponseRevisionsError error
+		expectStatusCode          int
+	}
+
+	testCases := []test{
+		{
+			description:       "エラーなしなので200",
+			responseRevisions: responseRevisions,
+			expectStatusCode:  http.StatusOK,
+		},
+		{
+			description:               "GetResponseRevisionsがエラーなので500",
+			GetResponseRevisionsError: errors.New("error"),
+			expectStatusCode:          http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/responses/1/revisions", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set(responseIDKey, 1)
+
+			mockResponseRevision.
+				EXPECT().
+				GetResponseRevisions(c.Request().Context(), 1).
+				Return(testCase.responseRevisions, testCase.GetResponseRevisionsError)
+
+			e.HTTPErrorHandler(r.GetResponseRevisions(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+			if testCase.expectStatusCode != http.StatusOK {
+				return
+			}
+
+			var actualRevisions []model.ResponseRevisions
+			err := json.NewDecoder(rec.Body).Decode(&actualRevisions)
+			require.NoError(t, err)
+			require.Len(t, actualRevisions, len(testCase.responseRevisions))
+			for i, responseRevision := range testCase.responseRevisions {
+				assert.Equal(t, responseRevision.Revision, actualRevisions[i].Revision, "revision")
+				assert.Equal(t, responseRevision.SubmittedAt.Valid, actualRevisions[i].SubmittedAt.Valid, "submitted_at")
+				assert.WithinDuration(t, responseRevision.CreatedAt, actualRevisions[i].CreatedAt, time.Second, "created_at")
+			}
+		})
+	}
+}
+
+func TestGetResponseRevision(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+
+	r := NewResponse(nil, nil, nil, mockRespondent, nil, nil, nil, nil, &model.MockTransaction{}, nil, nil, nil)
+
+	type test struct {
+		description              string
+		revision                 string
+		executesGet              bool
+		GetRespondentDetailError error
+		expectStatusCode         int
+	}
+
+	testCases := []test{
+		{
+			description:      "エラーなしなので200",
+			revision:         "1",
+			executesGet:      true,
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			description:      "revisionが数字でないので400",
+			revision:         "latest",
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:              "版が存在しないので404",
+			revision:                 "3",
+			executesGet:              true,
+			GetRespondentDetailError: model.ErrRecordNotFound,
+			expectStatusCode:         http.StatusNotFound,
+		},
+		{
+			description:              "GetRespondentDetailがエラーなので500",
+			revision:                 "1",
+			executesGet:              true,
+			GetRespondentDetailError: errors.New("error"),
+			expectStatusCode:         http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/responses/1/revisions/%s", testCase.revision), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/responses/:responseID/revisions/:revision")
+			c.SetParamNames("responseID", "revision")
+			c.SetParamValues("1", testCase.revision)
+			c.Set(responseIDKey, 1)
+
+			if testCase.executesGet {
+				revision, _ := strconv.Atoi(testCase.revision)
+				mockRespondent.
+					EXPECT().
+					GetRespondentDetail(c.Request().Context(), 1, null.NewInt(int64(revision), true)).
+					Return(model.RespondentDetail{
+						ResponseID: 1,
+						Revision:   revision,
+					}, testCase.GetRespondentDetailError)
+			}
+
+			e.HTTPErrorHandler(r.GetResponseRevision(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+			if testCase.expectStatusCode != http.StatusOK {
+				return
+			}
+
+			var respondentDetail model.RespondentDetail
+			err := json.NewDecoder(rec.Body).Decode(&respondentDetail)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.revision, strconv.Itoa(respondentDetail.Revision), "revision")
+		})
+	}
+}
+
+func TestGetResponseRevisionDiff(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+
+	r := NewResponse(nil, nil, nil, mockRespondent, nil, nil, nil, nil, &model.MockTransaction{}, nil, nil, nil)
+
+	revisions := map[int64]model.RespondentDetail{
+		1: {
+			ResponseID: 1,
+			Revision:   1,
+			Responses: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("リマインダーBOTを作った話")},
+				{QuestionID: 2, QuestionType: "Checkbox", OptionResponse: []string{"1日目"}},
+				{QuestionID: 3, QuestionType: "Number", Body: null.StringFrom("1")},
+			},
+		},
+		2: {
+			ResponseID: 1,
+			Revision:   2,
+			Responses: []model.ResponseBody{
+				{QuestionID: 1, QuestionType: "Text", Body: null.StringFrom("リマインダーBOTを作った話")},
+				{QuestionID: 2, QuestionType: "Checkbox", OptionResponse: []string{"1日目", "2日目"}},
+				{QuestionID: 3, QuestionType: "Number", Body: null.NewString("", false)},
+			},
+		},
+	}
+	mockRespondent.
+		EXPECT().
+		GetRespondentDetail(gomock.Any(), 1, gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ int, revision null.Int) (model.RespondentDetail, error) {
+			respondentDetail, ok := revisions[revision.Int64]
+			if !ok {
+				return model.RespondentDetail{}, model.ErrRecordNotFound
+			}
+			return respondentDetail, nil
+		}).
+		AnyTimes()
+
+	type test struct {
+		description      string
+		query            string
+		expectStatusCode int
+		expectDiff       []ResponseRevisionDiff
+	}
+
+	testCases := []test{
+		{
+			description:      "変わった質問のみ返す",
+			query:            "from=1&to=2",
+			expectStatusCode: http.StatusOK,
+			expectDiff: []ResponseRevisionDiff{
+				{
+					QuestionID:   2,
+					QuestionType: "Checkbox",
+					Before:       ResponseRevisionAnswer{OptionResponse: []string{"1日目"}},
+					After:        ResponseRevisionAnswer{OptionResponse: []string{"1日目", "2日目"}},
+				},
+				{
+					QuestionID:   3,
+					QuestionType: "Number",
+					Before:       ResponseRevisionAnswer{Body: null.StringFrom("1"), OptionResponse: []string{}},
+					After:        ResponseRevisionAnswer{OptionResponse: []string{}},
+				},
+			},
+		},
+		{
+			description:      "同じ版なら差分なし",
+			query:            "from=2&to=2",
+			expectStatusCode: http.StatusOK,
+			expectDiff:       []ResponseRevisionDiff{},
+		},
+		{
+			description:      "fromがないので400",
+			query:            "to=2",
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "toが数字でないので400",
+			query:            "from=1&to=latest",
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "版が存在しないので404",
+			query:            "from=1&to=3",
+			expectStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/responses/1/revisions/diff?"+testCase.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set(responseIDKey, 1)
+
+			e.HTTPErrorHandler(r.GetResponseRevisionDiff(c), c)
+
+			assert.Equal(t, testCase.expectStatusCode, rec.Code, "status code")
+			if testCase.expectStatusCode != http.StatusOK {
+				return
+			}
+
+			var actual struct {
+				Diff []ResponseRevisionDiff `json:"diff"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&actual)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectDiff, actual.Diff, "diff")
+		})
+	}
+}
@@ -1,11 +1,18 @@
 package router
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/traPtitech/anke-to/model"
+	"github.com/xuri/excelize/v2"
 )
 
 // Result Resultの構造体
@@ -13,14 +20,20 @@ type Result struct {
 	model.IRespondent
 	model.IQuestionnaire
 	model.IAdministrator
+	model.IResponse
+	model.IQuestion
+	model.IOption
 }
 
 // NewResult Resultのコンストラクタ
-func NewResult(respondent model.IRespondent, questionnaire model.IQuestionnaire, administrator model.IAdministrator) *Result {
+func NewResult(respondent model.IRespondent, questionnaire model.IQuestionnaire, administrator model.IAdministrator, response model.IResponse, question model.IQuestion, option model.IOption) *Result {
 	return &Result{
 		IRespondent:    respondent,
 		IQuestionnaire: questionnaire,
 		IAdministrator: administrator,
+		IResponse:      response,
+		IQuestion:      question,
+		IOption:        option,
 	}
 }
 
@@ -41,3 +54,361 @@ func (r *Result) GetResults(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, respondentDetails)
 }
+
+// GetResultStatistics GET /results/:questionnaireID/statistics
+func (r *Result) GetResultStatistics(c echo.Context) error {
+	questionnaireID, err := strconv.Atoi(c.Param("questionnaireID"))
+	if err != nil {
+		c.Logger().Infof("failed to convert questionnaireID to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	respondentCount, err := r.GetRespondentCount(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get respondent count: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	statistics, err := r.GetQuestionStatistics(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get question statistics: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"questionnaireID":  questionnaireID,
+		"respondent_count": respondentCount,
+		"questions":        statistics,
+	})
+}
+
+type ExportResultsQueryParam struct {
+	Format    string `validate:"required,oneof=csv xlsx"`
+	Checkbox  string `validate:"omitempty,oneof=join onehot"`
+	Separator string `validate:"max=10"`
+}
+
+const (
+	defaultCheckboxSeparator = ", "
+	// exportBatchSize 何件の回答ごとに取得してクライアントへ書き出すか
+	exportBatchSize = 100
+)
+
+// ExportResults GET /results/:questionnaireID/export
+func (r *Result) ExportResults(c echo.Context) error {
+	questionnaireID, err := strconv.Atoi(c.Param("questionnaireID"))
+	if err != nil {
+		c.Logger().Infof("failed to convert questionnaireID to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	sortParam := c.QueryParam("sort")
+	p := ExportResultsQueryParam{
+		Format:    c.QueryParam("format"),
+		Checkbox:  c.QueryParam("checkbox"),
+		Separator: c.QueryParam("separator"),
+	}
+
+	validate, err := getValidator(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get validator: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	err = validate.StructCtx(c.Request().Context(), p)
+	if err != nil {
+		c.Logger().Infof("failed to validate: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if len(p.Separator) == 0 {
+		p.Separator = defaultCheckboxSeparator
+	}
+
+	questions, err := r.GetQuestions(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get questions: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	sort.SliceStable(questions, func(i, j int) bool {
+		if questions[i].PageNum != questions[j].PageNum {
+			return questions[i].PageNum < questions[j].PageNum
+		}
+		return questions[i].QuestionNum < questions[j].QuestionNum
+	})
+
+	optionMap := map[int][]string{}
+	if p.Checkbox == "onehot" {
+		checkboxIDs := []int{}
+		for _, question := range questions {
+			if questionType, _ := model.GetQuestionTypeInfo(question.Type); questionType.MultipleSelection {
+				checkboxIDs = append(checkboxIDs, question.ID)
+			}
+		}
+
+		options, err := r.GetOptions(c.Request().Context(), checkboxIDs)
+		if err != nil {
+			c.Logger().Errorf("failed to get options: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+		for _, option := range options {
+			optionMap[option.QuestionID] = append(optionMap[option.QuestionID], option.Body)
+		}
+	}
+
+	// 全回答の詳細をまとめて取得するとメモリを圧迫するので、並び順だけ先に取得して一定数ずつ取得する
+	responseIDs, err := r.GetSortedResponseIDs(c.Request().Context(), questionnaireID, sortParam)
+	if err != nil {
+		c.Logger().Errorf("failed to get response IDs: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	table := newResultTable(questions, optionMap, p.Checkbox == "onehot", p.Separator)
+	forEachBatch := func(f func(respondentDetails []model.RespondentDetail) error) error {
+		for start := 0; start < len(responseIDs); start += exportBatchSize {
+			end := start + exportBatchSize
+			if end > len(responseIDs) {
+				end = len(responseIDs)
+			}
+
+			respondentDetails, err := r.GetRespondentDetailsByResponseIDs(c.Request().Context(), questionnaireID, responseIDs[start:end])
+			if err != nil {
+				return fmt.Errorf("failed to get respondent details: %w", err)
+			}
+
+			err = f(respondentDetails)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	filename := fmt.Sprintf("questionnaire_%d.%s", questionnaireID, p.Format)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	switch p.Format {
+	case "csv":
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=UTF-8")
+		c.Response().WriteHeader(http.StatusOK)
+
+		err = writeResultCSV(c.Response(), table, forEachBatch)
+	case "xlsx":
+		c.Response().Header().Set(echo.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		c.Response().WriteHeader(http.StatusOK)
+
+		err = writeResultXLSX(c.Response(), table, forEachBatch)
+	}
+	if err != nil {
+		// ヘッダーは送信済みなのでステータスコードは変更できない
+		c.Logger().Errorf("failed to write results: %+v", err)
+		return nil
+	}
+
+	return nil
+}
+
+// resultTable 回答を1行1回答者・1列1質問の表に変換するための情報
+type resultTable struct {
+	questions         []model.Questions
+	optionMap         map[int][]string
+	checkboxOneHot    bool
+	checkboxSeparator string
+}
+
+func newResultTable(questions []model.Questions, optionMap map[int][]string, checkboxOneHot bool, checkboxSeparator string) *resultTable {
+	return &resultTable{
+		questions:         questions,
+		optionMap:         optionMap,
+		checkboxOneHot:    checkboxOneHot,
+		checkboxSeparator: checkboxSeparator,
+	}
+}
+
+func (t *resultTable) header() []string {
+	header := []string{"responseID", "traqID", "submitted_at", "modified_at"}
+	for _, question := range t.questions {
+		if questionType, _ := model.GetQuestionTypeInfo(question.Type); questionType.MultipleSelection && t.checkboxOneHot {
+			for _, option := range t.optionMap[question.ID] {
+				header = append(header, escapeFormula(fmt.Sprintf("%s [%s]", question.Body, option)))
+			}
+			continue
+		}
+
+		header = append(header, escapeFormula(question.Body))
+	}
+
+	return header
+}
+
+func (t *resultTable) row(respondentDetail model.RespondentDetail) []string {
+	row := []string{
+		strconv.Itoa(respondentDetail.ResponseID),
+		respondentDetail.TraqID,
+		"",
+		respondentDetail.ModifiedAt.Format(time.RFC3339),
+	}
+	if respondentDetail.SubmittedAt.Valid {
+		row[2] = respondentDetail.SubmittedAt.Time.Format(time.RFC3339)
+	}
+
+	responseBodyMap := make(map[int]model.ResponseBody, len(respondentDetail.Responses))
+	for _, responseBody := range respondentDetail.Responses {
+		responseBodyMap[responseBody.QuestionID] = responseBody
+	}
+
+	for _, question := range t.questions {
+		responseBody := responseBodyMap[question.ID]
+
+		questionType, _ := model.GetQuestionTypeInfo(question.Type)
+		switch {
+		case questionType.MultipleSelection:
+			if t.checkboxOneHot {
+				selected := make(map[string]struct{}, len(responseBody.OptionResponse))
+				for _, option := range responseBody.OptionResponse {
+					selected[option] = struct{}{}
+				}
+				for _, option := range t.optionMap[question.ID] {
+					if _, ok := selected[option]; ok {
+						row = append(row, "1")
+					} else {
+						row = append(row, "0")
+					}
+				}
+				continue
+			}
+
+			row = append(row, escapeFormula(strings.Join(responseBody.OptionResponse, t.checkboxSeparator)))
+		case questionType.HasOptions:
+			row = append(row, escapeFormula(strings.Join(responseBody.OptionResponse, t.checkboxSeparator)))
+		case questionType.IsNumeric:
+			// 負の数は数式ではないので、数値として読めるならそのまま出力する
+			body := responseBody.Body.ValueOrZero()
+			if _, err := strconv.ParseFloat(body, 64); err == nil {
+				row = append(row, body)
+			} else {
+				row = append(row, escapeFormula(body))
+			}
+		default:
+			row = append(row, escapeFormula(responseBody.Body.ValueOrZero()))
+		}
+	}
+
+	return row
+}
+
+// escapeFormula 表計算ソフトで数式として解釈されないように、数式の開始文字で始まるセルの先頭に'をつける
+func escapeFormula(cell string) string {
+	if len(cell) != 0 && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
+
+// respondentDetailBatches 回答の詳細を一定数ずつfに渡す
+type respondentDetailBatches func(f func(respondentDetails []model.RespondentDetail) error) error
+
+func writeResultCSV(w *echo.Response, table *resultTable, forEachBatch respondentDetailBatches) error {
+	// Excelで開いたときに文字化けしないようにBOMをつける
+	_, err := io.WriteString(w, "\ufeff")
+	if err != nil {
+		return fmt.Errorf("failed to write BOM: %w", err)
+	}
+
+	csvWriter := csv.NewWriter(w)
+
+	err = csvWriter.Write(table.header())
+	if err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	err = forEachBatch(func(respondentDetails []model.RespondentDetail) error {
+		for _, respondentDetail := range respondentDetails {
+			err := csvWriter.Write(table.row(respondentDetail))
+			if err != nil {
+				return fmt.Errorf("failed to write row: %w", err)
+			}
+		}
+
+		csvWriter.Flush()
+		err := csvWriter.Error()
+		if err != nil {
+			return fmt.Errorf("failed to flush csv: %w", err)
+		}
+		w.Flush()
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	err = csvWriter.Error()
+	if err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+
+	return nil
+}
+
+func writeResultXLSX(w io.Writer, table *resultTable, forEachBatch respondentDetailBatches) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheetName = "Sheet1"
+	// StreamWriterは行数が多い場合には一時ファイルに書き出すのでメモリを圧迫しない
+	streamWriter, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to create stream writer: %w", err)
+	}
+
+	setRow := func(rowNum int, values []string) error {
+		cell, err := excelize.CoordinatesToCellName(1, rowNum)
+		if err != nil {
+			return fmt.Errorf("failed to get cell name: %w", err)
+		}
+
+		row := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			row = append(row, value)
+		}
+
+		return streamWriter.SetRow(cell, row)
+	}
+
+	err = setRow(1, table.header())
+	if err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	rowNum := 2
+	err = forEachBatch(func(respondentDetails []model.RespondentDetail) error {
+		for _, respondentDetail := range respondentDetails {
+			err := setRow(rowNum, table.row(respondentDetail))
+			if err != nil {
+				return fmt.Errorf("failed to write row: %w", err)
+			}
+			rowNum++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = streamWriter.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to flush stream writer: %w", err)
+	}
+
+	err = f.Write(w)
+	if err != nil {
+		return fmt.Errorf("failed to write xlsx: %w", err)
+	}
+
+	return nil
+}
@@ -1,19 +1,25 @@
 package router
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/traPtitech/anke-to/model"
 	"github.com/traPtitech/anke-to/model/mock_model"
+	"github.com/xuri/excelize/v2"
 	"gopkg.in/guregu/null.v4"
 )
 
@@ -27,8 +33,11 @@ func TestGetResults(t *testing.T) {
 	mockRespondent := mock_model.NewMockIRespondent(ctrl)
 	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
 
-	result := NewResult(mockRespondent, mockQuestionnaire, mockAdministrator)
+	result := NewResult(mockRespondent, mockQuestionnaire, mockAdministrator, mockResponse, mockQuestion, mockOption)
 
 	type request struct {
 		sortParam                 string
@@ -174,3 +183,537 @@ func TestGetResults(t *testing.T) {
 		}
 	}
 }
+
+func TestGetResultStatistics(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+
+	result := NewResult(mockRespondent, mockQuestionnaire, mockAdministrator, mockResponse, mockQuestion, mockOption)
+
+	type request struct {
+		questionnaireIDParam       string
+		questionnaireIDValid       bool
+		questionnaireID            int
+		respondentCount            int
+		getRespondentCountError    error
+		executesGetStatistics      bool
+		statistics                 []model.QuestionStatistics
+		getQuestionStatisticsError error
+	}
+	type response struct {
+		statusCode int
+		body       string
+	}
+	type test struct {
+		description string
+		request
+		response
+	}
+
+	statistics := []model.QuestionStatistics{
+		{
+			QuestionID:    1,
+			QuestionType:  "MultipleChoice",
+			ResponseCount: 2,
+			Options: []model.OptionCount{
+				{Body: "選択肢1", Count: 1, Percentage: 50},
+				{Body: "選択肢2", Count: 1, Percentage: 50},
+			},
+		},
+		{
+			QuestionID:    2,
+			QuestionType:  "LinearScale",
+			ResponseCount: 2,
+			Histogram: []model.ValueCount{
+				{Value: 1, Count: 1},
+				{Value: 3, Count: 1},
+			},
+			Mean:   null.NewFloat(2, true),
+			Median: null.NewFloat(2, true),
+			StdDev: null.NewFloat(1, true),
+		},
+	}
+	sb := strings.Builder{}
+	err := json.NewEncoder(&sb).Encode(map[string]interface{}{
+		"questionnaireID":  1,
+		"respondent_count": 2,
+		"questions":        statistics,
+	})
+	if err != nil {
+		t.Errorf("failed to encode statistics: %v", err)
+		return
+	}
+
+	testCases := []test{
+		{
+			description: "questionnaireIDが数字でないので400",
+			request: request{
+				questionnaireIDParam: "abc",
+			},
+			response: response{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "GetRespondentCountがエラーなので500",
+			request: request{
+				questionnaireIDValid:    true,
+				questionnaireIDParam:    "1",
+				questionnaireID:         1,
+				getRespondentCountError: errMock,
+			},
+			response: response{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "GetQuestionStatisticsがエラーなので500",
+			request: request{
+				questionnaireIDValid:       true,
+				questionnaireIDParam:       "1",
+				questionnaireID:            1,
+				respondentCount:            2,
+				executesGetStatistics:      true,
+				getQuestionStatisticsError: errMock,
+			},
+			response: response{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "集計結果がそのまま帰り200",
+			request: request{
+				questionnaireIDValid:  true,
+				questionnaireIDParam:  "1",
+				questionnaireID:       1,
+				respondentCount:       2,
+				executesGetStatistics: true,
+				statistics:            statistics,
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				body:       sb.String(),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/results/%s/statistics", testCase.request.questionnaireIDParam), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/results/:questionnaireID/statistics")
+		c.SetParamNames("questionnaireID")
+		c.SetParamValues(testCase.request.questionnaireIDParam)
+
+		if testCase.request.questionnaireIDValid {
+			mockRespondent.
+				EXPECT().
+				GetRespondentCount(c.Request().Context(), testCase.request.questionnaireID).
+				Return(testCase.request.respondentCount, testCase.request.getRespondentCountError)
+		}
+		if testCase.request.executesGetStatistics {
+			mockResponse.
+				EXPECT().
+				GetQuestionStatistics(c.Request().Context(), testCase.request.questionnaireID).
+				Return(testCase.request.statistics, testCase.request.getQuestionStatisticsError)
+		}
+
+		e.HTTPErrorHandler(result.GetResultStatistics(c), c)
+		assertion.Equalf(testCase.response.statusCode, rec.Code, testCase.description, "statusCode")
+		if testCase.response.statusCode == http.StatusOK {
+			assertion.Equalf(testCase.response.body, rec.Body.String(), testCase.description, "body")
+		}
+	}
+}
+
+func TestExportResults(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRespondent := mock_model.NewMockIRespondent(ctrl)
+	mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
+	mockResponse := mock_model.NewMockIResponse(ctrl)
+	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockOption := mock_model.NewMockIOption(ctrl)
+
+	result := NewResult(mockRespondent, mockQuestionnaire, mockAdministrator, mockResponse, mockQuestion, mockOption)
+
+	questions := []model.Questions{
+		{ID: 2, PageNum: 2, QuestionNum: 1, Type: "Checkbox", Body: "参加したい日"},
+		{ID: 1, PageNum: 1, QuestionNum: 2, Type: "Text", Body: "発表タイトル"},
+	}
+	options := []model.Options{
+		{QuestionID: 2, Body: "1日目"},
+		{QuestionID: 2, Body: "2日目"},
+	}
+	respondentDetails := []model.RespondentDetail{
+		{
+			ResponseID:      1,
+			TraqID:          "mazrean",
+			QuestionnaireID: 1,
+			SubmittedAt:     null.NewTime(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), true),
+			ModifiedAt:      time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Responses: []model.ResponseBody{
+				{
+					QuestionID:   1,
+					QuestionType: "Text",
+					Body:         null.NewString("リマインダーBOTを作った話", true),
+				},
+				{
+					QuestionID:     2,
+					QuestionType:   "Checkbox",
+					OptionResponse: []string{"1日目", "2日目"},
+				},
+			},
+		},
+	}
+
+	// 数式として解釈される文字で始まる質問と回答
+	formulaQuestions := []model.Questions{
+		{ID: 1, PageNum: 1, QuestionNum: 1, Type: "Text", Body: "=HYPERLINK(\"https://example.com\")"},
+		{ID: 2, PageNum: 1, QuestionNum: 2, Type: "Checkbox", Body: "@参加したい日"},
+		{ID: 3, PageNum: 1, QuestionNum: 3, Type: "Number", Body: "差分"},
+	}
+	formulaRespondentDetail := model.RespondentDetail{
+		ResponseID:      1,
+		TraqID:          "mazrean",
+		QuestionnaireID: 1,
+		SubmittedAt:     null.NewTime(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), true),
+		ModifiedAt:      time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Responses: []model.ResponseBody{
+			{
+				QuestionID:   1,
+				QuestionType: "Text",
+				Body:         null.NewString("+1+1", true),
+			},
+			{
+				QuestionID:     2,
+				QuestionType:   "Checkbox",
+				OptionResponse: []string{"-1日目", "2日目"},
+			},
+			{
+				QuestionID:   3,
+				QuestionType: "Number",
+				Body:         null.NewString("-5", true),
+			},
+		},
+	}
+	formulaRows := [][]string{
+		{"responseID", "traqID", "submitted_at", "modified_at", "'=HYPERLINK(\"https://example.com\")", "'@参加したい日", "差分"},
+		{"1", "mazrean", "2020-01-01T00:00:00Z", "2020-01-01T00:00:00Z", "'+1+1", "'-1日目, 2日目", "-5"},
+	}
+
+	// 一度に取得する件数を超える回答
+	manyResponseIDs := make([]int, 0, exportBatchSize+1)
+	manyRows := [][]string{
+		{"responseID", "traqID", "submitted_at", "modified_at", "発表タイトル", "参加したい日"},
+	}
+	for i := 1; i <= exportBatchSize+1; i++ {
+		manyResponseIDs = append(manyResponseIDs, i)
+		manyRows = append(manyRows, []string{strconv.Itoa(i), "mazrean", "2020-01-01T00:00:00Z", "2020-01-01T00:00:00Z", "リマインダーBOTを作った話", "1日目, 2日目"})
+	}
+
+	type request struct {
+		query                string
+		questionnaireIDParam string
+		questionnaireIDValid bool
+		questionnaireID      int
+		sortParam            string
+		executesGetOptions   bool
+		getQuestionsError    error
+		executesGetDetails   bool
+		responseIDs          []int
+		getResponseIDsError  error
+		questions            []model.Questions
+		respondentDetail     *model.RespondentDetail
+	}
+	type response struct {
+		statusCode int
+		rows       [][]string
+	}
+	type test struct {
+		description string
+		request
+		response
+	}
+
+	testCases := []test{
+		{
+			description: "questionnaireIDが数字でないので400",
+			request: request{
+				query:                "format=csv",
+				questionnaireIDParam: "abc",
+			},
+			response: response{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "formatが不正なので400",
+			request: request{
+				query:                "format=pdf",
+				questionnaireIDParam: "1",
+			},
+			response: response{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "checkboxが不正なので400",
+			request: request{
+				query:                "format=csv&checkbox=split",
+				questionnaireIDParam: "1",
+			},
+			response: response{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "GetQuestionsがエラーなので500",
+			request: request{
+				query:                "format=csv",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				getQuestionsError:    errMock,
+			},
+			response: response{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "GetSortedResponseIDsがエラーなので500",
+			request: request{
+				query:                "format=csv",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetDetails:   true,
+				getResponseIDsError:  errMock,
+			},
+			response: response{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "csvで質問がページ順に並び、Checkboxは結合される",
+			request: request{
+				query:                "format=csv&sort=-traqid",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				sortParam:            "-traqid",
+				executesGetDetails:   true,
+				responseIDs:          []int{1},
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows: [][]string{
+					{"responseID", "traqID", "submitted_at", "modified_at", "発表タイトル", "参加したい日"},
+					{"1", "mazrean", "2020-01-01T00:00:00Z", "2020-01-01T00:00:00Z", "リマインダーBOTを作った話", "1日目, 2日目"},
+				},
+			},
+		},
+		{
+			description: "separatorを指定するとCheckboxはその文字で結合される",
+			request: request{
+				query:                "format=csv&separator=%2F",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetDetails:   true,
+				responseIDs:          []int{1},
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows: [][]string{
+					{"responseID", "traqID", "submitted_at", "modified_at", "発表タイトル", "参加したい日"},
+					{"1", "mazrean", "2020-01-01T00:00:00Z", "2020-01-01T00:00:00Z", "リマインダーBOTを作った話", "1日目/2日目"},
+				},
+			},
+		},
+		{
+			description: "xlsxでCheckboxはone-hotの列になる",
+			request: request{
+				query:                "format=xlsx&checkbox=onehot",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetOptions:   true,
+				executesGetDetails:   true,
+				responseIDs:          []int{1},
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows: [][]string{
+					{"responseID", "traqID", "submitted_at", "modified_at", "発表タイトル", "参加したい日 [1日目]", "参加したい日 [2日目]"},
+					{"1", "mazrean", "2020-01-01T00:00:00Z", "2020-01-01T00:00:00Z", "リマインダーBOTを作った話", "1", "1"},
+				},
+			},
+		},
+		{
+			description: "csvで数式として解釈される文字で始まるセルは'でエスケープされる",
+			request: request{
+				query:                "format=csv",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetDetails:   true,
+				responseIDs:          []int{1},
+				questions:            formulaQuestions,
+				respondentDetail:     &formulaRespondentDetail,
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows:       formulaRows,
+			},
+		},
+		{
+			description: "xlsxで数式として解釈される文字で始まるセルは'でエスケープされる",
+			request: request{
+				query:                "format=xlsx",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetDetails:   true,
+				responseIDs:          []int{1},
+				questions:            formulaQuestions,
+				respondentDetail:     &formulaRespondentDetail,
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows:       formulaRows,
+			},
+		},
+		{
+			description: "csvで回答が多い場合は分けて取得して全て書き出す",
+			request: request{
+				query:                "format=csv",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetDetails:   true,
+				responseIDs:          manyResponseIDs,
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows:       manyRows,
+			},
+		},
+		{
+			description: "xlsxで回答が多い場合は分けて取得して全て書き出す",
+			request: request{
+				query:                "format=xlsx",
+				questionnaireIDParam: "1",
+				questionnaireIDValid: true,
+				questionnaireID:      1,
+				executesGetDetails:   true,
+				responseIDs:          manyResponseIDs,
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				rows:       manyRows,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/results/%s/export?%s", testCase.request.questionnaireIDParam, testCase.request.query), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/results/:questionnaireID/export")
+		c.SetParamNames("questionnaireID")
+		c.SetParamValues(testCase.request.questionnaireIDParam)
+		c.Set(validatorKey, validator.New())
+
+		if testCase.request.questionnaireIDValid {
+			testQuestions := questions
+			if testCase.request.questions != nil {
+				testQuestions = testCase.request.questions
+			}
+			copiedQuestions := make([]model.Questions, len(testQuestions))
+			copy(copiedQuestions, testQuestions)
+			mockQuestion.
+				EXPECT().
+				GetQuestions(c.Request().Context(), testCase.request.questionnaireID).
+				Return(copiedQuestions, testCase.request.getQuestionsError)
+		}
+		if testCase.request.executesGetOptions {
+			mockOption.
+				EXPECT().
+				GetOptions(c.Request().Context(), []int{2}).
+				Return(options, nil)
+		}
+		if testCase.request.executesGetDetails {
+			testRespondentDetail := respondentDetails[0]
+			if testCase.request.respondentDetail != nil {
+				testRespondentDetail = *testCase.request.respondentDetail
+			}
+
+			mockRespondent.
+				EXPECT().
+				GetSortedResponseIDs(c.Request().Context(), testCase.request.questionnaireID, testCase.request.sortParam).
+				Return(testCase.request.responseIDs, testCase.request.getResponseIDsError)
+
+			// 一度に取得する件数ごとに分けて取得する
+			for start := 0; start < len(testCase.request.responseIDs); start += exportBatchSize {
+				end := start + exportBatchSize
+				if end > len(testCase.request.responseIDs) {
+					end = len(testCase.request.responseIDs)
+				}
+
+				mockRespondent.
+					EXPECT().
+					GetRespondentDetailsByResponseIDs(c.Request().Context(), testCase.request.questionnaireID, testCase.request.responseIDs[start:end]).
+					DoAndReturn(func(_ context.Context, _ int, responseIDs []int) ([]model.RespondentDetail, error) {
+						batch := make([]model.RespondentDetail, 0, len(responseIDs))
+						for _, responseID := range responseIDs {
+							respondentDetail := testRespondentDetail
+							respondentDetail.ResponseID = responseID
+							batch = append(batch, respondentDetail)
+						}
+
+						return batch, nil
+					})
+			}
+		}
+
+		e.HTTPErrorHandler(result.ExportResults(c), c)
+		assertion.Equalf(testCase.response.statusCode, rec.Code, testCase.description, "statusCode")
+		if testCase.response.statusCode != http.StatusOK {
+			continue
+		}
+
+		var rows [][]string
+		if strings.Contains(testCase.request.query, "format=xlsx") {
+			f, err := excelize.OpenReader(bytes.NewReader(rec.Body.Bytes()))
+			if !assertion.NoError(err, testCase.description, "open xlsx") {
+				continue
+			}
+			rows, err = f.GetRows("Sheet1")
+			assertion.NoError(err, testCase.description, "get rows")
+		} else {
+			body := strings.TrimPrefix(rec.Body.String(), "\ufeff")
+			var err error
+			rows, err = csv.NewReader(strings.NewReader(body)).ReadAll()
+			assertion.NoError(err, testCase.description, "read csv")
+		}
+		assertion.Equalf(testCase.response.rows, rows, testCase.description, "rows")
+	}
+}
@@ -0,0 +1,404 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/traq"
+)
+
+// ReminderMode 回答期限前のリマインドの送り方
+type ReminderMode string
+
+const (
+	// ReminderModeMention 未回答者をメンションした1つのメッセージをチャンネルに送る
+	// 匿名のアンケートではReminderModeDMと同じようにDMを送る
+	ReminderModeMention ReminderMode = "mention"
+	// ReminderModeDM 未回答者それぞれにDMを送る
+	ReminderModeDM ReminderMode = "dm"
+)
+
+// ReminderConfig 回答期限前のリマインドの設定
+type ReminderConfig struct {
+	// Offsets 回答期限のどれだけ前にリマインドするか
+	Offsets []time.Duration
+	Mode    ReminderMode
+}
+
+// Scheduler 時刻に応じてアンケートの告知などを行う構造体
+type Scheduler struct {
+	model.IQuestionnaire
+	model.ITransaction
+	model.IOutbox
+	model.ITarget
+	model.IRespondent
+	model.IReminder
+	model.IQuestion
+	model.IResponse
+	traq.IClient
+	*MessageRenderer
+	*WebhookEventPublisher
+	reminderConfig ReminderConfig
+}
+
+// NewScheduler Schedulerのコンストラクタ
+func NewScheduler(
+	questionnaire model.IQuestionnaire,
+	transaction model.ITransaction,
+	outbox model.IOutbox,
+	target model.ITarget,
+	respondent model.IRespondent,
+	reminder model.IReminder,
+	question model.IQuestion,
+	response model.IResponse,
+	client traq.IClient,
+	messageRenderer *MessageRenderer,
+	webhookEventPublisher *WebhookEventPublisher,
+	reminderConfig ReminderConfig,
+) *Scheduler {
+	return &Scheduler{
+		IQuestionnaire:        questionnaire,
+		ITransaction:          transaction,
+		IOutbox:               outbox,
+		ITarget:               target,
+		IRespondent:           respondent,
+		IReminder:             reminder,
+		IQuestion:             question,
+		IResponse:             response,
+		IClient:               client,
+		MessageRenderer:       messageRenderer,
+		WebhookEventPublisher: webhookEventPublisher,
+		reminderConfig:        reminderConfig,
+	}
+}
+
+// Run intervalごとに予定された処理を実行する
+// ctxがキャンセルされるまで返らない
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.announceOpenedQuestionnaires(ctx)
+		if err != nil {
+			log.Printf("failed to announce opened questionnaires: %+v", err)
+		}
+
+		err = s.remindQuestionnaires(ctx)
+		if err != nil {
+			log.Printf("failed to remind questionnaires: %+v", err)
+		}
+
+		err = s.summarizeClosedQuestionnaires(ctx)
+		if err != nil {
+			log.Printf("failed to summarize closed questionnaires: %+v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// announceOpenedQuestionnaires 回答開始日時を過ぎたアンケートをtraQに告知する
+// 1つのアンケートの告知に失敗しても、他のアンケートの告知は続ける
+func (s *Scheduler) announceOpenedQuestionnaires(ctx context.Context) error {
+	questionnaires, err := s.GetQuestionnairesToAnnounce(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get questionnaires to announce: %w", err)
+	}
+
+	for _, questionnaire := range questionnaires {
+		err = s.announceQuestionnaire(ctx, questionnaire.ID)
+		if err != nil {
+			log.Printf("failed to announce questionnaire(%d): %+v", questionnaire.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// announceQuestionnaire アンケートを告知済みにしてtraQへの告知をoutboxに追加する
+func (s *Scheduler) announceQuestionnaire(ctx context.Context, questionnaireID int) error {
+	err := s.ITransaction.Do(ctx, nil, func(ctx context.Context) error {
+		err := s.UpdateQuestionnaireAnnounced(ctx, questionnaireID)
+		if err != nil {
+			return fmt.Errorf("failed to update questionnaire announced: %w", err)
+		}
+
+		questionnaire, targets, administrators, _, err := s.GetQuestionnaireInfo(ctx, questionnaireID)
+		if err != nil {
+			return fmt.Errorf("failed to get questionnaire info: %w", err)
+		}
+
+		message, err := s.RenderMessage(ctx, model.MessageTemplateKindAnnouncement, MessageData{
+			QuestionnaireID: questionnaire.ID,
+			Title:           questionnaire.Title,
+			Description:     questionnaire.Description,
+			Administrators:  administrators,
+			Targets:         targets,
+			ResTimeLimit:    questionnaire.ResTimeLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render message: %w", err)
+		}
+		_, err = s.InsertOutboxMessage(ctx, message)
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+
+		return nil
+	})
+	// 別の処理で既に告知されている
+	if errors.Is(err, model.ErrNoRecordUpdated) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// remindQuestionnaires 回答期限が近づいたアンケートの未回答者にリマインドする
+// 短いoffsetから処理し、複数のoffsetを同時に過ぎたアンケートには1回だけリマインドする
+// 1つのアンケートのリマインドに失敗しても、他のアンケートのリマインドは続ける
+func (s *Scheduler) remindQuestionnaires(ctx context.Context) error {
+	offsets := make([]time.Duration, len(s.reminderConfig.Offsets))
+	copy(offsets, s.reminderConfig.Offsets)
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	for i, offset := range offsets {
+		questionnaires, err := s.GetQuestionnairesToRemind(ctx, offset)
+		if err != nil {
+			return fmt.Errorf("failed to get questionnaires to remind: %w", err)
+		}
+
+		for _, questionnaire := range questionnaires {
+			err = s.remindQuestionnaire(ctx, questionnaire, offsets[i:])
+			if err != nil {
+				log.Printf("failed to remind questionnaire(%d): %+v", questionnaire.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// remindQuestionnaire アンケートの未回答者へのリマインドをoutboxに追加する
+// offsetsの最初の要素が今回のリマインドで、それより長いoffsetのリマインドも送ったことにする
+func (s *Scheduler) remindQuestionnaire(ctx context.Context, questionnaire model.Questionnaires, offsets []time.Duration) error {
+	targets, err := s.GetTargets(ctx, []int{questionnaire.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+
+	userIDs, err := s.expandTraP(targets)
+	if err != nil {
+		return fmt.Errorf("failed to expand traP: %w", err)
+	}
+
+	nonRespondents, err := s.GetNonRespondentUserIDs(ctx, questionnaire.ID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get non respondents: %w", err)
+	}
+
+	err = s.ITransaction.Do(ctx, nil, func(ctx context.Context) error {
+		for _, offset := range offsets {
+			err := s.InsertReminder(ctx, questionnaire.ID, questionnaire.ResTimeLimit.Time, offset)
+			// 長いoffsetのリマインドは既に送っていてもよい
+			if errors.Is(err, model.ErrNoRecordUpdated) && offset != offsets[0] {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to insert reminder: %w", err)
+			}
+		}
+
+		if len(nonRespondents) == 0 {
+			return nil
+		}
+
+		data := MessageData{
+			QuestionnaireID: questionnaire.ID,
+			Title:           questionnaire.Title,
+			Description:     questionnaire.Description,
+			ResTimeLimit:    questionnaire.ResTimeLimit,
+		}
+
+		// DMでは未回答者をメンションしない
+		// 匿名のアンケートでは未回答者の一覧から回答者がわかってしまうので、メンションの設定でもDMで送る
+		if s.reminderConfig.Mode == ReminderModeDM || questionnaire.IsAnonymous {
+			message, err := s.RenderMessage(ctx, model.MessageTemplateKindReminder, data)
+			if err != nil {
+				return fmt.Errorf("failed to render message: %w", err)
+			}
+			for _, userID := range nonRespondents {
+				_, err := s.InsertOutboxDirectMessage(ctx, userID, message)
+				if err != nil {
+					return fmt.Errorf("failed to insert outbox direct message: %w", err)
+				}
+			}
+
+			return nil
+		}
+
+		data.NonRespondents = nonRespondents
+		message, err := s.RenderMessage(ctx, model.MessageTemplateKindReminder, data)
+		if err != nil {
+			return fmt.Errorf("failed to render message: %w", err)
+		}
+		_, err = s.InsertOutboxMessage(ctx, message)
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+
+		return nil
+	})
+	// 別の処理で既にリマインドされている
+	if errors.Is(err, model.ErrNoRecordUpdated) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// summarizeClosedQuestionnaires 回答期限を過ぎたか締め切られたアンケートの結果をtraQに通知する
+// 1つのアンケートの通知に失敗しても、他のアンケートの通知は続ける
+func (s *Scheduler) summarizeClosedQuestionnaires(ctx context.Context) error {
+	questionnaires, err := s.GetQuestionnairesToSummarize(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get questionnaires to summarize: %w", err)
+	}
+
+	for _, questionnaire := range questionnaires {
+		err = s.summarizeQuestionnaire(ctx, questionnaire)
+		if err != nil {
+			log.Printf("failed to summarize questionnaire(%d): %+v", questionnaire.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// summarizeQuestionnaire アンケートを結果の通知済みにして、回答数などの通知をoutboxに追加する
+// 選択肢ごとの回答数は結果が全体に公開されている場合のみ載せる
+// 外部のWebhookにもquestionnaire.closedとして回答数などを送信する
+func (s *Scheduler) summarizeQuestionnaire(ctx context.Context, questionnaire model.Questionnaires) error {
+	targets, err := s.GetTargets(ctx, []int{questionnaire.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+
+	userIDs, err := s.expandTraP(targets)
+	if err != nil {
+		return fmt.Errorf("failed to expand traP: %w", err)
+	}
+
+	nonRespondents, err := s.GetNonRespondentUserIDs(ctx, questionnaire.ID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get non respondents: %w", err)
+	}
+
+	responseCount, err := s.GetRespondentCount(ctx, questionnaire.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get respondent count: %w", err)
+	}
+
+	data := MessageData{
+		QuestionnaireID:      questionnaire.ID,
+		Title:                questionnaire.Title,
+		Description:          questionnaire.Description,
+		ResTimeLimit:         questionnaire.ResTimeLimit,
+		ResponseCount:        responseCount,
+		TargetCount:          len(userIDs),
+		RespondedTargetCount: len(userIDs) - len(nonRespondents),
+	}
+
+	if questionnaire.ResSharedTo == "public" {
+		questions, err := s.GetQuestions(ctx, questionnaire.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get questions: %w", err)
+		}
+
+		statistics, err := s.GetQuestionStatistics(ctx, questionnaire.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get question statistics: %w", err)
+		}
+
+		data.QuestionSummaries = newQuestionSummaries(questions, statistics)
+	}
+
+	err = s.ITransaction.Do(ctx, nil, func(ctx context.Context) error {
+		err := s.UpdateQuestionnaireSummarized(ctx, questionnaire.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update questionnaire summarized: %w", err)
+		}
+
+		message, err := s.RenderMessage(ctx, model.MessageTemplateKindClosing, data)
+		if err != nil {
+			return fmt.Errorf("failed to render message: %w", err)
+		}
+		_, err = s.InsertOutboxMessage(ctx, message)
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+
+		err = s.PublishWebhookEvent(ctx, questionnaire.ID, model.WebhookEventQuestionnaireClosed, QuestionnaireClosedWebhookData{
+			ResponseCount:        data.ResponseCount,
+			TargetCount:          data.TargetCount,
+			RespondedTargetCount: data.RespondedTargetCount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish webhook event: %w", err)
+		}
+
+		return nil
+	})
+	// 別の処理で既に通知されている
+	if errors.Is(err, model.ErrNoRecordUpdated) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// expandTraP 対象者のtraQIDの一覧を返す
+// 全員を表すtraPはtraQの全てのユーザーに展開する
+func (s *Scheduler) expandTraP(targets []model.Targets) ([]string, error) {
+	userIDs := make([]string, 0, len(targets))
+	added := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		expanded := []string{target.UserTraqid}
+		if target.UserTraqid == "traP" {
+			var err error
+			expanded, err = s.GetUserIDs()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get traQ users: %w", err)
+			}
+		}
+
+		for _, userID := range expanded {
+			if _, ok := added[userID]; ok {
+				continue
+			}
+			added[userID] = struct{}{}
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,609 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+	"github.com/traPtitech/anke-to/traq/mock_traq"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestAnnounceOpenedQuestionnaires(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type test struct {
+		description                      string
+		questionnaires                   []model.Questionnaires
+		getQuestionnairesToAnnounceError error
+		updateQuestionnaireAnnouncedErrs map[int]error
+		insertOutboxMessageErrs          map[int]error
+		isErr                            bool
+	}
+
+	testCases := []test{
+		{
+			description: "告知対象がなくてもエラーなし",
+		},
+		{
+			description: "告知対象をすべて告知する",
+			questionnaires: []model.Questionnaires{
+				{ID: 1},
+				{ID: 2},
+			},
+		},
+		{
+			description: "既に告知済みのアンケートは告知しない",
+			questionnaires: []model.Questionnaires{
+				{ID: 1},
+			},
+			updateQuestionnaireAnnouncedErrs: map[int]error{
+				1: model.ErrNoRecordUpdated,
+			},
+		},
+		{
+			description: "告知に失敗しても他のアンケートは告知する",
+			questionnaires: []model.Questionnaires{
+				{ID: 1},
+				{ID: 2},
+			},
+			insertOutboxMessageErrs: map[int]error{
+				1: errors.New("failed to insert outbox message"),
+			},
+		},
+		{
+			description:                      "GetQuestionnairesToAnnounceがエラーなのでエラー",
+			getQuestionnairesToAnnounceError: errors.New("failed to get questionnaires"),
+			isErr:                            true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+			mockTransaction := &model.MockTransaction{}
+			mockOutbox := mock_model.NewMockIOutbox(ctrl)
+
+			mockTarget := mock_model.NewMockITarget(ctrl)
+			mockRespondent := mock_model.NewMockIRespondent(ctrl)
+			mockReminder := mock_model.NewMockIReminder(ctrl)
+			mockQuestion := mock_model.NewMockIQuestion(ctrl)
+			mockResponse := mock_model.NewMockIResponse(ctrl)
+			mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+			mockClient := mock_traq.NewMockIClient(ctrl)
+			mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+			messageRenderer := NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL})
+
+			s := NewScheduler(mockQuestionnaire, mockTransaction, mockOutbox, mockTarget, mockRespondent, mockReminder, mockQuestion, mockResponse, mockClient, messageRenderer, NewWebhookEventPublisher(mockWebhookDelivery), ReminderConfig{})
+
+			mockQuestionnaire.
+				EXPECT().
+				GetQuestionnairesToAnnounce(gomock.Any()).
+				Return(testCase.questionnaires, testCase.getQuestionnairesToAnnounceError)
+
+			mockMessageTemplate.
+				EXPECT().
+				GetQuestionnaireMessageTemplate(gomock.Any(), gomock.Any(), model.MessageTemplateKindAnnouncement).
+				Return("", model.ErrRecordNotFound).
+				AnyTimes()
+
+			for _, questionnaire := range testCase.questionnaires {
+				updateErr := testCase.updateQuestionnaireAnnouncedErrs[questionnaire.ID]
+				mockQuestionnaire.
+					EXPECT().
+					UpdateQuestionnaireAnnounced(gomock.Any(), questionnaire.ID).
+					Return(updateErr)
+				if updateErr != nil {
+					continue
+				}
+
+				mockQuestionnaire.
+					EXPECT().
+					GetQuestionnaireInfo(gomock.Any(), questionnaire.ID).
+					Return(&model.Questionnaires{ID: questionnaire.ID}, []string{}, []string{"mazrean"}, []string{}, nil)
+				mockOutbox.
+					EXPECT().
+					InsertOutboxMessage(gomock.Any(), gomock.Any()).
+					Return(questionnaire.ID, testCase.insertOutboxMessageErrs[questionnaire.ID])
+			}
+
+			err := s.announceOpenedQuestionnaires(context.Background())
+			if testCase.isErr {
+				assertion.Error(err, testCase.description)
+			} else {
+				assertion.NoError(err, testCase.description)
+			}
+		})
+	}
+}
+
+func TestRemindQuestionnaires(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	resTimeLimit := time.Now().Add(30 * time.Minute)
+	questionnaire := model.Questionnaires{
+		ID:           1,
+		Title:        "title",
+		ResTimeLimit: null.TimeFrom(resTimeLimit),
+	}
+	anonymousQuestionnaire := questionnaire
+	anonymousQuestionnaire.IsAnonymous = true
+
+	type test struct {
+		description string
+		mode        ReminderMode
+		// GetQuestionnairesToRemindで返すアンケートを、offsetごとに指定する
+		questionnaires map[time.Duration][]model.Questionnaires
+		targets        []string
+		traQUsers      []string
+		nonRespondents []string
+		// InsertReminderで返すエラーを、offsetごとに指定する
+		insertReminderErrs             map[time.Duration]error
+		expectOffsets                  []time.Duration
+		expectMessage                  bool
+		expectDMs                      []string
+		getQuestionnairesToRemindError error
+		isErr                          bool
+	}
+
+	testCases := []test{
+		{
+			description: "リマインド対象がなくてもエラーなし",
+			mode:        ReminderModeMention,
+		},
+		{
+			description: "未回答者をメンションしてリマインドする",
+			mode:        ReminderModeMention,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				time.Hour: {questionnaire},
+			},
+			targets:        []string{"mazrean", "ryoha"},
+			nonRespondents: []string{"ryoha"},
+			expectOffsets:  []time.Duration{time.Hour, 24 * time.Hour},
+			expectMessage:  true,
+		},
+		{
+			description: "未回答者それぞれにDMでリマインドする",
+			mode:        ReminderModeDM,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				time.Hour: {questionnaire},
+			},
+			targets:        []string{"mazrean", "ryoha"},
+			nonRespondents: []string{"mazrean", "ryoha"},
+			expectOffsets:  []time.Duration{time.Hour, 24 * time.Hour},
+			expectDMs:      []string{"mazrean", "ryoha"},
+		},
+		{
+			description: "匿名のアンケートなのでメンションの設定でも未回答者それぞれにDMでリマインドする",
+			mode:        ReminderModeMention,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				time.Hour: {anonymousQuestionnaire},
+			},
+			targets:        []string{"mazrean", "ryoha"},
+			nonRespondents: []string{"ryoha"},
+			expectOffsets:  []time.Duration{time.Hour, 24 * time.Hour},
+			expectDMs:      []string{"ryoha"},
+		},
+		{
+			description: "traPはtraQの全てのユーザーに展開する",
+			mode:        ReminderModeMention,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				24 * time.Hour: {questionnaire},
+			},
+			targets:        []string{"traP", "mazrean"},
+			traQUsers:      []string{"mazrean", "ryoha"},
+			nonRespondents: []string{"ryoha"},
+			expectOffsets:  []time.Duration{24 * time.Hour},
+			expectMessage:  true,
+		},
+		{
+			description: "未回答者がいないのでリマインドを送らない",
+			mode:        ReminderModeMention,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				24 * time.Hour: {questionnaire},
+			},
+			targets:        []string{"mazrean"},
+			nonRespondents: []string{},
+			expectOffsets:  []time.Duration{24 * time.Hour},
+		},
+		{
+			description: "既にリマインド済みなのでリマインドを送らない",
+			mode:        ReminderModeMention,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				24 * time.Hour: {questionnaire},
+			},
+			targets:        []string{"mazrean"},
+			nonRespondents: []string{"mazrean"},
+			insertReminderErrs: map[time.Duration]error{
+				24 * time.Hour: model.ErrNoRecordUpdated,
+			},
+			expectOffsets: []time.Duration{24 * time.Hour},
+		},
+		{
+			description: "長いoffsetのリマインドが送信済みでも短いoffsetのリマインドを送る",
+			mode:        ReminderModeMention,
+			questionnaires: map[time.Duration][]model.Questionnaires{
+				time.Hour: {questionnaire},
+			},
+			targets:        []string{"mazrean"},
+			nonRespondents: []string{"mazrean"},
+			insertReminderErrs: map[time.Duration]error{
+				24 * time.Hour: model.ErrNoRecordUpdated,
+			},
+			expectOffsets: []time.Duration{time.Hour, 24 * time.Hour},
+			expectMessage: true,
+		},
+		{
+			description:                    "GetQuestionnairesToRemindがエラーなのでエラー",
+			mode:                           ReminderModeMention,
+			getQuestionnairesToRemindError: errors.New("failed to get questionnaires"),
+			isErr:                          true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+			mockTransaction := &model.MockTransaction{}
+			mockOutbox := mock_model.NewMockIOutbox(ctrl)
+			mockTarget := mock_model.NewMockITarget(ctrl)
+			mockRespondent := mock_model.NewMockIRespondent(ctrl)
+			mockReminder := mock_model.NewMockIReminder(ctrl)
+			mockQuestion := mock_model.NewMockIQuestion(ctrl)
+			mockResponse := mock_model.NewMockIResponse(ctrl)
+			mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+			mockClient := mock_traq.NewMockIClient(ctrl)
+			mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+			messageRenderer := NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL})
+
+			s := NewScheduler(mockQuestionnaire, mockTransaction, mockOutbox, mockTarget, mockRespondent, mockReminder, mockQuestion, mockResponse, mockClient, messageRenderer, NewWebhookEventPublisher(mockWebhookDelivery), ReminderConfig{
+				Offsets: []time.Duration{24 * time.Hour, time.Hour},
+				Mode:    testCase.mode,
+			})
+
+			mockMessageTemplate.
+				EXPECT().
+				GetQuestionnaireMessageTemplate(gomock.Any(), questionnaire.ID, model.MessageTemplateKindReminder).
+				Return("", model.ErrRecordNotFound).
+				AnyTimes()
+
+			if testCase.getQuestionnairesToRemindError != nil {
+				mockQuestionnaire.
+					EXPECT().
+					GetQuestionnairesToRemind(gomock.Any(), time.Hour).
+					Return(nil, testCase.getQuestionnairesToRemindError)
+			} else {
+				for _, offset := range []time.Duration{time.Hour, 24 * time.Hour} {
+					mockQuestionnaire.
+						EXPECT().
+						GetQuestionnairesToRemind(gomock.Any(), offset).
+						Return(testCase.questionnaires[offset], nil)
+				}
+			}
+
+			if len(testCase.expectOffsets) != 0 {
+				targets := make([]model.Targets, 0, len(testCase.targets))
+				for _, target := range testCase.targets {
+					targets = append(targets, model.Targets{QuestionnaireID: questionnaire.ID, UserTraqid: target})
+				}
+				mockTarget.
+					EXPECT().
+					GetTargets(gomock.Any(), []int{questionnaire.ID}).
+					Return(targets, nil)
+				if testCase.traQUsers != nil {
+					mockClient.
+						EXPECT().
+						GetUserIDs().
+						Return(testCase.traQUsers, nil)
+				}
+				mockRespondent.
+					EXPECT().
+					GetNonRespondentUserIDs(gomock.Any(), questionnaire.ID, gomock.Any()).
+					Return(testCase.nonRespondents, nil)
+
+				for _, offset := range testCase.expectOffsets {
+					err := testCase.insertReminderErrs[offset]
+					mockReminder.
+						EXPECT().
+						InsertReminder(gomock.Any(), questionnaire.ID, resTimeLimit, offset).
+						Return(err)
+					// 今回のリマインドが送信済みならそこで終わる
+					if err != nil && offset == testCase.expectOffsets[0] {
+						break
+					}
+				}
+			}
+
+			var message string
+			if testCase.expectMessage {
+				mockOutbox.
+					EXPECT().
+					InsertOutboxMessage(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, m string) (int, error) {
+						message = m
+						return 1, nil
+					})
+			}
+			for i, userID := range testCase.expectDMs {
+				mockOutbox.
+					EXPECT().
+					InsertOutboxDirectMessage(gomock.Any(), userID, gomock.Any()).
+					Return(i+1, nil)
+			}
+
+			err := s.remindQuestionnaires(context.Background())
+			if testCase.isErr {
+				assertion.Error(err, testCase.description)
+				return
+			}
+			assertion.NoError(err, testCase.description)
+
+			if testCase.expectMessage {
+				for _, userID := range testCase.nonRespondents {
+					assertion.Contains(message, "@"+userID, testCase.description, "mention")
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeClosedQuestionnaires(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type test struct {
+		description                       string
+		questionnaires                    []model.Questionnaires
+		targets                           []string
+		traQUsers                         []string
+		nonRespondents                    []string
+		respondentCount                   int
+		questions                         []model.Questions
+		statistics                        []model.QuestionStatistics
+		updateQuestionnaireSummarizedErrs map[int]error
+		insertOutboxMessageErrs           map[int]error
+		getQuestionnairesToSummarizeError error
+		expectContains                    []string
+		expectNotContains                 []string
+		isErr                             bool
+	}
+
+	questions := []model.Questions{
+		{ID: 1, Body: "好きな色"},
+		{ID: 2, Body: "感想"},
+	}
+	statistics := []model.QuestionStatistics{
+		{
+			QuestionID:    1,
+			QuestionType:  "MultipleChoice",
+			ResponseCount: 10,
+			Options: []model.OptionCount{
+				{Body: "赤", Count: 1},
+				{Body: "青", Count: 4},
+				{Body: "緑", Count: 4},
+				{Body: "黄", Count: 1},
+			},
+		},
+		{
+			QuestionID:    2,
+			QuestionType:  "Text",
+			ResponseCount: 10,
+		},
+	}
+
+	testCases := []test{
+		{
+			description: "通知対象がなくてもエラーなし",
+		},
+		{
+			description: "回答数と回答率を通知する",
+			questionnaires: []model.Questionnaires{
+				{ID: 1, Title: "title", ResSharedTo: "administrators"},
+			},
+			targets:         []string{"mazrean", "ryoha", "xxarupakaxx", "kaitoyama"},
+			nonRespondents:  []string{"ryoha"},
+			respondentCount: 5,
+			expectContains: []string{
+				"『[title](https://anke-to.trap.jp/questionnaires/1)』の回答が締め切られました",
+				"5件",
+				"75.0% (3/4人)",
+				"https://anke-to.trap.jp/results/1",
+			},
+			expectNotContains: []string{"好きな色"},
+		},
+		{
+			description: "結果が公開されていれば回答数の多い選択肢を通知する",
+			questionnaires: []model.Questionnaires{
+				{ID: 1, Title: "title", ResSharedTo: "public"},
+			},
+			targets:         []string{"mazrean"},
+			nonRespondents:  []string{},
+			respondentCount: 10,
+			questions:       questions,
+			statistics:      statistics,
+			expectContains: []string{
+				"#### 好きな色\n- 青: 4件\n- 緑: 4件\n- 赤: 1件\n",
+			},
+			expectNotContains: []string{"黄", "感想"},
+		},
+		{
+			description: "対象者がいなければ回答率を通知しない",
+			questionnaires: []model.Questionnaires{
+				{ID: 1, Title: "title", ResSharedTo: "administrators"},
+			},
+			targets:           []string{},
+			nonRespondents:    []string{},
+			respondentCount:   2,
+			expectContains:    []string{"2件"},
+			expectNotContains: []string{"回答率"},
+		},
+		{
+			description: "traPはtraQの全てのユーザーに展開する",
+			questionnaires: []model.Questionnaires{
+				{ID: 1, Title: "title", ResSharedTo: "administrators"},
+			},
+			targets:         []string{"traP"},
+			traQUsers:       []string{"mazrean", "ryoha"},
+			nonRespondents:  []string{"ryoha"},
+			respondentCount: 1,
+			expectContains:  []string{"50.0% (1/2人)"},
+		},
+		{
+			description: "既に通知済みのアンケートは通知しない",
+			questionnaires: []model.Questionnaires{
+				{ID: 1, Title: "title", ResSharedTo: "administrators"},
+			},
+			targets:        []string{"mazrean"},
+			nonRespondents: []string{},
+			updateQuestionnaireSummarizedErrs: map[int]error{
+				1: model.ErrNoRecordUpdated,
+			},
+		},
+		{
+			description: "通知に失敗しても他のアンケートは通知する",
+			questionnaires: []model.Questionnaires{
+				{ID: 1, Title: "title", ResSharedTo: "administrators"},
+				{ID: 2, Title: "title", ResSharedTo: "administrators"},
+			},
+			targets:        []string{"mazrean"},
+			nonRespondents: []string{},
+			insertOutboxMessageErrs: map[int]error{
+				1: errors.New("failed to insert outbox message"),
+			},
+		},
+		{
+			description:                       "GetQuestionnairesToSummarizeがエラーなのでエラー",
+			getQuestionnairesToSummarizeError: errors.New("failed to get questionnaires"),
+			isErr:                             true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuestionnaire := mock_model.NewMockIQuestionnaire(ctrl)
+			mockTransaction := &model.MockTransaction{}
+			mockOutbox := mock_model.NewMockIOutbox(ctrl)
+			mockTarget := mock_model.NewMockITarget(ctrl)
+			mockRespondent := mock_model.NewMockIRespondent(ctrl)
+			mockReminder := mock_model.NewMockIReminder(ctrl)
+			mockQuestion := mock_model.NewMockIQuestion(ctrl)
+			mockResponse := mock_model.NewMockIResponse(ctrl)
+			mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+			mockClient := mock_traq.NewMockIClient(ctrl)
+			mockMessageTemplate := mock_model.NewMockIMessageTemplate(ctrl)
+			messageRenderer := NewMessageRenderer(mockMessageTemplate, MessageConfig{BaseURL: DefaultBaseURL})
+
+			s := NewScheduler(mockQuestionnaire, mockTransaction, mockOutbox, mockTarget, mockRespondent, mockReminder, mockQuestion, mockResponse, mockClient, messageRenderer, NewWebhookEventPublisher(mockWebhookDelivery), ReminderConfig{})
+
+			mockQuestionnaire.
+				EXPECT().
+				GetQuestionnairesToSummarize(gomock.Any()).
+				Return(testCase.questionnaires, testCase.getQuestionnairesToSummarizeError)
+
+			mockMessageTemplate.
+				EXPECT().
+				GetQuestionnaireMessageTemplate(gomock.Any(), gomock.Any(), model.MessageTemplateKindClosing).
+				Return("", model.ErrRecordNotFound).
+				AnyTimes()
+
+			messages := []string{}
+			for _, questionnaire := range testCase.questionnaires {
+				targets := make([]model.Targets, 0, len(testCase.targets))
+				for _, target := range testCase.targets {
+					targets = append(targets, model.Targets{QuestionnaireID: questionnaire.ID, UserTraqid: target})
+				}
+				mockTarget.
+					EXPECT().
+					GetTargets(gomock.Any(), []int{questionnaire.ID}).
+					Return(targets, nil)
+				if testCase.traQUsers != nil {
+					mockClient.
+						EXPECT().
+						GetUserIDs().
+						Return(testCase.traQUsers, nil)
+				}
+				mockRespondent.
+					EXPECT().
+					GetNonRespondentUserIDs(gomock.Any(), questionnaire.ID, gomock.Any()).
+					Return(testCase.nonRespondents, nil)
+				mockRespondent.
+					EXPECT().
+					GetRespondentCount(gomock.Any(), questionnaire.ID).
+					Return(testCase.respondentCount, nil)
+				if questionnaire.ResSharedTo == "public" {
+					mockQuestion.
+						EXPECT().
+						GetQuestions(gomock.Any(), questionnaire.ID).
+						Return(testCase.questions, nil)
+					mockResponse.
+						EXPECT().
+						GetQuestionStatistics(gomock.Any(), questionnaire.ID).
+						Return(testCase.statistics, nil)
+				}
+
+				updateErr := testCase.updateQuestionnaireSummarizedErrs[questionnaire.ID]
+				mockQuestionnaire.
+					EXPECT().
+					UpdateQuestionnaireSummarized(gomock.Any(), questionnaire.ID).
+					Return(updateErr)
+				if updateErr != nil {
+					continue
+				}
+
+				insertErr := testCase.insertOutboxMessageErrs[questionnaire.ID]
+				mockOutbox.
+					EXPECT().
+					InsertOutboxMessage(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, m string) (int, error) {
+						if insertErr == nil {
+							messages = append(messages, m)
+						}
+						return questionnaire.ID, insertErr
+					})
+				if insertErr != nil {
+					continue
+				}
+
+				mockWebhookDelivery.
+					EXPECT().
+					InsertWebhookDeliveries(gomock.Any(), questionnaire.ID, model.WebhookEventQuestionnaireClosed, gomock.Any()).
+					Return(nil)
+			}
+
+			err := s.summarizeClosedQuestionnaires(context.Background())
+			if testCase.isErr {
+				assertion.Error(err, testCase.description)
+				return
+			}
+			assertion.NoError(err, testCase.description)
+
+			for _, message := range messages {
+				for _, expect := range testCase.expectContains {
+					assertion.Contains(message, expect, testCase.description, "contains")
+				}
+				for _, expect := range testCase.expectNotContains {
+					assertion.NotContains(message, expect, testCase.description, "not contains")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/anke-to/model"
+)
+
+// SystemAdmin SystemAdminの構造体
+type SystemAdmin struct {
+	model.ISystemAdmin
+	model.ITransaction
+}
+
+// NewSystemAdmin SystemAdminのコンストラクタ
+func NewSystemAdmin(systemAdmin model.ISystemAdmin, transaction model.ITransaction) *SystemAdmin {
+	return &SystemAdmin{
+		ISystemAdmin: systemAdmin,
+		ITransaction: transaction,
+	}
+}
+
+// GetSystemAdministrators GET /system-admins
+func (sa *SystemAdmin) GetSystemAdministrators(c echo.Context) error {
+	systemAdmins, err := sa.GetSystemAdmins(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("failed to get system admins: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, systemAdmins)
+}
+
+type PostSystemAdministratorsRequest struct {
+	Users []string `json:"users" validate:"required,min=1,dive,required,max=32"`
+}
+
+// PostSystemAdministrators POST /system-admins
+func (sa *SystemAdmin) PostSystemAdministrators(c echo.Context) error {
+	req := PostSystemAdministratorsRequest{}
+	err := c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind PostSystemAdministratorsRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	validate, err := getValidator(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get validator: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	err = validate.StructCtx(c.Request().Context(), req)
+	if err != nil {
+		c.Logger().Infof("failed to validate: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	err = sa.InsertSystemAdmins(c.Request().Context(), req.Users)
+	if err != nil {
+		c.Logger().Errorf("failed to insert system admins: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// DeleteSystemAdministrator DELETE /system-admins/:traQID
+func (sa *SystemAdmin) DeleteSystemAdministrator(c echo.Context) error {
+	traQID := c.Param("traQID")
+
+	err := sa.ITransaction.Do(c.Request().Context(), nil, func(ctx context.Context) error {
+		systemAdmins, err := sa.GetSystemAdmins(ctx)
+		if err != nil {
+			c.Logger().Errorf("failed to get system admins: %+v", err)
+			return err
+		}
+
+		// 全体の管理者がいなくなると誰も管理者を追加できなくなるため、最後の1人は削除できない
+		if len(systemAdmins) == 1 && systemAdmins[0] == traQID {
+			c.Logger().Info("cannot delete the last system admin")
+			return echo.NewHTTPError(http.StatusBadRequest, "cannot delete the last system administrator")
+		}
+
+		err = sa.DeleteSystemAdmin(ctx, traQID)
+		if errors.Is(err, model.ErrNoRecordDeleted) {
+			c.Logger().Infof("system admin not found: %+v", err)
+			return echo.NewHTTPError(http.StatusNotFound, "system administrator not found")
+		}
+		if err != nil {
+			c.Logger().Errorf("failed to delete system admin: %+v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError
+		}
+
+		c.Logger().Errorf("failed to delete system admin: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete system administrator")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
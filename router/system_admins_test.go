@@ -0,0 +1,249 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+)
+
+func TestGetSystemAdministrators(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
+	mockTransaction := &model.MockTransaction{}
+
+	systemAdmin := NewSystemAdmin(mockSystemAdmin, mockTransaction)
+
+	type test struct {
+		description          string
+		systemAdmins         []string
+		getSystemAdminsError error
+		statusCode           int
+		body                 string
+	}
+
+	testCases := []test{
+		{
+			description:          "GetSystemAdminsがエラーなので500",
+			getSystemAdminsError: errMock,
+			statusCode:           http.StatusInternalServerError,
+		},
+		{
+			description:  "管理者の一覧がそのまま帰り200",
+			systemAdmins: []string{"mazrean", "ryoha"},
+			statusCode:   http.StatusOK,
+			body:         "[\"mazrean\",\"ryoha\"]\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/system-admins", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/system-admins")
+
+		mockSystemAdmin.
+			EXPECT().
+			GetSystemAdmins(c.Request().Context()).
+			Return(testCase.systemAdmins, testCase.getSystemAdminsError)
+
+		e.HTTPErrorHandler(systemAdmin.GetSystemAdministrators(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+		if testCase.statusCode == http.StatusOK {
+			assertion.Equalf(testCase.body, rec.Body.String(), testCase.description, "body")
+		}
+	}
+}
+
+func TestPostSystemAdministrators(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
+	mockTransaction := &model.MockTransaction{}
+
+	systemAdmin := NewSystemAdmin(mockSystemAdmin, mockTransaction)
+
+	type test struct {
+		description             string
+		invalidRequest          bool
+		request                 PostSystemAdministratorsRequest
+		executesInsertion       bool
+		insertSystemAdminsError error
+		statusCode              int
+	}
+
+	testCases := []test{
+		{
+			description:    "リクエストの形式が誤っているので400",
+			invalidRequest: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			description: "usersが空なので400",
+			request: PostSystemAdministratorsRequest{
+				Users: []string{},
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			description: "traQIDが長すぎるので400",
+			request: PostSystemAdministratorsRequest{
+				Users: []string{strings.Repeat("a", 33)},
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			description: "InsertSystemAdminsがエラーなので500",
+			request: PostSystemAdministratorsRequest{
+				Users: []string{"mazrean"},
+			},
+			executesInsertion:       true,
+			insertSystemAdminsError: errMock,
+			statusCode:              http.StatusInternalServerError,
+		},
+		{
+			description: "正常に追加できるので201",
+			request: PostSystemAdministratorsRequest{
+				Users: []string{"mazrean", "ryoha"},
+			},
+			executesInsertion: true,
+			statusCode:        http.StatusCreated,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var requestBody []byte
+		if testCase.invalidRequest {
+			requestBody = []byte("invalid")
+		} else {
+			var err error
+			requestBody, err = json.Marshal(testCase.request)
+			if err != nil {
+				t.Errorf("failed to marshal request: %v", err)
+				continue
+			}
+		}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/system-admins", bytes.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/system-admins")
+		c.Set(validatorKey, validator.New())
+
+		if testCase.executesInsertion {
+			mockSystemAdmin.
+				EXPECT().
+				InsertSystemAdmins(c.Request().Context(), testCase.request.Users).
+				Return(testCase.insertSystemAdminsError)
+		}
+
+		e.HTTPErrorHandler(systemAdmin.PostSystemAdministrators(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+	}
+}
+
+func TestDeleteSystemAdministrator(t *testing.T) {
+	t.Parallel()
+	assertion := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
+	mockTransaction := &model.MockTransaction{}
+
+	systemAdmin := NewSystemAdmin(mockSystemAdmin, mockTransaction)
+
+	type test struct {
+		description            string
+		traQID                 string
+		systemAdmins           []string
+		getSystemAdminsError   error
+		executesDeletion       bool
+		deleteSystemAdminError error
+		statusCode             int
+	}
+
+	testCases := []test{
+		{
+			description:          "GetSystemAdminsがエラーなので500",
+			traQID:               "mazrean",
+			getSystemAdminsError: errMock,
+			statusCode:           http.StatusInternalServerError,
+		},
+		{
+			description:  "最後の管理者なので400",
+			traQID:       "mazrean",
+			systemAdmins: []string{"mazrean"},
+			statusCode:   http.StatusBadRequest,
+		},
+		{
+			description:            "管理者でないので404",
+			traQID:                 "temma",
+			systemAdmins:           []string{"mazrean"},
+			executesDeletion:       true,
+			deleteSystemAdminError: model.ErrNoRecordDeleted,
+			statusCode:             http.StatusNotFound,
+		},
+		{
+			description:            "DeleteSystemAdminがエラーなので500",
+			traQID:                 "mazrean",
+			systemAdmins:           []string{"mazrean", "ryoha"},
+			executesDeletion:       true,
+			deleteSystemAdminError: errMock,
+			statusCode:             http.StatusInternalServerError,
+		},
+		{
+			description:      "正常に削除できるので200",
+			traQID:           "mazrean",
+			systemAdmins:     []string{"mazrean", "ryoha"},
+			executesDeletion: true,
+			statusCode:       http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/system-admins/"+testCase.traQID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/system-admins/:traQID")
+		c.SetParamNames("traQID")
+		c.SetParamValues(testCase.traQID)
+
+		mockSystemAdmin.
+			EXPECT().
+			GetSystemAdmins(gomock.Any()).
+			Return(testCase.systemAdmins, testCase.getSystemAdminsError)
+		if testCase.executesDeletion {
+			mockSystemAdmin.
+				EXPECT().
+				DeleteSystemAdmin(gomock.Any(), testCase.traQID).
+				Return(testCase.deleteSystemAdminError)
+		}
+
+		e.HTTPErrorHandler(systemAdmin.DeleteSystemAdministrator(c), c)
+		assertion.Equalf(testCase.statusCode, rec.Code, testCase.description, "statusCode")
+	}
+}
@@ -162,6 +162,7 @@ func TestGetUsersMe(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	u := NewUser(
 		mockRespondent,
@@ -174,6 +175,8 @@ func TestGetUsersMe(t *testing.T) {
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		newTestAuthenticator(),
 	)
 
 	type request struct {
@@ -305,6 +308,7 @@ func TestGetMyResponses(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	u := NewUser(
 		mockRespondent,
@@ -317,6 +321,8 @@ func TestGetMyResponses(t *testing.T) {
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		newTestAuthenticator(),
 	)
 
 	// Respondent
@@ -462,6 +468,7 @@ func TestGetMyResponsesByID(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	u := NewUser(
 		mockRespondent,
@@ -474,6 +481,8 @@ func TestGetMyResponsesByID(t *testing.T) {
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		newTestAuthenticator(),
 	)
 
 	// Respondent
@@ -634,6 +643,7 @@ func TestGetTargetedQuestionnaire(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	u := NewUser(
 		mockRespondent,
@@ -646,6 +656,8 @@ func TestGetTargetedQuestionnaire(t *testing.T) {
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		newTestAuthenticator(),
 	)
 
 	// Questionnaire
@@ -785,6 +797,7 @@ func TestGetTargettedQuestionnairesBytraQID(t *testing.T) {
 	mockAdministrator := mock_model.NewMockIAdministrator(ctrl)
 
 	mockQuestion := mock_model.NewMockIQuestion(ctrl)
+	mockSystemAdmin := mock_model.NewMockISystemAdmin(ctrl)
 
 	u := NewUser(
 		mockRespondent,
@@ -797,6 +810,8 @@ func TestGetTargettedQuestionnairesBytraQID(t *testing.T) {
 		mockRespondent,
 		mockQuestion,
 		mockQuestionnaire,
+		mockSystemAdmin,
+		newTestAuthenticator(),
 	)
 
 	// Questionnaire
@@ -0,0 +1,105 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/webhook"
+)
+
+// WebhookDispatcher 外部のWebhookにイベントを送信する構造体
+// 送信中にしてから送信することと、再送の間隔と回数はoutboxのメッセージと同じで、
+// 送信済みにする前にプロセスが終了すると同じイベントを再送することがある
+type WebhookDispatcher struct {
+	model.IWebhookDelivery
+	webhook.ISender
+}
+
+// NewWebhookDispatcher WebhookDispatcherのコンストラクタ
+func NewWebhookDispatcher(webhookDelivery model.IWebhookDelivery, sender webhook.ISender) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		IWebhookDelivery: webhookDelivery,
+		ISender:          sender,
+	}
+}
+
+// Run intervalごとに送信するイベントを確認して送信する
+// ctxがキャンセルされるまで返らない
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := d.dispatch(ctx)
+		if err != nil {
+			log.Printf("failed to dispatch webhook deliveries: %+v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch 送信時刻を過ぎたイベントを送信中にして送信する
+// 1つのイベントの送信に失敗しても、他のイベントの送信は続ける
+func (d *WebhookDispatcher) dispatch(ctx context.Context) error {
+	deliveries, err := d.ClaimWebhookDeliveriesToDispatch(ctx, outboxBatchSize, time.Now().Add(outboxLeaseDuration))
+	if err != nil {
+		return fmt.Errorf("failed to get webhook deliveries to dispatch: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		err = d.dispatchDelivery(ctx, delivery)
+		if err != nil {
+			log.Printf("failed to dispatch webhook delivery(%d): %+v", delivery.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// dispatchDelivery イベントを送信する
+// 失敗した場合は間隔を空けて再送し、outboxMaxAttempts回失敗したら送信を諦める
+func (d *WebhookDispatcher) dispatchDelivery(ctx context.Context, delivery model.WebhookDeliveries) error {
+	if delivery.Webhook == nil {
+		return fmt.Errorf("webhook(%d) is not loaded", delivery.WebhookID)
+	}
+
+	statusCode, sendErr := d.Send(delivery.Webhook.URL, delivery.Webhook.Secret, delivery.Event, delivery.ID, []byte(delivery.Payload))
+	if sendErr == nil {
+		err := d.UpdateWebhookDeliveryDelivered(ctx, delivery.ID, delivery.ClaimedBy.String, statusCode)
+		if err != nil {
+			return fmt.Errorf("failed to update webhook delivery delivered: %w", err)
+		}
+
+		return nil
+	}
+
+	// レスポンスが返ってこなかった場合はステータスコードを記録しない
+	nullStatusCode := null.NewInt(int64(statusCode), statusCode != 0)
+
+	attempts := delivery.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		err := d.UpdateWebhookDeliveryDead(ctx, delivery.ID, delivery.ClaimedBy.String, sendErr.Error(), nullStatusCode)
+		if err != nil {
+			return fmt.Errorf("failed to update webhook delivery dead: %w", err)
+		}
+
+		return fmt.Errorf("gave up sending webhook after %d attempts: %w", attempts, sendErr)
+	}
+
+	err := d.UpdateWebhookDeliveryRetry(ctx, delivery.ID, delivery.ClaimedBy.String, sendErr.Error(), nullStatusCode, time.Now().Add(outboxRetryDelay(attempts)))
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery retry: %w", err)
+	}
+
+	return fmt.Errorf("failed to send webhook: %w", sendErr)
+}
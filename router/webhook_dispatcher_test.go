@@ -0,0 +1,154 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/model/mock_model"
+	"github.com/traPtitech/anke-to/webhook/mock_webhook"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestDispatchWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	questionnaireWebhook := &model.QuestionnaireWebhooks{
+		ID:     1,
+		URL:    "https://example.com/webhook",
+		Secret: "secret",
+	}
+
+	// 送信中にしたときの値で、送信済みや再送にするときに同じ値を指定する
+	claimedBy := "claim-token"
+
+	type test struct {
+		description      string
+		delivery         model.WebhookDeliveries
+		sendStatusCode   int
+		sendError        error
+		expectStatusCode null.Int
+		expectRetry      bool
+		expectDead       bool
+		expectSend       bool
+	}
+
+	testCases := []test{
+		{
+			description: "送信に成功したので送信済みにする",
+			delivery: model.WebhookDeliveries{
+				ID: 1, WebhookID: 1, Event: model.WebhookEventResponseSubmitted, Payload: "{}", Webhook: questionnaireWebhook,
+			},
+			sendStatusCode: http.StatusNoContent,
+			expectSend:     true,
+		},
+		{
+			description: "エラーのステータスコードが返ってきたので記録して再送する",
+			delivery: model.WebhookDeliveries{
+				ID: 1, WebhookID: 1, Event: model.WebhookEventResponseSubmitted, Payload: "{}", Webhook: questionnaireWebhook,
+			},
+			sendStatusCode:   http.StatusInternalServerError,
+			sendError:        errors.New("unexpected status code"),
+			expectStatusCode: null.IntFrom(http.StatusInternalServerError),
+			expectRetry:      true,
+			expectSend:       true,
+		},
+		{
+			description: "レスポンスが返ってこなかったのでステータスコードを記録せずに再送する",
+			delivery: model.WebhookDeliveries{
+				ID: 1, WebhookID: 1, Event: model.WebhookEventResponseSubmitted, Payload: "{}", Attempts: 2, Webhook: questionnaireWebhook,
+			},
+			sendError:   errors.New("connection refused"),
+			expectRetry: true,
+			expectSend:  true,
+		},
+		{
+			description: "再送の上限に達したので送信を諦める",
+			delivery: model.WebhookDeliveries{
+				ID: 1, WebhookID: 1, Event: model.WebhookEventResponseSubmitted, Payload: "{}", Attempts: outboxMaxAttempts - 1, Webhook: questionnaireWebhook,
+			},
+			sendStatusCode:   http.StatusServiceUnavailable,
+			sendError:        errors.New("unexpected status code"),
+			expectStatusCode: null.IntFrom(http.StatusServiceUnavailable),
+			expectDead:       true,
+			expectSend:       true,
+		},
+		{
+			description: "Webhookが読み込まれていないので送信しない",
+			delivery: model.WebhookDeliveries{
+				ID: 1, WebhookID: 1, Event: model.WebhookEventResponseSubmitted, Payload: "{}",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookDelivery := mock_model.NewMockIWebhookDelivery(ctrl)
+			mockSender := mock_webhook.NewMockISender(ctrl)
+
+			d := NewWebhookDispatcher(mockWebhookDelivery, mockSender)
+
+			delivery := testCase.delivery
+			delivery.Status = model.OutboxStatusSending
+			delivery.ClaimedBy = null.StringFrom(claimedBy)
+
+			var leaseUntil time.Time
+			mockWebhookDelivery.
+				EXPECT().
+				ClaimWebhookDeliveriesToDispatch(gomock.Any(), outboxBatchSize, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ int, t time.Time) ([]model.WebhookDeliveries, error) {
+					leaseUntil = t
+					return []model.WebhookDeliveries{delivery}, nil
+				})
+
+			if testCase.expectSend {
+				mockSender.
+					EXPECT().
+					Send(questionnaireWebhook.URL, questionnaireWebhook.Secret, testCase.delivery.Event, testCase.delivery.ID, []byte(testCase.delivery.Payload)).
+					Return(testCase.sendStatusCode, testCase.sendError)
+			}
+
+			var nextAttemptAt time.Time
+			switch {
+			case testCase.expectRetry:
+				mockWebhookDelivery.
+					EXPECT().
+					UpdateWebhookDeliveryRetry(gomock.Any(), testCase.delivery.ID, claimedBy, testCase.sendError.Error(), testCase.expectStatusCode, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _ string, _ string, _ null.Int, t time.Time) error {
+						nextAttemptAt = t
+						return nil
+					})
+			case testCase.expectDead:
+				mockWebhookDelivery.
+					EXPECT().
+					UpdateWebhookDeliveryDead(gomock.Any(), testCase.delivery.ID, claimedBy, testCase.sendError.Error(), testCase.expectStatusCode).
+					Return(nil)
+			case testCase.expectSend:
+				mockWebhookDelivery.
+					EXPECT().
+					UpdateWebhookDeliveryDelivered(gomock.Any(), testCase.delivery.ID, claimedBy, testCase.sendStatusCode).
+					Return(nil)
+			}
+
+			now := time.Now()
+			err := d.dispatch(context.Background())
+			assertion.NoError(err, testCase.description)
+			assertion.WithinDuration(now.Add(outboxLeaseDuration), leaseUntil, time.Second, testCase.description, "leaseUntil")
+
+			if testCase.expectRetry {
+				expectNextAttemptAt := now.Add(outboxRetryDelay(testCase.delivery.Attempts + 1))
+				assertion.WithinDuration(expectNextAttemptAt, nextAttemptAt, time.Second, testCase.description, "nextAttemptAt")
+			}
+		})
+	}
+}
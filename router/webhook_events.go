@@ -0,0 +1,58 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/traPtitech/anke-to/model"
+)
+
+// WebhookPayload 外部のWebhookに送信するJSON
+type WebhookPayload struct {
+	Event           string      `json:"event"`
+	QuestionnaireID int         `json:"questionnaireID"`
+	OccurredAt      time.Time   `json:"occurred_at"`
+	Data            interface{} `json:"data"`
+}
+
+// QuestionnaireClosedWebhookData questionnaire.closedで送信する値
+type QuestionnaireClosedWebhookData struct {
+	ResponseCount        int `json:"response_count"`
+	TargetCount          int `json:"target_count"`
+	RespondedTargetCount int `json:"responded_target_count"`
+}
+
+// WebhookEventPublisher 外部のWebhookへのイベントの送信を追加する構造体
+type WebhookEventPublisher struct {
+	model.IWebhookDelivery
+}
+
+// NewWebhookEventPublisher WebhookEventPublisherのコンストラクタ
+func NewWebhookEventPublisher(webhookDelivery model.IWebhookDelivery) *WebhookEventPublisher {
+	return &WebhookEventPublisher{
+		IWebhookDelivery: webhookDelivery,
+	}
+}
+
+// PublishWebhookEvent アンケートのWebhookのうちeventを送信するものへの送信を追加する
+// 変更と同じトランザクションで呼び出すと、コミットされたときだけ送信される
+func (p *WebhookEventPublisher) PublishWebhookEvent(ctx context.Context, questionnaireID int, event string, data interface{}) error {
+	payload, err := json.Marshal(WebhookPayload{
+		Event:           event,
+		QuestionnaireID: questionnaireID,
+		OccurredAt:      time.Now(),
+		Data:            data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	err = p.InsertWebhookDeliveries(ctx, questionnaireID, event, string(payload))
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook deliveries: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/anke-to/model"
+	"github.com/traPtitech/anke-to/webhook"
+)
+
+const (
+	// maxWebhooksPerQuestionnaire 1つのアンケートに登録できるWebhookの最大数
+	maxWebhooksPerQuestionnaire = 10
+	// maxWebhookURLLength WebhookのURLの最大の長さ
+	maxWebhookURLLength = 2048
+	// maxWebhookDeliveries 一覧で返すWebhookへの送信の記録の最大数
+	maxWebhookDeliveries = 100
+)
+
+// Webhook Webhookの構造体
+type Webhook struct {
+	model.IWebhook
+	model.IWebhookDelivery
+}
+
+// NewWebhook Webhookのコンストラクタ
+func NewWebhook(webhook model.IWebhook, webhookDelivery model.IWebhookDelivery) *Webhook {
+	return &Webhook{
+		IWebhook:         webhook,
+		IWebhookDelivery: webhookDelivery,
+	}
+}
+
+type PostWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type PostWebhookResponse struct {
+	ID     int      `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret 署名の鍵で、登録したときのみ返す
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetWebhooks GET /questionnaires/:questionnaireID/webhooks
+func (w *Webhook) GetWebhooks(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	webhooks, err := w.GetQuestionnaireWebhooks(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get webhooks: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, webhooks)
+}
+
+// PostWebhook POST /questionnaires/:questionnaireID/webhooks
+func (w *Webhook) PostWebhook(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	req := PostWebhookRequest{}
+	err = c.Bind(&req)
+	if err != nil {
+		c.Logger().Infof("failed to bind PostWebhookRequest: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	err = validateWebhookURL(req.URL)
+	if err != nil {
+		c.Logger().Infof("invalid webhook url: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid url: %w", err))
+	}
+
+	err = validateWebhookEvents(req.Events)
+	if err != nil {
+		c.Logger().Infof("invalid webhook events: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid events: %w", err))
+	}
+
+	webhooks, err := w.GetQuestionnaireWebhooks(c.Request().Context(), questionnaireID)
+	if err != nil {
+		c.Logger().Errorf("failed to get webhooks: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(webhooks) >= maxWebhooksPerQuestionnaire {
+		c.Logger().Infof("too many webhooks: %d", len(webhooks))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("a questionnaire can have at most %d webhooks", maxWebhooksPerQuestionnaire))
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		c.Logger().Errorf("failed to generate webhook secret: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	webhookID, err := w.InsertQuestionnaireWebhook(c.Request().Context(), questionnaireID, req.URL, secret, req.Events)
+	if err != nil {
+		c.Logger().Errorf("failed to insert webhook: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, PostWebhookResponse{
+		ID:        webhookID,
+		URL:       req.URL,
+		Events:    req.Events,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	})
+}
+
+// DeleteWebhook DELETE /questionnaires/:questionnaireID/webhooks/:webhookID
+func (w *Webhook) DeleteWebhook(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	strWebhookID := c.Param("webhookID")
+	webhookID, err := strconv.Atoi(strWebhookID)
+	if err != nil {
+		c.Logger().Infof("failed to convert webhookID to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse webhookID(%s) to integer: %w", strWebhookID, err))
+	}
+
+	err = w.DeleteQuestionnaireWebhook(c.Request().Context(), questionnaireID, webhookID)
+	if errors.Is(err, model.ErrNoRecordDeleted) {
+		c.Logger().Infof("webhook not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to delete webhook: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetWebhookDeliveryLog GET /questionnaires/:questionnaireID/webhooks/:webhookID/deliveries
+func (w *Webhook) GetWebhookDeliveryLog(c echo.Context) error {
+	questionnaireID, err := getQuestionnaireID(c)
+	if err != nil {
+		c.Logger().Errorf("failed to get questionnaireID: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	strWebhookID := c.Param("webhookID")
+	webhookID, err := strconv.Atoi(strWebhookID)
+	if err != nil {
+		c.Logger().Infof("failed to convert webhookID to int: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("failed to parse webhookID(%s) to integer: %w", strWebhookID, err))
+	}
+
+	// 別のアンケートのWebhookの記録は返さない
+	_, err = w.GetQuestionnaireWebhook(c.Request().Context(), questionnaireID, webhookID)
+	if errors.Is(err, model.ErrRecordNotFound) {
+		c.Logger().Infof("webhook not found: %+v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get webhook: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	deliveries, err := w.GetWebhookDeliveries(c.Request().Context(), webhookID, maxWebhookDeliveries)
+	if err != nil {
+		c.Logger().Errorf("failed to get webhook deliveries: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// validateWebhookURL 登録するWebhookのURLの確認
+// 送信内容と署名が盗み見られないように、HTTPSのみ許可する
+// 内部のネットワークのアドレスは送信時にも確認するが、IPアドレスで指定された場合は登録時に拒否する
+func validateWebhookURL(rawURL string) error {
+	if len(rawURL) == 0 {
+		return errors.New("url is empty")
+	}
+	if len(rawURL) > maxWebhookURLLength {
+		return fmt.Errorf("url is longer than %d characters", maxWebhookURLLength)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse url: %w", err)
+	}
+	if u.Scheme != "https" {
+		return errors.New("url must be https")
+	}
+	if len(u.Hostname()) == 0 {
+		return errors.New("url has no host")
+	}
+	if u.User != nil {
+		return errors.New("url must not have user info")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !webhook.IsAllowedIP(ip) {
+		return errors.New("url must not point to an internal address")
+	}
+
+	return nil
+}
+
+// validateWebhookEvents 登録するWebhookで送信するイベントの種類の確認
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return errors.New("events is empty")
+	}
+
+	added := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		isWebhookEvent := false
+		for _, webhookEvent := range model.WebhookEvents {
+			if event == webhookEvent {
+				isWebhookEvent = true
+				break
+			}
+		}
+		if !isWebhookEvent {
+			return fmt.Errorf("unknown event: %s", event)
+		}
+
+		if _, ok := added[event]; ok {
+			return fmt.Errorf("duplicate event: %s", event)
+		}
+		added[event] = struct{}{}
+	}
+
+	return nil
+}
+
+// generateWebhookSecret Webhookの署名の鍵の生成
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return hex.EncodeToString(secret), nil
+}
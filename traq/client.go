@@ -0,0 +1,11 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE -aux_files github.com/traPtitech/anke-to/traq=webhook.go
+
+package traq
+
+// IClient traQのAPIのinterface
+// Webhookでのチャンネルへの投稿に加えて、ユーザーの取得やDMの送信ができる
+type IClient interface {
+	IWebhook
+	GetUserIDs() ([]string, error)
+	PostDirectMessage(userID string, message string) error
+}
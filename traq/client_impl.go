@@ -0,0 +1,142 @@
+package traq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	netUrl "net/url"
+	"os"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Client traQのAPIのクライアントの構造体
+type Client struct {
+	*Webhook
+	apiURL      string
+	accessToken string
+	client      *http.Client
+}
+
+// NewClient Clientのコンストラクター
+// BOTのアクセストークンは環境変数TRAQ_BOT_ACCESS_TOKENから読む
+func NewClient(webhook *Webhook) *Client {
+	return NewClientWithURL(webhook, "https://q.trap.jp/api/v3", os.Getenv("TRAQ_BOT_ACCESS_TOKEN"))
+}
+
+// NewClientWithURL APIのURLとアクセストークンを指定したClientのコンストラクター
+func NewClientWithURL(webhook *Webhook, apiURL string, accessToken string) *Client {
+	return &Client{
+		Webhook:     webhook,
+		apiURL:      apiURL,
+		accessToken: accessToken,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+		},
+	}
+}
+
+// traQUser traQのAPIが返すユーザー
+type traQUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Bot  bool   `json:"bot"`
+}
+
+// GetUserIDs botと凍結されたユーザーを除く全てのユーザーのtraQIDの取得
+func (c *Client) GetUserIDs() ([]string, error) {
+	users, err := c.getUsers(netUrl.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		if user.Bot {
+			continue
+		}
+
+		userIDs = append(userIDs, user.Name)
+	}
+
+	return userIDs, nil
+}
+
+// PostDirectMessage ユーザーへのDMの送信
+func (c *Client) PostDirectMessage(userID string, message string) error {
+	query := netUrl.Values{}
+	query.Add("name", userID)
+	users, err := c.getUsers(query)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("user not found: %s", userID)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"content": message,
+		"embed":   true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	err = c.do(http.MethodPost, "/users/"+users[0].ID+"/messages", nil, bytes.NewReader(body), nil)
+	if err != nil {
+		return fmt.Errorf("failed to post direct message: %w", err)
+	}
+
+	return nil
+}
+
+// getUsers queryで絞り込んだユーザーの取得
+func (c *Client) getUsers(query netUrl.Values) ([]traQUser, error) {
+	users := []traQUser{}
+	err := c.do(http.MethodGet, "/users", query, nil, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// do traQのAPIへのリクエスト
+// resがnilでない場合はレスポンスのJSONを読み込む
+func (c *Client) do(method string, path string, query netUrl.Values, body io.Reader, res interface{}) error {
+	req, err := http.NewRequest(method, c.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+c.accessToken)
+	if body != nil {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		sb := &bytes.Buffer{}
+		_, _ = io.Copy(sb, resp.Body)
+		return fmt.Errorf("unexpected status code(%d): %s", resp.StatusCode, sb.String())
+	}
+
+	if res == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
@@ -11,37 +11,59 @@ import (
 	netUrl "net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// webhookTimeout traQへのリクエストのタイムアウト
+const webhookTimeout = 10 * time.Second
+
 // Webhook Webhookの構造体
-type Webhook struct{}
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
 
 // NewWebhook Webhookのコンストラクター
+// 送信先と署名の鍵は環境変数TRAQ_WEBHOOK_ID,TRAQ_WEBHOOK_SECRETから読む
 func NewWebhook() *Webhook {
-	return new(Webhook)
+	return NewWebhookWithURL(
+		"https://q.trap.jp/api/v3/webhooks/"+os.Getenv("TRAQ_WEBHOOK_ID"),
+		os.Getenv("TRAQ_WEBHOOK_SECRET"),
+	)
+}
+
+// NewWebhookWithURL 送信先のURLと署名の鍵を指定したWebhookのコンストラクター
+func NewWebhookWithURL(url string, secret string) *Webhook {
+	return &Webhook{
+		url:    url,
+		secret: secret,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+		},
+	}
 }
 
 // PostMessage Webhookでのメッセージの投稿
-func (*Webhook) PostMessage(message string) error {
-	url := "https://q.trap.jp/api/v3/webhooks/" + os.Getenv("TRAQ_WEBHOOK_ID")
+// traQが2xx以外のステータスコードを返した場合もエラーを返す
+func (w *Webhook) PostMessage(message string) error {
 	req, err := http.NewRequest("POST",
-		url,
+		w.url,
 		strings.NewReader(message))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
-	req.Header.Set("X-TRAQ-Signature", calcHMACSHA1(message))
+	req.Header.Set("X-TRAQ-Signature", calcHMACSHA1(w.secret, message))
 
 	query := netUrl.Values{}
 	query.Add("embed", "1")
 	req.URL.RawQuery = query.Encode()
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := w.client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -53,13 +75,17 @@ func (*Webhook) PostMessage(message string) error {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	fmt.Printf("Message sent to %s, message: %s, response: %s\n", url, message, sb.String())
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code(%d): %s", resp.StatusCode, sb.String())
+	}
+
+	fmt.Printf("Message sent to %s, message: %s, response: %s\n", w.url, message, sb.String())
 
 	return nil
 }
 
-func calcHMACSHA1(message string) string {
-	mac := hmac.New(sha1.New, []byte(os.Getenv("TRAQ_WEBHOOK_SECRET")))
+func calcHMACSHA1(secret string, message string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
 	_, _ = mac.Write([]byte(message))
 	return hex.EncodeToString(mac.Sum(nil))
 }
@@ -0,0 +1,10 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+
+package webhook
+
+// ISender 外部のWebhookへの送信のinterface
+type ISender interface {
+	// Send payloadを署名してurlに送信し、返ってきたステータスコードを返す
+	// レスポンスが返ってこなかった場合のステータスコードは0
+	Send(url string, secret string, event string, deliveryID int, payload []byte) (int, error)
+}
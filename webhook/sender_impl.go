@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// sendTimeout Webhookへのリクエストのタイムアウト
+	sendTimeout = 10 * time.Second
+	// dialTimeout Webhookへの接続のタイムアウト
+	dialTimeout = 5 * time.Second
+)
+
+// ErrForbiddenAddress 内部のネットワークのアドレスに送信しようとした
+var ErrForbiddenAddress = errors.New("forbidden address")
+
+// 送信するリクエストのヘッダー
+const (
+	// HeaderEvent イベントの種類
+	HeaderEvent = "X-AnkeTo-Event"
+	// HeaderDelivery 送信のID(再送でも変わらないので重複の確認に使える)
+	HeaderDelivery = "X-AnkeTo-Delivery"
+	// HeaderTimestamp 送信した時刻のUNIX時間(秒)
+	HeaderTimestamp = "X-AnkeTo-Timestamp"
+	// HeaderSignature "sha256="に続けて、"{timestamp}.{本文}"のHMAC-SHA256を16進数で表したもの
+	HeaderSignature = "X-AnkeTo-Signature"
+)
+
+// Sender 外部のWebhookに送信する構造体
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender Senderのコンストラクター
+// 内部のネットワークに送信できないように、接続するアドレスを確認する
+func NewSender() *Sender {
+	return newSender(checkDialAddress)
+}
+
+// newSender 接続するアドレスの確認を指定したSenderを作る
+// テストでローカルのサーバーに送信するときはcontrolをnilにする
+func newSender(control func(network string, address string, c syscall.RawConn) error) *Sender {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		// 名前解決した後のアドレスで確認するので、DNS rebindingでも内部のネットワークに接続しない
+		Control: control,
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: sendTimeout,
+			Transport: &http.Transport{
+				// プロキシを経由するとプロキシのアドレスしか確認できないので、使わない
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: dialTimeout,
+			},
+			// 登録時に確認したURL以外に送信しないように、リダイレクトには従わない
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send payloadを署名してurlに送信する
+// 2xx以外のステータスコードが返ってきた場合もエラーを返す
+func (s *Sender) Send(url string, secret string, event string, deliveryID int, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(deliveryID))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+CalcSignature(secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 送信先の内容が送信のログから見えないように、レスポンスの本文は読まない
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code(%d)", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// checkDialAddress 接続するアドレスが内部のネットワークのものでないかの確認
+func checkDialAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("failed to split address(%s): %w", address, err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("address is not an ip(%s): %w", address, ErrForbiddenAddress)
+	}
+	if !IsAllowedIP(ip) {
+		return fmt.Errorf("%s: %w", address, ErrForbiddenAddress)
+	}
+
+	return nil
+}
+
+// IsAllowedIP Webhookを送信してよいアドレスか
+// ループバック、プライベート、リンクローカル、未指定、マルチキャストのアドレスには送信しない
+func IsAllowedIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// CalcSignature 送信するリクエストの署名
+// 受信側で古いリクエストを拒否できるように、送信した時刻も署名に含める
+func CalcSignature(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
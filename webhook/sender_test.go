@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	t.Parallel()
+
+	assertion := assert.New(t)
+
+	type test struct {
+		description      string
+		statusCode       int
+		expectStatusCode int
+		isErr            bool
+	}
+
+	testCases := []test{
+		{
+			description:      "2xxなのでエラーなし",
+			statusCode:       http.StatusNoContent,
+			expectStatusCode: http.StatusNoContent,
+		},
+		{
+			description:      "2xx以外なのでエラー",
+			statusCode:       http.StatusInternalServerError,
+			expectStatusCode: http.StatusInternalServerError,
+			isErr:            true,
+		},
+		{
+			description:      "リダイレクトには従わずエラー",
+			statusCode:       http.StatusFound,
+			expectStatusCode: http.StatusFound,
+			isErr:            true,
+		},
+	}
+
+	payload := []byte(`{"event":"response.submitted"}`)
+	now := time.Unix(1634000000, 0)
+
+	for _, testCase := range testCases {
+		var header http.Header
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, _ = io.ReadAll(r.Body)
+			if testCase.statusCode == http.StatusFound {
+				w.Header().Set("Location", "https://example.com")
+			}
+			w.WriteHeader(testCase.statusCode)
+			_, _ = w.Write([]byte("internal detail"))
+		}))
+
+		// ローカルのサーバーに送信するので、接続するアドレスは確認しない
+		sender := newSender(nil)
+		sender.now = func() time.Time { return now }
+
+		statusCode, err := sender.Send(server.URL, "secret", "response.submitted", 1, payload)
+		server.Close()
+
+		if testCase.isErr {
+			if assertion.Error(err, testCase.description, "error") {
+				assertion.NotContains(err.Error(), "internal detail", testCase.description, "error message")
+			}
+		} else {
+			assertion.NoError(err, testCase.description, "no error")
+		}
+		assertion.Equal(testCase.expectStatusCode, statusCode, testCase.description, "status code")
+
+		assertion.Equal(payload, body, testCase.description, "body")
+		assertion.Equal("response.submitted", header.Get(HeaderEvent), testCase.description, "event")
+		assertion.Equal("1", header.Get(HeaderDelivery), testCase.description, "delivery")
+		assertion.Equal("1634000000", header.Get(HeaderTimestamp), testCase.description, "timestamp")
+		assertion.Equal("sha256="+CalcSignature("secret", "1634000000", payload), header.Get(HeaderSignature), testCase.description, "signature")
+	}
+}
+
+func TestSendToForbiddenAddress(t *testing.T) {
+	t.Parallel()
+
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	statusCode, err := NewSender().Send(server.URL, "secret", "response.submitted", 1, []byte(`{}`))
+
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("invalid error: expected: %+v, actual: %+v", ErrForbiddenAddress, err)
+	}
+	assert.Equal(t, 0, statusCode, "status code")
+	assert.False(t, received, "received")
+}
+
+func TestIsAllowedIP(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		description string
+		ip          string
+		expect      bool
+	}
+
+	testCases := []test{
+		{
+			description: "グローバルなIPv4なのでtrue",
+			ip:          "93.184.216.34",
+			expect:      true,
+		},
+		{
+			description: "グローバルなIPv6なのでtrue",
+			ip:          "2606:2800:220:1:248:1893:25c8:1946",
+			expect:      true,
+		},
+		{
+			description: "ループバックなのでfalse",
+			ip:          "127.0.0.1",
+			expect:      false,
+		},
+		{
+			description: "IPv6のループバックなのでfalse",
+			ip:          "::1",
+			expect:      false,
+		},
+		{
+			description: "プライベートなのでfalse",
+			ip:          "10.0.0.1",
+			expect:      false,
+		},
+		{
+			description: "プライベートなのでfalse",
+			ip:          "192.168.1.1",
+			expect:      false,
+		},
+		{
+			description: "IPv6のユニークローカルなのでfalse",
+			ip:          "fd00::1",
+			expect:      false,
+		},
+		{
+			description: "リンクローカル(クラウドのメタデータ)なのでfalse",
+			ip:          "169.254.169.254",
+			expect:      false,
+		},
+		{
+			description: "未指定なのでfalse",
+			ip:          "0.0.0.0",
+			expect:      false,
+		},
+		{
+			description: "IPv4射影アドレスのループバックなのでfalse",
+			ip:          "::ffff:127.0.0.1",
+			expect:      false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expect, IsAllowedIP(net.ParseIP(testCase.ip)), testCase.description)
+	}
+}
+
+func TestCalcSignature(t *testing.T) {
+	t.Parallel()
+
+	// echo -n '1634000000.{"event":"response.submitted"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"ee2437fb6c9f6a1e617b2ef595b3ef8a00983c58ebf76b47403870805c8d224c",
+		CalcSignature("secret", "1634000000", []byte(`{"event":"response.submitted"}`)),
+	)
+}
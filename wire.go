@@ -8,24 +8,37 @@ import (
 	"github.com/traPtitech/anke-to/model"
 	"github.com/traPtitech/anke-to/router"
 	"github.com/traPtitech/anke-to/traq"
+	"github.com/traPtitech/anke-to/webhook"
 )
 
 var (
-	administratorBind = wire.Bind(new(model.IAdministrator), new(*model.Administrator))
-	optionBind        = wire.Bind(new(model.IOption), new(*model.Option))
-	questionnaireBind = wire.Bind(new(model.IQuestionnaire), new(*model.Questionnaire))
-	questionBind      = wire.Bind(new(model.IQuestion), new(*model.Question))
-	respondentBind    = wire.Bind(new(model.IRespondent), new(*model.Respondent))
-	responseBind      = wire.Bind(new(model.IResponse), new(*model.Response))
-	scaleLabelBind    = wire.Bind(new(model.IScaleLabel), new(*model.ScaleLabel))
-	targetBind        = wire.Bind(new(model.ITarget), new(*model.Target))
-	validationBind    = wire.Bind(new(model.IValidation), new(*model.Validation))
-	transactionBind   = wire.Bind(new(model.ITransaction), new(*model.Transaction))
-
-	webhookBind = wire.Bind(new(traq.IWebhook), new(*traq.Webhook))
+	administratorBind     = wire.Bind(new(model.IAdministrator), new(*model.Administrator))
+	optionBind            = wire.Bind(new(model.IOption), new(*model.Option))
+	questionnaireBind     = wire.Bind(new(model.IQuestionnaire), new(*model.Questionnaire))
+	questionBind          = wire.Bind(new(model.IQuestion), new(*model.Question))
+	respondentBind        = wire.Bind(new(model.IRespondent), new(*model.Respondent))
+	responseBind          = wire.Bind(new(model.IResponse), new(*model.Response))
+	scaleLabelBind        = wire.Bind(new(model.IScaleLabel), new(*model.ScaleLabel))
+	targetBind            = wire.Bind(new(model.ITarget), new(*model.Target))
+	validationBind        = wire.Bind(new(model.IValidation), new(*model.Validation))
+	questionConditionBind = wire.Bind(new(model.IQuestionCondition), new(*model.QuestionCondition))
+	groupBind             = wire.Bind(new(model.IGroup), new(*model.Group))
+	systemAdminBind       = wire.Bind(new(model.ISystemAdmin), new(*model.SystemAdmin))
+	transactionBind       = wire.Bind(new(model.ITransaction), new(*model.Transaction))
+	auditBind             = wire.Bind(new(model.IAudit), new(*model.Audit))
+	responseRevisionBind  = wire.Bind(new(model.IResponseRevision), new(*model.ResponseRevision))
+	outboxBind            = wire.Bind(new(model.IOutbox), new(*model.Outbox))
+	reminderBind          = wire.Bind(new(model.IReminder), new(*model.Reminder))
+	messageTemplateBind   = wire.Bind(new(model.IMessageTemplate), new(*model.MessageTemplate))
+	webhookBind           = wire.Bind(new(model.IWebhook), new(*model.Webhook))
+	webhookDeliveryBind   = wire.Bind(new(model.IWebhookDelivery), new(*model.WebhookDelivery))
+
+	clientBind = wire.Bind(new(traq.IClient), new(*traq.Client))
+
+	senderBind = wire.Bind(new(webhook.ISender), new(*webhook.Sender))
 )
 
-func InjectAPIServer() *router.API {
+func InjectAPIServer(authenticator router.Authenticator, messageConfig router.MessageConfig) *router.API {
 	wire.Build(
 		router.NewAPI,
 		router.NewMiddleware,
@@ -34,6 +47,13 @@ func InjectAPIServer() *router.API {
 		router.NewResponse,
 		router.NewResult,
 		router.NewUser,
+		router.NewGroup,
+		router.NewSystemAdmin,
+		router.NewOutbox,
+		router.NewMessageTemplate,
+		router.NewMessageRenderer,
+		router.NewWebhook,
+		router.NewWebhookEventPublisher,
 		model.NewAdministrator,
 		model.NewOption,
 		model.NewQuestionnaire,
@@ -43,8 +63,16 @@ func InjectAPIServer() *router.API {
 		model.NewScaleLabel,
 		model.NewTarget,
 		model.NewValidation,
+		model.NewQuestionCondition,
+		model.NewGroup,
+		model.NewSystemAdmin,
 		model.NewTransaction,
-		traq.NewWebhook,
+		model.NewAudit,
+		model.NewResponseRevision,
+		model.NewOutbox,
+		model.NewMessageTemplate,
+		model.NewWebhook,
+		model.NewWebhookDelivery,
 		administratorBind,
 		optionBind,
 		questionnaireBind,
@@ -54,8 +82,74 @@ func InjectAPIServer() *router.API {
 		scaleLabelBind,
 		targetBind,
 		validationBind,
+		questionConditionBind,
+		groupBind,
+		systemAdminBind,
 		transactionBind,
+		auditBind,
+		responseRevisionBind,
+		outboxBind,
+		messageTemplateBind,
 		webhookBind,
+		webhookDeliveryBind,
+	)
+
+	return nil
+}
+
+func InjectScheduler(reminderConfig router.ReminderConfig, messageConfig router.MessageConfig) *router.Scheduler {
+	wire.Build(
+		router.NewScheduler,
+		router.NewMessageRenderer,
+		router.NewWebhookEventPublisher,
+		model.NewQuestionnaire,
+		model.NewTransaction,
+		model.NewOutbox,
+		model.NewTarget,
+		model.NewRespondent,
+		model.NewReminder,
+		model.NewQuestion,
+		model.NewResponse,
+		model.NewMessageTemplate,
+		model.NewWebhookDelivery,
+		traq.NewWebhook,
+		traq.NewClient,
+		questionnaireBind,
+		transactionBind,
+		outboxBind,
+		targetBind,
+		respondentBind,
+		reminderBind,
+		questionBind,
+		responseBind,
+		messageTemplateBind,
+		webhookDeliveryBind,
+		clientBind,
+	)
+
+	return nil
+}
+
+func InjectOutboxDispatcher() *router.OutboxDispatcher {
+	wire.Build(
+		router.NewOutboxDispatcher,
+		model.NewOutbox,
+		traq.NewWebhook,
+		traq.NewClient,
+		outboxBind,
+		clientBind,
+	)
+
+	return nil
+}
+
+func InjectWebhookDispatcher() *router.WebhookDispatcher {
+	wire.Build(
+		router.NewWebhookDispatcher,
+		model.NewWebhookDelivery,
+		webhook.NewSender,
+		webhookDeliveryBind,
+		senderBind,
 	)
 
 	return nil
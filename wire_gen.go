@@ -11,6 +11,7 @@ import (
 	"github.com/traPtitech/anke-to/model"
 	"github.com/traPtitech/anke-to/router"
 	"github.com/traPtitech/anke-to/traq"
+	"github.com/traPtitech/anke-to/webhook"
 )
 
 import (
@@ -19,28 +20,77 @@ import (
 
 // Injectors from wire.go:
 
-func InjectAPIServer() *router.API {
+func InjectAPIServer(authenticator router.Authenticator, messageConfig router.MessageConfig) *router.API {
 	administrator := model.NewAdministrator()
 	respondent := model.NewRespondent()
 	question := model.NewQuestion()
 	questionnaire := model.NewQuestionnaire()
-	middleware := router.NewMiddleware(administrator, respondent, question, questionnaire)
+	systemAdmin := model.NewSystemAdmin()
+	middleware := router.NewMiddleware(administrator, respondent, question, questionnaire, systemAdmin, authenticator)
 	target := model.NewTarget()
 	option := model.NewOption()
 	scaleLabel := model.NewScaleLabel()
 	validation := model.NewValidation()
+	questionCondition := model.NewQuestionCondition()
+	group := model.NewGroup()
 	transaction := model.NewTransaction()
-	webhook := traq.NewWebhook()
-	routerQuestionnaire := router.NewQuestionnaire(questionnaire, target, administrator, question, option, scaleLabel, validation, transaction, webhook)
-	routerQuestion := router.NewQuestion(validation, question, option, scaleLabel)
+	audit := model.NewAudit()
+	responseRevision := model.NewResponseRevision()
+	outbox := model.NewOutbox()
+	messageTemplate := model.NewMessageTemplate()
+	messageRenderer := router.NewMessageRenderer(messageTemplate, messageConfig)
+	webhookDelivery := model.NewWebhookDelivery()
+	webhookEventPublisher := router.NewWebhookEventPublisher(webhookDelivery)
+	routerQuestionnaire := router.NewQuestionnaire(questionnaire, target, administrator, question, option, scaleLabel, validation, questionCondition, group, transaction, audit, outbox, messageRenderer, webhookEventPublisher)
+	routerQuestion := router.NewQuestion(validation, question, option, scaleLabel, questionCondition, questionnaire, transaction, audit)
 	response := model.NewResponse()
-	routerResponse := router.NewResponse(questionnaire, validation, scaleLabel, respondent, response)
-	result := router.NewResult(respondent, questionnaire, administrator)
+	routerResponse := router.NewResponse(questionnaire, validation, scaleLabel, respondent, response, question, option, questionCondition, transaction, audit, responseRevision, webhookEventPublisher)
+	result := router.NewResult(respondent, questionnaire, administrator, response, question, option)
 	user := router.NewUser(respondent, questionnaire, target, administrator)
-	api := router.NewAPI(middleware, routerQuestionnaire, routerQuestion, routerResponse, result, user)
+	routerGroup := router.NewGroup(group, transaction)
+	routerSystemAdmin := router.NewSystemAdmin(systemAdmin, transaction)
+	routerOutbox := router.NewOutbox(outbox)
+	routerMessageTemplate := router.NewMessageTemplate(questionnaire, respondent, question, response, messageRenderer)
+	modelWebhook := model.NewWebhook()
+	routerWebhook := router.NewWebhook(modelWebhook, webhookDelivery)
+	api := router.NewAPI(middleware, routerQuestionnaire, routerQuestion, routerResponse, result, user, routerGroup, routerSystemAdmin, routerOutbox, routerMessageTemplate, routerWebhook)
 	return api
 }
 
+func InjectScheduler(reminderConfig router.ReminderConfig, messageConfig router.MessageConfig) *router.Scheduler {
+	questionnaire := model.NewQuestionnaire()
+	transaction := model.NewTransaction()
+	outbox := model.NewOutbox()
+	target := model.NewTarget()
+	respondent := model.NewRespondent()
+	reminder := model.NewReminder()
+	question := model.NewQuestion()
+	response := model.NewResponse()
+	webhook := traq.NewWebhook()
+	client := traq.NewClient(webhook)
+	messageTemplate := model.NewMessageTemplate()
+	messageRenderer := router.NewMessageRenderer(messageTemplate, messageConfig)
+	webhookDelivery := model.NewWebhookDelivery()
+	webhookEventPublisher := router.NewWebhookEventPublisher(webhookDelivery)
+	scheduler := router.NewScheduler(questionnaire, transaction, outbox, target, respondent, reminder, question, response, client, messageRenderer, webhookEventPublisher, reminderConfig)
+	return scheduler
+}
+
+func InjectOutboxDispatcher() *router.OutboxDispatcher {
+	outbox := model.NewOutbox()
+	webhook := traq.NewWebhook()
+	client := traq.NewClient(webhook)
+	outboxDispatcher := router.NewOutboxDispatcher(outbox, client)
+	return outboxDispatcher
+}
+
+func InjectWebhookDispatcher() *router.WebhookDispatcher {
+	webhookDelivery := model.NewWebhookDelivery()
+	sender := webhook.NewSender()
+	webhookDispatcher := router.NewWebhookDispatcher(webhookDelivery, sender)
+	return webhookDispatcher
+}
+
 // wire.go:
 
 var (
@@ -53,7 +103,11 @@ var (
 	scaleLabelBind    = wire.Bind(new(model.IScaleLabel), new(*model.ScaleLabel))
 	targetBind        = wire.Bind(new(model.ITarget), new(*model.Target))
 	validationBind    = wire.Bind(new(model.IValidation), new(*model.Validation))
+	groupBind         = wire.Bind(new(model.IGroup), new(*model.Group))
+	systemAdminBind   = wire.Bind(new(model.ISystemAdmin), new(*model.SystemAdmin))
 	transactionBind   = wire.Bind(new(model.ITransaction), new(*model.Transaction))
 
-	webhookBind = wire.Bind(new(traq.IWebhook), new(*traq.Webhook))
+	clientBind = wire.Bind(new(traq.IClient), new(*traq.Client))
+
+	senderBind = wire.Bind(new(webhook.ISender), new(*webhook.Sender))
 )